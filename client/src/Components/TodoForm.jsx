@@ -113,6 +113,7 @@ class TodoForm extends Component {
             {
               headers: {
                 Authorization: `Bearer ${token}`,
+                "If-Match": this.props.editVersion,
               },
             }
           )
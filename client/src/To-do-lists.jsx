@@ -70,6 +70,12 @@ class ToDoList extends Component {
       });
   };
 
+  // Writes must name the version they change; it comes from the last load
+  versionOf = (id) => {
+    const item = this.state.items.find((item) => item.id === id);
+    return item ? `"${item.version}"` : "*";
+  };
+
   updateTask = (id) => {
     const token = localStorage.getItem("token");
     axios
@@ -81,6 +87,7 @@ class ToDoList extends Component {
         {
           headers: {
             Authorization: `Bearer ${token}`,
+            "If-Match": this.versionOf(id),
           },
         }
       )
@@ -95,6 +102,7 @@ class ToDoList extends Component {
       .delete(`${endpoint}/api/todo/${id}`, {
         headers: {
           Authorization: `Bearer ${token}`,
+          "If-Match": this.versionOf(id),
         },
       })
       .then(() => {
@@ -113,6 +121,7 @@ class ToDoList extends Component {
         {
           headers: {
             Authorization: `Bearer ${token}`,
+            "If-Match": this.versionOf(id),
           },
         }
       )
@@ -275,6 +284,7 @@ editTask = (id, task, description, date, time, important) => {
               initialImportant={this.state.important}
               initialDateTime={this.state.dateTime}
               editTaskId={this.state.editTaskId}
+              editVersion={this.versionOf(this.state.editTaskId)}
               initialMorning={this.state.morning}
               initialNoon={this.state.noon}
               initialEvening={this.state.evening}
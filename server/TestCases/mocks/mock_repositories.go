@@ -23,6 +23,16 @@ func (m *MockUserRepository) GetUserByUsername(ctx context.Context, username str
     return args.Get(0).(domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetUserTimezone(ctx context.Context, userID string) (string, error) {
+    args := m.Called(ctx, userID)
+    return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepository) SetUserTimezone(ctx context.Context, userID, timezone string) error {
+    args := m.Called(ctx, userID, timezone)
+    return args.Error(0)
+}
+
 // MockTodoRepository is a mock implementation of domain.TodoRepository
 type MockTodoRepository struct {
     mock.Mock
@@ -33,23 +43,28 @@ func (m *MockTodoRepository) CreateTodo(ctx context.Context, task, description s
     return args.String(0), args.Error(1)
 }
 
+func (m *MockTodoRepository) CreateTodoWithID(ctx context.Context, id, task, description string, done, important bool, userID string, date, todoTime time.Time) error {
+    args := m.Called(ctx, id, task, description, done, important, userID, date, todoTime)
+    return args.Error(0)
+}
+
 func (m *MockTodoRepository) GetTodosByUserID(ctx context.Context, userID string) ([]domain.Todo, error) {
     args := m.Called(ctx, userID)
     return args.Get(0).([]domain.Todo), args.Error(1)
 }
 
-func (m *MockTodoRepository) UpdateTodo(ctx context.Context, id, task, description string, done, important bool, userID string) (bool, error) {
-    args := m.Called(ctx, id, task, description, done, important, userID)
+func (m *MockTodoRepository) UpdateTodo(ctx context.Context, id, task, description string, done, important bool, userID string, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, task, description, done, important, userID, expectedVersion)
     return args.Bool(0), args.Error(1)
 }
 
-func (m *MockTodoRepository) DeleteTodo(ctx context.Context, id, userID string) (bool, error) {
-    args := m.Called(ctx, id, userID)
+func (m *MockTodoRepository) DeleteTodo(ctx context.Context, id, userID string, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, userID, expectedVersion)
     return args.Bool(0), args.Error(1)
 }
 
-func (m *MockTodoRepository) UndoTodo(ctx context.Context, id, userID string) (bool, error) {
-    args := m.Called(ctx, id, userID)
+func (m *MockTodoRepository) UndoTodo(ctx context.Context, id, userID string, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, userID, expectedVersion)
     return args.Bool(0), args.Error(1)
 }
 
@@ -61,6 +76,24 @@ func (m *MockTodoRepository) GetTodoByID(ctx context.Context, id string) (*domai
     return args.Get(0).(*domain.Todo), args.Error(1)
 }
 
+func (m *MockTodoRepository) PatchTodo(ctx context.Context, id, userID string, patch domain.TodoPatch, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, userID, patch, expectedVersion)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTodoRepository) ExecuteBatch(ctx context.Context, userID string, ops []domain.BatchOperation, atomic bool) ([]domain.BatchResult, bool, error) {
+    args := m.Called(ctx, userID, ops, atomic)
+    if args.Get(0) == nil {
+        return nil, args.Bool(1), args.Error(2)
+    }
+    return args.Get(0).([]domain.BatchResult), args.Bool(1), args.Error(2)
+}
+
+func (m *MockTodoRepository) SetTodoDetails(ctx context.Context, todoID string, tags []string, recurrence *domain.TodoRecurrence) error {
+    args := m.Called(ctx, todoID, tags, recurrence)
+    return args.Error(0)
+}
+
 // MockSharedTodoRepository is a mock implementation of domain.SharedTodoRepository
 type MockSharedTodoRepository struct {
     mock.Mock
@@ -81,8 +114,8 @@ func (m *MockSharedTodoRepository) GetSharedByMeTodos(ctx context.Context, share
     return args.Get(0).([]domain.SharedTodo), args.Error(1)
 }
 
-func (m *MockSharedTodoRepository) ShareTodo(ctx context.Context, originalTodoID string, recipientUserID string, sharedBy string) error {
-    args := m.Called(ctx, originalTodoID, recipientUserID, sharedBy)
+func (m *MockSharedTodoRepository) ShareTodo(ctx context.Context, originalTodoID string, recipientUserID string, sharedBy string, permission string) error {
+    args := m.Called(ctx, originalTodoID, recipientUserID, sharedBy, permission)
     return args.Error(0)
 }
 
@@ -91,6 +124,62 @@ func (m *MockSharedTodoRepository) IsSharedWithUser(ctx context.Context, todoID
     return args.Bool(0), args.Error(1)
 }
 
+func (m *MockSharedTodoRepository) GetSharedTodoByID(ctx context.Context, id, userID string) (*domain.SharedTodo, error) {
+    args := m.Called(ctx, id, userID)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(*domain.SharedTodo), args.Error(1)
+}
+
+func (m *MockSharedTodoRepository) UpdateSharePermission(ctx context.Context, id, sharedBy, permission string) (bool, error) {
+    args := m.Called(ctx, id, sharedBy, permission)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSharedTodoRepository) DeleteSharedTodo(ctx context.Context, id, sharedBy string) (bool, error) {
+    args := m.Called(ctx, id, sharedBy)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSharedTodoRepository) AcceptSharedTodo(ctx context.Context, id, userID string) (bool, error) {
+    args := m.Called(ctx, id, userID)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSharedTodoRepository) DeclineSharedTodo(ctx context.Context, id, userID string) (bool, error) {
+    args := m.Called(ctx, id, userID)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSharedTodoRepository) BlockSender(ctx context.Context, userID, senderID string) error {
+    args := m.Called(ctx, userID, senderID)
+    return args.Error(0)
+}
+
+func (m *MockSharedTodoRepository) UnblockSender(ctx context.Context, userID, senderID string) (bool, error) {
+    args := m.Called(ctx, userID, senderID)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSharedTodoRepository) GetBlockedSenders(ctx context.Context, userID string) ([]domain.ShareBlock, error) {
+    args := m.Called(ctx, userID)
+    return args.Get(0).([]domain.ShareBlock), args.Error(1)
+}
+
+func (m *MockSharedTodoRepository) IsSenderBlocked(ctx context.Context, userID, senderID string) (bool, error) {
+    args := m.Called(ctx, userID, senderID)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSharedTodoRepository) ShareTodoWithUsers(ctx context.Context, todoID string, recipientUserIDs []string, sharedBy, permission string) (map[string]string, error) {
+    args := m.Called(ctx, todoID, recipientUserIDs, sharedBy, permission)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(map[string]string), args.Error(1)
+}
+
 // MockTeamRepository is a mock implementation of domain.TeamRepository
 type MockTeamRepository struct {
     mock.Mock
@@ -106,12 +195,9 @@ func (m *MockTeamRepository) GetTeamsByAdminID(ctx context.Context, adminID stri
     return args.Get(0).([]domain.Team), args.Error(1)
 }
 
-func (m *MockTeamRepository) GetTeamByID(ctx context.Context, teamID string) (*domain.Team, error) {
+func (m *MockTeamRepository) GetTeamByID(ctx context.Context, teamID string) (domain.Team, error) {
     args := m.Called(ctx, teamID)
-    if args.Get(0) == nil {
-        return nil, args.Error(1)
-    }
-    return args.Get(0).(*domain.Team), args.Error(1)
+    return args.Get(0).(domain.Team), args.Error(1)
 }
 
 func (m *MockTeamRepository) GetTeams(ctx context.Context, userID string) ([]domain.Team, error) {
@@ -129,9 +215,9 @@ type MockTeamMemberRepository struct {
     mock.Mock
 }
 
-func (m *MockTeamMemberRepository) AddTeamMember(ctx context.Context, teamID, userID string, isAdmin bool) error {
+func (m *MockTeamMemberRepository) AddTeamMember(ctx context.Context, teamID, userID string, isAdmin bool) (bool, error) {
     args := m.Called(ctx, teamID, userID, isAdmin)
-    return args.Error(0)
+    return args.Bool(0), args.Error(1)
 }
 
 func (m *MockTeamMemberRepository) GetTeamMembers(ctx context.Context, teamID string) ([]domain.TeamMember, error) {
@@ -139,9 +225,9 @@ func (m *MockTeamMemberRepository) GetTeamMembers(ctx context.Context, teamID st
     return args.Get(0).([]domain.TeamMember), args.Error(1)
 }
 
-func (m *MockTeamMemberRepository) RemoveTeamMember(ctx context.Context, teamID, userID string) error {
+func (m *MockTeamMemberRepository) RemoveTeamMember(ctx context.Context, teamID, userID string) (bool, error) {
     args := m.Called(ctx, teamID, userID)
-    return args.Error(0)
+    return args.Bool(0), args.Error(1)
 }
 
 func (m *MockTeamMemberRepository) IsTeamAdmin(ctx context.Context, teamID, userID string) (bool, error) {
@@ -154,26 +240,87 @@ type MockTeamTodoRepository struct {
     mock.Mock
 }
 
-func (m *MockTeamTodoRepository) CreateTeamTodo(ctx context.Context, task, description string, done, important bool, teamID, assignedTo string) (string, error) {
-    args := m.Called(ctx, task, description, done, important, teamID, assignedTo)
+func (m *MockTeamTodoRepository) CreateTeamTodo(ctx context.Context, task, description string, done, important bool, teamID, assignedTo, createdBy string, date, todoTime time.Time) (string, error) {
+    args := m.Called(ctx, task, description, done, important, teamID, assignedTo, createdBy, date, todoTime)
     return args.String(0), args.Error(1)
 }
 
+func (m *MockTeamTodoRepository) CreateTeamTodoWithID(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo, createdBy string, date, todoTime time.Time) error {
+    args := m.Called(ctx, id, task, description, done, important, teamID, assignedTo, createdBy, date, todoTime)
+    return args.Error(0)
+}
+
 func (m *MockTeamTodoRepository) GetTeamTodos(ctx context.Context, teamID string) ([]domain.TeamTodo, error) {
     args := m.Called(ctx, teamID)
     return args.Get(0).([]domain.TeamTodo), args.Error(1)
 }
 
-func (m *MockTeamTodoRepository) UpdateTeamTodo(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo string) (bool, error) {
-    args := m.Called(ctx, id, task, description, done, important, teamID, assignedTo)
+func (m *MockTeamTodoRepository) UpdateTeamTodo(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo, updatedBy string, due domain.TeamTodoDue, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, task, description, done, important, teamID, assignedTo, updatedBy, due, expectedVersion)
     return args.Bool(0), args.Error(1)
 }
 
-func (m *MockTeamTodoRepository) DeleteTeamTodo(ctx context.Context, id, teamID string) (bool, error) {
+func (m *MockTeamTodoRepository) DeleteTeamTodo(ctx context.Context, id, teamID string, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, teamID, expectedVersion)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamTodoRepository) GetTeamTodoByID(ctx context.Context, id, teamID string) (*domain.TeamTodo, error) {
     args := m.Called(ctx, id, teamID)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(*domain.TeamTodo), args.Error(1)
+}
+
+func (m *MockTeamTodoRepository) GetTeamTodoTeamID(ctx context.Context, id string) (string, error) {
+    args := m.Called(ctx, id)
+    return args.String(0), args.Error(1)
+}
+
+func (m *MockTeamTodoRepository) PatchTeamTodo(ctx context.Context, id, teamID, updatedBy string, patch domain.TeamTodoPatch, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, teamID, updatedBy, patch, expectedVersion)
     return args.Bool(0), args.Error(1)
 }
 
+func (m *MockTeamTodoRepository) ExecuteBatch(ctx context.Context, teamID, userID string, ops []domain.BatchOperation, atomic bool) ([]domain.BatchResult, bool, error) {
+    args := m.Called(ctx, teamID, userID, ops, atomic)
+    if args.Get(0) == nil {
+        return nil, args.Bool(1), args.Error(2)
+    }
+    return args.Get(0).([]domain.BatchResult), args.Bool(1), args.Error(2)
+}
+
+func (m *MockTeamTodoRepository) ReassignTeamTodo(ctx context.Context, id, teamID, assignedTo, assignedBy string, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, teamID, assignedTo, assignedBy, expectedVersion)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamTodoRepository) SetTeamTodoAssignees(ctx context.Context, id, teamID string, userIDs []string, completionMode, changedBy string, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, teamID, userIDs, completionMode, changedBy, expectedVersion)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamTodoRepository) SetAssigneeDone(ctx context.Context, id, teamID, userID string, done bool) (bool, error) {
+    args := m.Called(ctx, id, teamID, userID, done)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamTodoRepository) MoveTeamTodo(ctx context.Context, id, teamID, statusID string, done bool, order []string, expectedVersion int) (bool, error) {
+    args := m.Called(ctx, id, teamID, statusID, done, order, expectedVersion)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamTodoRepository) GetTeamTodoAssignments(ctx context.Context, id, teamID string) ([]domain.TeamTodoAssignment, error) {
+    args := m.Called(ctx, id, teamID)
+    return args.Get(0).([]domain.TeamTodoAssignment), args.Error(1)
+}
+
+func (m *MockTeamTodoRepository) GetAssignedTeamTodos(ctx context.Context, userID string) ([]domain.AssignedTeamTodo, error) {
+    args := m.Called(ctx, userID)
+    return args.Get(0).([]domain.AssignedTeamTodo), args.Error(1)
+}
+
 // MockRoutineRepository is a mock implementation of domain.RoutineRepository
 type MockRoutineRepository struct {
     mock.Mock
@@ -189,22 +336,308 @@ func (m *MockRoutineRepository) GetRoutinesByTaskID(ctx context.Context, taskID
     return args.Get(0).([]domain.Routine), args.Error(1)
 }
 
-func (m *MockRoutineRepository) GetRoutinesByUserIDAndDay(ctx context.Context, userID, day, scheduleType string) ([]domain.Routine, error) {
-    args := m.Called(ctx, userID, day, scheduleType)
+func (m *MockRoutineRepository) GetDailyRoutines(ctx context.Context, day, scheduleType, userID string) ([]domain.Todo, error) {
+    args := m.Called(ctx, day, scheduleType, userID)
+    return args.Get(0).([]domain.Todo), args.Error(1)
+}
+
+func (m *MockRoutineRepository) UpdateRoutineStatus(ctx context.Context, id string, isActive bool, expectedVersion int) error {
+    args := m.Called(ctx, id, isActive, expectedVersion)
+    return args.Error(0)
+}
+
+func (m *MockRoutineRepository) UpdateRoutineDay(ctx context.Context, id, day string, expectedVersion int) error {
+    args := m.Called(ctx, id, day, expectedVersion)
+    return args.Error(0)
+}
+
+func (m *MockRoutineRepository) DeleteRoutinesByTaskID(ctx context.Context, taskID string) error {
+    args := m.Called(ctx, taskID)
+    return args.Error(0)
+}
+
+func (m *MockRoutineRepository) CreateOrUpdateRoutines(ctx context.Context, taskID string, schedules []string, day string, userID string) ([]domain.Routine, error) {
+    args := m.Called(ctx, taskID, schedules, day, userID)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
     return args.Get(0).([]domain.Routine), args.Error(1)
 }
 
-func (m *MockRoutineRepository) UpdateRoutineStatus(ctx context.Context, id string, isActive bool) (bool, error) {
-    args := m.Called(ctx, id, isActive)
+func (m *MockRoutineRepository) GetRoutineByID(ctx context.Context, id string) (*domain.Routine, error) {
+    args := m.Called(ctx, id)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(*domain.Routine), args.Error(1)
+}
+
+func (m *MockRoutineRepository) CheckInRoutine(ctx context.Context, routineID, userID string, date time.Time) error {
+    args := m.Called(ctx, routineID, userID, date)
+    return args.Error(0)
+}
+
+func (m *MockRoutineRepository) UncheckRoutine(ctx context.Context, routineID string, date time.Time) (bool, error) {
+    args := m.Called(ctx, routineID, date)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRoutineRepository) GetDailyRoutineCheckins(ctx context.Context, day, scheduleType, userID string, date time.Time) ([]domain.RoutineCheckin, error) {
+    args := m.Called(ctx, day, scheduleType, userID, date)
+    return args.Get(0).([]domain.RoutineCheckin), args.Error(1)
+}
+
+func (m *MockRoutineRepository) GetRoutineScheduleDays(ctx context.Context, taskID, scheduleType, userID string) ([]string, error) {
+    args := m.Called(ctx, taskID, scheduleType, userID)
+    return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRoutineRepository) GetRoutineCheckinDates(ctx context.Context, taskID, scheduleType, userID string) ([]time.Time, error) {
+    args := m.Called(ctx, taskID, scheduleType, userID)
+    return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockRoutineRepository) GetRoutineSlots(ctx context.Context, userID string) ([]domain.RoutineSlot, error) {
+    args := m.Called(ctx, userID)
+    return args.Get(0).([]domain.RoutineSlot), args.Error(1)
+}
+
+func (m *MockRoutineRepository) CreateRoutineSlots(ctx context.Context, slots []domain.RoutineSlot) error {
+    args := m.Called(ctx, slots)
+    return args.Error(0)
+}
+
+func (m *MockRoutineRepository) UpdateRoutineSlot(ctx context.Context, slot domain.RoutineSlot, oldName string) error {
+    args := m.Called(ctx, slot, oldName)
+    return args.Error(0)
+}
+
+func (m *MockRoutineRepository) DeleteRoutineSlot(ctx context.Context, id, userID string) (bool, error) {
+    args := m.Called(ctx, id, userID)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRoutineRepository) CountSlotRoutines(ctx context.Context, userID, name string) (int, error) {
+    args := m.Called(ctx, userID, name)
+    return args.Int(0), args.Error(1)
+}
+
+func (m *MockRoutineRepository) SetWeeklyRoutines(ctx context.Context, taskID, userID string, schedule []domain.RoutineSchedule) (domain.RoutineScheduleChanges, error) {
+    args := m.Called(ctx, taskID, userID, schedule)
+    return args.Get(0).(domain.RoutineScheduleChanges), args.Error(1)
+}
+
+func (m *MockRoutineRepository) GetWeekRoutines(ctx context.Context, userID string) ([]domain.WeekRoutine, error) {
+    args := m.Called(ctx, userID)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).([]domain.WeekRoutine), args.Error(1)
+}
+
+func (m *MockRoutineRepository) CreateRoutinePause(ctx context.Context, pause domain.RoutinePause) error {
+    args := m.Called(ctx, pause)
+    return args.Error(0)
+}
+
+func (m *MockRoutineRepository) GetRoutinePauses(ctx context.Context, userID string) ([]domain.RoutinePause, error) {
+    args := m.Called(ctx, userID)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).([]domain.RoutinePause), args.Error(1)
+}
+
+func (m *MockRoutineRepository) DeleteRoutinePause(ctx context.Context, id, userID string) (bool, error) {
+    args := m.Called(ctx, id, userID)
     return args.Bool(0), args.Error(1)
 }
 
-func (m *MockRoutineRepository) UpdateRoutineDay(ctx context.Context, id, day string) (bool, error) {
-    args := m.Called(ctx, id, day)
+// MockCalendarFeedRepository is a mock implementation of domain.CalendarFeedRepository
+type MockCalendarFeedRepository struct {
+    mock.Mock
+}
+
+func (m *MockCalendarFeedRepository) SetCalendarFeedToken(ctx context.Context, userID, tokenHash string) error {
+    args := m.Called(ctx, userID, tokenHash)
+    return args.Error(0)
+}
+
+func (m *MockCalendarFeedRepository) GetCalendarFeedUserID(ctx context.Context, tokenHash string) (string, error) {
+    args := m.Called(ctx, tokenHash)
+    return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepository) DeleteCalendarFeedToken(ctx context.Context, userID string) (bool, error) {
+    args := m.Called(ctx, userID)
     return args.Bool(0), args.Error(1)
 }
 
-func (m *MockRoutineRepository) DeleteRoutinesByTaskID(ctx context.Context, taskID string) (bool, error) {
-    args := m.Called(ctx, taskID)
+// MockDependencyRepository is a mock implementation of domain.DependencyRepository
+type MockDependencyRepository struct {
+    mock.Mock
+}
+
+// AddTodoDependency runs check on the nodes and edges given as the first two return
+// values, and returns its error before the third return value
+func (m *MockDependencyRepository) AddTodoDependency(ctx context.Context, userID, todoID, blockedByID string, check domain.DependencyCheck) error {
+    args := m.Called(ctx, userID, todoID, blockedByID)
+    if err := check(args.Get(0).([]domain.DependencyNode), args.Get(1).([]domain.Dependency)); err != nil {
+        return err
+    }
+    return args.Error(2)
+}
+
+func (m *MockDependencyRepository) RemoveTodoDependency(ctx context.Context, todoID, blockedByID string) (bool, error) {
+    args := m.Called(ctx, todoID, blockedByID)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDependencyRepository) GetTodoDependencies(ctx context.Context, userID string) ([]domain.Dependency, error) {
+    args := m.Called(ctx, userID)
+    return args.Get(0).([]domain.Dependency), args.Error(1)
+}
+
+func (m *MockDependencyRepository) AddTeamTodoDependency(ctx context.Context, teamID, todoID, blockedByID string, check domain.DependencyCheck) error {
+    args := m.Called(ctx, teamID, todoID, blockedByID)
+    if err := check(args.Get(0).([]domain.DependencyNode), args.Get(1).([]domain.Dependency)); err != nil {
+        return err
+    }
+    return args.Error(2)
+}
+
+func (m *MockDependencyRepository) RemoveTeamTodoDependency(ctx context.Context, teamID, todoID, blockedByID string) (bool, error) {
+    args := m.Called(ctx, teamID, todoID, blockedByID)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDependencyRepository) GetTeamTodoDependencies(ctx context.Context, teamID string) ([]domain.Dependency, error) {
+    args := m.Called(ctx, teamID)
+    return args.Get(0).([]domain.Dependency), args.Error(1)
+}
+
+// MockWorkflowRepository is a mock implementation of domain.WorkflowRepository
+type MockWorkflowRepository struct {
+    mock.Mock
+}
+
+func (m *MockWorkflowRepository) GetTeamStatuses(ctx context.Context, teamID string) ([]domain.TeamStatus, error) {
+    args := m.Called(ctx, teamID)
+    return args.Get(0).([]domain.TeamStatus), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) GetStatusTransitions(ctx context.Context, teamID string) ([]domain.StatusTransition, error) {
+    args := m.Called(ctx, teamID)
+    return args.Get(0).([]domain.StatusTransition), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) ReplaceTeamWorkflow(ctx context.Context, teamID string, statuses []domain.TeamStatus, transitions []domain.StatusTransition) error {
+    args := m.Called(ctx, teamID, statuses, transitions)
+    return args.Error(0)
+}
+
+func (m *MockWorkflowRepository) EnsureTeamWorkflow(ctx context.Context, teamID string, statuses []domain.TeamStatus) ([]domain.TeamStatus, error) {
+    args := m.Called(ctx, teamID, statuses)
+    return args.Get(0).([]domain.TeamStatus), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) GetTeamTodoPlacements(ctx context.Context, teamID string) ([]domain.TeamTodoPlacement, error) {
+    args := m.Called(ctx, teamID)
+    return args.Get(0).([]domain.TeamTodoPlacement), args.Error(1)
+}
+
+// MockTimeEntryRepository is a mock implementation of domain.TimeEntryRepository
+type MockTimeEntryRepository struct {
+    mock.Mock
+}
+
+func (m *MockTimeEntryRepository) CreateTimeEntry(ctx context.Context, entry domain.TimeEntry) error {
+    args := m.Called(ctx, entry)
+    return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) StopTimer(ctx context.Context, userID string, endedAt time.Time) (*domain.TimeEntry, error) {
+    args := m.Called(ctx, userID, endedAt)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(*domain.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) GetRunningTimer(ctx context.Context, userID string) (*domain.TimeEntry, error) {
+    args := m.Called(ctx, userID)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(*domain.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) DeleteTimeEntry(ctx context.Context, id, userID string) (bool, error) {
+    args := m.Called(ctx, id, userID)
     return args.Bool(0), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *MockTimeEntryRepository) GetTodoTimeEntries(ctx context.Context, todoID string) ([]domain.TimeEntry, error) {
+    args := m.Called(ctx, todoID)
+    return args.Get(0).([]domain.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) GetTeamTodoTimeEntries(ctx context.Context, teamID, todoID string) ([]domain.TimeEntry, error) {
+    args := m.Called(ctx, teamID, todoID)
+    return args.Get(0).([]domain.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) GetUserTimeEntries(ctx context.Context, userID string, from, to time.Time) ([]domain.TimeEntry, error) {
+    args := m.Called(ctx, userID, from, to)
+    return args.Get(0).([]domain.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) GetTeamTimeEntries(ctx context.Context, teamID string, from, to time.Time) ([]domain.TimeEntry, error) {
+    args := m.Called(ctx, teamID, from, to)
+    return args.Get(0).([]domain.TimeEntry), args.Error(1)
+}
+
+// MockWorkloadRepository is a mock implementation of domain.WorkloadRepository
+type MockWorkloadRepository struct {
+    mock.Mock
+}
+
+func (m *MockWorkloadRepository) SetTeamTodoEstimate(ctx context.Context, teamID string, estimate domain.Estimate) error {
+    args := m.Called(ctx, teamID, estimate)
+    return args.Error(0)
+}
+
+func (m *MockWorkloadRepository) DeleteTeamTodoEstimate(ctx context.Context, teamID, todoID string) (bool, error) {
+    args := m.Called(ctx, teamID, todoID)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWorkloadRepository) GetTeamTodoEstimates(ctx context.Context, teamID string) ([]domain.Estimate, error) {
+    args := m.Called(ctx, teamID)
+    return args.Get(0).([]domain.Estimate), args.Error(1)
+}
+
+func (m *MockWorkloadRepository) GetTeamWorkloadTodos(ctx context.Context, teamID string) ([]domain.WorkloadTodo, error) {
+    args := m.Called(ctx, teamID)
+    return args.Get(0).([]domain.WorkloadTodo), args.Error(1)
+}
+
+func (m *MockWorkloadRepository) GetTeamMemberDetails(ctx context.Context, teamID string) ([]domain.TeamMemberDetails, error) {
+    args := m.Called(ctx, teamID)
+    return args.Get(0).([]domain.TeamMemberDetails), args.Error(1)
+}
+
+// Ensure the repository mocks stay in line with the domain interfaces
+var (
+    _ domain.TodoRepository       = (*MockTodoRepository)(nil)
+    _ domain.TeamRepository       = (*MockTeamRepository)(nil)
+    _ domain.TeamMemberRepository = (*MockTeamMemberRepository)(nil)
+    _ domain.TeamTodoRepository   = (*MockTeamTodoRepository)(nil)
+    _ domain.RoutineRepository    = (*MockRoutineRepository)(nil)
+    _ domain.SharedTodoRepository = (*MockSharedTodoRepository)(nil)
+    _ domain.CalendarFeedRepository = (*MockCalendarFeedRepository)(nil)
+    _ domain.DependencyRepository   = (*MockDependencyRepository)(nil)
+    _ domain.WorkflowRepository     = (*MockWorkflowRepository)(nil)
+    _ domain.TimeEntryRepository    = (*MockTimeEntryRepository)(nil)
+    _ domain.WorkloadRepository     = (*MockWorkloadRepository)(nil)
+)
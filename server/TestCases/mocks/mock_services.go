@@ -2,6 +2,7 @@ package mocks
 
 import (
     "context"
+    "time"
 
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
@@ -213,22 +214,30 @@ func (m *MockRoutineService) GetRoutinesByTaskID(ctx context.Context, taskID str
     return args.Get(0).(*dto.RoutinesResponse), args.Error(1)
 }
 
-func (m *MockRoutineService) GetDailyRoutines(ctx context.Context, day, scheduleType, userID string) (*dto.TodosResponse, error) {
-    args := m.Called(ctx, day, scheduleType, userID)
+func (m *MockRoutineService) GetDailyRoutines(ctx context.Context, day, scheduleType, userID string, loc *time.Location) (*dto.TodosResponse, error) {
+    args := m.Called(ctx, day, scheduleType, userID, loc)
     if args.Get(0) == nil {
         return nil, args.Error(1)
     }
     return args.Get(0).(*dto.TodosResponse), args.Error(1)
 }
 
-func (m *MockRoutineService) GetTodayRoutines(ctx context.Context, scheduleType, userID string) (*dto.TodosResponse, error) {
-    args := m.Called(ctx, scheduleType, userID)
+func (m *MockRoutineService) GetTodayRoutines(ctx context.Context, scheduleType, userID string, loc *time.Location) (*dto.TodosResponse, error) {
+    args := m.Called(ctx, scheduleType, userID, loc)
     if args.Get(0) == nil {
         return nil, args.Error(1)
     }
     return args.Get(0).(*dto.TodosResponse), args.Error(1)
 }
 
+func (m *MockRoutineService) GetTodayCheckins(ctx context.Context, scheduleType, userID string, loc *time.Location) (*dto.RoutineTodosResponse, error) {
+    args := m.Called(ctx, scheduleType, userID, loc)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(*dto.RoutineTodosResponse), args.Error(1)
+}
+
 func (m *MockRoutineService) UpdateRoutineStatus(ctx context.Context, id string, isActive bool) (*dto.SuccessResponse, error) {
     args := m.Called(ctx, id, isActive)
     if args.Get(0) == nil {
@@ -0,0 +1,166 @@
+package handlers_test
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/caldav"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/team_todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/teams"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "golang.org/x/crypto/bcrypt"
+)
+
+// calDAVFixture serves alice's CalDAV requests over one todo, "todo-1" at version 2
+func calDAVFixture() (*mocks.MockTodoRepository, func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder) {
+    ctx := context.Background()
+    userID := "user-123"
+    hashed, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+
+    userRepo := new(mocks.MockUserRepository)
+    todoRepo := new(mocks.MockTodoRepository)
+    teamRepo := new(mocks.MockTeamRepository)
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    dependencyRepo := new(mocks.MockDependencyRepository)
+
+    userRepo.On("GetUserByUsername", ctx, "alice").Return(domain.User{ID: userID, Username: "alice", Password: string(hashed)}, nil)
+    teamRepo.On("GetTeams", ctx, userID).Return([]domain.Team{}, nil)
+    existing := &domain.Todo{ID: "todo-1", Task: "Buy milk", UserID: userID, Version: 2,
+        Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Time: time.Date(2000, 1, 1, 9, 30, 0, 0, time.UTC)}
+    todoRepo.On("GetTodosByUserID", ctx, userID).Return([]domain.Todo{*existing}, nil)
+    todoRepo.On("GetTodoByID", ctx, "todo-1").Return(existing, nil)
+    dependencyRepo.On("GetTodoDependencies", ctx, userID).Return([]domain.Dependency{}, nil)
+
+    dependencyService := dependencies.NewDependencyService(dependencyRepo, todoRepo, teamTodoRepo)
+    handler := caldav.NewHandler("/caldav",
+        users.NewUserService(userRepo),
+        todos.NewTodoService(todoRepo, dependencyService),
+        teams.NewTeamService(teamRepo),
+        team_todos.NewTeamTodoService(teamTodoRepo, new(mocks.MockTeamMemberRepository), dependencyService),
+    )
+    return todoRepo, func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+        req := httptest.NewRequest(method, path, strings.NewReader(body))
+        req.SetBasicAuth("alice", "secret")
+        for key, value := range headers {
+            req.Header.Set(key, value)
+        }
+        rec := httptest.NewRecorder()
+        handler.ServeHTTP(rec, req)
+        return rec
+    }
+}
+
+func TestCalDAVAccess(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestCalDAVAccess ===")
+    fmt.Println("Testing who may reach a CalDAV calendar")
+
+    _, serve := calDAVFixture()
+
+    // Scenario 1: Requests without credentials are challenged
+    fmt.Println("Scenario 1: Testing missing credentials")
+    rec := serve("PROPFIND", "/caldav/", "", map[string]string{"Authorization": ""})
+    assert.Equal(t, http.StatusUnauthorized, rec.Code)
+    assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Basic")
+    fmt.Println("✅ Unauthenticated request rejected with a Basic challenge")
+
+    // Scenario 2: Other users' calendars are off limits
+    fmt.Println("\nScenario 2: Testing access to another user's calendar")
+    rec = serve("PROPFIND", "/caldav/user-456/todos/", "", map[string]string{"Depth": "0"})
+    assert.Equal(t, http.StatusForbidden, rec.Code)
+    fmt.Println("✅ Access to another user's calendar forbidden")
+}
+
+func TestCalDAVPropfindETags(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestCalDAVPropfindETags ===")
+    fmt.Println("Testing that PROPFIND lists todos with their versions as ETags")
+
+    _, serve := calDAVFixture()
+    rec := serve("PROPFIND", "/caldav/user-123/todos/", `<?xml version="1.0"?>
+        <D:propfind xmlns:D="DAV:"><D:prop><D:getetag/><D:quota-used-bytes/></D:prop></D:propfind>`,
+        map[string]string{"Depth": "1"})
+
+    assert.Equal(t, http.StatusMultiStatus, rec.Code)
+    assert.Contains(t, rec.Body.String(), "<D:href>/caldav/user-123/todos/todo-1.ics</D:href>")
+    assert.Contains(t, rec.Body.String(), "<D:getetag>&#34;2&#34;</D:getetag>")
+    // Properties the server does not know are reported missing rather than failing the request
+    assert.Contains(t, rec.Body.String(), "HTTP/1.1 404 Not Found")
+    fmt.Println("✅ Todo listed with version 2 as its ETag")
+}
+
+func TestCalDAVMultigetMissingObject(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestCalDAVMultigetMissingObject ===")
+    fmt.Println("Testing that calendar-multiget reports unknown objects per href")
+
+    todoRepo, serve := calDAVFixture()
+    todoRepo.On("GetTodoByID", context.Background(), "missing").Return(nil, fmt.Errorf("todo not found"))
+    rec := serve("REPORT", "/caldav/user-123/todos/", `<?xml version="1.0"?>
+        <C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+          <D:prop><D:getetag/><C:calendar-data/></D:prop>
+          <D:href>/caldav/user-123/todos/todo-1.ics</D:href>
+          <D:href>/caldav/user-123/todos/missing.ics</D:href>
+        </C:calendar-multiget>`, nil)
+
+    assert.Equal(t, http.StatusMultiStatus, rec.Code)
+    assert.Contains(t, rec.Body.String(), "DUE:20240501T093000")
+    assert.Contains(t, rec.Body.String(), "<D:href>/caldav/user-123/todos/missing.ics</D:href><D:status>HTTP/1.1 404 Not Found</D:status>")
+    fmt.Println("✅ Found todo returned with its due time, missing one reported as 404")
+}
+
+func TestCalDAVPutCreatesTodo(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestCalDAVPutCreatesTodo ===")
+    fmt.Println("Testing that a PUT of a new object creates a todo under the client's ID")
+
+    ctx := context.Background()
+    todoRepo, serve := calDAVFixture()
+    todoRepo.On("GetTodoByID", ctx, "new-todo").Return(nil, fmt.Errorf("todo not found"))
+    // The folded, escaped description is unfolded, PRIORITY:1 marks it important and the alarm is ignored
+    todoRepo.On("CreateTodoWithID", ctx, "new-todo", "Call mom", "Sunday, after lunch", false, true, "user-123",
+        time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC), time.Time{}).Return(nil)
+    rec := serve(http.MethodPut, "/caldav/user-123/todos/new-todo.ics", "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"+
+        "BEGIN:VTODO\r\nUID:new-todo\r\nSUMMARY:Call mom\r\nDESCRIPTION:Sunday\\, after\r\n  lunch\r\nPRIORITY:1\r\n"+
+        "DUE;VALUE=DATE:20240505\r\nBEGIN:VALARM\r\nACTION:DISPLAY\r\nSUMMARY:Reminder\r\nEND:VALARM\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+        map[string]string{"If-None-Match": "*"})
+
+    assert.Equal(t, http.StatusCreated, rec.Code)
+    assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+    todoRepo.AssertCalled(t, "CreateTodoWithID", ctx, "new-todo", "Call mom", "Sunday, after lunch", false, true, "user-123",
+        time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC), time.Time{})
+    fmt.Println("✅ New todo created from VTODO")
+}
+
+func TestCalDAVStaleWrites(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestCalDAVStaleWrites ===")
+    fmt.Println("Testing that If-Match carries the todo version into writes")
+
+    ctx := context.Background()
+    todoRepo, serve := calDAVFixture()
+
+    // Scenario 1: PUT with a stale ETag is rejected
+    fmt.Println("Scenario 1: Testing PUT with a stale If-Match")
+    todoRepo.On("PatchTodo", ctx, "todo-1", "user-123", mock.AnythingOfType("domain.TodoPatch"), 1).
+        Return(false, fmt.Errorf("version mismatch: current version is 2"))
+    rec := serve(http.MethodPut, "/caldav/user-123/todos/todo-1.ics",
+        "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:todo-1\r\nSUMMARY:Buy oat milk\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+        map[string]string{"If-Match": `"1"`})
+    assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+    fmt.Println("✅ Stale write rejected with 412")
+
+    // Scenario 2: DELETE passes the current version on
+    fmt.Println("\nScenario 2: Testing DELETE with the current If-Match")
+    todoRepo.On("DeleteTodo", ctx, "todo-1", "user-123", 2).Return(true, nil)
+    rec = serve(http.MethodDelete, "/caldav/user-123/todos/todo-1.ics", "", map[string]string{"If-Match": `"2"`})
+    assert.Equal(t, http.StatusNoContent, rec.Code)
+    todoRepo.AssertCalled(t, "DeleteTodo", ctx, "todo-1", "user-123", 2)
+    fmt.Println("✅ Todo deleted at version 2")
+}
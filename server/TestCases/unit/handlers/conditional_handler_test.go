@@ -0,0 +1,107 @@
+package handlers_test
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/dgrijalva/jwt-go"
+    "github.com/gorilla/mux"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/api"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/middleware"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
+    "github.com/stretchr/testify/assert"
+)
+
+func TestConditionalWrites(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestConditionalWrites ===")
+    fmt.Println("Testing If-Match on writes and ETags on their responses")
+
+    ctx := context.Background()
+    userID := "user-123"
+
+    // Scenario 1: The original /api routes require If-Match like /api/v1.
+    // The check runs before any handler, so the router needs no database.
+    fmt.Println("Scenario 1: Testing legacy write routes without If-Match")
+    router := mux.NewRouter()
+    handler.SetupRoutes(router, nil)
+    token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{Username: "alice", UserID: userID}).
+        SignedString([]byte("ZLR+ZInOHXQst1seVlV6JVuZe1k3vasV1BRyqAHAyaY="))
+    assert.NoError(t, err)
+    for _, route := range []struct{ method, path string }{
+        {"PUT", "/api/todo/todo-1"},
+        {"DELETE", "/api/todo/todo-1"},
+        {"PUT", "/api/todo/undo/todo-1"},
+        {"PATCH", "/api/shared/share-1"},
+        {"PUT", "/api/shared/share-1/complete"},
+        {"PUT", "/api/team/team-1/todo/tt-1"},
+        {"DELETE", "/api/team/team-1/todo/tt-1"},
+        {"PUT", "/api/routine/routine-1"},
+        {"PUT", "/api/routine/routine-1/status"},
+    } {
+        req := httptest.NewRequest(route.method, route.path, strings.NewReader(`{}`))
+        req.Header.Set("Authorization", "Bearer "+token)
+        rec := httptest.NewRecorder()
+        router.ServeHTTP(rec, req)
+        assert.Equal(t, http.StatusPreconditionRequired, rec.Code, "%s %s", route.method, route.path)
+    }
+    fmt.Println("✅ Legacy writes without If-Match rejected with 428")
+
+    // Scenario 2: A full update returns the new version as its ETag
+    fmt.Println("\nScenario 2: Testing the ETag of a todo PUT")
+    todoRepo := new(mocks.MockTodoRepository)
+    todoRepo.On("UpdateTodo", ctx, "todo-1", "Buy milk", "", false, false, userID, 2).Return(true, nil)
+    todoRepo.On("GetTodoByID", ctx, "todo-1").Return(&domain.Todo{ID: "todo-1", Task: "Buy milk", UserID: userID, Version: 3}, nil)
+    todoService := todos.NewTodoService(todoRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), todoRepo, nil))
+
+    req := httptest.NewRequest("PUT", "/api/todo/todo-1", strings.NewReader(`{"task":"Buy milk"}`))
+    req = mux.SetURLVars(req, map[string]string{"id": "todo-1"})
+    req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+    req.Header.Set("If-Match", `"2"`)
+    rec := httptest.NewRecorder()
+    middleware.RequireIfMatch(api.UpdateTodo(todoService)).ServeHTTP(rec, req)
+    assert.Equal(t, http.StatusOK, rec.Code)
+    assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+    fmt.Println("✅ Todo PUT answered with the new version as the ETag")
+
+    // Scenario 3: Routine updates do the same
+    fmt.Println("\nScenario 3: Testing the ETag of a routine PUT")
+    routineRepo := new(mocks.MockRoutineRepository)
+    routineRepo.On("UpdateRoutineStatus", ctx, "routine-1", false, 4).Return(nil)
+    routineRepo.On("GetRoutineByID", ctx, "routine-1").Return(&domain.Routine{ID: "routine-1", UserID: userID, Version: 5}, nil)
+    routineService := routines.NewRoutineService(routineRepo, todoRepo)
+
+    req = httptest.NewRequest("PUT", "/api/routine/routine-1/status", strings.NewReader(`{"isActive":false}`))
+    req = mux.SetURLVars(req, map[string]string{"id": "routine-1"})
+    req.Header.Set("If-Match", `"4"`)
+    rec = httptest.NewRecorder()
+    middleware.RequireIfMatch(api.UpdateRoutineStatus(routineService)).ServeHTTP(rec, req)
+    assert.Equal(t, http.StatusOK, rec.Code)
+    assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+    fmt.Println("✅ Routine PUT answered with the new version as the ETag")
+
+    // Scenario 4: The shared helpers read back the tags they write
+    fmt.Println("\nScenario 4: Testing the shared If-Match parsing")
+    for header, want := range map[string]int{"": 0, "*": 0, middleware.VersionETag(7): 7} {
+        req = httptest.NewRequest("PUT", "/", nil)
+        req.Header.Set("If-Match", header)
+        version, ok := middleware.IfMatchVersion(req)
+        assert.True(t, ok, header)
+        assert.Equal(t, want, version, header)
+    }
+    for _, header := range []string{`W/"7"`, `"abc"`, `"0"`} {
+        req = httptest.NewRequest("PUT", "/", nil)
+        req.Header.Set("If-Match", header)
+        _, ok := middleware.IfMatchVersion(req)
+        assert.False(t, ok, header)
+    }
+    fmt.Println("✅ Strong version tags round trip; weak and malformed tags never match")
+}
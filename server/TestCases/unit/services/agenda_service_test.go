@@ -0,0 +1,107 @@
+package services_test
+
+import (
+    "context"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/agenda"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/stretchr/testify/assert"
+)
+
+func TestAgendaService(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestAgendaService ===")
+    fmt.Println("Testing the aggregated daily agenda")
+
+    ctx := context.Background()
+    userID := "user-123"
+    now := time.Now().UTC()
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+    daysAgo := func(n int) time.Time { return today.AddDate(0, 0, -n) }
+    clock := func(hour int) time.Time { return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC) }
+
+    todoRepo := new(mocks.MockTodoRepository)
+    routineRepo := new(mocks.MockRoutineRepository)
+    sharedTodoRepo := new(mocks.MockSharedTodoRepository)
+    teamRepo := new(mocks.MockTeamRepository)
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    agendaService := agenda.NewAgendaService(todoRepo, routines.NewRoutineService(routineRepo, todoRepo), sharedTodoRepo, teamRepo, teamTodoRepo)
+
+    todoRepo.On("GetTodosByUserID", ctx, userID).Return([]domain.Todo{
+        {ID: "todo-1", Task: "Pay rent", UserID: userID, Date: today, Time: clock(9)},
+        {ID: "todo-2", Task: "Stretch", UserID: userID, Date: today},
+        {ID: "todo-3", Task: "Call bank", UserID: userID, Date: daysAgo(2)},
+        {ID: "todo-4", Task: "Old and done", UserID: userID, Date: daysAgo(2), Done: true},
+        {ID: "todo-5", Task: "Undated", UserID: userID},
+    }, nil)
+    sharedTodoRepo.On("GetSharedTodos", ctx, userID).Return([]domain.SharedTodo{
+        {ID: "shared-1", Task: "Buy gift", UserID: userID, Date: today, Important: true, SharedBy: "alice", Status: domain.ShareStatusAccepted},
+        {ID: "shared-2", Task: "Unanswered invite", UserID: userID, Date: today, SharedBy: "bob", Status: domain.ShareStatusPending},
+        {ID: "shared-3", TodoID: "orig-1", Task: "Plan trip", UserID: userID, Date: today, SharedBy: "carol", Status: domain.ShareStatusAccepted},
+        {ID: "shared-4", TodoID: "orig-1", Task: "Plan trip", UserID: userID, Date: today, SharedBy: "dave", Status: domain.ShareStatusAccepted},
+    }, nil)
+    teamRepo.On("GetTeams", ctx, userID).Return([]domain.Team{{ID: "team-1", Name: "Core"}, {ID: "team-2", Name: "Ops"}}, nil)
+    teamTodoRepo.On("GetTeamTodos", ctx, "team-1").Return([]domain.TeamTodo{
+        {ID: "tt-1", Task: "Review PR", TeamID: "team-1", AssignedTo: userID, Date: today, Time: clock(8)},
+        {ID: "tt-2", Task: "Someone else's", TeamID: "team-1", AssignedTo: "user-456", Date: today},
+    }, nil)
+    teamTodoRepo.On("GetTeamTodos", ctx, "team-2").Return([]domain.TeamTodo{}, nil)
+    routineRepo.On("GetRoutineSlots", ctx, userID).Return([]domain.RoutineSlot{
+        {ID: "s-morning", UserID: userID, Name: "morning", StartTime: "08:00"},
+        {ID: "s-night", UserID: userID, Name: "night", StartTime: "21:00"},
+    }, nil)
+    todayName := []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}[today.Weekday()]
+    routineRepo.On("GetDailyRoutines", ctx, todayName, "morning", userID).Return([]domain.Todo{
+        {ID: "todo-2", Task: "Stretch", UserID: userID},
+        {ID: "todo-8", Task: "Meditate", UserID: userID},
+    }, nil)
+    routineRepo.On("GetDailyRoutines", ctx, todayName, "night", userID).Return([]domain.Todo{
+        {ID: "todo-6", Task: "Read", UserID: userID},
+    }, nil)
+    routineRepo.On("GetRoutinePauses", ctx, userID).Return([]domain.RoutinePause{
+        {ID: "p-1", RoutineID: "r-4", StartDate: today, EndDate: today},
+    }, nil)
+    routineRepo.On("GetDailyRoutineCheckins", ctx, todayName, "morning", userID, today).Return([]domain.RoutineCheckin{
+        {RoutineID: "r-2", TaskID: "todo-2", CheckedIn: true},
+        {RoutineID: "r-4", TaskID: "todo-8"},
+    }, nil)
+    routineRepo.On("GetDailyRoutineCheckins", ctx, todayName, "night", userID, today).Return([]domain.RoutineCheckin{
+        {RoutineID: "r-1", TaskID: "todo-6"},
+    }, nil)
+
+    // Scenario 1: A bad date is rejected
+    fmt.Println("Scenario 1: Testing date validation")
+    _, err := agendaService.GetAgenda(ctx, &dto.AgendaRequest{UserID: userID, Date: "tomorrow", Location: time.UTC})
+    assert.Contains(t, err.Error(), "invalid date")
+    fmt.Println("✅ Invalid date rejected")
+
+    // Scenario 2: Today's agenda merges every source, sorted and deduplicated
+    fmt.Println("\nScenario 2: Testing today's agenda")
+    res, err := agendaService.GetAgenda(ctx, &dto.AgendaRequest{UserID: userID, Location: time.UTC})
+    assert.NoError(t, err)
+    assert.Equal(t, today.Format("2006-01-02"), res.Date)
+    ids := make([]string, len(res.Items))
+    for i, item := range res.Items {
+        ids[i] = item.ID
+    }
+    assert.Equal(t, []string{"todo-3", "tt-1", "todo-1", "shared-1", "shared-3"}, ids)
+    assert.True(t, res.Items[0].Overdue)
+    assert.Equal(t, "Core", res.Items[1].TeamName)
+    assert.Equal(t, "alice", res.Items[3].SharedBy)
+    fmt.Println("✅ Overdue, timed and untimed items ordered; routine todos and repeated shares listed once")
+
+    // Scenario 3: Routines are grouped by slot without paused ones
+    fmt.Println("\nScenario 3: Testing routines by slot")
+    assert.Len(t, res.Routines, 2)
+    assert.Equal(t, "morning", res.Routines[0].Slot.Name)
+    assert.Len(t, res.Routines[0].Routines, 1)
+    assert.Equal(t, "r-2", res.Routines[0].Routines[0].RoutineID)
+    assert.True(t, res.Routines[0].Routines[0].CheckedIn)
+    assert.Equal(t, "r-1", res.Routines[1].Routines[0].RoutineID)
+    fmt.Println("✅ Routines grouped with check-ins and pauses applied")
+}
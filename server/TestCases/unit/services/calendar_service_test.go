@@ -0,0 +1,104 @@
+package services_test
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/calendar"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestCalendarFeed(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestCalendarFeed ===")
+    fmt.Println("Testing iCalendar feed tokens and rendering")
+
+    ctx := context.Background()
+    userID := "user-123"
+
+    feedRepo := new(mocks.MockCalendarFeedRepository)
+    todoRepo := new(mocks.MockTodoRepository)
+    routineRepo := new(mocks.MockRoutineRepository)
+    teamRepo := new(mocks.MockTeamRepository)
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    calendarService := calendar.NewCalendarService(feedRepo, todoRepo, routineRepo, teamRepo, teamTodoRepo)
+
+    // Scenario 1: Creating a token stores only its hash
+    fmt.Println("Scenario 1: Testing feed token creation")
+    feedRepo.On("SetCalendarFeedToken", ctx, userID, mock.AnythingOfType("string")).Return(nil)
+    res, err := calendarService.CreateFeedToken(ctx, userID)
+    assert.NoError(t, err)
+    assert.Len(t, res.Token, 64)
+
+    sum := sha256.Sum256([]byte(res.Token))
+    tokenHash := hex.EncodeToString(sum[:])
+    feedRepo.AssertCalled(t, "SetCalendarFeedToken", ctx, userID, tokenHash)
+    fmt.Println("✅ Feed token created and stored hashed")
+
+    // Scenario 2: The feed contains dated todos, team todos and weekly routines
+    fmt.Println("\nScenario 2: Testing feed rendering")
+    feedRepo.On("GetCalendarFeedUserID", ctx, tokenHash).Return(userID, nil)
+    todoRepo.On("GetTodosByUserID", ctx, userID).Return([]domain.Todo{
+        {ID: "todo-1", Task: "Pay rent, on time", UserID: userID, Important: true,
+            Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Time: time.Date(2000, 1, 1, 9, 30, 0, 0, time.UTC)},
+        {ID: "todo-2", Task: "Stretch", UserID: userID},
+    }, nil)
+    routineRepo.On("GetRoutineSlots", ctx, userID).Return([]domain.RoutineSlot{}, nil)
+    routineRepo.On("GetRoutinesByTaskID", ctx, "todo-1").Return([]domain.Routine{}, nil)
+    routineRepo.On("GetRoutinesByTaskID", ctx, "todo-2").Return([]domain.Routine{
+        {ID: "routine-1", Day: "monday", ScheduleType: "evening", TaskID: "todo-2", UserID: userID, IsActive: true,
+            CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+        {ID: "routine-2", Day: "friday", ScheduleType: "morning", TaskID: "todo-2", UserID: userID, IsActive: false},
+    }, nil)
+    teamRepo.On("GetTeams", ctx, userID).Return([]domain.Team{{ID: "team-1", Name: "Core"}}, nil)
+    teamTodoRepo.On("GetTeamTodos", ctx, "team-1").Return([]domain.TeamTodo{
+        {ID: "team-todo-1", Task: "Release", TeamID: "team-1", Done: true, Date: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+    }, nil)
+
+    data, err := calendarService.GetFeed(ctx, res.Token, "")
+    assert.NoError(t, err)
+    feed := string(data)
+    assert.True(t, strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\n"))
+    assert.Contains(t, feed, "BEGIN:VTODO\r\nUID:todo-todo-1@")
+    assert.Contains(t, feed, "SUMMARY:Pay rent\\, on time\r\n")
+    assert.Contains(t, feed, "DUE:20240501T093000\r\n")
+    assert.Contains(t, feed, "PRIORITY:1\r\n")
+    assert.Contains(t, feed, "DUE;VALUE=DATE:20240503\r\nSTATUS:COMPLETED\r\n")
+    assert.Contains(t, feed, "CATEGORIES:Core\r\n")
+    assert.Contains(t, feed, "DTSTART:20240506T180000\r\nDURATION:PT1H\r\nRRULE:FREQ=WEEKLY;BYDAY=MO\r\n")
+    assert.NotContains(t, feed, "routine-2")
+    assert.NotContains(t, feed, "UID:todo-todo-2@")
+    fmt.Println("✅ Feed rendered todos, team todos and active routines")
+
+    // Scenario 3: Todos can be published as events
+    fmt.Println("\nScenario 3: Testing todos rendered as events")
+    data, err = calendarService.GetFeed(ctx, res.Token, calendar.EntryModeEvent)
+    assert.NoError(t, err)
+    assert.NotContains(t, string(data), "BEGIN:VTODO")
+    assert.Contains(t, string(data), "DTSTART:20240501T093000\r\nDURATION:PT30M\r\n")
+    fmt.Println("✅ Todos rendered as VEVENT entries")
+
+    // Scenario 4: Unknown and revoked tokens are rejected
+    fmt.Println("\nScenario 4: Testing unknown token and revocation")
+    feedRepo.On("GetCalendarFeedUserID", ctx, mock.AnythingOfType("string")).Return("", fmt.Errorf("calendar feed not found"))
+    _, err = calendarService.GetFeed(ctx, "unknown", "")
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "not found")
+
+    feedRepo.On("DeleteCalendarFeedToken", ctx, userID).Return(true, nil).Once()
+    feedRepo.On("DeleteCalendarFeedToken", ctx, userID).Return(false, nil).Once()
+    revoked, err := calendarService.RevokeFeedToken(ctx, userID)
+    assert.NoError(t, err)
+    assert.True(t, revoked.Success)
+    _, err = calendarService.RevokeFeedToken(ctx, userID)
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "not found")
+    fmt.Println("✅ Unknown tokens and repeated revocation return not found")
+}
@@ -0,0 +1,135 @@
+package services_test
+
+import (
+    "context"
+    "fmt"
+    "testing"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestDependencyService(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestDependencyService ===")
+    fmt.Println("Testing blocked-by links between todos")
+
+    ctx := context.Background()
+    userID := "user-123"
+    teamID := "team-1"
+
+    repo := new(mocks.MockDependencyRepository)
+    todoRepo := new(mocks.MockTodoRepository)
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    dependencyService := dependencies.NewDependencyService(repo, todoRepo, teamTodoRepo)
+
+    // design <- build <- ship: build is blocked by design, ship by build
+    todoRepo.On("GetTodosByUserID", ctx, userID).Return([]domain.Todo{
+        {ID: "design", Task: "Design", UserID: userID},
+        {ID: "build", Task: "Build", UserID: userID},
+        {ID: "ship", Task: "Ship", UserID: userID},
+        {ID: "notes", Task: "Notes", UserID: userID, Done: true},
+    }, nil)
+    edges := []domain.Dependency{
+        {TodoID: "build", BlockedByID: "design"},
+        {TodoID: "ship", BlockedByID: "build"},
+    }
+    repo.On("GetTodoDependencies", ctx, userID).Return(edges, nil)
+
+    // The repository hands the check the same graph, locked inside its transaction
+    nodes := []domain.DependencyNode{
+        {ID: "design", Task: "Design"},
+        {ID: "build", Task: "Build"},
+        {ID: "ship", Task: "Ship"},
+        {ID: "notes", Task: "Notes", Done: true},
+    }
+    repo.On("AddTodoDependency", ctx, userID, mock.Anything, mock.Anything).Return(nodes, edges, nil)
+
+    // Scenario 1: A link that closes a loop is rejected with the cycle path
+    fmt.Println("Scenario 1: Testing cycle detection")
+    _, err := dependencyService.AddTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "design", BlockedByID: "ship", UserID: userID})
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "would create a cycle: design -> ship -> build -> design")
+    assert.NotContains(t, err.Error(), "failed to add dependency")
+    fmt.Println("✅ Cycle rejected")
+
+    // Scenario 2: Self links, duplicates and unknown todos are rejected
+    fmt.Println("\nScenario 2: Testing invalid links")
+    _, err = dependencyService.AddTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "ship", BlockedByID: "ship", UserID: userID})
+    assert.Contains(t, err.Error(), "cannot block itself")
+    _, err = dependencyService.AddTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "ship", BlockedByID: "build", UserID: userID})
+    assert.Contains(t, err.Error(), "already exists")
+    _, err = dependencyService.AddTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "ship", BlockedByID: "someone-elses", UserID: userID})
+    assert.Contains(t, err.Error(), "not found")
+    fmt.Println("✅ Invalid links rejected")
+
+    // Scenario 3: A valid link is stored and returned with blocker state
+    fmt.Println("\nScenario 3: Testing a valid link")
+    res, err := dependencyService.AddTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "ship", BlockedByID: "notes", UserID: userID})
+    assert.NoError(t, err)
+    assert.True(t, res.Blocked)
+    assert.Len(t, res.BlockedBy, 2)
+    repo.AssertCalled(t, "AddTodoDependency", ctx, userID, "ship", "notes")
+    fmt.Println("✅ Link added")
+
+    // Scenario 4: Completing a blocked todo fails unless its blockers complete with it
+    fmt.Println("\nScenario 4: Testing completion checks")
+    err = dependencyService.CheckTodosCanComplete(ctx, userID, "ship")
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "blocked by open todos: build")
+    assert.NoError(t, dependencyService.CheckTodosCanComplete(ctx, userID, "design", "build"))
+    fmt.Println("✅ Blocked completion rejected, joint completion allowed")
+
+    // Scenario 5: List block states only mark todos with open blockers as blocked
+    fmt.Println("\nScenario 5: Testing block states for list responses")
+    states, err := dependencyService.TodoBlockStates(ctx, userID, []dto.TodoResponse{
+        {ID: "design", Done: true},
+        {ID: "build"},
+        {ID: "ship"},
+    })
+    assert.NoError(t, err)
+    assert.False(t, states["build"].Blocked)
+    assert.Equal(t, []string{"design"}, states["build"].BlockedBy)
+    assert.True(t, states["ship"].Blocked)
+    _, listed := states["design"]
+    assert.False(t, listed)
+    fmt.Println("✅ Block states computed")
+
+    // Scenario 6: Team links only join todos of the same team
+    fmt.Println("\nScenario 6: Testing team todo links")
+    repo.On("AddTeamTodoDependency", ctx, teamID, mock.Anything, mock.Anything).Return([]domain.DependencyNode{
+        {ID: "t-1", Task: "Spec"},
+        {ID: "t-2", Task: "Review"},
+    }, []domain.Dependency{}, nil)
+    _, err = dependencyService.AddTeamTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "t-2", BlockedByID: "other-team-todo", TeamID: teamID})
+    assert.Contains(t, err.Error(), "not found")
+    res, err = dependencyService.AddTeamTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "t-2", BlockedByID: "t-1", TeamID: teamID})
+    assert.NoError(t, err)
+    assert.Equal(t, "t-1", res.BlockedBy[0].ID)
+    fmt.Println("✅ Team links validated")
+
+    // Scenario 7: The check sees links committed by a concurrent request
+    fmt.Println("\nScenario 7: Testing the cycle check against the locked graph")
+    repo.On("AddTeamTodoDependency", ctx, "team-2", "t-2", "t-1").Return([]domain.DependencyNode{
+        {ID: "t-1", Task: "Spec"},
+        {ID: "t-2", Task: "Review"},
+    }, []domain.Dependency{{TodoID: "t-1", BlockedByID: "t-2"}}, nil)
+    _, err = dependencyService.AddTeamTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "t-2", BlockedByID: "t-1", TeamID: "team-2"})
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "would create a cycle: t-2 -> t-1 -> t-2")
+    fmt.Println("✅ Link rejected once the reverse link is visible under the lock")
+
+    // Scenario 8: Storage failures are reported apart from rule violations
+    fmt.Println("\nScenario 8: Testing a failed insert")
+    repo.On("AddTeamTodoDependency", ctx, "team-3", "t-2", "t-1").Return([]domain.DependencyNode{
+        {ID: "t-1", Task: "Spec"},
+        {ID: "t-2", Task: "Review"},
+    }, []domain.Dependency{}, fmt.Errorf("deadlock found"))
+    _, err = dependencyService.AddTeamTodoDependency(ctx, &dto.AddDependencyRequest{TodoID: "t-2", BlockedByID: "t-1", TeamID: "team-3"})
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "failed to add dependency: deadlock found")
+    fmt.Println("✅ Insert failure reported")
+}
@@ -0,0 +1,76 @@
+package services_test
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/quick_add"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/shared_todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestQuickAddService(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestQuickAddService ===")
+    fmt.Println("Testing todos created from quick-add text")
+
+    ctx := context.Background()
+    userID := "user-123"
+    date := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+    nineAM := time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC)
+
+    todoRepo := new(mocks.MockTodoRepository)
+    sharedTodoRepo := new(mocks.MockSharedTodoRepository)
+    userRepo := new(mocks.MockUserRepository)
+    todoService := todos.NewTodoService(todoRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), todoRepo, nil))
+    quickAddService := quick_add.NewQuickAddService(todoRepo, todoService,
+        routines.NewRoutineService(new(mocks.MockRoutineRepository), todoRepo),
+        shared_todos.NewSharedTodoService(sharedTodoRepo, todoRepo, userRepo, new(mocks.MockTeamMemberRepository)),
+        users.NewUserService(userRepo))
+
+    // Scenario 1: Tags and a monthly rule are stored with the todo
+    fmt.Println("Scenario 1: Testing tags and a recurrence routines cannot express")
+    todoRepo.On("CreateTodo", ctx, "Pay rent", "", false, true, userID, date, nineAM).Return("todo-1", nil).Once()
+    todoRepo.On("SetTodoDetails", ctx, "todo-1", []string{"home"},
+        &domain.TodoRecurrence{TodoID: "todo-1", Frequency: "monthly", Interval: 1}).Return(nil).Once()
+    res, err := quickAddService.QuickAdd(ctx, &dto.QuickAddRequest{Text: "Pay rent 2030-01-15 9am !high #home every month", UserID: userID, Location: time.UTC})
+    assert.NoError(t, err)
+    assert.Equal(t, "todo-1", res.ID)
+    assert.Equal(t, []string{"home"}, res.Tags)
+    assert.Equal(t, "monthly", res.Recurrence.Frequency)
+    assert.Empty(t, res.RoutineIDs)
+    assert.Empty(t, res.Warnings)
+    fmt.Println("✅ Description left alone; tags and recurrence stored")
+
+    // Scenario 2: A todo whose tags cannot be stored is removed again
+    fmt.Println("\nScenario 2: Testing a failed tag write")
+    todoRepo.On("CreateTodo", ctx, "Water plants", "", false, false, userID, date, nineAM).Return("todo-2", nil).Once()
+    todoRepo.On("SetTodoDetails", ctx, "todo-2", []string(nil),
+        &domain.TodoRecurrence{TodoID: "todo-2", Frequency: "daily", Interval: 3}).Return(errors.New("connection reset")).Once()
+    todoRepo.On("DeleteTodo", ctx, "todo-2", userID, 0).Return(true, nil).Once()
+    _, err = quickAddService.QuickAdd(ctx, &dto.QuickAddRequest{Text: "Water plants 2030-01-15 9am every 3 days", UserID: userID, Location: time.UTC})
+    assert.Contains(t, err.Error(), "failed to save tags and recurrence")
+    todoRepo.AssertCalled(t, "DeleteTodo", ctx, "todo-2", userID, 0)
+    fmt.Println("✅ Half-saved todo removed and the error reported")
+
+    // Scenario 3: Tags too long for storage and dry runs never write
+    fmt.Println("\nScenario 3: Testing requests that must not write")
+    _, err = quickAddService.QuickAdd(ctx, &dto.QuickAddRequest{Text: "Call mom #family-long-tag-that-is-way-beyond-the-fifty-character-limit", UserID: userID, Location: time.UTC})
+    assert.Contains(t, err.Error(), "longer than 50 characters")
+    res, err = quickAddService.QuickAdd(ctx, &dto.QuickAddRequest{Text: "Call mom #family every 2 weeks", DryRun: true, UserID: userID, Location: time.UTC})
+    assert.NoError(t, err)
+    assert.Empty(t, res.ID)
+    todoRepo.AssertNumberOfCalls(t, "CreateTodo", 2)
+    todoRepo.AssertNotCalled(t, "SetTodoDetails", mock.Anything, mock.Anything, []string{"family"}, mock.Anything)
+    fmt.Println("✅ Over-long tag rejected and dry run left the store alone")
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/quickadd"
+    "github.com/stretchr/testify/assert"
+)
+
+func TestQuickAddParser(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestQuickAddParser ===")
+    fmt.Println("Testing natural-language quick-add parsing")
+
+    // Wednesday
+    now := time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC)
+    day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+    clock := func(h, m int) time.Time { return time.Date(2000, 1, 1, h, m, 0, 0, time.UTC) }
+
+    // Scenario 1: All token kinds in one line
+    fmt.Println("Scenario 1: Testing a fully annotated line")
+    res, err := quickadd.Parse("Pay rent tomorrow 9am !high #home @alice every month", now)
+    assert.NoError(t, err)
+    assert.Equal(t, "Pay rent", res.Task)
+    assert.Equal(t, day(2024, 5, 2), res.Date)
+    assert.Equal(t, clock(9, 0), res.Time)
+    assert.Equal(t, quickadd.PriorityHigh, res.Priority)
+    assert.Equal(t, []string{"home"}, res.Tags)
+    assert.Equal(t, []string{"alice"}, res.Mentions)
+    assert.Equal(t, &quickadd.Recurrence{Frequency: quickadd.FrequencyMonthly, Interval: 1}, res.Recurrence)
+    fmt.Println("✅ Task, date, time, priority, tag, mention and recurrence parsed")
+
+    // Scenario 2: Relative and absolute dates
+    fmt.Println("\nScenario 2: Testing date phrases")
+    cases := map[string]time.Time{
+        "Standup on friday":            day(2024, 5, 3),
+        "Standup next wednesday":       day(2024, 5, 8),
+        "Standup this wednesday":       day(2024, 5, 1),
+        "Review in 2 weeks":            day(2024, 5, 15),
+        "Renew passport by 2024-06-30": day(2024, 6, 30),
+        "Birthday march 3rd":           day(2025, 3, 3),
+        "Dentist 12 may":               day(2024, 5, 12),
+    }
+    for text, want := range cases {
+        res, err := quickadd.Parse(text, now)
+        assert.NoError(t, err, text)
+        assert.Equal(t, want, res.Date, text)
+    }
+    fmt.Println("✅ Date phrases resolved against the current day")
+
+    // Scenario 3: Times and connector words
+    fmt.Println("\nScenario 3: Testing time phrases")
+    res, err = quickadd.Parse("Call mom at 6:30 pm", now)
+    assert.NoError(t, err)
+    assert.Equal(t, "Call mom", res.Task)
+    assert.Equal(t, clock(18, 30), res.Time)
+    res, err = quickadd.Parse("Meet at the cafe at noon", now)
+    assert.NoError(t, err)
+    assert.Equal(t, "Meet at the cafe", res.Task)
+    assert.Equal(t, clock(12, 0), res.Time)
+    res, err = quickadd.Parse("Buy 2 apples", now)
+    assert.NoError(t, err)
+    assert.Equal(t, "Buy 2 apples", res.Task)
+    assert.True(t, res.Time.IsZero())
+    fmt.Println("✅ Times parsed and unrelated words kept in the title")
+
+    // Scenario 4: Weekly recurrence on weekdays starts on the next matching day
+    fmt.Println("\nScenario 4: Testing weekday recurrence")
+    res, err = quickadd.Parse("Gym every monday and thursday 7am", now)
+    assert.NoError(t, err)
+    assert.Equal(t, "Gym", res.Task)
+    assert.Equal(t, []string{"monday", "thursday"}, res.Recurrence.Weekdays)
+    assert.Equal(t, day(2024, 5, 6), res.Date)
+    for _, text := range []string{"gym every mon, wed and fri 6pm", "gym every mon,wed,fri 6pm"} {
+        res, err = quickadd.Parse(text, now)
+        assert.NoError(t, err, text)
+        assert.Equal(t, "gym", res.Task, text)
+        assert.Equal(t, []string{"monday", "wednesday", "friday"}, res.Recurrence.Weekdays, text)
+        assert.Equal(t, day(2024, 5, 6), res.Date, text)
+        assert.Equal(t, clock(18, 0), res.Time, text)
+    }
+    res, err = quickadd.Parse("Water plants every 3 days", now)
+    assert.NoError(t, err)
+    assert.Equal(t, &quickadd.Recurrence{Frequency: quickadd.FrequencyDaily, Interval: 3}, res.Recurrence)
+    fmt.Println("✅ Recurrence rules parsed")
+
+    // Scenario 5: Text with nothing left for the title is rejected
+    fmt.Println("\nScenario 5: Testing empty task")
+    _, err = quickadd.Parse("tomorrow 9am !high", now)
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "task cannot be empty")
+    fmt.Println("✅ Empty task rejected")
+}
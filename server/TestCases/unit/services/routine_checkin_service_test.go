@@ -0,0 +1,112 @@
+package services_test
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/stretchr/testify/assert"
+)
+
+func TestRoutineCheckins(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestRoutineCheckins ===")
+    fmt.Println("Testing routine check-ins, daily reset and streaks")
+
+    ctx := context.Background()
+    userID := "user-123"
+    // A zone far from the server's makes "today" depend on the user's timezone
+    loc := time.FixedZone("UTC+14", 14*60*60)
+    now := time.Now().In(loc)
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+    todayName := strings.ToLower(today.Weekday().String())
+    daysAgo := func(n int) time.Time { return today.AddDate(0, 0, -n) }
+
+    repo := new(mocks.MockRoutineRepository)
+    routineService := routines.NewRoutineService(repo, new(mocks.MockTodoRepository))
+    routine := &domain.Routine{ID: "r-1", Day: todayName, ScheduleType: "morning", TaskID: "todo-1", UserID: userID, IsActive: true}
+    repo.On("GetRoutineByID", ctx, "r-1").Return(routine, nil)
+    repo.On("GetRoutinePauses", ctx, userID).Return([]domain.RoutinePause{}, nil)
+
+    // Scenario 1: Check-ins are limited to the owner's scheduled, non-future days
+    fmt.Println("Scenario 1: Testing check-in validation")
+    _, err := routineService.CheckIn(ctx, &dto.RoutineCheckinRequest{RoutineID: "r-1", UserID: "someone-else"})
+    assert.Contains(t, err.Error(), "routine not found")
+    _, err = routineService.CheckIn(ctx, &dto.RoutineCheckinRequest{RoutineID: "r-1", UserID: userID, Location: loc, Date: "yesterday"})
+    assert.Contains(t, err.Error(), "invalid date")
+    _, err = routineService.CheckIn(ctx, &dto.RoutineCheckinRequest{RoutineID: "r-1", UserID: userID, Location: loc, Date: today.AddDate(0, 0, 7).Format("2006-01-02")})
+    assert.Contains(t, err.Error(), "cannot check in a future date")
+    _, err = routineService.CheckIn(ctx, &dto.RoutineCheckinRequest{RoutineID: "r-1", UserID: userID, Location: loc, Date: daysAgo(1).Format("2006-01-02")})
+    assert.Contains(t, err.Error(), "routine is not scheduled on")
+    repo.AssertNotCalled(t, "CheckInRoutine")
+    fmt.Println("✅ Invalid check-ins rejected")
+
+    // Scenario 2: Checking in defaults to today and unchecking is idempotent
+    fmt.Println("\nScenario 2: Testing check-in and uncheck")
+    repo.On("CheckInRoutine", ctx, "r-1", userID, today).Return(nil)
+    checkin, err := routineService.CheckIn(ctx, &dto.RoutineCheckinRequest{RoutineID: "r-1", UserID: userID, Location: loc})
+    assert.NoError(t, err)
+    assert.True(t, checkin.CheckedIn)
+    assert.Equal(t, today.Format("2006-01-02"), checkin.Date)
+    repo.On("UncheckRoutine", ctx, "r-1", daysAgo(7)).Return(false, nil)
+    checkin, err = routineService.Uncheck(ctx, &dto.RoutineCheckinRequest{RoutineID: "r-1", UserID: userID, Location: loc, Date: daysAgo(7).Format("2006-01-02")})
+    assert.NoError(t, err)
+    assert.False(t, checkin.CheckedIn)
+    fmt.Println("✅ Check-in recorded and removed")
+
+    // Scenario 3: Today's routines are done only when checked in today
+    fmt.Println("\nScenario 3: Testing the daily reset")
+    repo.On("GetDailyRoutines", ctx, todayName, "morning", userID).Return([]domain.Todo{
+        {ID: "todo-1", Task: "Stretch", UserID: userID, Done: true},
+        {ID: "todo-2", Task: "Journal", UserID: userID},
+    }, nil)
+    repo.On("GetDailyRoutineCheckins", ctx, todayName, "morning", userID, today).Return([]domain.RoutineCheckin{
+        {RoutineID: "r-1", TaskID: "todo-1"},
+        {RoutineID: "r-2", TaskID: "todo-2", CheckedIn: true},
+    }, nil)
+    todayCheckins, err := routineService.GetTodayCheckins(ctx, "morning", userID, loc)
+    assert.NoError(t, err)
+    assert.Len(t, todayCheckins.Todos, 2)
+    assert.False(t, todayCheckins.Todos[0].Done)
+    assert.Equal(t, "r-1", todayCheckins.Todos[0].RoutineID)
+    assert.True(t, todayCheckins.Todos[1].Done)
+    assert.True(t, todayCheckins.Todos[1].CheckedIn)
+    
+    // The original endpoint keeps its plain todo list
+    todayRoutines, err := routineService.GetTodayRoutines(ctx, "morning", userID, loc)
+    assert.NoError(t, err)
+    assert.Equal(t, []dto.TodoResponse{todayCheckins.Todos[0].TodoResponse, todayCheckins.Todos[1].TodoResponse}, todayRoutines.Todos)
+    fmt.Println("✅ Done reflects today's check-ins only")
+
+    // Scenario 4: Daily streaks survive an unchecked today but break on a missed day
+    fmt.Println("\nScenario 4: Testing daily streaks")
+    everyDay := []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+    repo.On("GetRoutineScheduleDays", ctx, "todo-1", "morning", userID).Return(everyDay, nil).Once()
+    repo.On("GetRoutineCheckinDates", ctx, "todo-1", "morning", userID).Return([]time.Time{
+        daysAgo(9), daysAgo(8), daysAgo(7), daysAgo(6), daysAgo(3), daysAgo(2), daysAgo(1),
+    }, nil).Once()
+    streak, err := routineService.GetStreak(ctx, "r-1", userID, loc)
+    assert.NoError(t, err)
+    assert.Equal(t, 3, streak.CurrentStreak)
+    assert.Equal(t, 4, streak.LongestStreak)
+    assert.Equal(t, 7, streak.TotalCheckins)
+    assert.Equal(t, daysAgo(1).Format("2006-01-02"), streak.LastCheckin)
+    fmt.Println("✅ Current and longest daily streaks computed")
+
+    // Scenario 5: Weekly routines only count their scheduled weekday
+    fmt.Println("\nScenario 5: Testing weekly streaks")
+    repo.On("GetRoutineScheduleDays", ctx, "todo-1", "morning", userID).Return([]string{todayName}, nil).Once()
+    repo.On("GetRoutineCheckinDates", ctx, "todo-1", "morning", userID).Return([]time.Time{
+        daysAgo(28), daysAgo(14), daysAgo(7), today,
+    }, nil).Once()
+    streak, err = routineService.GetStreak(ctx, "r-1", userID, loc)
+    assert.NoError(t, err)
+    assert.Equal(t, 3, streak.CurrentStreak)
+    assert.Equal(t, 3, streak.LongestStreak)
+    fmt.Println("✅ Days off neither extend nor break the streak")
+}
@@ -0,0 +1,107 @@
+package services_test
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestRoutinePauses(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestRoutinePauses ===")
+    fmt.Println("Testing vacation mode for routines")
+
+    ctx := context.Background()
+    userID := "user-123"
+    now := time.Now().UTC()
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+    todayName := strings.ToLower(today.Weekday().String())
+    daysAgo := func(n int) time.Time { return today.AddDate(0, 0, -n) }
+
+    repo := new(mocks.MockRoutineRepository)
+    routineService := routines.NewRoutineService(repo, new(mocks.MockTodoRepository))
+    routine := &domain.Routine{ID: "r-1", Day: todayName, ScheduleType: "morning", TaskID: "todo-1", UserID: userID, IsActive: true}
+    repo.On("GetRoutineByID", ctx, "r-1").Return(routine, nil)
+    repo.On("GetRoutineByID", ctx, "missing").Return(nil, errors.New("routine not found"))
+
+    // Scenario 1: Pauses need a valid date range and one of the user's routines
+    fmt.Println("Scenario 1: Testing pause validation")
+    _, err := routineService.CreatePause(ctx, &dto.CreateRoutinePauseRequest{UserID: userID, StartDate: "soon", EndDate: "2024-08-10"}, time.UTC)
+    assert.Contains(t, err.Error(), "invalid start_date")
+    _, err = routineService.CreatePause(ctx, &dto.CreateRoutinePauseRequest{UserID: userID, StartDate: "2024-08-10", EndDate: "2024-08-01"}, time.UTC)
+    assert.Contains(t, err.Error(), "end_date must not be before start_date")
+    _, err = routineService.CreatePause(ctx, &dto.CreateRoutinePauseRequest{UserID: "someone-else", RoutineID: "r-1", StartDate: "2024-08-01", EndDate: "2024-08-10"}, time.UTC)
+    assert.Contains(t, err.Error(), "routine not found")
+    _, err = routineService.CreatePause(ctx, &dto.CreateRoutinePauseRequest{UserID: userID, RoutineID: "missing", StartDate: "2024-08-01", EndDate: "2024-08-10"}, time.UTC)
+    assert.Contains(t, err.Error(), "routine not found")
+    repo.AssertNotCalled(t, "CreateRoutinePause", mock.Anything, mock.Anything)
+    fmt.Println("✅ Invalid pauses rejected")
+
+    // Scenario 2: A pause over today is reported as active
+    fmt.Println("\nScenario 2: Testing pause creation")
+    repo.On("CreateRoutinePause", ctx, mock.MatchedBy(func(p domain.RoutinePause) bool {
+        return p.RoutineID == "" && p.UserID == userID && p.StartDate.Equal(daysAgo(1)) && p.Reason == "Holiday"
+    })).Return(nil)
+    pause, err := routineService.CreatePause(ctx, &dto.CreateRoutinePauseRequest{UserID: userID,
+        StartDate: daysAgo(1).Format("2006-01-02"), EndDate: today.AddDate(0, 0, 3).Format("2006-01-02"), Reason: " Holiday "}, time.UTC)
+    assert.NoError(t, err)
+    assert.NotEmpty(t, pause.ID)
+    assert.True(t, pause.Active)
+    fmt.Println("✅ Pause stored")
+
+    // Scenario 3: Paused routines drop out of today's list
+    fmt.Println("\nScenario 3: Testing today's routines during pauses")
+    repo.On("GetDailyRoutines", ctx, todayName, "morning", userID).Return([]domain.Todo{
+        {ID: "todo-1", Task: "Stretch", UserID: userID},
+        {ID: "todo-2", Task: "Journal", UserID: userID},
+    }, nil)
+    repo.On("GetDailyRoutineCheckins", ctx, todayName, "morning", userID, today).Return([]domain.RoutineCheckin{
+        {RoutineID: "r-1", TaskID: "todo-1"},
+        {RoutineID: "r-2", TaskID: "todo-2"},
+    }, nil)
+    repo.On("GetRoutinePauses", ctx, userID).Return([]domain.RoutinePause{
+        {ID: "p-1", RoutineID: "", StartDate: daysAgo(1), EndDate: today},
+    }, nil).Once()
+    todayRoutines, err := routineService.GetTodayRoutines(ctx, "morning", userID, time.UTC)
+    assert.NoError(t, err)
+    assert.Empty(t, todayRoutines.Todos)
+    repo.On("GetRoutinePauses", ctx, userID).Return([]domain.RoutinePause{
+        {ID: "p-1", RoutineID: "", StartDate: daysAgo(9), EndDate: daysAgo(8)},
+        {ID: "p-2", RoutineID: "r-1", Day: todayName, TaskID: "todo-1", ScheduleType: "morning", StartDate: today, EndDate: today},
+    }, nil).Once()
+    todayRoutines, err = routineService.GetTodayRoutines(ctx, "morning", userID, time.UTC)
+    assert.NoError(t, err)
+    assert.Len(t, todayRoutines.Todos, 1)
+    assert.Equal(t, "todo-2", todayRoutines.Todos[0].ID)
+    fmt.Println("✅ Paused routines hidden and others kept")
+
+    // Scenario 4: Paused days neither extend nor break a streak
+    fmt.Println("\nScenario 4: Testing streaks across a pause")
+    repo.On("GetRoutineScheduleDays", ctx, "todo-1", "morning", userID).
+        Return([]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}, nil)
+    repo.On("GetRoutineCheckinDates", ctx, "todo-1", "morning", userID).Return([]time.Time{daysAgo(6), daysAgo(5), daysAgo(1)}, nil)
+    repo.On("GetRoutinePauses", ctx, userID).Return([]domain.RoutinePause{
+        {ID: "p-1", RoutineID: "", StartDate: daysAgo(4), EndDate: daysAgo(2)},
+    }, nil).Once()
+    streak, err := routineService.GetStreak(ctx, "r-1", userID, time.UTC)
+    assert.NoError(t, err)
+    assert.Equal(t, 3, streak.CurrentStreak)
+    assert.Equal(t, 3, streak.LongestStreak)
+    fmt.Println("✅ Streak kept across the pause")
+
+    // Scenario 5: Deleting another user's pause fails
+    fmt.Println("\nScenario 5: Testing pause deletion")
+    repo.On("DeleteRoutinePause", ctx, "p-1", "someone-else").Return(false, nil)
+    _, err = routineService.DeletePause(ctx, "p-1", "someone-else")
+    assert.Contains(t, err.Error(), "pause not found")
+    fmt.Println("✅ Foreign pause not deleted")
+}
@@ -0,0 +1,106 @@
+package services_test
+
+import (
+    "context"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestRoutineSlots(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestRoutineSlots ===")
+    fmt.Println("Testing custom routine slots")
+
+    ctx := context.Background()
+    userID := "user-123"
+
+    // Scenario 1: A user without slots gets the defaults
+    fmt.Println("Scenario 1: Testing default slot creation")
+    repo := new(mocks.MockRoutineRepository)
+    routineService := routines.NewRoutineService(repo, new(mocks.MockTodoRepository))
+    repo.On("GetRoutineSlots", ctx, "new-user").Return([]domain.RoutineSlot{}, nil)
+    repo.On("CreateRoutineSlots", ctx, mock.MatchedBy(func(slots []domain.RoutineSlot) bool {
+        return len(slots) == 4 && slots[0].UserID == "new-user"
+    })).Return(nil)
+    res, err := routineService.GetSlots(ctx, "new-user")
+    assert.NoError(t, err)
+    assert.Len(t, res.Slots, 4)
+    assert.Equal(t, "morning", res.Slots[0].Name)
+    assert.Equal(t, "21:00", res.Slots[3].StartTime)
+    fmt.Println("✅ Default slots created")
+
+    slots := []domain.RoutineSlot{
+        {ID: "s-gym", UserID: userID, Name: "Gym", StartTime: "06:30"},
+        {ID: "s-morning", UserID: userID, Name: "morning", StartTime: "08:00"},
+        {ID: "s-night", UserID: userID, Name: "night", StartTime: "21:00"},
+    }
+    repo.On("GetRoutineSlots", ctx, userID).Return(slots, nil)
+
+    // Scenario 2: Slots need a usable name and an HH:MM start time
+    fmt.Println("\nScenario 2: Testing slot validation")
+    _, err = routineService.CreateSlot(ctx, &dto.RoutineSlotRequest{UserID: userID, Name: "  ", StartTime: "07:00"})
+    assert.Contains(t, err.Error(), "invalid slot name")
+    _, err = routineService.CreateSlot(ctx, &dto.RoutineSlotRequest{UserID: userID, Name: "Lunch", StartTime: "noon"})
+    assert.Contains(t, err.Error(), "invalid start time")
+    repo.On("CreateRoutineSlots", ctx, mock.MatchedBy(func(slots []domain.RoutineSlot) bool {
+        return len(slots) == 1 && slots[0].Name == "Lunch" && slots[0].StartTime == "12:30"
+    })).Return(nil)
+    created, err := routineService.CreateSlot(ctx, &dto.RoutineSlotRequest{UserID: userID, Name: " Lunch ", StartTime: "12:30"})
+    assert.NoError(t, err)
+    assert.NotEmpty(t, created.ID)
+    fmt.Println("✅ Slot validated and created")
+
+    // Scenario 3: Routines must use one of the user's slots
+    fmt.Println("\nScenario 3: Testing routine slot checks")
+    _, err = routineService.CreateOrUpdateRoutines(ctx, &dto.CreateOrUpdateRoutinesRequest{TaskID: "todo-1", UserID: userID, Day: "monday", Schedules: []string{"noon"}})
+    assert.Contains(t, err.Error(), `unknown slot "noon"`)
+    repo.AssertNotCalled(t, "CreateOrUpdateRoutines", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    fmt.Println("✅ Unknown slot rejected")
+
+    // Scenario 4: Renaming a slot moves its routines; busy slots cannot be deleted
+    fmt.Println("\nScenario 4: Testing slot rename and delete")
+    repo.On("UpdateRoutineSlot", ctx, domain.RoutineSlot{ID: "s-gym", UserID: userID, Name: "Workout", StartTime: "06:45"}, "Gym").Return(nil)
+    updated, err := routineService.UpdateSlot(ctx, &dto.RoutineSlotRequest{ID: "s-gym", UserID: userID, Name: "Workout", StartTime: "06:45"})
+    assert.NoError(t, err)
+    assert.Equal(t, "Workout", updated.Name)
+    _, err = routineService.UpdateSlot(ctx, &dto.RoutineSlotRequest{ID: "missing", UserID: userID, Name: "X", StartTime: "06:45"})
+    assert.Contains(t, err.Error(), "slot not found")
+    repo.On("CountSlotRoutines", ctx, userID, "Gym").Return(2, nil)
+    _, err = routineService.DeleteSlot(ctx, "s-gym", userID)
+    assert.Contains(t, err.Error(), "slot is in use by 2 routines")
+    repo.AssertNotCalled(t, "DeleteRoutineSlot", ctx, "s-gym", userID)
+    fmt.Println("✅ Rename saved and busy slot kept")
+
+    // Scenario 5: A day's routines are grouped by slot in start time order
+    fmt.Println("\nScenario 5: Testing daily routines ordered by slot time")
+    repo.On("GetRoutinePauses", ctx, userID).Return([]domain.RoutinePause{}, nil)
+    repo.On("GetDailyRoutines", ctx, "monday", "Gym", userID).Return([]domain.Todo{{ID: "todo-2", Task: "Lift"}}, nil)
+    repo.On("GetDailyRoutines", ctx, "monday", "morning", userID).Return([]domain.Todo{}, nil)
+    repo.On("GetDailyRoutines", ctx, "monday", "night", userID).Return([]domain.Todo{{ID: "todo-3", Task: "Read"}}, nil)
+    day, err := routineService.GetDayRoutines(ctx, "monday", userID, time.UTC)
+    assert.NoError(t, err)
+    assert.Len(t, day.Slots, 2)
+    assert.Equal(t, "Gym", day.Slots[0].Slot.Name)
+    assert.Equal(t, "todo-3", day.Slots[1].Todos[0].ID)
+    fmt.Println("✅ Empty slots skipped and slots ordered by start time")
+
+    // Scenario 6: Quick-add times map to the slot they fall into
+    fmt.Println("\nScenario 6: Testing slot lookup by time")
+    at := func(hour, minute int) time.Time { return time.Date(2000, 1, 1, hour, minute, 0, 0, time.UTC) }
+    for _, tc := range []struct {
+        at   time.Time
+        slot string
+    }{{time.Time{}, "Gym"}, {at(5, 0), "Gym"}, {at(6, 30), "Gym"}, {at(12, 0), "morning"}, {at(23, 15), "night"}} {
+        name, err := routineService.SlotForTime(ctx, userID, tc.at)
+        assert.NoError(t, err)
+        assert.Equal(t, tc.slot, name)
+    }
+    fmt.Println("✅ Times mapped to slots")
+}
@@ -0,0 +1,85 @@
+package services_test
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "testing"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestRoutineWeek(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestRoutineWeek ===")
+    fmt.Println("Testing the weekly routine planner")
+
+    ctx := context.Background()
+    userID := "user-123"
+
+    repo := new(mocks.MockRoutineRepository)
+    todoRepo := new(mocks.MockTodoRepository)
+    routineService := routines.NewRoutineService(repo, todoRepo)
+
+    repo.On("GetRoutineSlots", ctx, userID).Return([]domain.RoutineSlot{
+        {ID: "s-gym", UserID: userID, Name: "Gym", StartTime: "06:30"},
+        {ID: "s-morning", UserID: userID, Name: "morning", StartTime: "08:00"},
+    }, nil)
+    todoRepo.On("GetTodoByID", ctx, "todo-1").Return(&domain.Todo{ID: "todo-1", Task: "Stretch", UserID: userID}, nil)
+    todoRepo.On("GetTodoByID", ctx, "other-todo").Return(&domain.Todo{ID: "other-todo", UserID: "someone-else"}, nil)
+    todoRepo.On("GetTodoByID", ctx, "missing").Return(nil, errors.New("todo not found"))
+
+    // Scenario 1: The task must belong to the user and the matrix must be valid
+    fmt.Println("Scenario 1: Testing schedule validation")
+    _, err := routineService.SetWeeklySchedule(ctx, &dto.SetWeeklyScheduleRequest{TaskID: "missing", UserID: userID})
+    assert.Contains(t, err.Error(), "todo not found")
+    _, err = routineService.SetWeeklySchedule(ctx, &dto.SetWeeklyScheduleRequest{TaskID: "other-todo", UserID: userID})
+    assert.Contains(t, err.Error(), "todo not found")
+    _, err = routineService.SetWeeklySchedule(ctx, &dto.SetWeeklyScheduleRequest{TaskID: "todo-1", UserID: userID,
+        Schedule: map[string][]string{"funday": {"Gym"}}})
+    assert.Contains(t, err.Error(), `invalid day "funday"`)
+    _, err = routineService.SetWeeklySchedule(ctx, &dto.SetWeeklyScheduleRequest{TaskID: "todo-1", UserID: userID,
+        Schedule: map[string][]string{"monday": {"evening"}}})
+    assert.Contains(t, err.Error(), `unknown slot "evening"`)
+    repo.AssertNotCalled(t, "SetWeeklyRoutines", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    fmt.Println("✅ Invalid schedules rejected")
+
+    // Scenario 2: The whole matrix is saved in one call, in week order and without duplicates
+    fmt.Println("\nScenario 2: Testing a schedule update")
+    repo.On("SetWeeklyRoutines", ctx, "todo-1", userID, []domain.RoutineSchedule{
+        {Day: "monday", ScheduleType: "Gym"},
+        {Day: "monday", ScheduleType: "morning"},
+        {Day: "friday", ScheduleType: "Gym"},
+    }).Return(domain.RoutineScheduleChanges{Created: 2, Reactivated: 1, Deactivated: 3}, nil)
+    res, err := routineService.SetWeeklySchedule(ctx, &dto.SetWeeklyScheduleRequest{TaskID: "todo-1", UserID: userID,
+        Schedule: map[string][]string{"friday": {"Gym"}, "monday": {"Gym", "morning", "Gym"}}})
+    assert.NoError(t, err)
+    assert.Equal(t, []string{"Gym", "morning"}, res.Schedule["monday"])
+    assert.Equal(t, 2, res.Created)
+    assert.Equal(t, 1, res.Reactivated)
+    assert.Equal(t, 3, res.Deactivated)
+    fmt.Println("✅ Schedule saved with change counts")
+
+    // Scenario 3: The week view has every day and slot, with leftover slots at the end
+    fmt.Println("\nScenario 3: Testing the week view")
+    repo.On("GetWeekRoutines", ctx, userID).Return([]domain.WeekRoutine{
+        {RoutineID: "r-1", Day: "monday", ScheduleType: "Gym", TaskID: "todo-1", Task: "Stretch"},
+        {RoutineID: "r-2", Day: "friday", ScheduleType: "Gym", TaskID: "todo-1", Task: "Stretch"},
+        {RoutineID: "r-3", Day: "sunday", ScheduleType: "evening", TaskID: "todo-2", Task: "Read"},
+    }, nil)
+    week, err := routineService.GetWeek(ctx, userID)
+    assert.NoError(t, err)
+    assert.Len(t, week.Days, 7)
+    assert.Equal(t, "sunday", week.Days[0].Day)
+    assert.Len(t, week.Days[1].Slots, 3)
+    assert.Equal(t, "r-1", week.Days[1].Slots[0].Routines[0].RoutineID)
+    assert.Empty(t, week.Days[1].Slots[1].Routines)
+    assert.Equal(t, "evening", week.Days[0].Slots[2].Slot.Name)
+    assert.Equal(t, "r-3", week.Days[0].Slots[2].Routines[0].RoutineID)
+    assert.Equal(t, "r-2", week.Days[5].Slots[0].Routines[0].RoutineID)
+    fmt.Println("✅ Week grouped by day and slot")
+}
@@ -9,6 +9,7 @@ import (
 
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/shared_todos"
     "github.com/stretchr/testify/assert"
 )
@@ -23,7 +24,7 @@ func TestGetSharedTodos(t *testing.T) {
     mockUserRepo := new(mocks.MockUserRepository)
     
     // Create the service with the mock repositories
-    service := shared_todos.NewSharedTodoService(mockRepo, mockTodoRepo, mockUserRepo)
+    service := shared_todos.NewSharedTodoService(mockRepo, mockTodoRepo, mockUserRepo, new(mocks.MockTeamMemberRepository))
     
     // Setup test data
     userID := "user-123"
@@ -41,6 +42,7 @@ func TestGetSharedTodos(t *testing.T) {
             Date:        currentTime,
             Time:        currentTime,
             SharedBy:    "sender-user-1",
+            Status:      domain.ShareStatusAccepted,
         },
         {
             ID:          "shared-todo-2",
@@ -52,6 +54,7 @@ func TestGetSharedTodos(t *testing.T) {
             Date:        currentTime,
             Time:        currentTime,
             SharedBy:    "sender-user-2",
+            Status:      domain.ShareStatusAccepted,
         },
     }
     
@@ -110,7 +113,7 @@ func TestShareTodo(t *testing.T) {
     mockUserRepo := new(mocks.MockUserRepository)
     
     // Create the service with the mock repositories
-    service := shared_todos.NewSharedTodoService(mockRepo, mockTodoRepo, mockUserRepo)
+    service := shared_todos.NewSharedTodoService(mockRepo, mockTodoRepo, mockUserRepo, new(mocks.MockTeamMemberRepository))
     
     // Setup test data
     todoID := "todo-123"
@@ -154,12 +157,13 @@ func TestShareTodo(t *testing.T) {
     
     mockRepo.On("IsSharedWithUser", context.Background(), todoID, recipientID).Return(false, nil)
     mockRepo.On("IsSharedWithUser", context.Background(), "already-shared", recipientID).Return(true, nil)
+    mockRepo.On("IsSenderBlocked", context.Background(), recipientID, ownerID).Return(false, nil)
     
-    mockRepo.On("ShareTodo", context.Background(), todoID, recipientID, ownerID).Return(nil)
+    mockRepo.On("ShareTodo", context.Background(), todoID, recipientID, ownerID, domain.SharePermissionView).Return(nil)
     
     // Scenario 1: Successful share
     fmt.Println("Scenario 1: Testing successful todo sharing")
-    err := service.ShareTodo(context.Background(), todoID, recipientID, ownerID)
+    err := service.ShareTodo(context.Background(), todoID, recipientID, ownerID, "")
     
     // Assertions
     assert.NoError(t, err)
@@ -167,7 +171,7 @@ func TestShareTodo(t *testing.T) {
     
     // Scenario 2: Todo not found
     fmt.Println("\nScenario 2: Testing sharing non-existent todo")
-    err = service.ShareTodo(context.Background(), "nonexistent-todo", recipientID, ownerID)
+    err = service.ShareTodo(context.Background(), "nonexistent-todo", recipientID, ownerID, "")
     
     // Assertions
     assert.Error(t, err)
@@ -176,7 +180,7 @@ func TestShareTodo(t *testing.T) {
     
     // Scenario 3: Not owner of todo
     fmt.Println("\nScenario 3: Testing sharing a todo that user doesn't own")
-    err = service.ShareTodo(context.Background(), "not-owned-todo", recipientID, ownerID)
+    err = service.ShareTodo(context.Background(), "not-owned-todo", recipientID, ownerID, "")
     
     // Assertions
     assert.Error(t, err)
@@ -185,13 +189,22 @@ func TestShareTodo(t *testing.T) {
     
     // Scenario 4: Already shared todo
     fmt.Println("\nScenario 4: Testing sharing a todo that is already shared")
-    err = service.ShareTodo(context.Background(), "already-shared", recipientID, ownerID)
+    err = service.ShareTodo(context.Background(), "already-shared", recipientID, ownerID, "")
     
     // Assertions
     assert.Error(t, err)
     assert.Contains(t, err.Error(), "already shared")
     fmt.Printf("✅ Correctly received error: %v\n", err)
     
+    // Scenario 5: Unknown permission
+    fmt.Println("\nScenario 5: Testing sharing with an unknown permission")
+    err = service.ShareTodo(context.Background(), todoID, recipientID, ownerID, "admin")
+    
+    // Assertions
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid permission")
+    fmt.Printf("✅ Correctly received error: %v\n", err)
+    
     // Verify all expected methods were called
     mockRepo.AssertExpectations(t)
     mockTodoRepo.AssertExpectations(t)
@@ -209,7 +222,7 @@ func TestGetSharedByMeTodos(t *testing.T) {
     mockUserRepo := new(mocks.MockUserRepository)
     
     // Create the service with the mock repositories
-    service := shared_todos.NewSharedTodoService(mockRepo, mockTodoRepo, mockUserRepo)
+    service := shared_todos.NewSharedTodoService(mockRepo, mockTodoRepo, mockUserRepo, new(mocks.MockTeamMemberRepository))
     
     // Setup test data
     userID := "user-123"
@@ -284,4 +297,214 @@ func TestGetSharedByMeTodos(t *testing.T) {
     // Verify all expected methods were called
     mockRepo.AssertExpectations(t)
     fmt.Println("✅ All GetSharedByMeTodos test scenarios passed")
+}
+func TestSharedTodoLinks(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestSharedTodoLinks ===")
+    fmt.Println("Testing shared todos linked to the sharer's todo")
+    
+    ctx := context.Background()
+    mockRepo := new(mocks.MockSharedTodoRepository)
+    service := shared_todos.NewSharedTodoService(mockRepo, new(mocks.MockTodoRepository), new(mocks.MockUserRepository), new(mocks.MockTeamMemberRepository))
+    
+    // Scenario 1: Received shares expose the linked todo and its version
+    fmt.Println("Scenario 1: Testing the link in the shared list")
+    mockRepo.On("GetSharedTodos", ctx, "recipient").Return([]domain.SharedTodo{
+        {ID: "share-1", Task: "Plan trip", Done: true, UserID: "recipient", SharedBy: "owner", TodoID: "todo-1", Version: 3, Status: domain.ShareStatusAccepted},
+    }, nil)
+    res, err := service.GetSharedTodos(ctx, "recipient")
+    assert.NoError(t, err)
+    assert.Equal(t, "todo-1", res.Received[0].TodoID)
+    assert.Equal(t, 3, res.Received[0].Version)
+    assert.True(t, res.Received[0].Done)
+    fmt.Println("✅ Shared todo shows the original's state")
+    
+    // Scenario 2: Only linked shares of the recipient can be edited
+    fmt.Println("\nScenario 2: Testing edit access to shared todos")
+    mockRepo.On("GetSharedTodoByID", ctx, "share-1", "recipient").
+        Return(&domain.SharedTodo{ID: "share-1", UserID: "recipient", SharedBy: "owner", TodoID: "todo-1", Permission: domain.SharePermissionEdit, Status: domain.ShareStatusAccepted}, nil)
+    mockRepo.On("GetSharedTodoByID", ctx, "share-old", "recipient").
+        Return(&domain.SharedTodo{ID: "share-old", UserID: "recipient", SharedBy: "owner", Permission: domain.SharePermissionEdit, Status: domain.ShareStatusAccepted}, nil)
+    mockRepo.On("GetSharedTodoByID", ctx, "share-1", "intruder").Return(nil, errors.New("shared todo not found"))
+    linked, err := service.LinkedTodo(ctx, "share-1", "recipient")
+    assert.NoError(t, err)
+    assert.Equal(t, "todo-1", linked.TodoID)
+    assert.Equal(t, "owner", linked.SharedBy)
+    _, err = service.LinkedTodo(ctx, "share-old", "recipient")
+    assert.Contains(t, err.Error(), "not linked")
+    _, err = service.LinkedTodo(ctx, "share-1", "intruder")
+    assert.Contains(t, err.Error(), "shared todo not found")
+    fmt.Println("✅ Linked share resolved to the sharer's todo")
+}
+
+func TestSharePermissions(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestSharePermissions ===")
+    fmt.Println("Testing share permissions and revoking shares")
+    
+    ctx := context.Background()
+    mockRepo := new(mocks.MockSharedTodoRepository)
+    service := shared_todos.NewSharedTodoService(mockRepo, new(mocks.MockTodoRepository), new(mocks.MockUserRepository), new(mocks.MockTeamMemberRepository))
+    
+    // Scenario 1: View-only recipients cannot edit
+    fmt.Println("Scenario 1: Testing edits on a view-only share")
+    mockRepo.On("GetSharedTodoByID", ctx, "share-1", "recipient").
+        Return(&domain.SharedTodo{ID: "share-1", UserID: "recipient", SharedBy: "owner", TodoID: "todo-1", Permission: domain.SharePermissionView, Status: domain.ShareStatusAccepted}, nil)
+    _, err := service.LinkedTodo(ctx, "share-1", "recipient")
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "edit permission required")
+    fmt.Println("✅ View-only share refused")
+    
+    // Scenario 2: The owner changes the permission
+    fmt.Println("\nScenario 2: Testing permission updates")
+    mockRepo.On("UpdateSharePermission", ctx, "share-1", "owner", domain.SharePermissionEdit).Return(true, nil)
+    mockRepo.On("UpdateSharePermission", ctx, "share-1", "recipient", domain.SharePermissionEdit).Return(false, nil)
+    res, err := service.UpdateSharePermission(ctx, "share-1", "owner", domain.SharePermissionEdit)
+    assert.NoError(t, err)
+    assert.True(t, res.Success)
+    _, err = service.UpdateSharePermission(ctx, "share-1", "recipient", domain.SharePermissionEdit)
+    assert.Contains(t, err.Error(), "shared todo not found")
+    _, err = service.UpdateSharePermission(ctx, "share-1", "owner", "owner")
+    assert.Contains(t, err.Error(), "invalid permission")
+    fmt.Println("✅ Only the owner can change a share's permission")
+    
+    // Scenario 3: The owner revokes the share
+    fmt.Println("\nScenario 3: Testing revoking shares")
+    mockRepo.On("DeleteSharedTodo", ctx, "share-1", "owner").Return(true, nil)
+    mockRepo.On("DeleteSharedTodo", ctx, "missing", "owner").Return(false, nil)
+    res, err = service.RevokeShare(ctx, "share-1", "owner")
+    assert.NoError(t, err)
+    assert.True(t, res.Success)
+    _, err = service.RevokeShare(ctx, "missing", "owner")
+    assert.Contains(t, err.Error(), "shared todo not found")
+    fmt.Println("✅ Share revoked")
+    
+    mockRepo.AssertExpectations(t)
+}
+
+func TestShareInbox(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestShareInbox ===")
+    fmt.Println("Testing accepting, declining and blocking shares")
+    
+    ctx := context.Background()
+    mockRepo := new(mocks.MockSharedTodoRepository)
+    mockTodoRepo := new(mocks.MockTodoRepository)
+    service := shared_todos.NewSharedTodoService(mockRepo, mockTodoRepo, new(mocks.MockUserRepository), new(mocks.MockTeamMemberRepository))
+    
+    // Scenario 1: Pending shares are listed apart from accepted ones and cannot be edited
+    fmt.Println("Scenario 1: Testing the inbox split")
+    mockRepo.On("GetSharedTodos", ctx, "recipient").Return([]domain.SharedTodo{
+        {ID: "share-1", Task: "Plan trip", UserID: "recipient", SharedBy: "owner", Status: domain.ShareStatusPending},
+        {ID: "share-2", Task: "Buy milk", UserID: "recipient", SharedBy: "owner", Status: domain.ShareStatusAccepted},
+    }, nil)
+    res, err := service.GetSharedTodos(ctx, "recipient")
+    assert.NoError(t, err)
+    assert.Len(t, res.Pending, 1)
+    assert.Equal(t, "share-1", res.Pending[0].ID)
+    assert.Len(t, res.Received, 1)
+    assert.Equal(t, "share-2", res.Received[0].ID)
+    mockRepo.On("GetSharedTodoByID", ctx, "share-1", "recipient").
+        Return(&domain.SharedTodo{ID: "share-1", UserID: "recipient", SharedBy: "owner", TodoID: "todo-1", Permission: domain.SharePermissionEdit, Status: domain.ShareStatusPending}, nil)
+    _, err = service.LinkedTodo(ctx, "share-1", "recipient")
+    assert.Contains(t, err.Error(), "not been accepted")
+    fmt.Println("✅ Pending shares kept in the inbox")
+    
+    // Scenario 2: Accepting and declining
+    fmt.Println("\nScenario 2: Testing accept and decline")
+    mockRepo.On("AcceptSharedTodo", ctx, "share-1", "recipient").Return(true, nil)
+    mockRepo.On("AcceptSharedTodo", ctx, "share-2", "recipient").Return(false, nil)
+    mockRepo.On("DeclineSharedTodo", ctx, "share-3", "recipient").Return(true, nil)
+    mockRepo.On("DeclineSharedTodo", ctx, "share-1", "intruder").Return(false, nil)
+    _, err = service.AcceptShare(ctx, "share-1", "recipient")
+    assert.NoError(t, err)
+    _, err = service.AcceptShare(ctx, "share-2", "recipient")
+    assert.Contains(t, err.Error(), "pending share not found")
+    _, err = service.DeclineShare(ctx, "share-3", "recipient")
+    assert.NoError(t, err)
+    _, err = service.DeclineShare(ctx, "share-1", "intruder")
+    assert.Contains(t, err.Error(), "shared todo not found")
+    fmt.Println("✅ Shares accepted and declined")
+    
+    // Scenario 3: Blocking the sender stops further shares
+    fmt.Println("\nScenario 3: Testing blocking a sender")
+    mockRepo.On("BlockSender", ctx, "recipient", "owner").Return(nil)
+    _, err = service.BlockSender(ctx, "share-1", "recipient")
+    assert.NoError(t, err)
+    mockTodoRepo.On("GetTodoByID", ctx, "todo-1").Return(&domain.Todo{ID: "todo-1", UserID: "owner"}, nil)
+    mockRepo.On("IsSharedWithUser", ctx, "todo-1", "recipient").Return(false, nil)
+    mockRepo.On("IsSenderBlocked", ctx, "recipient", "owner").Return(true, nil)
+    err = service.ShareTodo(ctx, "todo-1", "recipient", "owner", "")
+    assert.Contains(t, err.Error(), "does not accept shares")
+    mockRepo.On("GetBlockedSenders", ctx, "recipient").Return([]domain.ShareBlock{{UserID: "owner", Username: "olivia"}}, nil)
+    blocks, err := service.GetBlockedSenders(ctx, "recipient")
+    assert.NoError(t, err)
+    assert.Equal(t, "olivia", blocks.Blocked[0].Username)
+    mockRepo.On("UnblockSender", ctx, "recipient", "owner").Return(true, nil)
+    _, err = service.UnblockSender(ctx, "recipient", "owner")
+    assert.NoError(t, err)
+    fmt.Println("✅ Blocked sender cannot share")
+    
+    mockRepo.AssertExpectations(t)
+}
+func TestShareTodoWithMany(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestShareTodoWithMany ===")
+    fmt.Println("Testing sharing a todo with several users and a team")
+    
+    ctx := context.Background()
+    mockRepo := new(mocks.MockSharedTodoRepository)
+    mockTodoRepo := new(mocks.MockTodoRepository)
+    mockUserRepo := new(mocks.MockUserRepository)
+    mockMemberRepo := new(mocks.MockTeamMemberRepository)
+    service := shared_todos.NewSharedTodoService(mockRepo, mockTodoRepo, mockUserRepo, mockMemberRepo)
+    
+    mockTodoRepo.On("GetTodoByID", ctx, "todo-1").Return(&domain.Todo{ID: "todo-1", UserID: "owner"}, nil)
+    mockUserRepo.On("GetUserByUsername", ctx, "alice").Return(domain.User{ID: "u-alice", Username: "alice"}, nil)
+    mockUserRepo.On("GetUserByUsername", ctx, "bob").Return(domain.User{ID: "u-bob", Username: "bob"}, nil)
+    mockUserRepo.On("GetUserByUsername", ctx, "olivia").Return(domain.User{ID: "owner", Username: "olivia"}, nil)
+    mockUserRepo.On("GetUserByUsername", ctx, "ghost").Return(domain.User{}, errors.New("sql: no rows in result set"))
+    
+    // Scenario 1: Requests need recipients and the sharer must own the todo
+    fmt.Println("Scenario 1: Testing request validation")
+    _, err := service.ShareTodoWithMany(ctx, &dto.ShareTodoRequest{TodoID: "todo-1", SharedBy: "owner"})
+    assert.Contains(t, err.Error(), "no recipients")
+    _, err = service.ShareTodoWithMany(ctx, &dto.ShareTodoRequest{TodoID: "todo-1", SharedBy: "intruder", Usernames: []string{"alice"}})
+    assert.Contains(t, err.Error(), "unauthorized")
+    fmt.Println("✅ Invalid requests rejected")
+    
+    // Scenario 2: Usernames and team members are shared with once, each with a result
+    fmt.Println("\nScenario 2: Testing per-recipient results")
+    mockMemberRepo.On("GetTeamMembers", ctx, "team-1").Return([]domain.TeamMember{
+        {TeamID: "team-1", UserID: "owner"},
+        {TeamID: "team-1", UserID: "u-alice"},
+        {TeamID: "team-1", UserID: "u-carol"},
+    }, nil)
+    mockRepo.On("ShareTodoWithUsers", ctx, "todo-1", []string{"u-alice", "u-bob", "u-carol"}, "owner", domain.SharePermissionEdit).
+        Return(map[string]string{
+            "u-alice": domain.ShareResultShared,
+            "u-bob":   domain.ShareResultAlreadyShared,
+            "u-carol": domain.ShareResultBlocked,
+        }, nil)
+    res, err := service.ShareTodoWithMany(ctx, &dto.ShareTodoRequest{
+        TodoID:     "todo-1",
+        SharedBy:   "owner",
+        Usernames:  []string{"alice", "bob", "alice", "ghost", "olivia"},
+        TeamID:     "team-1",
+        Permission: domain.SharePermissionEdit,
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, []dto.ShareResultResponse{
+        {Username: "alice", UserID: "u-alice", Result: domain.ShareResultShared},
+        {Username: "bob", UserID: "u-bob", Result: domain.ShareResultAlreadyShared},
+        {Username: "ghost", Result: domain.ShareResultNotFound},
+        {Username: "olivia", UserID: "owner", Result: domain.ShareResultSelf},
+        {UserID: "u-carol", Result: domain.ShareResultBlocked},
+    }, res.Results)
+    fmt.Println("✅ Every recipient reported once")
+    
+    // Scenario 3: Only members can share with a team
+    fmt.Println("\nScenario 3: Testing sharing with a team the user is not in")
+    mockMemberRepo.On("GetTeamMembers", ctx, "team-2").Return([]domain.TeamMember{{TeamID: "team-2", UserID: "u-alice"}}, nil)
+    _, err = service.ShareTodoWithMany(ctx, &dto.ShareTodoRequest{TodoID: "todo-1", SharedBy: "owner", TeamID: "team-2"})
+    assert.Contains(t, err.Error(), "not a member")
+    fmt.Println("✅ Non-members refused")
+    
+    mockRepo.AssertExpectations(t)
 }
\ No newline at end of file
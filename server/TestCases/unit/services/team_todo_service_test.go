@@ -0,0 +1,389 @@
+package services_test
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/team_todos"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestTeamTodoAssignments(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestTeamTodoAssignments ===")
+    fmt.Println("Testing team todo assignee validation, reassignment and history")
+
+    ctx := context.Background()
+    teamID := "team-1"
+
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    teamMemberRepo := new(mocks.MockTeamMemberRepository)
+    teamTodoService := team_todos.NewTeamTodoService(teamTodoRepo, teamMemberRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), nil, teamTodoRepo))
+
+    teamMemberRepo.On("GetTeamMembers", ctx, teamID).Return([]domain.TeamMember{
+        {TeamID: teamID, UserID: "user-1", IsAdmin: true},
+        {TeamID: teamID, UserID: "user-2"},
+    }, nil)
+
+    // Scenario 1: Non-members cannot be assigned or assign
+    fmt.Println("Scenario 1: Testing assignment to and by a non-member")
+    _, err := teamTodoService.CreateTeamTodo(ctx, &dto.CreateTeamTodoRequest{Task: "Deploy", TeamID: teamID, AssignedTo: "outsider", CreatedBy: "user-1"})
+    assert.Contains(t, err.Error(), "is not a member of this team")
+    _, err = teamTodoService.UpdateTeamTodo(ctx, &dto.UpdateTeamTodoRequest{ID: "tt-1", Task: "Deploy", TeamID: teamID, AssignedTo: "outsider", UpdatedBy: "user-1"})
+    assert.Contains(t, err.Error(), "is not a member of this team")
+    _, err = teamTodoService.PatchTeamTodo(ctx, &dto.PatchTeamTodoRequest{ID: "tt-1", TeamID: teamID, UserID: "user-1", Fields: map[string]json.RawMessage{"assigned_to": json.RawMessage(`"outsider"`)}})
+    assert.Contains(t, err.Error(), "is not a member of this team")
+    _, err = teamTodoService.ExecuteBatch(ctx, &dto.BatchRequest{TeamID: teamID, UserID: "user-1", Operations: []dto.BatchOperationRequest{
+        {Op: domain.BatchOpCreate, Task: "Ok", AssignedTo: "user-2"},
+        {Op: domain.BatchOpCreate, Task: "Not ok", AssignedTo: "outsider"},
+    }})
+    assert.Contains(t, err.Error(), "operation 1: assignee \"outsider\" is not a member of this team")
+    teamTodoRepo.AssertNotCalled(t, "CreateTeamTodo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    teamTodoRepo.AssertNotCalled(t, "ExecuteBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+    // Non-members cannot assign members either
+    _, err = teamTodoService.UpdateTeamTodo(ctx, &dto.UpdateTeamTodoRequest{ID: "tt-1", Task: "Deploy", TeamID: teamID, AssignedTo: "user-2", UpdatedBy: "outsider"})
+    assert.Contains(t, err.Error(), "only team members can assign todos")
+    _, err = teamTodoService.PatchTeamTodo(ctx, &dto.PatchTeamTodoRequest{ID: "tt-1", TeamID: teamID, UserID: "outsider", Fields: map[string]json.RawMessage{"assigned_to": json.RawMessage(`"user-2"`)}})
+    assert.Contains(t, err.Error(), "only team members can assign todos")
+    _, err = teamTodoService.ExecuteBatch(ctx, &dto.BatchRequest{TeamID: teamID, UserID: "outsider", Operations: []dto.BatchOperationRequest{
+        {Op: domain.BatchOpCreate, Task: "Deploy", AssignedTo: "user-2"},
+    }})
+    assert.Contains(t, err.Error(), "only team members can assign todos")
+    teamTodoRepo.AssertNotCalled(t, "UpdateTeamTodo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    fmt.Println("✅ Non-member assignees and assigners rejected on create, update, patch and batch")
+
+    // Scenario 2: Members and unassigned todos are accepted
+    fmt.Println("\nScenario 2: Testing valid assignees")
+    teamTodoRepo.On("CreateTeamTodo", ctx, "Deploy", "", false, false, teamID, "user-2", "user-1", mock.Anything, mock.Anything).Return("tt-1", nil).Once()
+    res, err := teamTodoService.CreateTeamTodo(ctx, &dto.CreateTeamTodoRequest{Task: "Deploy", TeamID: teamID, AssignedTo: "user-2", CreatedBy: "user-1"})
+    assert.NoError(t, err)
+    assert.Equal(t, "tt-1", res.ID)
+    teamTodoRepo.On("CreateTeamTodo", ctx, "Unowned", "", false, false, teamID, "", "user-1", mock.Anything, mock.Anything).Return("tt-2", nil).Once()
+    _, err = teamTodoService.CreateTeamTodo(ctx, &dto.CreateTeamTodoRequest{Task: "Unowned", TeamID: teamID, CreatedBy: "user-1"})
+    assert.NoError(t, err)
+    fmt.Println("✅ Members and empty assignees accepted")
+
+    // Scenario 3: Only team members can reassign
+    fmt.Println("\nScenario 3: Testing reassignment by a non-member")
+    _, err = teamTodoService.ReassignTeamTodo(ctx, &dto.ReassignTeamTodoRequest{ID: "tt-1", TeamID: teamID, AssignedTo: "user-1", AssignedBy: "outsider"})
+    assert.Contains(t, err.Error(), "only team members can assign todos")
+    fmt.Println("✅ Non-member reassignment rejected")
+
+    // Scenario 4: Reassigning and unassigning return the updated todo
+    fmt.Println("\nScenario 4: Testing reassignment and unassignment")
+    teamTodoRepo.On("ReassignTeamTodo", ctx, "tt-1", teamID, "user-1", "user-2", 2).Return(true, nil).Once()
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "tt-1", teamID).Return(&domain.TeamTodo{ID: "tt-1", TeamID: teamID, AssignedTo: "user-1", Version: 3}, nil).Once()
+    todo, err := teamTodoService.ReassignTeamTodo(ctx, &dto.ReassignTeamTodoRequest{ID: "tt-1", TeamID: teamID, AssignedTo: "user-1", AssignedBy: "user-2", Version: 2})
+    assert.NoError(t, err)
+    assert.Equal(t, "user-1", todo.AssignedTo)
+    assert.Equal(t, 3, todo.Version)
+    teamTodoRepo.On("ReassignTeamTodo", ctx, "tt-1", teamID, "", "user-1", 0).Return(true, nil).Once()
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "tt-1", teamID).Return(&domain.TeamTodo{ID: "tt-1", TeamID: teamID, Version: 4}, nil).Once()
+    todo, err = teamTodoService.ReassignTeamTodo(ctx, &dto.ReassignTeamTodoRequest{ID: "tt-1", TeamID: teamID, AssignedBy: "user-1"})
+    assert.NoError(t, err)
+    assert.Empty(t, todo.AssignedTo)
+    fmt.Println("✅ Todo reassigned and unassigned")
+
+    // Scenario 5: History is listed for existing todos only
+    fmt.Println("\nScenario 5: Testing assignment history")
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "missing", teamID).Return(nil, fmt.Errorf("team todo not found")).Once()
+    _, err = teamTodoService.GetTeamTodoAssignments(ctx, "missing", teamID)
+    assert.Contains(t, err.Error(), "not found")
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "tt-1", teamID).Return(&domain.TeamTodo{ID: "tt-1", TeamID: teamID}, nil).Once()
+    teamTodoRepo.On("GetTeamTodoAssignments", ctx, "tt-1", teamID).Return([]domain.TeamTodoAssignment{
+        {ID: 1, AssignedTo: "user-2", AssignedToName: "bob", AssignedBy: "user-1", AssignedByName: "alice"},
+        {ID: 2, AssignedTo: "user-1", AssignedToName: "alice", PreviousAssignee: "user-2", PreviousAssigneeName: "bob", AssignedBy: "user-2"},
+        {ID: 3, PreviousAssignee: "user-1", AssignedBy: "user-1"},
+    }, nil)
+    history, err := teamTodoService.GetTeamTodoAssignments(ctx, "tt-1", teamID)
+    assert.NoError(t, err)
+    assert.Len(t, history.Assignments, 3)
+    assert.Equal(t, "bob", history.Assignments[1].PreviousAssigneeUsername)
+    assert.Empty(t, history.Assignments[2].AssignedTo)
+    fmt.Println("✅ Assignment history listed oldest first")
+
+    // Scenario 6: Todos assigned to the caller across teams
+    fmt.Println("\nScenario 6: Testing todos assigned to the caller")
+    teamTodoRepo.On("GetAssignedTeamTodos", ctx, "user-2").Return([]domain.AssignedTeamTodo{
+        {TeamTodo: domain.TeamTodo{ID: "tt-1", Task: "Deploy", TeamID: teamID, AssignedTo: "user-2"}, TeamName: "Core"},
+        {TeamTodo: domain.TeamTodo{ID: "tt-9", Task: "Rotate keys", TeamID: "team-2", AssignedTo: "user-2"}, TeamName: "Ops"},
+    }, nil)
+    assigned, err := teamTodoService.GetAssignedTeamTodos(ctx, "user-2", time.UTC)
+    assert.NoError(t, err)
+    assert.Len(t, assigned.Todos, 2)
+    assert.Equal(t, "Ops", assigned.Todos[1].TeamName)
+    assert.Equal(t, "team-2", assigned.Todos[1].TeamID)
+    fmt.Println("✅ Assigned todos listed with their team names")
+
+    teamTodoRepo.AssertExpectations(t)
+    fmt.Println("✅ All TestTeamTodoAssignments scenarios passed")
+}
+
+func TestSetTeamTodoAssignees(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestSetTeamTodoAssignees ===")
+    fmt.Println("Testing that a todo can be shared by several team members")
+
+    ctx := context.Background()
+    teamID := "team-1"
+
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    teamMemberRepo := new(mocks.MockTeamMemberRepository)
+    teamTodoService := team_todos.NewTeamTodoService(teamTodoRepo, teamMemberRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), nil, teamTodoRepo))
+    teamMemberRepo.On("GetTeamMembers", ctx, teamID).Return([]domain.TeamMember{
+        {TeamID: teamID, UserID: "user-1", IsAdmin: true},
+        {TeamID: teamID, UserID: "user-2"},
+        {TeamID: teamID, UserID: "user-3"},
+    }, nil)
+
+    // Scenario 1: Only members can be assigned, by members, with a known mode
+    fmt.Println("Scenario 1: Testing who can be assigned")
+    _, err := teamTodoService.SetTeamTodoAssignees(ctx, &dto.SetTeamTodoAssigneesRequest{ID: "tt-1", TeamID: teamID, Assignees: []string{"user-2"}, CompletionMode: "most", ChangedBy: "user-1"})
+    assert.ErrorContains(t, err, "invalid completion mode")
+    _, err = teamTodoService.SetTeamTodoAssignees(ctx, &dto.SetTeamTodoAssigneesRequest{ID: "tt-1", TeamID: teamID, Assignees: []string{"user-2", "outsider"}, ChangedBy: "user-1"})
+    assert.ErrorContains(t, err, "is not a member of this team")
+    _, err = teamTodoService.SetTeamTodoAssignees(ctx, &dto.SetTeamTodoAssigneesRequest{ID: "tt-1", TeamID: teamID, Assignees: []string{"user-2"}, ChangedBy: "outsider"})
+    assert.ErrorContains(t, err, "only team members can assign todos")
+    fmt.Println("✅ Outsiders and unknown modes rejected")
+
+    // Scenario 2: Repeated and empty IDs collapse into one assignee each
+    fmt.Println("\nScenario 2: Testing duplicate assignees")
+    teamTodoRepo.On("SetTeamTodoAssignees", ctx, "tt-1", teamID, []string{"user-2", "user-3"}, domain.CompletionModeAny, "user-1", 4).Return(true, nil)
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "tt-1", teamID).Return(&domain.TeamTodo{ID: "tt-1", TeamID: teamID, Version: 5}, nil)
+    _, err = teamTodoService.SetTeamTodoAssignees(ctx, &dto.SetTeamTodoAssigneesRequest{
+        ID: "tt-1", TeamID: teamID, Assignees: []string{"user-2", "", "user-3", "user-2"}, CompletionMode: domain.CompletionModeAny, ChangedBy: "user-1", Version: 4,
+    })
+    assert.NoError(t, err)
+    fmt.Println("✅ Each member assigned once")
+}
+
+func TestAssigneeDone(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestAssigneeDone ===")
+    fmt.Println("Testing that assignees tick off their own part of a team todo")
+
+    ctx := context.Background()
+    teamID := "team-1"
+
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    dependencyRepo := new(mocks.MockDependencyRepository)
+    teamTodoService := team_todos.NewTeamTodoService(teamTodoRepo, new(mocks.MockTeamMemberRepository), dependencies.NewDependencyService(dependencyRepo, nil, teamTodoRepo))
+
+    // "release" needs both bob and carol and is blocked by the open "review"
+    release := domain.TeamTodo{ID: "release", Task: "Release", TeamID: teamID, CompletionMode: domain.CompletionModeAll,
+        Assignees: []domain.TeamTodoAssignee{{UserID: "bob"}, {UserID: "carol", Done: true}}}
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "release", teamID).Return(&release, nil)
+    teamTodoRepo.On("GetTeamTodos", ctx, teamID).Return([]domain.TeamTodo{{ID: "review", Task: "Review", TeamID: teamID}, release}, nil)
+    dependencyRepo.On("GetTeamTodoDependencies", ctx, teamID).Return([]domain.Dependency{{TodoID: "release", BlockedByID: "review"}}, nil)
+
+    // Scenario 1: Nobody ticks off someone else's part
+    fmt.Println("Scenario 1: Testing another member's part")
+    _, err := teamTodoService.SetAssigneeDone(ctx, &dto.SetAssigneeDoneRequest{ID: "release", TeamID: teamID, UserID: "bob", CallerID: "carol", Done: true})
+    assert.ErrorContains(t, err, "only the assignee can update their part")
+    fmt.Println("✅ Only the assignee changes their part")
+
+    // Scenario 2: The last part would complete a blocked todo
+    fmt.Println("\nScenario 2: Testing the last part of a blocked todo")
+    _, err = teamTodoService.SetAssigneeDone(ctx, &dto.SetAssigneeDoneRequest{ID: "release", TeamID: teamID, UserID: "bob", CallerID: "bob", Done: true})
+    assert.ErrorContains(t, err, "blocked by open todos: review")
+    teamTodoRepo.AssertNotCalled(t, "SetAssigneeDone", ctx, "release", teamID, "bob", true)
+    fmt.Println("✅ Blocked todo not completed by its assignees")
+
+    // Scenario 3: Parts that leave the todo open are not held back by blockers
+    fmt.Println("\nScenario 3: Testing a part that does not complete the todo")
+    teamTodoRepo.On("SetAssigneeDone", ctx, "release", teamID, "carol", false).Return(true, nil)
+    _, err = teamTodoService.SetAssigneeDone(ctx, &dto.SetAssigneeDoneRequest{ID: "release", TeamID: teamID, UserID: "carol", CallerID: "carol", Done: false})
+    assert.NoError(t, err)
+    fmt.Println("✅ Reopening a part is always allowed")
+
+    // Scenario 4: Forcing completes the todo anyway
+    fmt.Println("\nScenario 4: Testing a forced completion")
+    teamTodoRepo.On("SetAssigneeDone", ctx, "release", teamID, "bob", true).Return(true, nil)
+    _, err = teamTodoService.SetAssigneeDone(ctx, &dto.SetAssigneeDoneRequest{ID: "release", TeamID: teamID, UserID: "bob", CallerID: "bob", Done: true, Force: true})
+    assert.NoError(t, err)
+    fmt.Println("✅ Forced part completion accepted")
+}
+
+func TestCompletionModes(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestCompletionModes ===")
+    fmt.Println("Testing how the assignees' parts combine into the todo's done flag")
+
+    partly := []domain.TeamTodoAssignee{{UserID: "user-2", Done: true}, {UserID: "user-3"}}
+    finished := []domain.TeamTodoAssignee{{UserID: "user-2", Done: true}, {UserID: "user-3", Done: true}}
+
+    assert.False(t, domain.AssigneesDone(domain.CompletionModeAll, partly))
+    assert.True(t, domain.AssigneesDone(domain.CompletionModeAll, finished))
+    fmt.Println("✅ All mode waits for every assignee")
+
+    assert.True(t, domain.AssigneesDone(domain.CompletionModeAny, partly))
+    assert.False(t, domain.AssigneesDone(domain.CompletionModeAny, []domain.TeamTodoAssignee{{UserID: "user-2"}}))
+    fmt.Println("✅ Any mode completes with the first assignee")
+
+    assert.False(t, domain.AssigneesDone(domain.CompletionModeAll, nil))
+    fmt.Println("✅ Unassigned todos are never completed by their parts")
+}
+
+func TestBoardPlacementAfterCompletion(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestBoardPlacementAfterCompletion ===")
+    fmt.Println("Testing the board status of a team todo completed outside of a move")
+
+    statuses := []domain.TeamStatus{
+        {ID: "s-backlog", Name: "Backlog", Position: 0},
+        {ID: "s-review", Name: "Review", Position: 1},
+        {ID: "s-done", Name: "Done", Position: 2, Terminal: true},
+        {ID: "s-archived", Name: "Archived", Position: 3, Terminal: true},
+    }
+    placements := []domain.TeamTodoPlacement{
+        {TodoID: "release", StatusID: "s-review", Position: 0},
+        {TodoID: "kickoff", StatusID: "s-done", Position: 0},
+        {TodoID: "spec", StatusID: "s-done", Position: 1},
+    }
+
+    // Scenario 1: A todo completed by its assignees leaves Review for the end of Done
+    fmt.Println("Scenario 1: Testing auto-completion of a placed todo")
+    place, ok := domain.PlaceForDone(statuses, placements, "release", true)
+    assert.True(t, ok)
+    assert.Equal(t, "s-done", place.StatusID)
+    assert.Equal(t, 2, place.Position)
+    fmt.Println("✅ Completed todo placed last in the first terminal status")
+
+    // Scenario 2: Reopening a done todo moves it back to the first open status
+    fmt.Println("\nScenario 2: Testing a reopened todo")
+    place, ok = domain.PlaceForDone(statuses, placements, "spec", false)
+    assert.True(t, ok)
+    assert.Equal(t, "s-backlog", place.StatusID)
+    assert.Equal(t, 0, place.Position)
+    fmt.Println("✅ Reopened todo placed in Backlog")
+
+    // Scenario 3: Todos already in a matching status, or never placed, stay put
+    fmt.Println("\nScenario 3: Testing todos that need no new place")
+    _, ok = domain.PlaceForDone(statuses, placements, "kickoff", true)
+    assert.False(t, ok)
+    _, ok = domain.PlaceForDone(statuses, placements, "unplaced", true)
+    assert.False(t, ok)
+    fmt.Println("✅ Matching and unplaced todos left alone")
+}
+
+func TestTeamTodosByAssignee(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestTeamTodosByAssignee ===")
+    fmt.Println("Testing the assignee filter of a team's todos")
+
+    ctx := context.Background()
+    teamID := "team-1"
+
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    teamTodoService := team_todos.NewTeamTodoService(teamTodoRepo, new(mocks.MockTeamMemberRepository), dependencies.NewDependencyService(new(mocks.MockDependencyRepository), nil, teamTodoRepo))
+    teamTodoRepo.On("GetTeamTodos", ctx, teamID).Return([]domain.TeamTodo{
+        {ID: "tt-1", TeamID: teamID, AssignedTo: "user-2", Assignees: []domain.TeamTodoAssignee{{UserID: "user-2"}, {UserID: "user-3"}}},
+        {ID: "tt-2", TeamID: teamID, AssignedTo: "user-1", Assignees: []domain.TeamTodoAssignee{{UserID: "user-1"}}},
+        {ID: "tt-3", TeamID: teamID},
+    }, nil)
+
+    // Scenario 1: A secondary assignee finds the todos they share
+    fmt.Println("Scenario 1: Testing a secondary assignee")
+    list, err := teamTodoService.GetTeamTodosByAssignee(ctx, teamID, "user-3", nil)
+    assert.NoError(t, err)
+    assert.Len(t, list.Todos, 1)
+    assert.Equal(t, "tt-1", list.Todos[0].ID)
+    fmt.Println("✅ Shared todo found by its second assignee")
+
+    // Scenario 2: "none" lists the unassigned todos
+    fmt.Println("\nScenario 2: Testing unassigned todos")
+    list, err = teamTodoService.GetTeamTodosByAssignee(ctx, teamID, "none", nil)
+    assert.NoError(t, err)
+    assert.Len(t, list.Todos, 1)
+    assert.Equal(t, "tt-3", list.Todos[0].ID)
+    fmt.Println("✅ Unassigned todo listed")
+}
+
+func TestTeamTodoDueDates(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestTeamTodoDueDates ===")
+    fmt.Println("Testing team todo due dates, overdue detection and upcoming deadlines")
+
+    ctx := context.Background()
+    teamID := "team-1"
+
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    teamMemberRepo := new(mocks.MockTeamMemberRepository)
+    teamTodoService := team_todos.NewTeamTodoService(teamTodoRepo, teamMemberRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), nil, teamTodoRepo))
+
+    teamMemberRepo.On("GetTeamMembers", ctx, teamID).Return([]domain.TeamMember{{TeamID: teamID, UserID: "user-1", IsAdmin: true}}, nil)
+
+    // Scenario 1: The requested date and time are stored
+    fmt.Println("Scenario 1: Testing due date on create")
+    due := time.Date(2030, 5, 17, 0, 0, 0, 0, time.UTC)
+    dueTime := time.Date(2000, 1, 1, 14, 30, 0, 0, time.UTC)
+    teamTodoRepo.On("CreateTeamTodo", ctx, "Release", "", false, false, teamID, "", "user-1", due, dueTime).Return("tt-1", nil).Once()
+    _, err := teamTodoService.CreateTeamTodo(ctx, &dto.CreateTeamTodoRequest{Task: "Release", TeamID: teamID, DateString: "2030-05-17", TimeString: "14:30:00", CreatedBy: "user-1"})
+    assert.NoError(t, err)
+    _, err = teamTodoService.CreateTeamTodo(ctx, &dto.CreateTeamTodoRequest{Task: "Release", TeamID: teamID, DateString: "17/05/2030", CreatedBy: "user-1"})
+    assert.Contains(t, err.Error(), "invalid date format")
+    fmt.Println("✅ Due date honoured and invalid dates rejected")
+
+    // Scenario 2: Updates keep, clear or change the due date and time
+    fmt.Println("\nScenario 2: Testing due date on update")
+    date, empty := "2030-06-01", ""
+    newDate := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+    teamTodoRepo.On("UpdateTeamTodo", ctx, "tt-1", "Release", "", false, false, teamID, "", "user-1", domain.TeamTodoDue{}, 2).Return(true, nil).Once()
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "tt-1", teamID).Return(&domain.TeamTodo{ID: "tt-1", Task: "Release", TeamID: teamID, Version: 3}, nil).Once()
+    updated, err := teamTodoService.UpdateTeamTodo(ctx, &dto.UpdateTeamTodoRequest{ID: "tt-1", Task: "Release", TeamID: teamID, UpdatedBy: "user-1", Version: 2})
+    assert.NoError(t, err)
+    assert.Equal(t, 3, updated.Version)
+    teamTodoRepo.On("UpdateTeamTodo", ctx, "tt-1", "Release", "", false, false, teamID, "", "user-1", domain.TeamTodoDue{Date: &newDate, ClearTime: true}, 3).Return(true, nil).Once()
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "tt-1", teamID).Return(&domain.TeamTodo{ID: "tt-1", Task: "Release", TeamID: teamID, Date: newDate, Version: 4}, nil).Once()
+    _, err = teamTodoService.UpdateTeamTodo(ctx, &dto.UpdateTeamTodoRequest{ID: "tt-1", Task: "Release", TeamID: teamID, UpdatedBy: "user-1", DateString: &date, TimeString: &empty, Version: 3})
+    assert.NoError(t, err)
+    bad := "25:00"
+    _, err = teamTodoService.UpdateTeamTodo(ctx, &dto.UpdateTeamTodoRequest{ID: "tt-1", Task: "Release", TeamID: teamID, UpdatedBy: "user-1", TimeString: &bad, Version: 4})
+    assert.Contains(t, err.Error(), "invalid time format")
+    fmt.Println("✅ Omitted fields kept, empty ones cleared and new ones set")
+
+    // Scenario 3: Open todos past their due date and time are overdue
+    fmt.Println("\nScenario 3: Testing overdue detection")
+    now := time.Date(2030, 5, 17, 12, 0, 0, 0, time.UTC)
+    assert.True(t, domain.TeamTodo{Date: due, Time: time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC)}.Overdue(now))
+    assert.False(t, domain.TeamTodo{Date: due, Time: dueTime}.Overdue(now))
+    assert.False(t, domain.TeamTodo{Date: due}.Overdue(now))
+    assert.True(t, domain.TeamTodo{Date: due.AddDate(0, 0, -1)}.Overdue(now))
+    assert.False(t, domain.TeamTodo{Date: due.AddDate(0, 0, -1), Done: true}.Overdue(now))
+    assert.False(t, domain.TeamTodo{}.Overdue(now))
+    fmt.Println("✅ Overdue todos detected by date and time")
+
+    // Scenario 4: Deadlines split into overdue and upcoming, soonest first
+    fmt.Println("\nScenario 4: Testing upcoming deadlines")
+    clock := time.Now().UTC()
+    today := time.Date(clock.Year(), clock.Month(), clock.Day(), 0, 0, 0, 0, time.UTC)
+    teamTodoRepo.On("GetTeamTodos", ctx, teamID).Return([]domain.TeamTodo{
+        {ID: "later", Task: "Later", Date: today.AddDate(0, 0, 3)},
+        {ID: "far", Task: "Far", Date: today.AddDate(0, 0, 30)},
+        {ID: "late", Task: "Late", Date: today.AddDate(0, 0, -2)},
+        {ID: "done", Task: "Done", Date: today.AddDate(0, 0, -1), Done: true},
+        {ID: "soon", Task: "Soon", Date: today.AddDate(0, 0, 1)},
+        {ID: "undated", Task: "Undated"},
+    }, nil)
+    res, err := teamTodoService.GetTeamDeadlines(ctx, teamID, 7, time.UTC)
+    assert.NoError(t, err)
+    assert.Len(t, res.Overdue, 1)
+    assert.Equal(t, "late", res.Overdue[0].ID)
+    assert.True(t, res.Overdue[0].Overdue)
+    assert.Len(t, res.Upcoming, 2)
+    assert.Equal(t, "soon", res.Upcoming[0].ID)
+    assert.Equal(t, "later", res.Upcoming[1].ID)
+    assert.Equal(t, today.AddDate(0, 0, 7), res.Until)
+    _, err = teamTodoService.GetTeamDeadlines(ctx, teamID, 0, time.UTC)
+    assert.Contains(t, err.Error(), "days must be between 1 and 90")
+    list, err := teamTodoService.GetTeamTodos(ctx, teamID, time.UTC)
+    assert.NoError(t, err)
+    assert.True(t, list.Todos[2].Overdue)
+    assert.False(t, list.Todos[3].Overdue)
+    fmt.Println("✅ Overdue and upcoming deadlines listed")
+
+    teamTodoRepo.AssertExpectations(t)
+    fmt.Println("✅ All TestTeamTodoDueDates scenarios passed")
+}
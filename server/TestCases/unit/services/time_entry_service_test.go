@@ -0,0 +1,112 @@
+package services_test
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/time_entries"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestTimeEntryService(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestTimeEntryService ===")
+    fmt.Println("Testing timers, logged time and reports")
+
+    ctx := context.Background()
+    userID := "user-123"
+    teamID := "team-1"
+
+    repo := new(mocks.MockTimeEntryRepository)
+    todoRepo := new(mocks.MockTodoRepository)
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    timeEntryService := time_entries.NewTimeEntryService(repo, todoRepo, teamTodoRepo)
+
+    todoRepo.On("GetTodoByID", ctx, "todo-1").Return(&domain.Todo{ID: "todo-1", Task: "Write report", UserID: userID}, nil)
+    todoRepo.On("GetTodoByID", ctx, "other-todo").Return(&domain.Todo{ID: "other-todo", UserID: "someone-else"}, nil)
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "team-todo-1", teamID).Return(&domain.TeamTodo{ID: "team-todo-1", Task: "Review", TeamID: teamID}, nil)
+
+    // Scenario 1: A second timer is refused while one is running
+    fmt.Println("Scenario 1: Testing one running timer per user")
+    repo.On("CreateTimeEntry", ctx, mock.MatchedBy(func(e domain.TimeEntry) bool { return e.TeamID == teamID })).
+        Return(errors.New("timer already running")).Once()
+    _, err := timeEntryService.StartTimer(ctx, &dto.StartTimerRequest{TodoID: "team-todo-1", TeamID: teamID, UserID: userID})
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "timer already running")
+    _, err = timeEntryService.StartTimer(ctx, &dto.StartTimerRequest{TodoID: "other-todo", UserID: userID})
+    assert.Contains(t, err.Error(), "todo not found")
+    fmt.Println("✅ Second timer and foreign todos rejected")
+
+    // Scenario 2: Stopping returns the finished entry with its duration
+    fmt.Println("\nScenario 2: Testing stopping a timer")
+    started := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+    repo.On("StopTimer", ctx, userID, mock.AnythingOfType("time.Time")).
+        Return(&domain.TimeEntry{ID: "e-1", UserID: userID, TodoID: "todo-1", StartedAt: started, EndedAt: started.Add(25 * time.Minute)}, nil)
+    stopped, err := timeEntryService.StopTimer(ctx, userID)
+    assert.NoError(t, err)
+    assert.False(t, stopped.Running)
+    assert.Equal(t, int64(25*60), stopped.Seconds)
+    fmt.Println("✅ Timer stopped")
+
+    // Scenario 3: Logged entries need a valid end
+    fmt.Println("\nScenario 3: Testing manual entries")
+    _, err = timeEntryService.CreateTimeEntry(ctx, &dto.CreateTimeEntryRequest{TodoID: "todo-1", UserID: userID, StartedAt: started})
+    assert.Contains(t, err.Error(), "invalid time entry")
+    before := started.Add(-time.Hour)
+    _, err = timeEntryService.CreateTimeEntry(ctx, &dto.CreateTimeEntryRequest{TodoID: "todo-1", UserID: userID, StartedAt: started, EndedAt: &before})
+    assert.Contains(t, err.Error(), "ended_at must be after started_at")
+    repo.On("CreateTimeEntry", ctx, mock.MatchedBy(func(e domain.TimeEntry) bool { return e.TodoID == "todo-1" })).Return(nil).Once()
+    logged, err := timeEntryService.CreateTimeEntry(ctx, &dto.CreateTimeEntryRequest{TodoID: "todo-1", UserID: userID, StartedAt: started, Minutes: 90})
+    assert.NoError(t, err)
+    assert.Equal(t, int64(90*60), logged.Seconds)
+    assert.Equal(t, started.Add(90*time.Minute), *logged.EndedAt)
+    fmt.Println("✅ Manual entries validated and stored")
+
+    // Scenario 4: Per-todo totals add up every entry
+    fmt.Println("\nScenario 4: Testing team todo totals")
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "missing", teamID).Return(nil, errors.New("team todo not found"))
+    _, err = timeEntryService.GetTeamTodoTimeEntries(ctx, teamID, "missing")
+    assert.Contains(t, err.Error(), "team todo not found")
+    repo.On("GetTeamTodoTimeEntries", ctx, teamID, "team-todo-1").Return([]domain.TimeEntry{
+        {ID: "e-2", UserID: "alice", TodoID: "team-todo-1", TeamID: teamID, StartedAt: started, EndedAt: started.Add(time.Hour)},
+        {ID: "e-3", UserID: "bob", TodoID: "team-todo-1", TeamID: teamID, StartedAt: started, EndedAt: started.Add(30 * time.Minute)},
+    }, nil)
+    totals, err := timeEntryService.GetTeamTodoTimeEntries(ctx, teamID, "team-todo-1")
+    assert.NoError(t, err)
+    assert.Equal(t, int64(90*60), totals.TotalSeconds)
+    assert.Len(t, totals.Entries, 2)
+    fmt.Println("✅ Todo total computed")
+
+    // Scenario 5: Reports group by the requested fields
+    fmt.Println("\nScenario 5: Testing grouped reports")
+    from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+    to := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+    repo.On("GetTeamTimeEntries", ctx, teamID, from, to).Return([]domain.TimeEntry{
+        {UserID: "u-alice", Username: "alice", TodoID: "t-1", Task: "Spec", StartedAt: started, EndedAt: started.Add(time.Hour)},
+        {UserID: "u-bob", Username: "bob", TodoID: "t-1", Task: "Spec", StartedAt: started, EndedAt: started.Add(30 * time.Minute)},
+        {UserID: "u-alice", Username: "alice", TodoID: "t-2", Task: "Build", StartedAt: started.Add(24 * time.Hour), EndedAt: started.Add(26 * time.Hour)},
+    }, nil)
+    report, err := timeEntryService.GetReport(ctx, &dto.TimeReportRequest{TeamID: teamID, From: from, To: to, GroupBy: []string{"user"}})
+    assert.NoError(t, err)
+    assert.Equal(t, int64(3*3600+30*60), report.TotalSeconds)
+    assert.Equal(t, []dto.TimeReportRow{
+        {UserID: "u-alice", Username: "alice", Seconds: 3 * 3600},
+        {UserID: "u-bob", Username: "bob", Seconds: 30 * 60},
+    }, report.Rows)
+    assert.Equal(t, "2024-05-02", report.To)
+    report, err = timeEntryService.GetReport(ctx, &dto.TimeReportRequest{TeamID: teamID, From: from, To: to, GroupBy: []string{"todo", "day"}})
+    assert.NoError(t, err)
+    assert.Equal(t, []string{"day", "todo"}, report.GroupBy)
+    assert.Len(t, report.Rows, 2)
+    assert.Equal(t, "2024-05-01", report.Rows[0].Day)
+    assert.Equal(t, int64(90*60), report.Rows[0].Seconds)
+    _, err = timeEntryService.GetReport(ctx, &dto.TimeReportRequest{TeamID: teamID, GroupBy: []string{"week"}})
+    assert.Contains(t, err.Error(), "invalid group_by")
+    fmt.Println("✅ Report rows grouped and totalled")
+}
@@ -2,6 +2,7 @@ package services_test
 
 import (
     "context"
+    "encoding/json"
     "errors"
     "fmt"
     "testing"
@@ -10,6 +11,7 @@ import (
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
@@ -23,7 +25,7 @@ func TestCreateTodo(t *testing.T) {
     mockRepo := new(mocks.MockTodoRepository)
     
     // Create the service with the mock repository
-    todoService := todos.NewTodoService(mockRepo)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), mockRepo, nil))
     
     // Setup test data
     todoID := "todo-123"
@@ -101,6 +103,21 @@ func TestCreateTodo(t *testing.T) {
     assert.Contains(t, err.Error(), "database error")
     fmt.Printf("✅ Correctly received error: %v\n", err)
     
+    // Scenario 3: Without a date the todo is due today in the user's timezone
+    fmt.Println("\nScenario 3: Testing the default date in the user's timezone")
+    loc := time.FixedZone("UTC+14", 14*60*60)
+    localNow := time.Now().In(loc)
+    mockRepo.On("CreateTodo", context.Background(), "Local Task", "", false, false, userID,
+        mock.MatchedBy(func(d time.Time) bool {
+            return d.Format("2006-01-02") == localNow.Format("2006-01-02") && d.Location() == time.UTC
+        }),
+        mock.AnythingOfType("time.Time"),
+    ).Return("todo-local", nil)
+    res, err = todoService.CreateTodo(context.Background(), &dto.CreateTodoRequest{Task: "Local Task", UserID: userID, Location: loc})
+    assert.NoError(t, err)
+    assert.Equal(t, "todo-local", res.ID)
+    fmt.Println("✅ Default date taken from the user's timezone")
+    
     // Verify all expected methods were called
     mockRepo.AssertExpectations(t)
     fmt.Println("✅ All CreateTodo test scenarios passed")
@@ -114,7 +131,7 @@ func TestGetTodosByUserID(t *testing.T) {
     mockRepo := new(mocks.MockTodoRepository)
     
     // Create the service with the mock repository
-    todoService := todos.NewTodoService(mockRepo)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), mockRepo, nil))
     
     // Setup test data
     userID := "user-123"
@@ -193,8 +210,11 @@ func TestUpdateTodo(t *testing.T) {
     // Create a mock repository
     mockRepo := new(mocks.MockTodoRepository)
     
-    // Create the service with the mock repository
-    todoService := todos.NewTodoService(mockRepo)
+    // Create the service with the mock repository; the todos have no blockers
+    dependencyRepo := new(mocks.MockDependencyRepository)
+    dependencyRepo.On("GetTodoDependencies", mock.Anything, mock.Anything).Return([]domain.Dependency{}, nil)
+    mockRepo.On("GetTodosByUserID", mock.Anything, mock.Anything).Return([]domain.Todo{}, nil)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(dependencyRepo, mockRepo, nil))
     
     // Setup test data
     todoID := "todo-123"
@@ -213,7 +233,9 @@ func TestUpdateTodo(t *testing.T) {
         done,
         important,
         userID,
+        0,
     ).Return(true, nil)
+    mockRepo.On("GetTodoByID", context.Background(), todoID).Return(&domain.Todo{ID: todoID, UserID: userID, Version: 2}, nil)
     
     mockRepo.On("UpdateTodo", 
         context.Background(),
@@ -223,6 +245,7 @@ func TestUpdateTodo(t *testing.T) {
         done,
         important,
         userID,
+        0,
     ).Return(false, errors.New("todo not found"))
     
     mockRepo.On("UpdateTodo", 
@@ -233,6 +256,7 @@ func TestUpdateTodo(t *testing.T) {
         done,
         important,
         "wrong-user",
+        0,
     ).Return(false, errors.New("unauthorized"))
     
     // Create the request
@@ -253,6 +277,7 @@ func TestUpdateTodo(t *testing.T) {
     assert.NoError(t, err)
     assert.NotNil(t, res)
     assert.True(t, res.Success)
+    assert.Equal(t, 2, res.Version)
     fmt.Printf("✅ Todo %s updated successfully\n", todoID)
     
     // Scenario 2: Non-existent todo
@@ -291,20 +316,20 @@ func TestDeleteTodo(t *testing.T) {
     mockRepo := new(mocks.MockTodoRepository)
     
     // Create the service with the mock repository
-    todoService := todos.NewTodoService(mockRepo)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), mockRepo, nil))
     
     // Setup test data
     todoID := "todo-123"
     userID := "user-123"
     
     // Set up expectations
-    mockRepo.On("DeleteTodo", context.Background(), todoID, userID).Return(true, nil)
-    mockRepo.On("DeleteTodo", context.Background(), "nonexistent-todo", userID).Return(false, errors.New("todo not found"))
-    mockRepo.On("DeleteTodo", context.Background(), todoID, "wrong-user").Return(false, errors.New("unauthorized"))
+    mockRepo.On("DeleteTodo", context.Background(), todoID, userID, 0).Return(true, nil)
+    mockRepo.On("DeleteTodo", context.Background(), "nonexistent-todo", userID, 0).Return(false, errors.New("todo not found"))
+    mockRepo.On("DeleteTodo", context.Background(), todoID, "wrong-user", 0).Return(false, errors.New("unauthorized"))
     
     // Scenario 1: Successful deletion
     fmt.Println("Scenario 1: Testing successful todo deletion")
-    res, err := todoService.DeleteTodo(context.Background(), todoID, userID)
+    res, err := todoService.DeleteTodo(context.Background(), todoID, userID, 0)
     
     // Assertions
     assert.NoError(t, err)
@@ -314,7 +339,7 @@ func TestDeleteTodo(t *testing.T) {
     
     // Scenario 2: Non-existent todo
     fmt.Println("\nScenario 2: Testing deletion of non-existent todo")
-    res, err = todoService.DeleteTodo(context.Background(), "nonexistent-todo", userID)
+    res, err = todoService.DeleteTodo(context.Background(), "nonexistent-todo", userID, 0)
     
     // Assertions
     assert.Error(t, err)
@@ -324,7 +349,7 @@ func TestDeleteTodo(t *testing.T) {
     
     // Scenario 3: Unauthorized deletion
     fmt.Println("\nScenario 3: Testing unauthorized deletion")
-    res, err = todoService.DeleteTodo(context.Background(), todoID, "wrong-user")
+    res, err = todoService.DeleteTodo(context.Background(), todoID, "wrong-user", 0)
     
     // Assertions
     assert.Error(t, err)
@@ -345,20 +370,21 @@ func TestUndoTodo(t *testing.T) {
     mockRepo := new(mocks.MockTodoRepository)
     
     // Create the service with the mock repository
-    todoService := todos.NewTodoService(mockRepo)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), mockRepo, nil))
     
     // Setup test data
     todoID := "todo-123"
     userID := "user-123"
     
     // Set up expectations
-    mockRepo.On("UndoTodo", context.Background(), todoID, userID).Return(true, nil)
-    mockRepo.On("UndoTodo", context.Background(), "nonexistent-todo", userID).Return(false, errors.New("todo not found"))
-    mockRepo.On("UndoTodo", context.Background(), todoID, "wrong-user").Return(false, errors.New("unauthorized"))
+    mockRepo.On("UndoTodo", context.Background(), todoID, userID, 0).Return(true, nil)
+    mockRepo.On("GetTodoByID", context.Background(), todoID).Return(&domain.Todo{ID: todoID, UserID: userID, Version: 3}, nil)
+    mockRepo.On("UndoTodo", context.Background(), "nonexistent-todo", userID, 0).Return(false, errors.New("todo not found"))
+    mockRepo.On("UndoTodo", context.Background(), todoID, "wrong-user", 0).Return(false, errors.New("unauthorized"))
     
     // Scenario 1: Successful undo
     fmt.Println("Scenario 1: Testing successful todo undo")
-    res, err := todoService.UndoTodo(context.Background(), todoID, userID)
+    res, err := todoService.UndoTodo(context.Background(), todoID, userID, 0)
     
     // Assertions
     assert.NoError(t, err)
@@ -368,7 +394,7 @@ func TestUndoTodo(t *testing.T) {
     
     // Scenario 2: Non-existent todo
     fmt.Println("\nScenario 2: Testing undo of non-existent todo")
-    res, err = todoService.UndoTodo(context.Background(), "nonexistent-todo", userID)
+    res, err = todoService.UndoTodo(context.Background(), "nonexistent-todo", userID, 0)
     
     // Assertions
     assert.Error(t, err)
@@ -378,7 +404,7 @@ func TestUndoTodo(t *testing.T) {
     
     // Scenario 3: Unauthorized undo
     fmt.Println("\nScenario 3: Testing unauthorized undo")
-    res, err = todoService.UndoTodo(context.Background(), todoID, "wrong-user")
+    res, err = todoService.UndoTodo(context.Background(), todoID, "wrong-user", 0)
     
     // Assertions
     assert.Error(t, err)
@@ -389,4 +415,340 @@ func TestUndoTodo(t *testing.T) {
     // Verify all expected methods were called
     mockRepo.AssertExpectations(t)
     fmt.Println("✅ All UndoTodo test scenarios passed")
-}
\ No newline at end of file
+}
+func TestExecuteBatchDefaultsToAtomic(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestExecuteBatchDefaultsToAtomic ===")
+    fmt.Println("Testing that a batch without a mode is all-or-nothing")
+    
+    ctx := context.Background()
+    userID := "user-123"
+    mockRepo := new(mocks.MockTodoRepository)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), mockRepo, nil))
+    
+    // The create carries its parsed date to the repository, which rolls both steps back
+    createsOnDate := mock.MatchedBy(func(ops []domain.BatchOperation) bool {
+        return len(ops) == 2 && ops[0].Task == "New Task" && ops[0].Date.Format("2006-01-02") == "2025-01-15"
+    })
+    mockRepo.On("ExecuteBatch", ctx, userID, createsOnDate, true).Return([]domain.BatchResult{
+        {Index: 0, Op: domain.BatchOpCreate, Status: domain.BatchStatusRolledBack},
+        {Index: 1, Op: domain.BatchOpDelete, ID: "missing", Status: domain.BatchStatusFailed, Error: "todo not found"},
+    }, false, nil)
+    
+    res, err := todoService.ExecuteBatch(ctx, &dto.BatchRequest{
+        UserID: userID,
+        Operations: []dto.BatchOperationRequest{
+            {Op: domain.BatchOpCreate, Task: "New Task", DateString: "2025-01-15"},
+            {Op: domain.BatchOpDelete, ID: "missing"},
+        },
+    })
+    
+    assert.NoError(t, err)
+    assert.Equal(t, domain.BatchModeAtomic, res.Mode)
+    assert.False(t, res.Committed)
+    assert.Equal(t, domain.BatchStatusRolledBack, res.Results[0].Status)
+    assert.Equal(t, "todo not found", res.Results[1].Error)
+    mockRepo.AssertExpectations(t)
+    fmt.Println("✅ Failed atomic batch reported as not committed with the failing step")
+}
+
+func TestExecuteBatchPartialMode(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestExecuteBatchPartialMode ===")
+    fmt.Println("Testing that a partial batch keeps the steps that succeeded")
+    
+    ctx := context.Background()
+    userID := "user-123"
+    mockRepo := new(mocks.MockTodoRepository)
+    dependencyRepo := new(mocks.MockDependencyRepository)
+    dependencyRepo.On("GetTodoDependencies", ctx, userID).Return([]domain.Dependency{}, nil)
+    mockRepo.On("GetTodosByUserID", ctx, userID).Return([]domain.Todo{{ID: "todo-1", UserID: userID}}, nil)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(dependencyRepo, mockRepo, nil))
+    
+    mockRepo.On("ExecuteBatch", ctx, userID, mock.AnythingOfType("[]domain.BatchOperation"), false).Return([]domain.BatchResult{
+        {Index: 0, Op: domain.BatchOpComplete, ID: "todo-1", Status: domain.BatchStatusOK},
+        {Index: 1, Op: domain.BatchOpDelete, ID: "missing", Status: domain.BatchStatusFailed, Error: "todo not found"},
+    }, true, nil)
+    
+    res, err := todoService.ExecuteBatch(ctx, &dto.BatchRequest{
+        Mode:   domain.BatchModePartial,
+        UserID: userID,
+        Operations: []dto.BatchOperationRequest{
+            {Op: domain.BatchOpComplete, ID: "todo-1"},
+            {Op: domain.BatchOpDelete, ID: "missing"},
+        },
+    })
+    
+    assert.NoError(t, err)
+    assert.Equal(t, domain.BatchModePartial, res.Mode)
+    assert.True(t, res.Committed)
+    assert.Equal(t, domain.BatchStatusOK, res.Results[0].Status)
+    assert.Equal(t, domain.BatchStatusFailed, res.Results[1].Status)
+    mockRepo.AssertExpectations(t)
+    fmt.Println("✅ Partial batch committed the completion despite the failed delete")
+}
+
+func TestExecuteBatchPartialModeWithBlockedTodo(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestExecuteBatchPartialModeWithBlockedTodo ===")
+    fmt.Println("Testing that a blocked completion only fails its own step in a partial batch")
+    
+    ctx := context.Background()
+    userID := "user-123"
+    mockRepo := new(mocks.MockTodoRepository)
+    dependencyRepo := new(mocks.MockDependencyRepository)
+    // todo-2 is blocked by the open todo-3; todo-1 has no blockers
+    dependencyRepo.On("GetTodoDependencies", ctx, userID).Return([]domain.Dependency{{TodoID: "todo-2", BlockedByID: "todo-3"}}, nil)
+    mockRepo.On("GetTodosByUserID", ctx, userID).Return([]domain.Todo{
+        {ID: "todo-1", UserID: userID},
+        {ID: "todo-2", UserID: userID},
+        {ID: "todo-3", UserID: userID},
+    }, nil)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(dependencyRepo, mockRepo, nil))
+    
+    operations := []dto.BatchOperationRequest{
+        {Op: domain.BatchOpComplete, ID: "todo-2"},
+        {Op: domain.BatchOpComplete, ID: "todo-1"},
+    }
+    
+    // Scenario 1: Partial mode fails the blocked step and runs the valid one
+    fmt.Println("Scenario 1: Testing a partial batch with a blocked completion")
+    onlyTodo1 := mock.MatchedBy(func(ops []domain.BatchOperation) bool {
+        return len(ops) == 1 && ops[0].ID == "todo-1"
+    })
+    mockRepo.On("ExecuteBatch", ctx, userID, onlyTodo1, false).Return([]domain.BatchResult{
+        {Index: 0, Op: domain.BatchOpComplete, ID: "todo-1", Status: domain.BatchStatusOK},
+    }, true, nil)
+    
+    res, err := todoService.ExecuteBatch(ctx, &dto.BatchRequest{Mode: domain.BatchModePartial, UserID: userID, Operations: operations})
+    assert.NoError(t, err)
+    assert.True(t, res.Committed)
+    assert.Len(t, res.Results, 2)
+    assert.Equal(t, 0, res.Results[0].Index)
+    assert.Equal(t, domain.BatchStatusFailed, res.Results[0].Status)
+    assert.Equal(t, "blocked by open todos: todo-3", res.Results[0].Error)
+    assert.Equal(t, 1, res.Results[1].Index)
+    assert.Equal(t, "todo-1", res.Results[1].ID)
+    assert.Equal(t, domain.BatchStatusOK, res.Results[1].Status)
+    fmt.Println("✅ Blocked step failed at its own index while the valid step ran")
+    
+    // Scenario 2: Atomic mode still rejects the whole batch
+    fmt.Println("\nScenario 2: Testing the same batch in atomic mode")
+    _, err = todoService.ExecuteBatch(ctx, &dto.BatchRequest{Mode: domain.BatchModeAtomic, UserID: userID, Operations: operations})
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "blocked by open todos: todo-3")
+    mockRepo.AssertNotCalled(t, "ExecuteBatch", ctx, userID, mock.Anything, true)
+    fmt.Println("✅ Atomic batch rejected before reaching the repository")
+}
+
+func TestExecuteBatchValidation(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestExecuteBatchValidation ===")
+    fmt.Println("Testing that invalid batches never reach the repository")
+    
+    ctx := context.Background()
+    userID := "user-123"
+    mockRepo := new(mocks.MockTodoRepository)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), mockRepo, nil))
+    
+    // Scenario 1: An empty batch
+    fmt.Println("Scenario 1: Testing an empty batch")
+    _, err := todoService.ExecuteBatch(ctx, &dto.BatchRequest{UserID: userID})
+    assert.Contains(t, err.Error(), "at least one operation")
+    fmt.Println("✅ Empty batch rejected")
+    
+    // Scenario 2: An unknown mode
+    fmt.Println("\nScenario 2: Testing an unknown mode")
+    _, err = todoService.ExecuteBatch(ctx, &dto.BatchRequest{UserID: userID, Mode: "sometimes", Operations: []dto.BatchOperationRequest{{Op: domain.BatchOpDelete, ID: "todo-1"}}})
+    assert.Contains(t, err.Error(), `invalid batch mode "sometimes"`)
+    fmt.Println("✅ Unknown mode rejected")
+    
+    // Scenario 3: A bad step names its index, even after valid ones
+    fmt.Println("\nScenario 3: Testing a bad date in the second step")
+    _, err = todoService.ExecuteBatch(ctx, &dto.BatchRequest{UserID: userID, Operations: []dto.BatchOperationRequest{
+        {Op: domain.BatchOpDelete, ID: "todo-1"},
+        {Op: domain.BatchOpCreate, Task: "Task", DateString: "15/01/2025"},
+    }})
+    assert.Contains(t, err.Error(), "operation 1: invalid date format")
+    fmt.Println("✅ Bad step rejected with its index")
+    
+    mockRepo.AssertNotCalled(t, "ExecuteBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchTodo(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestPatchTodo ===")
+    fmt.Println("Testing partial todo updates with JSON Merge Patch")
+    
+    // Create a mock repository
+    mockRepo := new(mocks.MockTodoRepository)
+    
+    // Create the service with the mock repository; the todos have no blockers
+    dependencyRepo := new(mocks.MockDependencyRepository)
+    dependencyRepo.On("GetTodoDependencies", mock.Anything, mock.Anything).Return([]domain.Dependency{}, nil)
+    mockRepo.On("GetTodosByUserID", mock.Anything, mock.Anything).Return([]domain.Todo{}, nil)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(dependencyRepo, mockRepo, nil))
+    
+    todoID := "todo-123"
+    userID := "user-123"
+    done := true
+    
+    // Only the done flag is present, and description is explicitly cleared
+    expectedPatch := domain.TodoPatch{Done: &done, ClearDescription: true}
+    mockRepo.On("PatchTodo", context.Background(), todoID, userID, expectedPatch, 0).Return(true, nil)
+    mockRepo.On("GetTodoByID", context.Background(), todoID).Return(&domain.Todo{
+        ID:        todoID,
+        Task:      "Keep this task",
+        Done:      true,
+        Important: true,
+        UserID:    userID,
+    }, nil)
+    
+    // Scenario 1: Toggle done without sending the rest of the todo
+    fmt.Println("Scenario 1: Testing patch of a single field")
+    req := &dto.PatchTodoRequest{
+        ID:     todoID,
+        UserID: userID,
+        Fields: map[string]json.RawMessage{
+            "done":        json.RawMessage(`true`),
+            "description": json.RawMessage(`null`),
+        },
+    }
+    res, err := todoService.PatchTodo(context.Background(), req)
+    
+    assert.NoError(t, err)
+    assert.NotNil(t, res)
+    assert.True(t, res.Done)
+    assert.Equal(t, "Keep this task", res.Task)
+    fmt.Printf("✅ Todo %s patched, task preserved: %s\n", res.ID, res.Task)
+    
+    // Scenario 2: Invalid patches are rejected
+    fmt.Println("\nScenario 2: Testing invalid patch documents")
+    invalidFields := []map[string]json.RawMessage{
+        {"task": json.RawMessage(`null`)},
+        {"task": json.RawMessage(`""`)},
+        {"done": json.RawMessage(`"yes"`)},
+        {"date": json.RawMessage(`"15/01/2025"`)},
+        {"owner": json.RawMessage(`"someone"`)},
+    }
+    for _, fields := range invalidFields {
+        res, err = todoService.PatchTodo(context.Background(), &dto.PatchTodoRequest{ID: todoID, UserID: userID, Fields: fields})
+        assert.Error(t, err)
+        assert.Nil(t, res)
+        assert.Contains(t, err.Error(), "invalid patch")
+        fmt.Printf("✅ Correctly rejected patch: %v\n", err)
+    }
+    
+    // Verify all expected methods were called
+    mockRepo.AssertExpectations(t)
+    fmt.Println("✅ All PatchTodo test scenarios passed")
+}
+
+func TestUpdateTodoWithVersion(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestUpdateTodoWithVersion ===")
+    fmt.Println("Testing optimistic concurrency on todo updates")
+    
+    // Create a mock repository
+    mockRepo := new(mocks.MockTodoRepository)
+    
+    // Create the service with the mock repository
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(new(mocks.MockDependencyRepository), mockRepo, nil))
+    
+    todoID := "todo-123"
+    userID := "user-123"
+    
+    // Version 2 is current, version 1 is stale
+    mockRepo.On("UpdateTodo", context.Background(), todoID, "Task", "", false, false, userID, 2).Return(true, nil)
+    mockRepo.On("GetTodoByID", context.Background(), todoID).Return(&domain.Todo{ID: todoID, Task: "Task", UserID: userID, Version: 3}, nil)
+    mockRepo.On("UpdateTodo", context.Background(), todoID, "Task", "", false, false, userID, 1).Return(false, errors.New("version mismatch: current version is 2"))
+    mockRepo.On("DeleteTodo", context.Background(), todoID, userID, 1).Return(false, errors.New("version mismatch: current version is 2"))
+    
+    // Scenario 1: Update with the current version
+    fmt.Println("Scenario 1: Testing update with the current version")
+    req := &dto.UpdateTodoRequest{ID: todoID, Task: "Task", UserID: userID, Version: 2}
+    res, err := todoService.UpdateTodo(context.Background(), req)
+    
+    assert.NoError(t, err)
+    assert.True(t, res.Success)
+    assert.Equal(t, 3, res.Version)
+    fmt.Println("✅ Update with current version succeeded")
+    
+    // Scenario 2: Update with a stale version
+    fmt.Println("\nScenario 2: Testing update with a stale version")
+    req.Version = 1
+    res, err = todoService.UpdateTodo(context.Background(), req)
+    
+    assert.Error(t, err)
+    assert.Nil(t, res)
+    assert.Contains(t, err.Error(), "version mismatch")
+    fmt.Printf("✅ Correctly rejected stale update: %v\n", err)
+    
+    // Scenario 3: Delete with a stale version
+    fmt.Println("\nScenario 3: Testing delete with a stale version")
+    res, err = todoService.DeleteTodo(context.Background(), todoID, userID, 1)
+    
+    assert.Error(t, err)
+    assert.Nil(t, res)
+    assert.Contains(t, err.Error(), "version mismatch")
+    fmt.Printf("✅ Correctly rejected stale delete: %v\n", err)
+    
+    // Verify all expected methods were called
+    mockRepo.AssertExpectations(t)
+    fmt.Println("✅ All optimistic concurrency test scenarios passed")
+}
+
+func TestCompleteBlockedTodo(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestCompleteBlockedTodo ===")
+    fmt.Println("Testing that every completion path refuses a todo with open blockers")
+    
+    ctx := context.Background()
+    userID := "user-123"
+    
+    // "write" is blocked by the open "research"
+    mockRepo := new(mocks.MockTodoRepository)
+    dependencyRepo := new(mocks.MockDependencyRepository)
+    mockRepo.On("GetTodosByUserID", ctx, userID).Return([]domain.Todo{
+        {ID: "research", Task: "Research", UserID: userID},
+        {ID: "write", Task: "Write", UserID: userID},
+    }, nil)
+    dependencyRepo.On("GetTodoDependencies", ctx, userID).Return([]domain.Dependency{
+        {TodoID: "write", BlockedByID: "research"},
+    }, nil)
+    todoService := todos.NewTodoService(mockRepo, dependencies.NewDependencyService(dependencyRepo, mockRepo, nil))
+    
+    // Scenario 1: PUT, PATCH and batch completion are all rejected
+    fmt.Println("Scenario 1: Testing completion of a blocked todo")
+    _, err := todoService.UpdateTodo(ctx, &dto.UpdateTodoRequest{ID: "write", Task: "Write", Done: true, UserID: userID})
+    assert.ErrorContains(t, err, "blocked by open todos")
+    
+    _, err = todoService.PatchTodo(ctx, &dto.PatchTodoRequest{ID: "write", UserID: userID, Fields: map[string]json.RawMessage{"done": json.RawMessage("true")}})
+    assert.ErrorContains(t, err, "blocked by open todos")
+    
+    _, err = todoService.ExecuteBatch(ctx, &dto.BatchRequest{UserID: userID, Operations: []dto.BatchOperationRequest{{Op: domain.BatchOpComplete, ID: "write"}}})
+    assert.ErrorContains(t, err, "blocked by open todos")
+    mockRepo.AssertNotCalled(t, "UpdateTodo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    mockRepo.AssertNotCalled(t, "PatchTodo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    mockRepo.AssertNotCalled(t, "ExecuteBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    fmt.Println("✅ Blocked todo was not completed")
+    
+    // Scenario 2: Completing the blocker in the same batch is allowed
+    fmt.Println("\nScenario 2: Testing a batch that completes the blocker too")
+    ops := []domain.BatchOperation{{Op: domain.BatchOpComplete, ID: "research"}, {Op: domain.BatchOpComplete, ID: "write"}}
+    mockRepo.On("ExecuteBatch", ctx, userID, ops, true).Return([]domain.BatchResult{
+        {Index: 0, Op: domain.BatchOpComplete, ID: "research", Status: domain.BatchStatusOK},
+        {Index: 1, Op: domain.BatchOpComplete, ID: "write", Status: domain.BatchStatusOK},
+    }, true, nil)
+    res, err := todoService.ExecuteBatch(ctx, &dto.BatchRequest{UserID: userID, Operations: []dto.BatchOperationRequest{
+        {Op: domain.BatchOpComplete, ID: "research"},
+        {Op: domain.BatchOpComplete, ID: "write"},
+    }})
+    assert.NoError(t, err)
+    assert.True(t, res.Committed)
+    fmt.Println("✅ Blocker and blocked todo completed together")
+    
+    // Scenario 3: Force skips the check
+    fmt.Println("\nScenario 3: Testing a forced completion")
+    mockRepo.On("UpdateTodo", ctx, "write", "Write", "", true, false, userID, 0).Return(true, nil)
+    mockRepo.On("GetTodoByID", ctx, "write").Return(&domain.Todo{ID: "write", Task: "Write", Done: true, UserID: userID, Version: 2}, nil)
+    _, err = todoService.UpdateTodo(ctx, &dto.UpdateTodoRequest{ID: "write", Task: "Write", Done: true, UserID: userID, Force: true})
+    assert.NoError(t, err)
+    fmt.Println("✅ Forced completion succeeded")
+    
+    mockRepo.AssertExpectations(t)
+    fmt.Println("✅ All blocked completion test scenarios passed")
+}
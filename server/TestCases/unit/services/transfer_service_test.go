@@ -0,0 +1,162 @@
+package services_test
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/transfer"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestTransferService(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestTransferService ===")
+    fmt.Println("Testing export and import of user data")
+
+    ctx := context.Background()
+    userID := "user-123"
+    date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+    todoTime := time.Date(2000, 1, 1, 9, 30, 0, 0, time.UTC)
+
+    existingTodos := []domain.Todo{
+        {ID: "todo-1", Task: "Write report", Description: "Q2", Important: true, UserID: userID, Date: date, Time: todoTime},
+    }
+    routines := []domain.Routine{
+        {ID: "routine-1", Day: "monday", ScheduleType: "morning", TaskID: "todo-1", UserID: userID, IsActive: true},
+    }
+    teams := []domain.Team{{ID: "team-1", Name: "Core"}}
+    teamTodos := []domain.TeamTodo{
+        {ID: "team-todo-1", Task: "Deploy", TeamID: "team-1", AssignedTo: userID, Date: date, Time: todoTime},
+    }
+
+    newService := func() (*transfer.TransferService, *mocks.MockTodoRepository, *mocks.MockRoutineRepository, *mocks.MockTeamTodoRepository) {
+        todoRepo := new(mocks.MockTodoRepository)
+        routineRepo := new(mocks.MockRoutineRepository)
+        sharedRepo := new(mocks.MockSharedTodoRepository)
+        teamRepo := new(mocks.MockTeamRepository)
+        teamTodoRepo := new(mocks.MockTeamTodoRepository)
+        teamMemberRepo := new(mocks.MockTeamMemberRepository)
+
+        todoRepo.On("GetTodosByUserID", ctx, userID).Return(existingTodos, nil)
+        routineRepo.On("GetRoutinesByTaskID", ctx, "todo-1").Return(routines, nil)
+        routineRepo.On("GetRoutineSlots", ctx, userID).Return([]domain.RoutineSlot{}, nil)
+        sharedRepo.On("GetSharedTodos", ctx, userID).Return([]domain.SharedTodo{}, nil)
+        sharedRepo.On("GetSharedByMeTodos", ctx, userID).Return([]domain.SharedTodo{
+            {ID: "shared-1", Task: "Write report", UserID: "user-456", SharedBy: userID},
+        }, nil)
+        teamRepo.On("GetTeams", ctx, userID).Return(teams, nil)
+        teamTodoRepo.On("GetTeamTodos", ctx, "team-1").Return(teamTodos, nil)
+        teamTodoRepo.On("GetTeamTodoByID", ctx, "team-todo-1", "team-1").Return(&teamTodos[0], nil)
+        teamTodoRepo.On("GetTeamTodoTeamID", ctx, "team-todo-1").Return("team-1", nil)
+        teamTodoRepo.On("GetTeamTodoTeamID", ctx, "team-todo-2").Return("team-2", nil)
+        teamTodoRepo.On("GetTeamTodoTeamID", ctx, mock.Anything).Return("", fmt.Errorf("team todo not found"))
+        teamMemberRepo.On("GetTeamMembers", ctx, "team-1").Return([]domain.TeamMember{{TeamID: "team-1", UserID: userID}}, nil)
+
+        return transfer.NewTransferService(todoRepo, routineRepo, sharedRepo, teamRepo, teamTodoRepo, teamMemberRepo), todoRepo, routineRepo, teamTodoRepo
+    }
+
+    // Scenario 1: JSON export contains every record type
+    fmt.Println("Scenario 1: Testing JSON export")
+    service, _, _, _ := newService()
+    data, err := service.Export(ctx, userID, transfer.FormatJSON)
+    assert.NoError(t, err)
+
+    var doc dto.ExportDocument
+    assert.NoError(t, json.Unmarshal(data, &doc))
+    assert.Len(t, doc.Todos, 1)
+    assert.Equal(t, "2024-05-01", doc.Todos[0].Date)
+    assert.Equal(t, "09:30:00", doc.Todos[0].Time)
+    assert.Len(t, doc.Routines, 1)
+    assert.Len(t, doc.SharedTodos, 1)
+    assert.Len(t, doc.TeamTodos, 1)
+    fmt.Println("✅ JSON export contains todos, routines, shared todos and team todos")
+
+    // Scenario 2: Re-importing an unchanged export writes nothing
+    fmt.Println("\nScenario 2: Testing idempotent JSON import with preserved IDs")
+    service, todoRepo, routineRepo, teamTodoRepo := newService()
+    res, err := service.Import(ctx, &dto.ImportRequest{UserID: userID, Format: transfer.FormatJSON, Data: data})
+    assert.NoError(t, err)
+    assert.Equal(t, transfer.IDModePreserve, res.IDMode)
+    assert.Equal(t, 0, res.Created)
+    assert.Equal(t, 0, res.Updated)
+    assert.Equal(t, 4, res.Skipped)
+    todoRepo.AssertNotCalled(t, "CreateTodoWithID", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    routineRepo.AssertNotCalled(t, "CreateOrUpdateRoutines", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    teamTodoRepo.AssertNotCalled(t, "PatchTeamTodo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    fmt.Println("✅ Unchanged records were skipped")
+
+    // Scenario 3: CSV round trip creates missing todos with their original IDs
+    fmt.Println("\nScenario 3: Testing CSV export and import of a new todo")
+    service, _, _, _ = newService()
+    csvData, err := service.Export(ctx, userID, transfer.FormatCSV)
+    assert.NoError(t, err)
+    assert.True(t, strings.HasPrefix(string(csvData), "record_type,id,task"))
+
+    csvData = append(csvData, []byte("todo,todo-2,Plan sprint,,false,false,2024-05-02,,,,,,,,,\n")...)
+    service, todoRepo, _, _ = newService()
+    todoRepo.On("GetTodoByID", ctx, "todo-2").Return(nil, fmt.Errorf("todo not found"))
+    todoRepo.On("CreateTodoWithID", ctx, "todo-2", "Plan sprint", "", false, false, userID,
+        time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), time.Time{}).Return(nil)
+
+    res, err = service.Import(ctx, &dto.ImportRequest{UserID: userID, Format: transfer.FormatCSV, Data: csvData})
+    assert.NoError(t, err)
+    assert.Equal(t, 1, res.Created)
+    todoRepo.AssertCalled(t, "CreateTodoWithID", ctx, "todo-2", "Plan sprint", "", false, false, userID,
+        time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), time.Time{})
+    fmt.Println("✅ CSV import created the new todo with its original ID")
+
+    // Scenario 4: Invalid records are reported and nothing is written
+    fmt.Println("\nScenario 4: Testing import validation errors")
+    service, todoRepo, _, _ = newService()
+    todoRepo.On("GetTodoByID", ctx, "todo-9").Return(&domain.Todo{ID: "todo-9", UserID: "user-456"}, nil)
+    invalid := []byte(`{"format_version":1,"todos":[{"id":"todo-9","task":"","date":"05/02/2024"}],
+        "routines":[{"id":"r-9","task_id":"missing","day":"someday","schedule_type":"morning","is_active":true}],
+        "team_todos":[{"id":"tt-9","team_id":"other-team","task":"Deploy"}]}`)
+    res, err = service.Import(ctx, &dto.ImportRequest{UserID: userID, Format: transfer.FormatJSON, Data: invalid})
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid import")
+    assert.NotNil(t, res)
+    assert.Len(t, res.Errors, 6)
+    todoRepo.AssertNotCalled(t, "CreateTodoWithID", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    fmt.Printf("✅ Import rejected with %d validation errors\n", len(res.Errors))
+
+    // Scenario 5: Unsupported formats are rejected
+    fmt.Println("\nScenario 5: Testing unsupported export format")
+    service, _, _, _ = newService()
+    _, err = service.Export(ctx, userID, "xml")
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "unsupported format")
+    fmt.Println("✅ Unsupported format rejected")
+
+    // Scenario 6: Team todos can only be imported for members of the team
+    fmt.Println("\nScenario 6: Testing import of a team todo assigned to a non-member")
+    service, _, _, teamTodoRepo = newService()
+    outsider := []byte(`{"format_version":1,"team_todos":[{"id":"tt-1","team_id":"team-1","task":"Deploy","assigned_to":"user-999"}]}`)
+    res, err = service.Import(ctx, &dto.ImportRequest{UserID: userID, Format: transfer.FormatJSON, Data: outsider})
+    assert.Error(t, err)
+    assert.Len(t, res.Errors, 1)
+    assert.Equal(t, "assigned_to is not a member of the team", res.Errors[0].Error)
+    teamTodoRepo.AssertNotCalled(t, "CreateTeamTodoWithID", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    fmt.Println("✅ Import rejected the non-member assignee")
+
+    // Scenario 7: A preserved team todo ID owned by another team is rejected before any write
+    fmt.Println("\nScenario 7: Testing import of a team todo ID that belongs to another team")
+    service, todoRepo, _, teamTodoRepo = newService()
+    todoRepo.On("GetTodoByID", ctx, "todo-3").Return(nil, fmt.Errorf("todo not found"))
+    taken := []byte(`{"format_version":1,"todos":[{"id":"todo-3","task":"Plan sprint"}],
+        "team_todos":[{"id":"team-todo-2","team_id":"team-1","task":"Deploy"}]}`)
+    res, err = service.Import(ctx, &dto.ImportRequest{UserID: userID, Format: transfer.FormatJSON, Data: taken})
+    assert.Error(t, err)
+    assert.Len(t, res.Errors, 1)
+    assert.Equal(t, "id is already used by another team", res.Errors[0].Error)
+    todoRepo.AssertNotCalled(t, "CreateTodoWithID", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    teamTodoRepo.AssertNotCalled(t, "CreateTeamTodoWithID", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    fmt.Println("✅ Import rejected the team todo ID before writing anything")
+}
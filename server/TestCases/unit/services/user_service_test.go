@@ -150,4 +150,37 @@ func TestVerifyPassword(t *testing.T) {
     fmt.Printf("✅ Correctly received error for invalid hash: %v\n", err)
     
     fmt.Println("✅ All VerifyPassword test scenarios passed")
-}
\ No newline at end of file
+}
+func TestUserTimezone(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestUserTimezone ===")
+    fmt.Println("Testing stored timezones and the request override")
+    
+    ctx := context.Background()
+    mockRepo := new(mocks.MockUserRepository)
+    userService := users.NewUserService(mockRepo)
+    
+    // Scenario 1: Only IANA names are stored
+    fmt.Println("Scenario 1: Testing timezone validation")
+    _, err := userService.SetTimezone(ctx, &dto.SetTimezoneRequest{UserID: "user-123", Timezone: "Mars/Olympus"})
+    assert.Contains(t, err.Error(), "invalid timezone")
+    _, err = userService.SetTimezone(ctx, &dto.SetTimezoneRequest{UserID: "user-123", Timezone: "Local"})
+    assert.Contains(t, err.Error(), "invalid timezone")
+    mockRepo.On("SetUserTimezone", ctx, "user-123", "Asia/Tokyo").Return(nil)
+    res, err := userService.SetTimezone(ctx, &dto.SetTimezoneRequest{UserID: "user-123", Timezone: "Asia/Tokyo"})
+    assert.NoError(t, err)
+    assert.Equal(t, "Asia/Tokyo", res.Timezone)
+    fmt.Println("✅ Timezone validated and stored")
+    
+    // Scenario 2: The override wins over the stored timezone
+    fmt.Println("\nScenario 2: Testing timezone resolution")
+    mockRepo.On("GetUserTimezone", ctx, "user-123").Return("Asia/Tokyo", nil)
+    loc, err := userService.Location(ctx, "user-123", "")
+    assert.NoError(t, err)
+    assert.Equal(t, "Asia/Tokyo", loc.String())
+    loc, err = userService.Location(ctx, "user-123", "America/New_York")
+    assert.NoError(t, err)
+    assert.Equal(t, "America/New_York", loc.String())
+    _, err = userService.Location(ctx, "user-123", "Nowhere")
+    assert.Contains(t, err.Error(), "invalid timezone")
+    fmt.Println("✅ Header override applied and validated")
+}
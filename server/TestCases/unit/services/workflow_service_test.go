@@ -0,0 +1,148 @@
+package services_test
+
+import (
+    "context"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/workflow"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+)
+
+func TestWorkflowService(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestWorkflowService ===")
+    fmt.Println("Testing team workflow statuses and the board")
+
+    ctx := context.Background()
+
+    // Scenario 1: A team without statuses reads the default workflow without storing it
+    fmt.Println("Scenario 1: Testing the default workflow on reads")
+    repo := new(mocks.MockWorkflowRepository)
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    dependencyRepo := new(mocks.MockDependencyRepository)
+    workflowService := workflow.NewWorkflowService(repo, teamTodoRepo, dependencies.NewDependencyService(dependencyRepo, nil, teamTodoRepo))
+    repo.On("GetTeamStatuses", ctx, "new-team").Return([]domain.TeamStatus{}, nil)
+    res, err := workflowService.GetWorkflow(ctx, "new-team")
+    assert.NoError(t, err)
+    assert.Len(t, res.Statuses, 4)
+    assert.Equal(t, "Backlog", res.Statuses[0].Name)
+    assert.True(t, res.Statuses[3].Terminal)
+    again, err := workflowService.GetWorkflow(ctx, "new-team")
+    assert.NoError(t, err)
+    assert.Equal(t, res.Statuses, again.Statuses)
+    repo.AssertNotCalled(t, "ReplaceTeamWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    repo.AssertNotCalled(t, "EnsureTeamWorkflow", mock.Anything, mock.Anything, mock.Anything)
+    fmt.Println("✅ Default statuses returned with stable IDs and nothing written")
+
+    // Scenario 2: Invalid workflows are rejected before saving
+    fmt.Println("\nScenario 2: Testing workflow validation")
+    _, err = workflowService.UpdateWorkflow(ctx, &dto.UpdateWorkflowRequest{TeamID: "team-1", Statuses: []dto.TeamStatusRequest{{Name: "Todo"}, {Name: "todo", Terminal: true}}})
+    assert.Contains(t, err.Error(), "duplicate name")
+    _, err = workflowService.UpdateWorkflow(ctx, &dto.UpdateWorkflowRequest{TeamID: "team-1", Statuses: []dto.TeamStatusRequest{{Name: "Todo"}, {Name: "Doing"}}})
+    assert.Contains(t, err.Error(), "terminal")
+    _, err = workflowService.UpdateWorkflow(ctx, &dto.UpdateWorkflowRequest{TeamID: "team-1",
+        Statuses:    []dto.TeamStatusRequest{{Name: "Todo"}, {Name: "Done", Terminal: true}},
+        Transitions: []dto.StatusTransitionRequest{{From: "Todo", To: "Shipped"}}})
+    assert.Contains(t, err.Error(), "unknown status")
+    repo.AssertNotCalled(t, "ReplaceTeamWorkflow", ctx, "team-1", mock.Anything, mock.Anything)
+    fmt.Println("✅ Invalid workflows rejected")
+
+    // Board fixture: todo-1 placed in Review, todo-2 unplaced and overdue, todo-3 marked done by an older client
+    statuses := []domain.TeamStatus{
+        {ID: "s-backlog", TeamID: "team-1", Name: "Backlog", Position: 0},
+        {ID: "s-review", TeamID: "team-1", Name: "Review", Position: 1},
+        {ID: "s-done", TeamID: "team-1", Name: "Done", Position: 2, Terminal: true},
+    }
+    repo.On("GetTeamStatuses", ctx, "team-1").Return(statuses, nil)
+    repo.On("GetStatusTransitions", ctx, "team-1").Return([]domain.StatusTransition{
+        {FromStatusID: "s-backlog", ToStatusID: "s-review"},
+        {FromStatusID: "s-review", ToStatusID: "s-done"},
+    }, nil)
+    teamTodoRepo.On("GetTeamTodos", ctx, "team-1").Return([]domain.TeamTodo{
+        {ID: "todo-1", Task: "Spec", TeamID: "team-1"},
+        {ID: "todo-2", Task: "Build", TeamID: "team-1", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+            CompletionMode: domain.CompletionModeAll, Assignees: []domain.TeamTodoAssignee{{UserID: "user-1", Username: "alice"}}},
+        {ID: "todo-3", Task: "Kickoff", TeamID: "team-1", Done: true},
+    }, nil)
+    repo.On("GetTeamTodoPlacements", ctx, "team-1").Return([]domain.TeamTodoPlacement{
+        {TodoID: "todo-1", StatusID: "s-review", Position: 0},
+        {TodoID: "todo-3", StatusID: "s-backlog", Position: 0},
+    }, nil)
+
+    // Scenario 3: The board groups todos and derives columns from the done flag when needed
+    fmt.Println("\nScenario 3: Testing the board")
+    board, err := workflowService.GetBoard(ctx, "team-1", time.UTC)
+    assert.NoError(t, err)
+    assert.Len(t, board.Columns, 3)
+    assert.Equal(t, "todo-2", board.Columns[0].Todos[0].ID)
+    assert.Equal(t, "todo-1", board.Columns[1].Todos[0].ID)
+    assert.Equal(t, "todo-3", board.Columns[2].Todos[0].ID)
+    backlog := board.Columns[0].Todos[0]
+    assert.True(t, backlog.Overdue)
+    assert.Equal(t, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), backlog.Due)
+    assert.Equal(t, domain.CompletionModeAll, backlog.CompletionMode)
+    assert.Len(t, backlog.Assignees, 1)
+    assert.False(t, board.Columns[1].Todos[0].Overdue)
+    fmt.Println("✅ Todos grouped by status with done todos in the terminal column, carrying due dates and assignees")
+
+    // Scenario 4: Moves that skip the allowed transitions are rejected
+    fmt.Println("\nScenario 4: Testing transition rules")
+    _, err = workflowService.MoveTeamTodo(ctx, &dto.MoveTeamTodoRequest{ID: "todo-2", TeamID: "team-1", Status: "Done"})
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), `transition from "Backlog" to "Done" is not allowed`)
+    fmt.Println("✅ Disallowed transition rejected")
+
+    // Scenario 5: An allowed move renumbers the target column and derives done
+    fmt.Println("\nScenario 5: Testing an allowed move")
+    position := 0
+    dependencyRepo.On("GetTeamTodoDependencies", ctx, "team-1").Return([]domain.Dependency{}, nil).Once()
+    teamTodoRepo.On("MoveTeamTodo", ctx, "todo-1", "team-1", "s-done", true, []string{"todo-1", "todo-3"}, 4).Return(true, nil)
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "todo-1", "team-1").Return(&domain.TeamTodo{ID: "todo-1", Task: "Spec", TeamID: "team-1", Done: true, Version: 5}, nil)
+    moved, err := workflowService.MoveTeamTodo(ctx, &dto.MoveTeamTodoRequest{ID: "todo-1", TeamID: "team-1", Status: "done", Position: &position, Version: 4})
+    assert.NoError(t, err)
+    assert.True(t, moved.Todo.Done)
+    assert.Equal(t, "s-done", moved.Status.ID)
+    assert.Equal(t, 0, moved.Position)
+    teamTodoRepo.AssertCalled(t, "MoveTeamTodo", ctx, "todo-1", "team-1", "s-done", true, []string{"todo-1", "todo-3"}, 4)
+    fmt.Println("✅ Todo moved to the top of the terminal column and marked done")
+
+    // Scenario 6: A todo with an open blocker cannot be moved into a terminal status
+    fmt.Println("\nScenario 6: Testing a move of a blocked todo")
+    dependencyRepo.On("GetTeamTodoDependencies", ctx, "team-1").Return([]domain.Dependency{{TodoID: "todo-1", BlockedByID: "todo-2"}}, nil)
+    _, err = workflowService.MoveTeamTodo(ctx, &dto.MoveTeamTodoRequest{ID: "todo-1", TeamID: "team-1", Status: "Done", Version: 5})
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "blocked by open todos: todo-2")
+    teamTodoRepo.AssertNotCalled(t, "MoveTeamTodo", ctx, "todo-1", "team-1", "s-done", true, mock.Anything, 5)
+    fmt.Println("✅ Blocked todo kept out of the terminal column")
+
+    // Scenario 7: Forcing the move skips the blocker check
+    fmt.Println("\nScenario 7: Testing a forced move of a blocked todo")
+    teamTodoRepo.On("MoveTeamTodo", ctx, "todo-1", "team-1", "s-done", true, []string{"todo-1", "todo-3"}, 5).Return(true, nil)
+    _, err = workflowService.MoveTeamTodo(ctx, &dto.MoveTeamTodoRequest{ID: "todo-1", TeamID: "team-1", Status: "Done", Position: &position, Version: 5, Force: true})
+    assert.NoError(t, err)
+    fmt.Println("✅ Forced move completed the todo")
+
+    // Scenario 8: The first write stores the default workflow with the IDs reads returned
+    fmt.Println("\nScenario 8: Testing a move on a team without stored statuses")
+    defaults := make([]domain.TeamStatus, len(res.Statuses))
+    for i, status := range res.Statuses {
+        defaults[i] = domain.TeamStatus{ID: status.ID, TeamID: "new-team", Name: status.Name, Position: status.Position, Terminal: status.Terminal}
+    }
+    repo.On("EnsureTeamWorkflow", ctx, "new-team", defaults).Return(defaults, nil)
+    repo.On("GetStatusTransitions", ctx, "new-team").Return([]domain.StatusTransition{}, nil)
+    repo.On("GetTeamTodoPlacements", ctx, "new-team").Return([]domain.TeamTodoPlacement{}, nil)
+    teamTodoRepo.On("GetTeamTodos", ctx, "new-team").Return([]domain.TeamTodo{{ID: "todo-9", Task: "Plan", TeamID: "new-team"}}, nil)
+    teamTodoRepo.On("MoveTeamTodo", ctx, "todo-9", "new-team", res.Statuses[1].ID, false, []string{"todo-9"}, 1).Return(true, nil)
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "todo-9", "new-team").Return(&domain.TeamTodo{ID: "todo-9", Task: "Plan", TeamID: "new-team", Version: 2}, nil)
+    moved, err = workflowService.MoveTeamTodo(ctx, &dto.MoveTeamTodoRequest{ID: "todo-9", TeamID: "new-team", Status: "In Progress", Version: 1})
+    assert.NoError(t, err)
+    assert.Equal(t, res.Statuses[1].ID, moved.Status.ID)
+    repo.AssertCalled(t, "EnsureTeamWorkflow", ctx, "new-team", defaults)
+    fmt.Println("✅ Default statuses stored before the move")
+}
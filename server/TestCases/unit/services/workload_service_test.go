@@ -0,0 +1,132 @@
+package services_test
+
+import (
+    "context"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/TestCases/mocks"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/workload"
+    "github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadService(t *testing.T) {
+    fmt.Println("\n=== RUNNING TEST: TestWorkloadService ===")
+    fmt.Println("Testing team todo estimates and the workload report")
+
+    ctx := context.Background()
+    teamID := "team-1"
+
+    repo := new(mocks.MockWorkloadRepository)
+    teamTodoRepo := new(mocks.MockTeamTodoRepository)
+    workloadService := workload.NewWorkloadService(repo, teamTodoRepo)
+
+    // Scenario 1: Estimates need a known unit and a positive value
+    fmt.Println("Scenario 1: Testing estimate validation")
+    _, err := workloadService.SetEstimate(ctx, &dto.SetEstimateRequest{TodoID: "todo-1", TeamID: teamID, Value: 3, Unit: "days"})
+    assert.Contains(t, err.Error(), "invalid estimate")
+    _, err = workloadService.SetEstimate(ctx, &dto.SetEstimateRequest{TodoID: "todo-1", TeamID: teamID, Value: 0})
+    assert.Contains(t, err.Error(), "invalid estimate")
+    repo.AssertNotCalled(t, "SetTeamTodoEstimate")
+    fmt.Println("✅ Invalid estimates rejected")
+
+    // Scenario 2: A valid estimate defaults to hours
+    fmt.Println("\nScenario 2: Testing setting an estimate")
+    teamTodoRepo.On("GetTeamTodoByID", ctx, "todo-1", teamID).Return(&domain.TeamTodo{ID: "todo-1", TeamID: teamID}, nil)
+    repo.On("SetTeamTodoEstimate", ctx, teamID, domain.Estimate{TodoID: "todo-1", Value: 2.5, Unit: domain.EstimateUnitHours}).Return(nil)
+    res, err := workloadService.SetEstimate(ctx, &dto.SetEstimateRequest{TodoID: "todo-1", TeamID: teamID, Value: 2.5})
+    assert.NoError(t, err)
+    assert.Equal(t, "hours", res.Unit)
+    fmt.Println("✅ Estimate stored")
+
+    // Scenario 3: The report counts and totals per member, busiest first
+    fmt.Println("\nScenario 3: Testing the workload report")
+    past := time.Now().UTC().AddDate(0, 0, -3)
+    future := time.Now().UTC().AddDate(0, 0, 3)
+    repo.On("GetTeamMemberDetails", ctx, teamID).Return([]domain.TeamMemberDetails{
+        {UserID: "u-alice", Username: "alice", IsAdmin: true},
+        {UserID: "u-bob", Username: "bob"},
+        {UserID: "u-carol", Username: "carol"},
+    }, nil)
+    repo.On("GetTeamWorkloadTodos", ctx, teamID).Return([]domain.WorkloadTodo{
+        {ID: "t-1", AssignedTo: "u-bob", Date: past, Estimate: &domain.Estimate{Value: 4, Unit: "hours"}},
+        {ID: "t-2", AssignedTo: "u-bob", Date: future, Estimate: &domain.Estimate{Value: 3, Unit: "points"}},
+        {ID: "t-3", AssignedTo: "u-bob", Done: true, Date: past, Estimate: &domain.Estimate{Value: 1.5, Unit: "hours"}},
+        {ID: "t-4", AssignedTo: "u-alice", Estimate: &domain.Estimate{Value: 1, Unit: "hours"}},
+        {ID: "t-5", AssignedTo: "u-alice"},
+        {ID: "t-6", AssignedTo: "u-gone", AssigneeName: "dave", Date: past},
+        {ID: "t-7", Date: past},
+    }, nil)
+    report, err := workloadService.GetWorkload(ctx, teamID, time.UTC)
+    assert.NoError(t, err)
+    assert.Len(t, report.Members, 4)
+
+    bob := report.Members[0]
+    assert.Equal(t, "bob", bob.Username)
+    assert.Equal(t, 2, bob.Open)
+    assert.Equal(t, 1, bob.Overdue)
+    assert.Equal(t, 1, bob.Completed)
+    assert.Equal(t, dto.EstimateTotals{Hours: 4, Points: 3}, bob.OpenEstimate)
+    assert.Equal(t, dto.EstimateTotals{Hours: 1.5}, bob.CompletedEstimate)
+
+    alice := report.Members[1]
+    assert.Equal(t, "alice", alice.Username)
+    assert.True(t, alice.IsAdmin)
+    assert.Equal(t, 1, alice.Unestimated)
+    assert.Equal(t, 0, alice.Overdue)
+
+    dave := report.Members[2]
+    assert.Equal(t, "dave", dave.Username)
+    assert.False(t, dave.IsMember)
+    assert.Equal(t, 1, dave.Overdue)
+
+    assert.Equal(t, "carol", report.Members[3].Username)
+    assert.Equal(t, 0, report.Members[3].Open)
+    assert.Equal(t, 1, report.Unassigned.Open)
+    assert.Equal(t, 1, report.Unassigned.Overdue)
+    fmt.Println("✅ Workload counted per member with former members and unassigned todos")
+
+    // Scenario 4: Overdue is judged on the wall clock of the caller's timezone
+    fmt.Println("\nScenario 4: Testing overdue in the caller's timezone")
+    tokyo, _ := time.LoadLocation("Asia/Tokyo")
+    honolulu, _ := time.LoadLocation("Pacific/Honolulu")
+    due := users.LocalNow(tokyo).Add(-time.Hour)
+    repo = new(mocks.MockWorkloadRepository)
+    repo.On("GetTeamMemberDetails", ctx, teamID).Return([]domain.TeamMemberDetails{}, nil)
+    repo.On("GetTeamWorkloadTodos", ctx, teamID).Return([]domain.WorkloadTodo{
+        {ID: "t-1", Date: due.Truncate(24 * time.Hour), Time: time.Date(2000, 1, 1, due.Hour(), due.Minute(), 0, 0, time.UTC)},
+    }, nil)
+    workloadService = workload.NewWorkloadService(repo, teamTodoRepo)
+    report, err = workloadService.GetWorkload(ctx, teamID, tokyo)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, report.Unassigned.Overdue)
+    report, err = workloadService.GetWorkload(ctx, teamID, honolulu)
+    assert.NoError(t, err)
+    assert.Equal(t, 0, report.Unassigned.Overdue)
+    fmt.Println("✅ A todo due an hour ago in Tokyo is not yet due in Honolulu")
+
+    // Scenario 5: A shared todo counts for every assignee by their own part
+    fmt.Println("\nScenario 5: Testing a todo with several assignees")
+    repo = new(mocks.MockWorkloadRepository)
+    repo.On("GetTeamMemberDetails", ctx, teamID).Return([]domain.TeamMemberDetails{
+        {UserID: "u-alice", Username: "alice"},
+        {UserID: "u-bob", Username: "bob"},
+    }, nil)
+    repo.On("GetTeamWorkloadTodos", ctx, teamID).Return([]domain.WorkloadTodo{
+        {ID: "t-1", AssignedTo: "u-alice", Done: true, Estimate: &domain.Estimate{Value: 2, Unit: "hours"}},
+        {ID: "t-1", AssignedTo: "u-bob", Estimate: &domain.Estimate{Value: 2, Unit: "hours"}},
+    }, nil)
+    workloadService = workload.NewWorkloadService(repo, teamTodoRepo)
+    report, err = workloadService.GetWorkload(ctx, teamID, time.UTC)
+    assert.NoError(t, err)
+    assert.Equal(t, "bob", report.Members[0].Username)
+    assert.Equal(t, 1, report.Members[0].Open)
+    assert.Equal(t, dto.EstimateTotals{Hours: 2}, report.Members[0].OpenEstimate)
+    assert.Equal(t, 1, report.Members[1].Completed)
+    assert.Equal(t, dto.EstimateTotals{Hours: 2}, report.Members[1].CompletedEstimate)
+    fmt.Println("✅ Alice's finished part is completed while Bob's stays open")
+}
@@ -6,6 +6,8 @@ import (
     "net/http"
     "strings"
     "time"
+    // Embed the tz database so user timezones resolve on minimal images
+    _ "time/tzdata"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/infra"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler"
     "github.com/gorilla/mux"
@@ -71,8 +73,9 @@ func main() {
     // Configure CORS
     c := cors.New(cors.Options{
         AllowedOrigins:   []string{"http://localhost:5173"},
-        AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-        AllowedHeaders:   []string{"Authorization", "Content-Type"},
+        AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+        AllowedHeaders:   []string{"Authorization", "Content-Type", "If-Match", "If-None-Match", "X-Timezone"},
+        ExposedHeaders:   []string{"ETag"},
         AllowCredentials: true,
     })
 
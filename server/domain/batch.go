@@ -0,0 +1,87 @@
+package domain
+
+import (
+    "time"
+)
+
+// Batch execution modes
+const (
+    BatchModeAtomic  = "atomic"
+    BatchModePartial = "partial"
+)
+
+// Batch operation types
+const (
+    BatchOpCreate   = "create"
+    BatchOpUpdate   = "update"
+    BatchOpComplete = "complete"
+    BatchOpDelete   = "delete"
+)
+
+// Batch result statuses
+const (
+    BatchStatusOK         = "ok"
+    BatchStatusFailed     = "failed"
+    BatchStatusRolledBack = "rolled_back"
+    BatchStatusSkipped    = "skipped"
+)
+
+// BatchOperation is a single create/update/complete/delete step in a batch request
+type BatchOperation struct {
+    Op          string
+    ID          string
+    Task        string
+    Description string
+    Done        bool
+    Important   bool
+    AssignedTo  string
+    Date        time.Time
+    Time        time.Time
+    Version     int // expected version for update/complete/delete; 0 skips the check
+}
+
+// BatchResult reports the outcome of one operation in a batch
+type BatchResult struct {
+    Index  int
+    Op     string
+    ID     string
+    Status string
+    Error  string
+}
+
+// RunBatch applies each operation in order and collects the per-operation results.
+// In atomic mode nothing runs after the first failure and earlier successes are
+// reported as rolled back. It returns whether the caller should commit.
+func RunBatch(ops []BatchOperation, atomic bool, apply func(op BatchOperation) (string, error)) ([]BatchResult, bool) {
+    results := make([]BatchResult, len(ops))
+    failed := false
+
+    for i, op := range ops {
+        results[i] = BatchResult{Index: i, Op: op.Op, ID: op.ID}
+
+        if failed && atomic {
+            results[i].Status = BatchStatusSkipped
+            continue
+        }
+
+        id, err := apply(op)
+        if err != nil {
+            failed = true
+            results[i].Status = BatchStatusFailed
+            results[i].Error = err.Error()
+            continue
+        }
+        results[i].ID = id
+        results[i].Status = BatchStatusOK
+    }
+
+    if failed && atomic {
+        for i := range results {
+            if results[i].Status == BatchStatusOK {
+                results[i].Status = BatchStatusRolledBack
+            }
+        }
+        return results, false
+    }
+    return results, true
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+    "context"
+)
+
+// CalendarFeedRepository defines the interface for iCalendar feed token persistence.
+// Tokens are stored hashed, so callers pass and look up the hash rather than the token.
+type CalendarFeedRepository interface {
+    // SetCalendarFeedToken replaces the user's feed token, revoking any previous one
+    SetCalendarFeedToken(ctx context.Context, userID, tokenHash string) error
+    GetCalendarFeedUserID(ctx context.Context, tokenHash string) (string, error)
+    // DeleteCalendarFeedToken revokes the user's feed token and reports whether one existed
+    DeleteCalendarFeedToken(ctx context.Context, userID string) (bool, error)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+    "context"
+)
+
+// Dependency is a blocked-by link: TodoID cannot be completed until BlockedByID is done
+type Dependency struct {
+    TodoID      string
+    BlockedByID string
+}
+
+// DependencyNode is a todo or team todo as the dependency rules see it
+type DependencyNode struct {
+    ID   string
+    Task string
+    Done bool
+}
+
+// DependencyCheck validates a new link against the todos and links of one user
+// or team. A non-nil error stops the link from being stored.
+type DependencyCheck func(nodes []DependencyNode, edges []Dependency) error
+
+// DependencyRepository defines the interface for blocked-by link persistence.
+// Personal links join todos of one user; team links join todos of one team.
+type DependencyRepository interface {
+    // AddTodoDependency locks the user's todos and links, runs check on them and
+    // stores the link if it passes, all in one transaction
+    AddTodoDependency(ctx context.Context, userID, todoID, blockedByID string, check DependencyCheck) error
+    // RemoveTodoDependency deletes a link and reports whether it existed
+    RemoveTodoDependency(ctx context.Context, todoID, blockedByID string) (bool, error)
+    // GetTodoDependencies returns every link between the user's todos
+    GetTodoDependencies(ctx context.Context, userID string) ([]Dependency, error)
+    // AddTeamTodoDependency does the same for the team's todos and links
+    AddTeamTodoDependency(ctx context.Context, teamID, todoID, blockedByID string, check DependencyCheck) error
+    RemoveTeamTodoDependency(ctx context.Context, teamID, todoID, blockedByID string) (bool, error)
+    GetTeamTodoDependencies(ctx context.Context, teamID string) ([]Dependency, error)
+}
@@ -15,16 +15,136 @@ type Routine struct {
     CreatedAt    time.Time `json:"createdAt"`
     UpdatedAt    time.Time `json:"updatedAt"`
     IsActive     bool      `json:"isActive"`
+    Version      int       `json:"version"`
+}
+
+// RoutineCheckin reports whether a routine scheduled on a day was done on a date
+type RoutineCheckin struct {
+    RoutineID string
+    TaskID    string
+    CheckedIn bool
+}
+
+// RoutineSlot is a named time of day, e.g. "gym" at "06:30". A routine's
+// ScheduleType holds the name of one of its user's slots.
+type RoutineSlot struct {
+    ID        string
+    UserID    string
+    Name      string
+    StartTime string // "15:04"
+}
+
+// DefaultRoutineSlots returns the slots every user starts with. They match the
+// fixed schedule types routines used before slots could be customised.
+func DefaultRoutineSlots() []RoutineSlot {
+    return []RoutineSlot{
+        {Name: "morning", StartTime: "08:00"},
+        {Name: "noon", StartTime: "12:00"},
+        {Name: "evening", StartTime: "18:00"},
+        {Name: "night", StartTime: "21:00"},
+    }
+}
+
+// RoutineSchedule is one day and slot a task repeats in
+type RoutineSchedule struct {
+    Day          string
+    ScheduleType string
+}
+
+// RoutineScheduleChanges counts what replacing a task's weekly schedule did
+type RoutineScheduleChanges struct {
+    Created     int
+    Reactivated int
+    Deactivated int
+}
+
+// WeekRoutine is an active routine with the task it repeats
+type WeekRoutine struct {
+    RoutineID    string
+    Day          string
+    ScheduleType string
+    TaskID       string
+    Task         string
+    Description  string
+    Important    bool
+}
+
+// RoutinePause skips routines from StartDate through EndDate, both dates
+// included. A pause without RoutineID covers all of the user's routines;
+// otherwise Day, TaskID and ScheduleType describe the paused routine.
+type RoutinePause struct {
+    ID           string
+    UserID       string
+    RoutineID    string
+    Day          string
+    TaskID       string
+    ScheduleType string
+    StartDate    time.Time
+    EndDate      time.Time
+    Reason       string
+}
+
+// Covers reports whether date falls inside the pause
+func (p RoutinePause) Covers(date time.Time) bool {
+    return !date.Before(p.StartDate) && !date.After(p.EndDate)
+}
+
+// PausedRoutines reports whether pauses cover all routines on date, and
+// otherwise which routines are paused by ID
+func PausedRoutines(pauses []RoutinePause, date time.Time) (bool, map[string]bool) {
+    paused := make(map[string]bool)
+    for _, pause := range pauses {
+        if !pause.Covers(date) {
+            continue
+        }
+        if pause.RoutineID == "" {
+            return true, nil
+        }
+        paused[pause.RoutineID] = true
+    }
+    return false, paused
 }
 
 // RoutineRepository defines the interface for routine persistence operations
 type RoutineRepository interface {
     CreateRoutine(ctx context.Context, day, scheduleType, taskID, userID string, isActive bool) (string, error)
-    UpdateRoutineStatus(ctx context.Context, id string, isActive bool) error
-    UpdateRoutineDay(ctx context.Context, id, day string) error
+    UpdateRoutineStatus(ctx context.Context, id string, isActive bool, expectedVersion int) error
+    UpdateRoutineDay(ctx context.Context, id, day string, expectedVersion int) error
     GetRoutinesByTaskID(ctx context.Context, taskID string) ([]Routine, error)
     GetDailyRoutines(ctx context.Context, day, scheduleType, userID string) ([]Todo, error)
     DeleteRoutinesByTaskID(ctx context.Context, taskID string) error
     CreateOrUpdateRoutines(ctx context.Context, taskID string, schedules []string, day string, userID string) ([]Routine, error)
-
+    // GetRoutineByID returns the routine without its timestamps
+    GetRoutineByID(ctx context.Context, id string) (*Routine, error)
+    // CheckInRoutine records that the routine was done on date; repeating it is a no-op
+    CheckInRoutine(ctx context.Context, routineID, userID string, date time.Time) error
+    // UncheckRoutine removes a check-in and reports whether it existed
+    UncheckRoutine(ctx context.Context, routineID string, date time.Time) (bool, error)
+    // GetDailyRoutineCheckins lists the active routines of a day and schedule type
+    // with whether each was checked in on date
+    GetDailyRoutineCheckins(ctx context.Context, day, scheduleType, userID string, date time.Time) ([]RoutineCheckin, error)
+    // GetRoutineScheduleDays returns the active days of the user's routines for a task and schedule type
+    GetRoutineScheduleDays(ctx context.Context, taskID, scheduleType, userID string) ([]string, error)
+    // GetRoutineCheckinDates returns every check-in date of those routines, oldest first
+    GetRoutineCheckinDates(ctx context.Context, taskID, scheduleType, userID string) ([]time.Time, error)
+    // GetRoutineSlots returns the user's slots ordered by start time
+    GetRoutineSlots(ctx context.Context, userID string) ([]RoutineSlot, error)
+    // CreateRoutineSlots stores all slots or none, failing with "slot already exists"
+    // when a name is taken
+    CreateRoutineSlots(ctx context.Context, slots []RoutineSlot) error
+    // UpdateRoutineSlot saves the slot and moves the routines of oldName to its new name
+    UpdateRoutineSlot(ctx context.Context, slot RoutineSlot, oldName string) error
+    DeleteRoutineSlot(ctx context.Context, id, userID string) (bool, error)
+    // CountSlotRoutines counts the user's routines scheduled in the named slot
+    CountSlotRoutines(ctx context.Context, userID, name string) (int, error)
+    // SetWeeklyRoutines makes schedule the complete set of active routines of the
+    // user's task in one transaction. Routines outside it are deactivated, not
+    // deleted, so their check-ins are kept.
+    SetWeeklyRoutines(ctx context.Context, taskID, userID string, schedule []RoutineSchedule) (RoutineScheduleChanges, error)
+    // GetWeekRoutines returns every active routine of the user
+    GetWeekRoutines(ctx context.Context, userID string) ([]WeekRoutine, error)
+    CreateRoutinePause(ctx context.Context, pause RoutinePause) error
+    // GetRoutinePauses returns every pause of the user, earliest first
+    GetRoutinePauses(ctx context.Context, userID string) ([]RoutinePause, error)
+    DeleteRoutinePause(ctx context.Context, id, userID string) (bool, error)
 }
\ No newline at end of file
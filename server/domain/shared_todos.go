@@ -5,7 +5,27 @@ import (
     "time"
 )
 
-// SharedTodo entity
+// Share permissions. Recipients can always see a shared todo; with edit
+// permission they can also update and complete it.
+const (
+    SharePermissionView = "view"
+    SharePermissionEdit = "edit"
+)
+
+// ValidSharePermission reports whether p is a known share permission
+func ValidSharePermission(p string) bool {
+    return p == SharePermissionView || p == SharePermissionEdit
+}
+
+// Share statuses. A share waits in the recipient's inbox until they accept it.
+const (
+    ShareStatusPending  = "pending"
+    ShareStatusAccepted = "accepted"
+)
+
+// SharedTodo entity. TodoID links the share to the sharer's todo, whose task,
+// done flag and other fields the share shows; shares created before linking that
+// could not be matched to their todo have no TodoID and keep their own copy.
 type SharedTodo struct {
     ID          string
     Task        string
@@ -16,6 +36,35 @@ type SharedTodo struct {
     Date        time.Time
     Time        time.Time
     SharedBy    string
+    TodoID      string
+    Version     int
+    Permission  string
+    Status      string
+}
+
+// CanEdit reports whether the recipient may change the shared todo
+func (t SharedTodo) CanEdit() bool {
+    return t.Permission == SharePermissionEdit
+}
+
+// Accepted reports whether the recipient has accepted the share
+func (t SharedTodo) Accepted() bool {
+    return t.Status == ShareStatusAccepted
+}
+
+// Outcomes of sharing a todo with one recipient
+const (
+    ShareResultShared        = "shared"
+    ShareResultAlreadyShared = "already_shared"
+    ShareResultBlocked       = "blocked"
+    ShareResultNotFound      = "not_found"
+    ShareResultSelf          = "self"
+)
+
+// ShareBlock is a sender whose shares a user no longer receives
+type ShareBlock struct {
+    UserID   string
+    Username string
 }
 
 // SharedTodoRepository defines the interface for shared todo persistence operations
@@ -23,7 +72,25 @@ type SharedTodoRepository interface {
     CreateSharedTodo(ctx context.Context, task, description string, done, important bool, userID, sharedBy string) (string, error)
     GetSharedTodos(ctx context.Context, userID string) ([]SharedTodo, error)
     GetSharedByMeTodos(ctx context.Context, sharedBy string) ([]SharedTodo, error)
-    ShareTodo(ctx context.Context, originalTodoID string, recipientUserID string, sharedBy string) error
+    ShareTodo(ctx context.Context, originalTodoID string, recipientUserID string, sharedBy string, permission string) error
+    // ShareTodoWithUsers shares a todo with every recipient in one transaction and
+    // returns each recipient's outcome: shared, already_shared or blocked
+    ShareTodoWithUsers(ctx context.Context, todoID string, recipientUserIDs []string, sharedBy, permission string) (map[string]string, error)
     // Check if a todo is already shared with a user
     IsSharedWithUser(ctx context.Context, todoID string, userID string) (bool, error)
+    // GetSharedTodoByID returns a todo shared with userID, or "shared todo not found"
+    GetSharedTodoByID(ctx context.Context, id, userID string) (*SharedTodo, error)
+    // UpdateSharePermission and DeleteSharedTodo only touch shares made by sharedBy
+    // and report whether the share was found
+    UpdateSharePermission(ctx context.Context, id, sharedBy, permission string) (bool, error)
+    DeleteSharedTodo(ctx context.Context, id, sharedBy string) (bool, error)
+    // AcceptSharedTodo accepts a pending share and reports whether one was found
+    AcceptSharedTodo(ctx context.Context, id, userID string) (bool, error)
+    // DeclineSharedTodo removes a share from the recipient's inbox or lists
+    DeclineSharedTodo(ctx context.Context, id, userID string) (bool, error)
+    // BlockSender stops shares from senderID and removes the ones userID already has
+    BlockSender(ctx context.Context, userID, senderID string) error
+    UnblockSender(ctx context.Context, userID, senderID string) (bool, error)
+    GetBlockedSenders(ctx context.Context, userID string) ([]ShareBlock, error)
+    IsSenderBlocked(ctx context.Context, userID, senderID string) (bool, error)
 }
\ No newline at end of file
@@ -5,22 +5,145 @@ import (
     "time"
 )
 
+// Completion modes decide when a todo with several assignees is done
+const (
+    CompletionModeAll = "all" // once every assignee has finished their part
+    CompletionModeAny = "any" // once any assignee has finished their part
+)
+
+// ValidCompletionMode reports whether mode is a known completion mode
+func ValidCompletionMode(mode string) bool {
+    return mode == CompletionModeAll || mode == CompletionModeAny
+}
+
 type TeamTodo struct {
-    ID          string
-    Task        string
-    Description string
-    Done        bool
-    Important   bool
-    TeamID      string
-    AssignedTo  string
-    Date        time.Time
-    Time        time.Time
+    ID             string
+    Task           string
+    Description    string
+    Done           bool
+    Important      bool
+    TeamID         string
+    AssignedTo     string // primary assignee, also listed in Assignees
+    Date           time.Time
+    Time           time.Time
+    Version        int
+    CompletionMode string
+    Assignees      []TeamTodoAssignee
+}
+
+// TeamTodoAssignee is one member assigned to a team todo and whether they finished their part
+type TeamTodoAssignee struct {
+    UserID   string
+    Username string
+    Done     bool
+}
+
+// IsAssignedTo reports whether the user is one of the todo's assignees
+func (t TeamTodo) IsAssignedTo(userID string) bool {
+    if t.AssignedTo == userID {
+        return true
+    }
+    for _, assignee := range t.Assignees {
+        if assignee.UserID == userID {
+            return true
+        }
+    }
+    return false
+}
+
+// AssigneesDone reports whether the assignees' parts complete the todo under mode.
+// A todo without assignees is never completed this way.
+func AssigneesDone(mode string, assignees []TeamTodoAssignee) bool {
+    if len(assignees) == 0 {
+        return false
+    }
+    for _, assignee := range assignees {
+        if assignee.Done && mode == CompletionModeAny {
+            return true
+        }
+        if !assignee.Done && mode != CompletionModeAny {
+            return false
+        }
+    }
+    return mode != CompletionModeAny
+}
+
+// Due returns when a dated todo falls due: its date at its time, or the end of
+// the day when it has no time. It reports false for a todo without a date.
+func (t TeamTodo) Due() (time.Time, bool) {
+    if t.Date.IsZero() {
+        return time.Time{}, false
+    }
+    year, month, day := t.Date.Date()
+    if t.Time.IsZero() {
+        return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC), true
+    }
+    hour, min, sec := t.Time.Clock()
+    return time.Date(year, month, day, hour, min, sec, 0, time.UTC), true
+}
+
+// Overdue reports whether an open todo was due before now. Like the stored
+// dates, now is a wall clock time labelled UTC.
+func (t TeamTodo) Overdue(now time.Time) bool {
+    due, ok := t.Due()
+    return ok && !t.Done && due.Before(now)
+}
+
+// TeamTodoDue holds the due date and time supplied in a full update of a team
+// todo. Nil fields keep the current value.
+type TeamTodoDue struct {
+    Date      *time.Time
+    ClearDate bool
+    Time      *time.Time
+    ClearTime bool
+}
+
+// TeamTodoPatch holds the fields supplied in a partial update of a team todo
+type TeamTodoPatch struct {
+    TodoPatch
+    AssignedTo      *string
+    ClearAssignedTo bool
+}
+
+// AssignedTeamTodo is a team todo assigned to a user, with the name of its team
+type AssignedTeamTodo struct {
+    TeamTodo
+    TeamName string
+}
+
+// TeamTodoAssignment records one change of a team todo's assignee.
+// An empty AssignedTo means the todo was unassigned.
+type TeamTodoAssignment struct {
+    ID                   int64
+    TodoID               string
+    TeamID               string
+    AssignedTo           string
+    AssignedToName       string
+    PreviousAssignee     string
+    PreviousAssigneeName string
+    AssignedBy           string
+    AssignedByName       string
+    AssignedAt           time.Time
 }
 
 // TeamTodoRepository defines the interface for team todo persistence operations
 type TeamTodoRepository interface {
-    CreateTeamTodo(ctx context.Context, task, description string, done, important bool, teamID, assignedTo string) (string, error)
+    CreateTeamTodo(ctx context.Context, task, description string, done, important bool, teamID, assignedTo, createdBy string, date, time time.Time) (string, error)
+    CreateTeamTodoWithID(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo, createdBy string, date, time time.Time) error
     GetTeamTodos(ctx context.Context, teamID string) ([]TeamTodo, error)
-    UpdateTeamTodo(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo string) (bool, error)
-    DeleteTeamTodo(ctx context.Context, id, teamID string) (bool, error)
+    GetTeamTodoByID(ctx context.Context, id, teamID string) (*TeamTodo, error)
+    // GetTeamTodoTeamID returns the team that owns a todo, whichever team that is
+    GetTeamTodoTeamID(ctx context.Context, id string) (string, error)
+    UpdateTeamTodo(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo, updatedBy string, due TeamTodoDue, expectedVersion int) (bool, error)
+    DeleteTeamTodo(ctx context.Context, id, teamID string, expectedVersion int) (bool, error)
+    PatchTeamTodo(ctx context.Context, id, teamID, updatedBy string, patch TeamTodoPatch, expectedVersion int) (bool, error)
+    ExecuteBatch(ctx context.Context, teamID, userID string, ops []BatchOperation, atomic bool) ([]BatchResult, bool, error)
+    ReassignTeamTodo(ctx context.Context, id, teamID, assignedTo, assignedBy string, expectedVersion int) (bool, error)
+    SetTeamTodoAssignees(ctx context.Context, id, teamID string, userIDs []string, completionMode, changedBy string, expectedVersion int) (bool, error)
+    SetAssigneeDone(ctx context.Context, id, teamID, userID string, done bool) (bool, error)
+    // MoveTeamTodo puts a todo into a board status, sets its done flag and renumbers
+    // that status's column in the given order
+    MoveTeamTodo(ctx context.Context, id, teamID, statusID string, done bool, order []string, expectedVersion int) (bool, error)
+    GetTeamTodoAssignments(ctx context.Context, id, teamID string) ([]TeamTodoAssignment, error)
+    GetAssignedTeamTodos(ctx context.Context, userID string) ([]AssignedTeamTodo, error)
 }
\ No newline at end of file
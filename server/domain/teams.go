@@ -16,4 +16,6 @@ type TeamRepository interface {
     CreateTeam(ctx context.Context, name, password, adminID string) (string, error)
     GetTeamsByAdminID(ctx context.Context, adminID string) ([]Team, error)
     GetTeamByID(ctx context.Context, id string) (Team, error)
+    // GetTeams returns every team the user is a member of
+    GetTeams(ctx context.Context, userID string) ([]Team, error)
 }
\ No newline at end of file
@@ -0,0 +1,55 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// TimeEntry is time a user spent on a todo, or on a team todo when TeamID is set.
+// A zero EndedAt marks the user's running timer.
+type TimeEntry struct {
+    ID        string
+    UserID    string
+    Username  string
+    TodoID    string
+    TeamID    string
+    Task      string
+    StartedAt time.Time
+    EndedAt   time.Time
+    Note      string
+}
+
+// Running reports whether the entry is a timer that has not been stopped
+func (e TimeEntry) Running() bool {
+    return e.EndedAt.IsZero()
+}
+
+// Duration returns the tracked time, counting a running timer up to now
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+    end := e.EndedAt
+    if e.Running() {
+        end = now
+    }
+    if end.Before(e.StartedAt) {
+        return 0
+    }
+    return end.Sub(e.StartedAt)
+}
+
+// TimeEntryRepository defines the interface for time entry persistence operations
+type TimeEntryRepository interface {
+    // CreateTimeEntry stores an entry. An entry without EndedAt starts a timer and
+    // fails with "timer already running" when the user already has one.
+    CreateTimeEntry(ctx context.Context, entry TimeEntry) error
+    // StopTimer ends the user's running timer at endedAt and returns it,
+    // or fails with "no running timer"
+    StopTimer(ctx context.Context, userID string, endedAt time.Time) (*TimeEntry, error)
+    // GetRunningTimer returns nil when the user has no running timer
+    GetRunningTimer(ctx context.Context, userID string) (*TimeEntry, error)
+    DeleteTimeEntry(ctx context.Context, id, userID string) (bool, error)
+    GetTodoTimeEntries(ctx context.Context, todoID string) ([]TimeEntry, error)
+    GetTeamTodoTimeEntries(ctx context.Context, teamID, todoID string) ([]TimeEntry, error)
+    // GetUserTimeEntries and GetTeamTimeEntries return entries started in [from, to)
+    GetUserTimeEntries(ctx context.Context, userID string, from, to time.Time) ([]TimeEntry, error)
+    GetTeamTimeEntries(ctx context.Context, teamID string, from, to time.Time) ([]TimeEntry, error)
+}
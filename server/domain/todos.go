@@ -14,6 +14,30 @@ type Todo struct {
     UserID      string
     Date        time.Time
     Time        time.Time
+    Version     int
+}
+
+// TodoRecurrence is a repeat rule kept on a todo when routines cannot express it,
+// such as "every month" or "every 2 weeks"
+type TodoRecurrence struct {
+    TodoID    string
+    Frequency string
+    Interval  int
+    Weekdays  []string
+}
+
+// TodoPatch holds the fields supplied in a partial update. A nil pointer means the
+// field was absent; the Clear flags mark nullable fields explicitly set to null.
+type TodoPatch struct {
+    Task             *string
+    Description      *string
+    ClearDescription bool
+    Done             *bool
+    Important        *bool
+    Date             *time.Time
+    ClearDate        bool
+    Time             *time.Time
+    ClearTime        bool
 }
 
 // TodoRepository defines the interface for todo persistence operations
@@ -23,8 +47,18 @@ type TodoRepository interface {
     
     // Existing methods
     CreateTodo(ctx context.Context, task, description string, done, important bool, userID string, date, time time.Time) (string, error)
+    // CreateTodoWithID stores a todo under a caller-chosen ID, used when restoring an export
+    CreateTodoWithID(ctx context.Context, id, task, description string, done, important bool, userID string, date, time time.Time) error
     GetTodosByUserID(ctx context.Context, userID string) ([]Todo, error)
-    UpdateTodo(ctx context.Context, id, task, description string, done, important bool, userID string) (bool, error)
-    DeleteTodo(ctx context.Context, id, userID string) (bool, error)
-    UndoTodo(ctx context.Context, id, userID string) (bool, error)
+    // The mutating methods take the version the caller last saw and fail with a
+    // version mismatch when the row has changed since. A version of 0 skips the check.
+    UpdateTodo(ctx context.Context, id, task, description string, done, important bool, userID string, expectedVersion int) (bool, error)
+    DeleteTodo(ctx context.Context, id, userID string, expectedVersion int) (bool, error)
+    UndoTodo(ctx context.Context, id, userID string, expectedVersion int) (bool, error)
+    PatchTodo(ctx context.Context, id, userID string, patch TodoPatch, expectedVersion int) (bool, error)
+    // ExecuteBatch runs all operations in a single transaction. When atomic is true
+    // any failure rolls back the whole batch; otherwise failures are reported per item.
+    ExecuteBatch(ctx context.Context, userID string, ops []BatchOperation, atomic bool) ([]BatchResult, bool, error)
+    // SetTodoDetails stores the tags and, unless nil, the recurrence of a todo in one transaction
+    SetTodoDetails(ctx context.Context, todoID string, tags []string, recurrence *TodoRecurrence) error
 }
\ No newline at end of file
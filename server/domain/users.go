@@ -8,10 +8,13 @@ type User struct {
     ID       string
     Username string
     Password string
+    Timezone string // IANA name, e.g. "Europe/Berlin"
 }
 
 // UserRepository defines the interface for user persistence operations
 type UserRepository interface {
     CreateUser(ctx context.Context, username, password string) (string, error)
     GetUserByUsername(ctx context.Context, username string) (User, error)
+    GetUserTimezone(ctx context.Context, userID string) (string, error)
+    SetUserTimezone(ctx context.Context, userID, timezone string) error
 }
\ No newline at end of file
@@ -0,0 +1,81 @@
+package domain
+
+import (
+    "context"
+)
+
+// TeamStatus is one column of a team's workflow. Todos in a terminal status count as done.
+type TeamStatus struct {
+    ID       string
+    TeamID   string
+    Name     string
+    Position int
+    Terminal bool
+}
+
+// StatusTransition allows moving a todo from one status to another. A team
+// without any transitions allows every move.
+type StatusTransition struct {
+    FromStatusID string
+    ToStatusID   string
+}
+
+// TeamTodoPlacement records the status and board position of a team todo
+type TeamTodoPlacement struct {
+    TodoID   string
+    StatusID string
+    Position int
+}
+
+// PlaceForDone returns where a todo goes on the board after its done flag changed
+// outside of a move: the end of the first of statuses, given in board order, whose
+// terminal flag matches done. It reports false when the todo's status already matches or
+// the todo was never placed, since unplaced todos are shown there anyway.
+func PlaceForDone(statuses []TeamStatus, placements []TeamTodoPlacement, todoID string, done bool) (TeamTodoPlacement, bool) {
+    terminal := make(map[string]bool, len(statuses))
+    for _, status := range statuses {
+        terminal[status.ID] = status.Terminal
+    }
+    placed := false
+    for _, p := range placements {
+        if p.TodoID != todoID {
+            continue
+        }
+        if isTerminal, known := terminal[p.StatusID]; known && isTerminal == done {
+            return TeamTodoPlacement{}, false
+        }
+        placed = true
+    }
+    if !placed || len(statuses) == 0 {
+        return TeamTodoPlacement{}, false
+    }
+
+    target := statuses[0]
+    for _, status := range statuses {
+        if status.Terminal == done {
+            target = status
+            break
+        }
+    }
+    place := TeamTodoPlacement{TodoID: todoID, StatusID: target.ID}
+    for _, p := range placements {
+        if p.StatusID == target.ID && p.TodoID != todoID && p.Position >= place.Position {
+            place.Position = p.Position + 1
+        }
+    }
+    return place, true
+}
+
+// WorkflowRepository defines the interface for team workflow persistence
+type WorkflowRepository interface {
+    GetTeamStatuses(ctx context.Context, teamID string) ([]TeamStatus, error)
+    GetStatusTransitions(ctx context.Context, teamID string) ([]StatusTransition, error)
+    // ReplaceTeamWorkflow stores the given statuses and transitions in one transaction.
+    // Statuses keep their IDs; existing statuses missing from the list are deleted.
+    ReplaceTeamWorkflow(ctx context.Context, teamID string, statuses []TeamStatus, transitions []StatusTransition) error
+    // EnsureTeamWorkflow stores the given statuses unless the team already has some
+    // and returns the team's statuses. The team is locked while it checks, so two
+    // first writes store the statuses once.
+    EnsureTeamWorkflow(ctx context.Context, teamID string, statuses []TeamStatus) ([]TeamStatus, error)
+    GetTeamTodoPlacements(ctx context.Context, teamID string) ([]TeamTodoPlacement, error)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// Estimate units
+const (
+    EstimateUnitHours  = "hours"
+    EstimateUnitPoints = "points"
+)
+
+// Estimate is the expected effort for a team todo
+type Estimate struct {
+    TodoID string
+    Value  float64
+    Unit   string
+}
+
+// TeamMemberDetails is a team member with their username
+type TeamMemberDetails struct {
+    UserID   string
+    Username string
+    IsAdmin  bool
+}
+
+// WorkloadTodo is the part of a team todo the workload report looks at, seen by
+// one of its assignees. Done is set once that assignee's part or the whole todo is.
+type WorkloadTodo struct {
+    ID           string
+    Done         bool
+    AssignedTo   string
+    AssigneeName string
+    Date         time.Time
+    Time         time.Time
+    Estimate     *Estimate
+}
+
+// WorkloadRepository defines the interface for team todo estimates and the
+// data behind the team workload report
+type WorkloadRepository interface {
+    SetTeamTodoEstimate(ctx context.Context, teamID string, estimate Estimate) error
+    // DeleteTeamTodoEstimate removes an estimate and reports whether it existed
+    DeleteTeamTodoEstimate(ctx context.Context, teamID, todoID string) (bool, error)
+    GetTeamTodoEstimates(ctx context.Context, teamID string) ([]Estimate, error)
+    // GetTeamWorkloadTodos returns a team's todos once per assignee, and once
+    // without AssignedTo for unassigned todos
+    GetTeamWorkloadTodos(ctx context.Context, teamID string) ([]WorkloadTodo, error)
+    GetTeamMemberDetails(ctx context.Context, teamID string) ([]TeamMemberDetails, error)
+}
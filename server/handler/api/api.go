@@ -2,8 +2,12 @@ package api
 
 import (
     "context"
+    "crypto/sha1"
     "encoding/json"
+    "fmt"
+    "io"
     "net/http"
+    "strconv"
     "time"
     "log"
     "strings"
@@ -17,6 +21,14 @@ import (
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/team_todos"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/shared_todos"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/calendar"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/transfer"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/workflow"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/time_entries"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/workload"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/agenda"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/quick_add"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/middleware"
 )
@@ -58,9 +70,161 @@ func formatTodoResponse(todo dto.TodoResponse) map[string]interface{} {
         "user_id":     todo.UserID,
         "date":        dateStr,
         "time":        timeStr,
+        "version":     todo.Version,
     }
 }
 
+// formatTeamTodoResponse formats a team todo response with proper date/time strings
+func formatTeamTodoResponse(todo dto.TeamTodoResponse) map[string]interface{} {
+    dateStr := ""
+    timeStr := ""
+    
+    if !todo.Date.IsZero() {
+        dateStr = todo.Date.Format("2006-01-02")
+    }
+    
+    if !todo.Time.IsZero() {
+        timeStr = todo.Time.Format("15:04:05")
+    }
+    
+    dueStr := ""
+    if !todo.Due.IsZero() {
+        dueStr = todo.Due.Format("2006-01-02T15:04:05")
+    }
+    
+    return map[string]interface{}{
+        "id":              todo.ID,
+        "task":            todo.Task,
+        "description":     todo.Description,
+        "done":            todo.Done,
+        "important":       todo.Important,
+        "team_id":         todo.TeamID,
+        "assigned_to":     todo.AssignedTo,
+        "date":            dateStr,
+        "time":            timeStr,
+        "version":         todo.Version,
+        "completion_mode": todo.CompletionMode,
+        "assignees":       todo.Assignees,
+        "due":             dueStr,
+        "overdue":         todo.Overdue,
+    }
+}
+
+// decodeMergePatch reads a JSON Merge Patch (RFC 7396) body. It writes the error
+// response itself and returns false when the request is not a valid patch.
+func decodeMergePatch(w http.ResponseWriter, r *http.Request) (map[string]json.RawMessage, bool) {
+    contentType := strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0])
+    if contentType != "application/merge-patch+json" && contentType != "application/json" {
+        http.Error(w, "Content-Type must be application/merge-patch+json", http.StatusUnsupportedMediaType)
+        return nil, false
+    }
+    
+    var fields map[string]json.RawMessage
+    if err := json.NewDecoder(r.Body).Decode(&fields); err != nil || fields == nil {
+        http.Error(w, "Invalid request payload, expected a JSON object", http.StatusBadRequest)
+        return nil, false
+    }
+    return fields, true
+}
+
+// writePatchError maps patch service errors to HTTP status codes
+func writePatchError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "invalid patch"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    default:
+        writeVersionedError(w, err)
+    }
+}
+
+// writeVersionedError maps errors from version-checked writes to HTTP status codes
+func writeVersionedError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "version mismatch"):
+        http.Error(w, err.Error(), http.StatusPreconditionFailed)
+    case strings.Contains(err.Error(), "blocked by open todos"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// forceComplete reports whether the client asked to complete todos despite open blockers
+func forceComplete(r *http.Request) bool {
+    force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+    return force
+}
+
+// writeBatchError maps batch service errors to HTTP status codes. Anything the
+// service rejected before running the batch is a bad request.
+func writeBatchError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "blocked by open todos"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    case strings.Contains(err.Error(), "only team members"):
+        http.Error(w, err.Error(), http.StatusForbidden)
+    case strings.Contains(err.Error(), "failed to execute batch"),
+        strings.Contains(err.Error(), "failed to get team members"),
+        strings.Contains(err.Error(), "failed to load"):
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    default:
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    }
+}
+
+// writeDependencyError maps dependency service errors to HTTP status codes
+func writeDependencyError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "blocked by open todos"),
+        strings.Contains(err.Error(), "would create a cycle"),
+        strings.Contains(err.Error(), "already exists"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    case strings.Contains(err.Error(), "cannot block itself"), strings.Contains(err.Error(), "is required"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// expectedVersion reads the version the client expects to change from the If-Match
+// header. It writes a 412 response itself and returns false when the tag can never match.
+func expectedVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+    version, ok := middleware.IfMatchVersion(r)
+    if !ok {
+        http.Error(w, "If-Match does not match the current version", http.StatusPreconditionFailed)
+        return 0, false
+    }
+    return version, true
+}
+
+// writeConditionalJSON writes a list response with a weak ETag derived from its body
+// and answers 304 Not Modified when the client's If-None-Match already has it
+func writeConditionalJSON(w http.ResponseWriter, r *http.Request, body interface{}) {
+    payload, err := json.Marshal(body)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    
+    etag := fmt.Sprintf("W/\"%x\"", sha1.Sum(payload))
+    w.Header().Set("ETag", etag)
+    
+    // If-None-Match uses weak comparison and may list several tags
+    for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+        candidate = strings.TrimSpace(candidate)
+        if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+            w.WriteHeader(http.StatusNotModified)
+            return
+        }
+    }
+    
+    w.Write(append(payload, '\n'))
+}
+
 // Register handles user registration
 func Register(userService *users.UserService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
@@ -127,13 +291,79 @@ func Login(userService *users.UserService) http.HandlerFunc {
     }
 }
 
+// userLocation resolves the timezone a request's "today" is counted in: the
+// X-Timezone header when present, otherwise the user's stored timezone
+func userLocation(r *http.Request, userService *users.UserService) (*time.Location, error) {
+    userID := r.Context().Value(middleware.UserIDKey).(string)
+    return userService.Location(context.Background(), userID, r.Header.Get("X-Timezone"))
+}
+
+// writeTimezoneError maps timezone lookup errors to HTTP status codes
+func writeTimezoneError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "invalid timezone"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// GetTimezone returns the authenticated user's timezone
+func GetTimezone(userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := userService.GetTimezone(context.Background(), userID)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// SetTimezone stores the authenticated user's IANA timezone
+func SetTimezone(userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.SetTimezoneRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := userService.SetTimezone(context.Background(), &req)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
 // Todo Handlers
-func GetTodos(todoService *todos.TodoService) http.HandlerFunc {
+func GetTodos(todoService *todos.TodoService, dependencyService *dependencies.DependencyService, userService *users.UserService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
         userID := r.Context().Value(middleware.UserIDKey).(string)
         
+        // Missing dates and times are shown as now in the user's timezone
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        now := users.LocalNow(loc)
+        
         todosResponse, err := todoService.GetTodosByUserID(context.Background(), userID)
         if err != nil {
             log.Printf("Error getting todos: %v", err)
@@ -141,6 +371,12 @@ func GetTodos(todoService *todos.TodoService) http.HandlerFunc {
             return
         }
         
+        blockStates, err := dependencyService.TodoBlockStates(context.Background(), userID, todosResponse.Todos)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
         // Format all todos with proper date/time strings
         formattedTodos := make([]map[string]interface{}, len(todosResponse.Todos))
         for i, todo := range todosResponse.Todos {
@@ -151,14 +387,14 @@ func GetTodos(todoService *todos.TodoService) http.HandlerFunc {
                 dateStr = todo.Date.Format("2006-01-02")
             } else {
                 // Set today's date as default if missing
-                dateStr = time.Now().Format("2006-01-02")
+                dateStr = now.Format("2006-01-02")
             }
             
             if !todo.Time.IsZero() {
                 timeStr = todo.Time.Format("15:04:05")
             } else {
                 // Set current time as default if missing
-                timeStr = time.Now().Format("15:04:05")
+                timeStr = now.Format("15:04:05")
             }
             
             formattedTodos[i] = map[string]interface{}{
@@ -170,14 +406,17 @@ func GetTodos(todoService *todos.TodoService) http.HandlerFunc {
                 "user_id":     todo.UserID,
                 "date":        dateStr,
                 "time":        timeStr,
+                "version":     todo.Version,
+                "blocked_by":  blockedBy(blockStates[todo.ID]),
+                "blocked":     blockStates[todo.ID].Blocked,
             }
         }
         
-        json.NewEncoder(w).Encode(formattedTodos)
+        writeConditionalJSON(w, r, formattedTodos)
     }
 }
 
-func CreateTodo(todoService *todos.TodoService) http.HandlerFunc {
+func CreateTodo(todoService *todos.TodoService, userService *users.UserService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
@@ -211,6 +450,13 @@ func CreateTodo(todoService *todos.TodoService) http.HandlerFunc {
             req.Time = parsedTime
         }
         
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
         res, err := todoService.CreateTodo(context.Background(), &req)
         if err != nil {
             http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -221,10 +467,33 @@ func CreateTodo(todoService *todos.TodoService) http.HandlerFunc {
         json.NewEncoder(w).Encode(res)
     }
 }
+// GetTodo returns a single todo with its version as the ETag
+func GetTodo(todoService *todos.TodoService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := todoService.GetTodo(context.Background(), mux.Vars(r)["id"], userID)
+        if err != nil {
+            writeVersionedError(w, err)
+            return
+        }
+        
+        w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        json.NewEncoder(w).Encode(formatTodoResponse(*res))
+    }
+}
+
 func UpdateTodo(todoService *todos.TodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
         var req dto.UpdateTodoRequest
         if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
             http.Error(w, "Invalid request payload", http.StatusBadRequest)
@@ -235,126 +504,148 @@ func UpdateTodo(todoService *todos.TodoService) http.HandlerFunc {
         userID := r.Context().Value(middleware.UserIDKey).(string)
         req.UserID = userID
         req.ID = mux.Vars(r)["id"]
+        req.Version = version
+        req.Force = forceComplete(r)
         
         res, err := todoService.UpdateTodo(context.Background(), &req)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeVersionedError(w, err)
             return
         }
         
+        if res.Version > 0 {
+            w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        }
         json.NewEncoder(w).Encode(res)
     }
 }
 
-func DeleteTodo(todoService *todos.TodoService) http.HandlerFunc {
+// PatchTodo applies a JSON Merge Patch to a todo, changing only the supplied fields
+func PatchTodo(todoService *todos.TodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        params := mux.Vars(r)
-        userID := r.Context().Value(middleware.UserIDKey).(string)
-        
-        res, err := todoService.DeleteTodo(context.Background(), params["id"], userID)
-        if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+        version, ok := expectedVersion(w, r)
+        if !ok {
             return
         }
         
-        json.NewEncoder(w).Encode(res)
-    }
-}
-
-func UndoTodo(todoService *todos.TodoService) http.HandlerFunc {
-    return func(w http.ResponseWriter, r *http.Request) {
-        w.Header().Set("Content-Type", "application/json")
+        fields, ok := decodeMergePatch(w, r)
+        if !ok {
+            return
+        }
         
-        params := mux.Vars(r)
-        userID := r.Context().Value(middleware.UserIDKey).(string)
+        req := dto.PatchTodoRequest{
+            ID:      mux.Vars(r)["id"],
+            UserID:  r.Context().Value(middleware.UserIDKey).(string),
+            Fields:  fields,
+            Version: version,
+            Force:   forceComplete(r),
+        }
         
-        res, err := todoService.UndoTodo(context.Background(), params["id"], userID)
+        res, err := todoService.PatchTodo(context.Background(), &req)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writePatchError(w, err)
             return
         }
         
-        json.NewEncoder(w).Encode(res)
+        w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        json.NewEncoder(w).Encode(formatTodoResponse(*res))
     }
 }
 
-// Shared Todos Handlers
+// writeShareError maps share errors to status codes
+func writeShareError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "invalid permission"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "edit permission required"):
+        http.Error(w, err.Error(), http.StatusForbidden)
+    case strings.Contains(err.Error(), "not linked"), strings.Contains(err.Error(), "not been accepted"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    default:
+        writeVersionedError(w, err)
+    }
+}
 
-func CreateSharedTodo(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
+// PatchSharedTodo lets a recipient with edit permission change a shared todo.
+// The change is made to the sharer's todo so both sides see it.
+func PatchSharedTodo(sharedTodoService *shared_todos.SharedTodoService, todoService *todos.TodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.CreateSharedTodoRequest
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+        version, ok := expectedVersion(w, r)
+        if !ok {
             return
         }
         
-        // Set the sharedBy field from the context
-        sharedBy := r.Context().Value(middleware.UserIDKey).(string)
-        req.SharedBy = sharedBy
-        
-        res, err := sharedTodoService.CreateSharedTodo(context.Background(), &req)
-        if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+        fields, ok := decodeMergePatch(w, r)
+        if !ok {
             return
         }
         
-        json.NewEncoder(w).Encode(res)
+        patchSharedTodo(w, r, sharedTodoService, todoService, fields, version)
     }
 }
 
-func GetSharedTodos(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
+// CompleteSharedTodo lets a recipient with edit permission mark a shared todo as done
+func CompleteSharedTodo(sharedTodoService *shared_todos.SharedTodoService, todoService *todos.TodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        userID := r.Context().Value(middleware.UserIDKey).(string)
-        
-        // Get todos shared with the user
-        received, err := sharedTodoService.GetSharedTodos(context.Background(), userID)
-        if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
-            return
-        }
-        
-        // Get todos shared by the user
-        shared, err := sharedTodoService.GetSharedByMeTodos(context.Background(), userID)
-        if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+        version, ok := expectedVersion(w, r)
+        if !ok {
             return
         }
         
-        // Combine the responses
-        response := dto.SharedTodosResponse{
-            Received: received.Received,
-            Shared: shared.Shared,
-        }
-        
-        json.NewEncoder(w).Encode(response)
+        fields := map[string]json.RawMessage{"done": json.RawMessage("true")}
+        patchSharedTodo(w, r, sharedTodoService, todoService, fields, version)
     }
 }
 
-// Team Handlers
+func patchSharedTodo(w http.ResponseWriter, r *http.Request, sharedTodoService *shared_todos.SharedTodoService, todoService *todos.TodoService, fields map[string]json.RawMessage, version int) {
+    userID := r.Context().Value(middleware.UserIDKey).(string)
+    shared, err := sharedTodoService.LinkedTodo(context.Background(), mux.Vars(r)["id"], userID)
+    if err != nil {
+        writeShareError(w, err)
+        return
+    }
+    
+    req := dto.PatchTodoRequest{
+        ID:      shared.TodoID,
+        UserID:  shared.SharedBy,
+        Fields:  fields,
+        Version: version,
+        Force:   forceComplete(r),
+    }
+    
+    res, err := todoService.PatchTodo(context.Background(), &req)
+    if err != nil {
+        writePatchError(w, err)
+        return
+    }
+    
+    w.Header().Set("ETag", middleware.VersionETag(res.Version))
+    json.NewEncoder(w).Encode(formatTodoResponse(*res))
+}
 
-func CreateTeam(teamService *teams.TeamService) http.HandlerFunc {
+// UpdateSharePermission lets the owner of a share switch it between view and edit
+func UpdateSharePermission(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.CreateTeamRequest
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        var request struct {
+            Permission string `json:"permission"`
+        }
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
             http.Error(w, "Invalid request payload", http.StatusBadRequest)
             return
         }
         
-        // Set the admin ID from the context
-        adminID := r.Context().Value(middleware.UserIDKey).(string)
-        req.AdminID = adminID
-        
-        res, err := teamService.CreateTeam(context.Background(), &req)
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := sharedTodoService.UpdateSharePermission(context.Background(), mux.Vars(r)["id"], userID, request.Permission)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeShareError(w, err)
             return
         }
         
@@ -362,15 +653,15 @@ func CreateTeam(teamService *teams.TeamService) http.HandlerFunc {
     }
 }
 
-func GetTeams(teamService *teams.TeamService) http.HandlerFunc {
+// RevokeShare lets the owner of a share stop sharing the todo
+func RevokeShare(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
         userID := r.Context().Value(middleware.UserIDKey).(string)
-        
-        res, err := teamService.GetTeamsByAdminID(context.Background(), userID)
+        res, err := sharedTodoService.RevokeShare(context.Background(), mux.Vars(r)["id"], userID)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeShareError(w, err)
             return
         }
         
@@ -378,15 +669,15 @@ func GetTeams(teamService *teams.TeamService) http.HandlerFunc {
     }
 }
 
-func GetTeamByID(teamService *teams.TeamService) http.HandlerFunc {
+// AcceptShare moves a pending share into the recipient's lists
+func AcceptShare(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        params := mux.Vars(r)
-        
-        res, err := teamService.GetTeamByID(context.Background(), params["teamId"])
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := sharedTodoService.AcceptShare(context.Background(), mux.Vars(r)["id"], userID)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeShareError(w, err)
             return
         }
         
@@ -394,94 +685,61 @@ func GetTeamByID(teamService *teams.TeamService) http.HandlerFunc {
     }
 }
 
-// Team Todos Handlers
-
-func GetTeamTodos(teamTodoService *team_todos.TeamTodoService) http.HandlerFunc {
+// DeclineShare lets the recipient turn down or leave a share
+func DeclineShare(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        params := mux.Vars(r)
-        
-        res, err := teamTodoService.GetTeamTodos(context.Background(), params["teamId"])
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := sharedTodoService.DeclineShare(context.Background(), mux.Vars(r)["id"], userID)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeShareError(w, err)
             return
         }
         
-        // Format team todos with proper date/time strings
-        formattedTodos := make([]map[string]interface{}, len(res.Todos))
-        for i, todo := range res.Todos {
-            dateStr := ""
-            timeStr := ""
-            
-            if !todo.Date.IsZero() {
-                dateStr = todo.Date.Format("2006-01-02")
-            }
-            
-            if !todo.Time.IsZero() {
-                timeStr = todo.Time.Format("15:04:05")
-            }
-            
-            formattedTodos[i] = map[string]interface{}{
-                "id":          todo.ID,
-                "task":        todo.Task,
-                "description": todo.Description,
-                "done":        todo.Done,
-                "important":   todo.Important,
-                "team_id":     todo.TeamID,
-                "assigned_to": todo.AssignedTo,
-                "date":        dateStr,
-                "time":        timeStr,
-            }
-        }
-        
-        json.NewEncoder(w).Encode(formattedTodos)
+        json.NewEncoder(w).Encode(res)
     }
 }
 
-
-func CreateTeamTodo(teamTodoService *team_todos.TeamTodoService) http.HandlerFunc {
+// BlockShareSender declines a share and blocks its sender from sharing again
+func BlockShareSender(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.CreateTeamTodoRequest
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := sharedTodoService.BlockSender(context.Background(), mux.Vars(r)["id"], userID)
+        if err != nil {
+            writeShareError(w, err)
             return
         }
         
-        // Set the team ID from the URL parameters
-        params := mux.Vars(r)
-        req.TeamID = params["teamId"]
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+func GetShareBlocks(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
         
-        res, err := teamTodoService.CreateTeamTodo(context.Background(), &req)
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := sharedTodoService.GetBlockedSenders(context.Background(), userID)
         if err != nil {
             http.Error(w, err.Error(), http.StatusInternalServerError)
             return
         }
         
-        json.NewEncoder(w).Encode(res)
+        writeConditionalJSON(w, r, res)
     }
 }
 
-func UpdateTeamTodo(teamTodoService *team_todos.TeamTodoService) http.HandlerFunc {
+func UnblockShareSender(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.UpdateTeamTodoRequest
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-            http.Error(w, "Invalid request payload", http.StatusBadRequest)
-            return
-        }
-        
-        // Set the ID and team ID from the URL parameters
-        params := mux.Vars(r)
-        req.ID = params["id"]
-        req.TeamID = params["teamId"]
-        
-        res, err := teamTodoService.UpdateTeamTodo(context.Background(), &req)
-        if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := sharedTodoService.UnblockSender(context.Background(), userID, mux.Vars(r)["userId"])
+        if err != nil {
+            writeShareError(w, err)
             return
         }
         
@@ -489,15 +747,21 @@ func UpdateTeamTodo(teamTodoService *team_todos.TeamTodoService) http.HandlerFun
     }
 }
 
-func DeleteTeamTodo(teamTodoService *team_todos.TeamTodoService) http.HandlerFunc {
+func DeleteTodo(todoService *todos.TodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
         params := mux.Vars(r)
+        userID := r.Context().Value(middleware.UserIDKey).(string)
         
-        res, err := teamTodoService.DeleteTeamTodo(context.Background(), params["id"], params["teamId"])
+        res, err := todoService.DeleteTodo(context.Background(), params["id"], userID, version)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeVersionedError(w, err)
             return
         }
         
@@ -505,41 +769,56 @@ func DeleteTeamTodo(teamTodoService *team_todos.TeamTodoService) http.HandlerFun
     }
 }
 
-// Team Members Handlers
-
-func GetTeamMembers(teamMemberService *team_members.TeamMemberService) http.HandlerFunc {
+func UndoTodo(todoService *todos.TodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
         params := mux.Vars(r)
+        userID := r.Context().Value(middleware.UserIDKey).(string)
         
-        res, err := teamMemberService.GetTeamMembers(context.Background(), params["teamId"])
+        res, err := todoService.UndoTodo(context.Background(), params["id"], userID, version)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeVersionedError(w, err)
             return
         }
         
+        if res.Version > 0 {
+            w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        }
         json.NewEncoder(w).Encode(res)
     }
 }
 
-func AddTeamMember(teamMemberService *team_members.TeamMemberService) http.HandlerFunc {
+// BatchTodos runs several todo operations in one transaction
+func BatchTodos(todoService *todos.TodoService, userService *users.UserService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.AddTeamMemberRequest
+        var req dto.BatchRequest
         if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
             http.Error(w, "Invalid request payload", http.StatusBadRequest)
             return
         }
         
-        // Set the team ID from the URL parameters
-        params := mux.Vars(r)
-        req.TeamID = params["teamId"]
+        // Set the user ID from the context
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
         
-        res, err := teamMemberService.AddTeamMember(context.Background(), &req)
+        loc, err := userLocation(r, userService)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        req.Force = forceComplete(r)
+        
+        res, err := todoService.ExecuteBatch(context.Background(), &req)
+        if err != nil {
+            writeBatchError(w, err)
             return
         }
         
@@ -547,15 +826,26 @@ func AddTeamMember(teamMemberService *team_members.TeamMemberService) http.Handl
     }
 }
 
-func RemoveTeamMember(teamMemberService *team_members.TeamMemberService) http.HandlerFunc {
+// blockedBy returns the blocker IDs of a todo as a non-nil list for JSON output
+func blockedBy(state dto.BlockState) []string {
+    if state.BlockedBy == nil {
+        return []string{}
+    }
+    return state.BlockedBy
+}
+
+// Dependency Handlers
+
+// GetTodoDependencies lists the todos blocking a todo and the todos it blocks
+func GetTodoDependencies(dependencyService *dependencies.DependencyService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        params := mux.Vars(r)
+        userID := r.Context().Value(middleware.UserIDKey).(string)
         
-        res, err := teamMemberService.RemoveTeamMember(context.Background(), params["teamId"], params["userId"])
+        res, err := dependencyService.GetTodoDependencies(context.Background(), userID, mux.Vars(r)["id"])
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeDependencyError(w, err)
             return
         }
         
@@ -563,51 +853,58 @@ func RemoveTeamMember(teamMemberService *team_members.TeamMemberService) http.Ha
     }
 }
 
-
-// Routine Handlers
-
-// CreateRoutine creates a new routine
-func CreateRoutine(routineService *routines.RoutineService) http.HandlerFunc {
+// AddTodoDependency marks a todo as blocked by another of the user's todos
+func AddTodoDependency(dependencyService *dependencies.DependencyService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.CreateRoutineRequest
+        var req dto.AddDependencyRequest
         if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
             http.Error(w, "Invalid request payload", http.StatusBadRequest)
             return
         }
+        req.TodoID = mux.Vars(r)["id"]
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
         
-        // Set the user ID from the context
-        userID := r.Context().Value(middleware.UserIDKey).(string)
-        req.UserID = userID
-        
-        res, err := routineService.CreateRoutine(context.Background(), &req)
+        res, err := dependencyService.AddTodoDependency(context.Background(), &req)
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeDependencyError(w, err)
             return
         }
         
+        w.WriteHeader(http.StatusCreated)
         json.NewEncoder(w).Encode(res)
     }
 }
 
-// UpdateRoutineStatus updates the active status of a routine
-func UpdateRoutineStatus(routineService *routines.RoutineService) http.HandlerFunc {
+// RemoveTodoDependency deletes a blocked-by link
+func RemoveTodoDependency(dependencyService *dependencies.DependencyService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.UpdateRoutineStatusRequest
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+        params := mux.Vars(r)
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := dependencyService.RemoveTodoDependency(context.Background(), userID, params["id"], params["blockedById"])
+        if err != nil {
+            writeDependencyError(w, err)
             return
         }
         
-        // Set the ID from the URL parameter
-        req.ID = mux.Vars(r)["id"]
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTeamTodoDependencies lists the team todos blocking a team todo and the ones it blocks
+func GetTeamTodoDependencies(dependencyService *dependencies.DependencyService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        params := mux.Vars(r)
         
-        res, err := routineService.UpdateRoutineStatus(context.Background(), req.ID, req.IsActive)
+        res, err := dependencyService.GetTeamTodoDependencies(context.Background(), params["teamId"], params["id"])
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeDependencyError(w, err)
             return
         }
         
@@ -615,23 +912,42 @@ func UpdateRoutineStatus(routineService *routines.RoutineService) http.HandlerFu
     }
 }
 
-// UpdateRoutineDay updates the day of a routine
-func UpdateRoutineDay(routineService *routines.RoutineService) http.HandlerFunc {
+// AddTeamTodoDependency marks a team todo as blocked by another todo of the same team
+func AddTeamTodoDependency(dependencyService *dependencies.DependencyService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.UpdateRoutineDayRequest
+        var req dto.AddDependencyRequest
         if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
             http.Error(w, "Invalid request payload", http.StatusBadRequest)
             return
         }
+        params := mux.Vars(r)
+        req.TodoID = params["id"]
+        req.TeamID = params["teamId"]
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
         
-        // Set the ID from the URL parameter
-        req.ID = mux.Vars(r)["id"]
+        res, err := dependencyService.AddTeamTodoDependency(context.Background(), &req)
+        if err != nil {
+            writeDependencyError(w, err)
+            return
+        }
+        
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// RemoveTeamTodoDependency deletes a blocked-by link between team todos
+func RemoveTeamTodoDependency(dependencyService *dependencies.DependencyService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
         
-        res, err := routineService.UpdateRoutineDay(context.Background(), req.ID, req.Day)
+        params := mux.Vars(r)
+        
+        res, err := dependencyService.RemoveTeamTodoDependency(context.Background(), params["teamId"], params["id"], params["blockedById"])
         if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
+            writeDependencyError(w, err)
             return
         }
         
@@ -639,15 +955,23 @@ func UpdateRoutineDay(routineService *routines.RoutineService) http.HandlerFunc
     }
 }
 
-// GetRoutinesByTaskID gets routines for a specific task
-func GetRoutinesByTaskID(routineService *routines.RoutineService) http.HandlerFunc {
+// Shared Todos Handlers
+
+func CreateSharedTodo(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        // Get the task ID from the URL parameter
-        taskID := mux.Vars(r)["taskId"]
+        var req dto.CreateSharedTodoRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
         
-        res, err := routineService.GetRoutinesByTaskID(context.Background(), taskID)
+        // Set the sharedBy field from the context
+        sharedBy := r.Context().Value(middleware.UserIDKey).(string)
+        req.SharedBy = sharedBy
+        
+        res, err := sharedTodoService.CreateSharedTodo(context.Background(), &req)
         if err != nil {
             http.Error(w, err.Error(), http.StatusInternalServerError)
             return
@@ -657,85 +981,70 @@ func GetRoutinesByTaskID(routineService *routines.RoutineService) http.HandlerFu
     }
 }
 
-// GetDailyRoutines gets todos for a specific day and schedule type
-func GetDailyRoutines(routineService *routines.RoutineService) http.HandlerFunc {
+func GetSharedTodos(sharedTodoService *shared_todos.SharedTodoService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        // Get parameters from URL
-        params := mux.Vars(r)
-        day := params["day"]
-        scheduleType := params["scheduleType"]
-        
-        // Get the user ID from the context
         userID := r.Context().Value(middleware.UserIDKey).(string)
         
-        res, err := routineService.GetDailyRoutines(context.Background(), day, scheduleType, userID)
+        // Get todos shared with the user
+        received, err := sharedTodoService.GetSharedTodos(context.Background(), userID)
         if err != nil {
             http.Error(w, err.Error(), http.StatusInternalServerError)
             return
         }
         
-        // Format todos with proper date/time strings
-        formattedTodos := make([]map[string]interface{}, len(res.Todos))
-        for i, todo := range res.Todos {
-            dateStr := ""
-            timeStr := ""
-            
-            if !todo.Date.IsZero() {
-                dateStr = todo.Date.Format("2006-01-02")
-            }
-            
-            if !todo.Time.IsZero() {
-                timeStr = todo.Time.Format("15:04:05")
-            }
-            
-            formattedTodos[i] = map[string]interface{}{
-                "id":          todo.ID,
-                "task":        todo.Task,
-                "description": todo.Description,
-                "done":        todo.Done,
-                "important":   todo.Important,
-                "user_id":     todo.UserID,
-                "date":        dateStr,
-                "time":        timeStr,
-            }
+        // Get todos shared by the user
+        shared, err := sharedTodoService.GetSharedByMeTodos(context.Background(), userID)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
         }
         
-        json.NewEncoder(w).Encode(formattedTodos)
+        // Combine the responses
+        response := dto.SharedTodosResponse{
+            Received: received.Received,
+            Pending: received.Pending,
+            Shared: shared.Shared,
+        }
+        
+        json.NewEncoder(w).Encode(response)
     }
 }
 
-// GetTodayRoutines gets todos for today by schedule type
-func GetTodayRoutines(routineService *routines.RoutineService) http.HandlerFunc {
+// Team Handlers
+
+func CreateTeam(teamService *teams.TeamService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        // Get the schedule type from URL
-        scheduleType := mux.Vars(r)["scheduleType"]
+        var req dto.CreateTeamRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
         
-        // Get the user ID from the context
-        userID := r.Context().Value(middleware.UserIDKey).(string)
+        // Set the admin ID from the context
+        adminID := r.Context().Value(middleware.UserIDKey).(string)
+        req.AdminID = adminID
         
-        res, err := routineService.GetTodayRoutines(context.Background(), scheduleType, userID)
+        res, err := teamService.CreateTeam(context.Background(), &req)
         if err != nil {
             http.Error(w, err.Error(), http.StatusInternalServerError)
             return
         }
         
-        json.NewEncoder(w).Encode(res.Todos)
+        json.NewEncoder(w).Encode(res)
     }
 }
 
-// DeleteRoutinesByTaskID deletes all routines for a task
-func DeleteRoutinesByTaskID(routineService *routines.RoutineService) http.HandlerFunc {
+func GetTeams(teamService *teams.TeamService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        // Get the task ID from the URL parameter
-        taskID := mux.Vars(r)["taskId"]
+        userID := r.Context().Value(middleware.UserIDKey).(string)
         
-        res, err := routineService.DeleteRoutinesByTaskID(context.Background(), taskID)
+        res, err := teamService.GetTeamsByAdminID(context.Background(), userID)
         if err != nil {
             http.Error(w, err.Error(), http.StatusInternalServerError)
             return
@@ -745,22 +1054,13 @@ func DeleteRoutinesByTaskID(routineService *routines.RoutineService) http.Handle
     }
 }
 
-// CreateOrUpdateRoutines creates or updates routines for a task
-func CreateOrUpdateRoutines(routineService *routines.RoutineService) http.HandlerFunc {
+func GetTeamByID(teamService *teams.TeamService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        var req dto.CreateOrUpdateRoutinesRequest
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-            http.Error(w, "Invalid request payload", http.StatusBadRequest)
-            return
-        }
-        
-        // Set the user ID from the context
-        userID := r.Context().Value(middleware.UserIDKey).(string)
-        req.UserID = userID
+        params := mux.Vars(r)
         
-        res, err := routineService.CreateOrUpdateRoutines(context.Background(), &req)
+        res, err := teamService.GetTeamByID(context.Background(), params["teamId"])
         if err != nil {
             http.Error(w, err.Error(), http.StatusInternalServerError)
             return
@@ -770,60 +1070,1941 @@ func CreateOrUpdateRoutines(routineService *routines.RoutineService) http.Handle
     }
 }
 
-// In handler/api/api.go
-// ShareTodo handles sharing a todo with another user
-func ShareTodo(sharedTodoService *shared_todos.SharedTodoService, userService *users.UserService, todoService *todos.TodoService) http.HandlerFunc {
+// Team Todos Handlers
+
+func GetTeamTodos(teamTodoService *team_todos.TeamTodoService, dependencyService *dependencies.DependencyService, workloadService *workload.WorkloadService, userService *users.UserService) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         
-        // Parse request body
-        var request struct {
-            TaskId   string `json:"taskId"`
-            Username string `json:"username"`
-        }
+        params := mux.Vars(r)
         
-        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+        // Overdue todos are flagged by the caller's clock
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
             return
         }
         
-        // Get the current user ID from context
-        currentUserID := r.Context().Value(middleware.UserIDKey).(string)
-        
-        // Find the user by username
-        recipient, err := userService.GetUserByUsername(context.Background(), request.Username)
+        // ?assignee=<user id>, "me" or "none" narrows the list
+        var res *dto.TeamTodosResponse
+        switch assignee := r.URL.Query().Get("assignee"); assignee {
+        case "":
+            res, err = teamTodoService.GetTeamTodos(context.Background(), params["teamId"], loc)
+        case "me":
+            userID := r.Context().Value(middleware.UserIDKey).(string)
+            res, err = teamTodoService.GetTeamTodosByAssignee(context.Background(), params["teamId"], userID, loc)
+        default:
+            res, err = teamTodoService.GetTeamTodosByAssignee(context.Background(), params["teamId"], assignee, loc)
+        }
         if err != nil {
-            http.Error(w, "User not found", http.StatusNotFound)
+            http.Error(w, err.Error(), http.StatusInternalServerError)
             return
         }
         
-        // Cannot share with yourself
-        if recipient.ID == currentUserID {
-            http.Error(w, "Cannot share todo with yourself", http.StatusBadRequest)
+        blockStates, err := dependencyService.TeamTodoBlockStates(context.Background(), params["teamId"], res.Todos)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
             return
         }
         
-        // Share the todo
-        err = sharedTodoService.ShareTodo(context.Background(), request.TaskId, recipient.ID, currentUserID)
+        estimates, err := workloadService.TeamTodoEstimates(context.Background(), params["teamId"])
         if err != nil {
-            if strings.Contains(err.Error(), "already shared") {
-                http.Error(w, "Todo is already shared with this user", http.StatusConflict)
-                return
-            }
-            if strings.Contains(err.Error(), "failed to get todo") {
-                http.Error(w, "Todo not found", http.StatusNotFound)
-                return
-            }
-            if strings.Contains(err.Error(), "unauthorized") {
-                http.Error(w, "You can only share your own todos", http.StatusForbidden)
-                return
-            }
-            http.Error(w, "Error sharing todo: "+err.Error(), http.StatusInternalServerError)
+            http.Error(w, err.Error(), http.StatusInternalServerError)
             return
         }
         
-        // Return success response
-        json.NewEncoder(w).Encode(map[string]string{"result": "success"})
+        // Format team todos with proper date/time strings
+        formattedTodos := make([]map[string]interface{}, len(res.Todos))
+        for i, todo := range res.Todos {
+            formattedTodos[i] = formatTeamTodoResponse(todo)
+            formattedTodos[i]["blocked_by"] = blockedBy(blockStates[todo.ID])
+            formattedTodos[i]["blocked"] = blockStates[todo.ID].Blocked
+            formattedTodos[i]["estimate"] = estimateValue(estimates, todo.ID)
+        }
+        
+        writeConditionalJSON(w, r, formattedTodos)
     }
 }
 
+
+func CreateTeamTodo(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.CreateTeamTodoRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Set the team ID from the URL parameters
+        params := mux.Vars(r)
+        req.TeamID = params["teamId"]
+        req.CreatedBy = r.Context().Value(middleware.UserIDKey).(string)
+        
+        // A missing date or time defaults to now in the creator's timezone
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := teamTodoService.CreateTeamTodo(context.Background(), &req)
+        if err != nil {
+            writeAssignmentError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTeamTodo returns a single team todo with its version as the ETag
+func GetTeamTodo(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        params := mux.Vars(r)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := teamTodoService.GetTeamTodo(context.Background(), params["id"], params["teamId"], loc)
+        if err != nil {
+            writeVersionedError(w, err)
+            return
+        }
+        
+        w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        json.NewEncoder(w).Encode(formatTeamTodoResponse(*res))
+    }
+}
+
+func UpdateTeamTodo(teamTodoService *team_todos.TeamTodoService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
+        var req dto.UpdateTeamTodoRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Set the ID and team ID from the URL parameters
+        params := mux.Vars(r)
+        req.ID = params["id"]
+        req.TeamID = params["teamId"]
+        req.Version = version
+        req.Force = forceComplete(r)
+        req.UpdatedBy = r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := teamTodoService.UpdateTeamTodo(context.Background(), &req)
+        if err != nil {
+            writeAssignmentError(w, err)
+            return
+        }
+        
+        if res.Version > 0 {
+            w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        }
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// PatchTeamTodo applies a JSON Merge Patch to a team todo, changing only the supplied fields
+func PatchTeamTodo(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
+        fields, ok := decodeMergePatch(w, r)
+        if !ok {
+            return
+        }
+        
+        // Overdue is flagged by the caller's clock
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        params := mux.Vars(r)
+        req := dto.PatchTeamTodoRequest{
+            ID:       params["id"],
+            TeamID:   params["teamId"],
+            UserID:   r.Context().Value(middleware.UserIDKey).(string),
+            Fields:   fields,
+            Version:  version,
+            Force:    forceComplete(r),
+            Location: loc,
+        }
+        
+        res, err := teamTodoService.PatchTeamTodo(context.Background(), &req)
+        if err != nil {
+            writeAssignmentError(w, err)
+            return
+        }
+        
+        w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        json.NewEncoder(w).Encode(formatTeamTodoResponse(*res))
+    }
+}
+
+func DeleteTeamTodo(teamTodoService *team_todos.TeamTodoService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
+        params := mux.Vars(r)
+        
+        res, err := teamTodoService.DeleteTeamTodo(context.Background(), params["id"], params["teamId"], version)
+        if err != nil {
+            writeVersionedError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// BatchTeamTodos runs several team todo operations in one transaction
+func BatchTeamTodos(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.BatchRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Set the team ID from the URL parameters
+        req.TeamID = mux.Vars(r)["teamId"]
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        req.Force = forceComplete(r)
+        
+        res, err := teamTodoService.ExecuteBatch(context.Background(), &req)
+        if err != nil {
+            writeBatchError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// writeAssignmentError maps team todo write errors, including assignee checks, to HTTP status codes
+func writeAssignmentError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "is not a member of this team"),
+        strings.Contains(err.Error(), "invalid completion mode"),
+        strings.Contains(err.Error(), "invalid date format"),
+        strings.Contains(err.Error(), "invalid time format"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "only team members"),
+        strings.Contains(err.Error(), "only the assignee"):
+        http.Error(w, err.Error(), http.StatusForbidden)
+    default:
+        writePatchError(w, err)
+    }
+}
+
+// ReassignTeamTodo assigns a team todo to another member, or unassigns it
+func ReassignTeamTodo(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
+        var req dto.ReassignTeamTodoRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        params := mux.Vars(r)
+        req.ID = params["id"]
+        req.TeamID = params["teamId"]
+        req.AssignedBy = r.Context().Value(middleware.UserIDKey).(string)
+        req.Version = version
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := teamTodoService.ReassignTeamTodo(context.Background(), &req)
+        if err != nil {
+            writeAssignmentError(w, err)
+            return
+        }
+        
+        w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        json.NewEncoder(w).Encode(formatTeamTodoResponse(*res))
+    }
+}
+
+// SetTeamTodoAssignees replaces the assignees of a team todo and optionally its completion mode
+func SetTeamTodoAssignees(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
+        var req dto.SetTeamTodoAssigneesRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        params := mux.Vars(r)
+        req.ID = params["id"]
+        req.TeamID = params["teamId"]
+        req.ChangedBy = r.Context().Value(middleware.UserIDKey).(string)
+        req.Version = version
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := teamTodoService.SetTeamTodoAssignees(context.Background(), &req)
+        if err != nil {
+            writeAssignmentError(w, err)
+            return
+        }
+        
+        w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        json.NewEncoder(w).Encode(formatTeamTodoResponse(*res))
+    }
+}
+
+// SetAssigneeDone lets an assignee mark their own part of a team todo as done or not done
+func SetAssigneeDone(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.SetAssigneeDoneRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        params := mux.Vars(r)
+        req.ID = params["id"]
+        req.TeamID = params["teamId"]
+        req.UserID = params["userId"]
+        req.CallerID = r.Context().Value(middleware.UserIDKey).(string)
+        req.Force = forceComplete(r)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := teamTodoService.SetAssigneeDone(context.Background(), &req)
+        if err != nil {
+            writeAssignmentError(w, err)
+            return
+        }
+        
+        w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        json.NewEncoder(w).Encode(formatTeamTodoResponse(*res))
+    }
+}
+
+// GetTeamTodoAssignments returns the assignment history of a team todo
+func GetTeamTodoAssignments(teamTodoService *team_todos.TeamTodoService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        params := mux.Vars(r)
+        
+        res, err := teamTodoService.GetTeamTodoAssignments(context.Background(), params["id"], params["teamId"])
+        if err != nil {
+            writeVersionedError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetAssignedTeamTodos lists the team todos assigned to the caller across all of their teams
+func GetAssignedTeamTodos(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := teamTodoService.GetAssignedTeamTodos(context.Background(), userID, loc)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTeamDeadlines lists the team's overdue todos and those due in the next
+// ?days= days (7 by default), by the caller's clock
+func GetTeamDeadlines(teamTodoService *team_todos.TeamTodoService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        days := 7
+        if value := r.URL.Query().Get("days"); value != "" {
+            parsed, err := strconv.Atoi(value)
+            if err != nil {
+                http.Error(w, "days must be a number", http.StatusBadRequest)
+                return
+            }
+            days = parsed
+        }
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := teamTodoService.GetTeamDeadlines(context.Background(), mux.Vars(r)["teamId"], days, loc)
+        if err != nil {
+            if strings.Contains(err.Error(), "days must be between") {
+                http.Error(w, err.Error(), http.StatusBadRequest)
+                return
+            }
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        format := func(todos []dto.TeamTodoResponse) []map[string]interface{} {
+            formatted := make([]map[string]interface{}, len(todos))
+            for i, todo := range todos {
+                formatted[i] = formatTeamTodoResponse(todo)
+            }
+            return formatted
+        }
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "from":     res.From.Format("2006-01-02"),
+            "until":    res.Until.Format("2006-01-02"),
+            "overdue":  format(res.Overdue),
+            "upcoming": format(res.Upcoming),
+        })
+    }
+}
+
+// Team Workflow Handlers
+
+// writeWorkflowError maps workflow service errors to HTTP status codes
+func writeWorkflowError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "invalid workflow"), strings.Contains(err.Error(), "cannot be negative"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "is not allowed"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    default:
+        writeVersionedError(w, err)
+    }
+}
+
+// formatStatusResponse formats a workflow status for board responses
+func formatStatusResponse(status dto.TeamStatusResponse) map[string]interface{} {
+    return map[string]interface{}{
+        "id":       status.ID,
+        "name":     status.Name,
+        "position": status.Position,
+        "terminal": status.Terminal,
+    }
+}
+
+// GetTeamWorkflow returns a team's statuses and transition rules
+func GetTeamWorkflow(workflowService *workflow.WorkflowService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        res, err := workflowService.GetWorkflow(context.Background(), mux.Vars(r)["teamId"])
+        if err != nil {
+            writeWorkflowError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// UpdateTeamWorkflow replaces a team's statuses and transition rules
+func UpdateTeamWorkflow(workflowService *workflow.WorkflowService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.UpdateWorkflowRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        req.TeamID = mux.Vars(r)["teamId"]
+        
+        res, err := workflowService.UpdateWorkflow(context.Background(), &req)
+        if err != nil {
+            writeWorkflowError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTeamBoard returns the team's todos grouped into status columns
+func GetTeamBoard(workflowService *workflow.WorkflowService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := workflowService.GetBoard(context.Background(), mux.Vars(r)["teamId"], loc)
+        if err != nil {
+            writeWorkflowError(w, err)
+            return
+        }
+        
+        columns := make([]map[string]interface{}, len(res.Columns))
+        for i, column := range res.Columns {
+            todos := make([]map[string]interface{}, len(column.Todos))
+            for j, todo := range column.Todos {
+                todos[j] = formatTeamTodoResponse(todo)
+            }
+            formatted := formatStatusResponse(column.Status)
+            formatted["todos"] = todos
+            columns[i] = formatted
+        }
+        
+        writeConditionalJSON(w, r, map[string]interface{}{
+            "team_id": res.TeamID,
+            "columns": columns,
+        })
+    }
+}
+
+// MoveTeamTodo moves a team todo to another status or position on the board
+func MoveTeamTodo(workflowService *workflow.WorkflowService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
+        var req dto.MoveTeamTodoRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Status == "" {
+            http.Error(w, "Invalid request payload, status is required", http.StatusBadRequest)
+            return
+        }
+        params := mux.Vars(r)
+        req.ID = params["id"]
+        req.TeamID = params["teamId"]
+        req.Version = version
+        req.Force = forceComplete(r)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := workflowService.MoveTeamTodo(context.Background(), &req)
+        if err != nil {
+            writeWorkflowError(w, err)
+            return
+        }
+        
+        w.Header().Set("ETag", middleware.VersionETag(res.Todo.Version))
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "todo":     formatTeamTodoResponse(res.Todo),
+            "status":   formatStatusResponse(res.Status),
+            "position": res.Position,
+        })
+    }
+}
+
+// Time Tracking Handlers
+
+// writeTimeEntryError maps time entry service errors to HTTP status codes
+func writeTimeEntryError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "not found"), strings.Contains(err.Error(), "no running timer"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "timer already running"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "is required"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// timeRange reads the optional from and to dates (YYYY-MM-DD, both inclusive) of
+// a time query as a half-open range. It writes a 400 response itself on bad input.
+func timeRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+    var from, to time.Time
+    if value := r.URL.Query().Get("from"); value != "" {
+        parsed, err := time.Parse("2006-01-02", value)
+        if err != nil {
+            http.Error(w, "Invalid from date, use YYYY-MM-DD", http.StatusBadRequest)
+            return from, to, false
+        }
+        from = parsed
+    }
+    if value := r.URL.Query().Get("to"); value != "" {
+        parsed, err := time.Parse("2006-01-02", value)
+        if err != nil {
+            http.Error(w, "Invalid to date, use YYYY-MM-DD", http.StatusBadRequest)
+            return from, to, false
+        }
+        to = parsed.AddDate(0, 0, 1)
+    }
+    return from, to, true
+}
+
+// StartTimer starts the user's timer on a todo or team todo
+func StartTimer(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.StartTimerRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := timeEntryService.StartTimer(context.Background(), &req)
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// StopTimer stops the user's running timer
+func StopTimer(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := timeEntryService.StopTimer(context.Background(), userID)
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetRunningTimer returns the user's running timer
+func GetRunningTimer(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := timeEntryService.GetRunningTimer(context.Background(), userID)
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// CreateTodoTimeEntry logs time on one of the user's todos
+func CreateTodoTimeEntry(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.CreateTimeEntryRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        req.TodoID = mux.Vars(r)["id"]
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := timeEntryService.CreateTimeEntry(context.Background(), &req)
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTodoTimeEntries lists the time logged on one of the user's todos with its total
+func GetTodoTimeEntries(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := timeEntryService.GetTodoTimeEntries(context.Background(), userID, mux.Vars(r)["id"])
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// CreateTeamTodoTimeEntry logs the user's time on a team todo
+func CreateTeamTodoTimeEntry(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.CreateTimeEntryRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        params := mux.Vars(r)
+        req.TodoID = params["id"]
+        req.TeamID = params["teamId"]
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := timeEntryService.CreateTimeEntry(context.Background(), &req)
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTeamTodoTimeEntries lists the time all members logged on a team todo with its total
+func GetTeamTodoTimeEntries(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        params := mux.Vars(r)
+        res, err := timeEntryService.GetTeamTodoTimeEntries(context.Background(), params["teamId"], params["id"])
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTeamTimeEntries lists the time logged on all of a team's todos with the team total
+func GetTeamTimeEntries(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        from, to, ok := timeRange(w, r)
+        if !ok {
+            return
+        }
+        
+        res, err := timeEntryService.GetTeamTimeEntries(context.Background(), mux.Vars(r)["teamId"], from, to)
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// DeleteTimeEntry removes one of the user's time entries
+func DeleteTimeEntry(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        res, err := timeEntryService.DeleteTimeEntry(context.Background(), userID, mux.Vars(r)["id"])
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTimeReport totals the user's time, or a team's with ?team_id=, grouped by
+// the comma-separated ?group_by= fields (day, user, todo; all by default)
+func GetTimeReport(timeEntryService *time_entries.TimeEntryService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        from, to, ok := timeRange(w, r)
+        if !ok {
+            return
+        }
+        req := dto.TimeReportRequest{
+            UserID: r.Context().Value(middleware.UserIDKey).(string),
+            TeamID: r.URL.Query().Get("team_id"),
+            From:   from,
+            To:     to,
+        }
+        if groupBy := r.URL.Query().Get("group_by"); groupBy != "" {
+            req.GroupBy = strings.Split(groupBy, ",")
+        }
+        
+        res, err := timeEntryService.GetReport(context.Background(), &req)
+        if err != nil {
+            writeTimeEntryError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// Workload Handlers
+
+// writeWorkloadError maps workload service errors to HTTP status codes
+func writeWorkloadError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "invalid estimate"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// estimateValue formats a todo's estimate for list responses, or null without one
+func estimateValue(estimates map[string]dto.EstimateResponse, todoID string) interface{} {
+    estimate, ok := estimates[todoID]
+    if !ok {
+        return nil
+    }
+    return map[string]interface{}{
+        "value": estimate.Value,
+        "unit":  estimate.Unit,
+    }
+}
+
+// SetTeamTodoEstimate sets the estimate of a team todo
+func SetTeamTodoEstimate(workloadService *workload.WorkloadService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.SetEstimateRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        params := mux.Vars(r)
+        req.TodoID = params["id"]
+        req.TeamID = params["teamId"]
+        
+        res, err := workloadService.SetEstimate(context.Background(), &req)
+        if err != nil {
+            writeWorkloadError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// ClearTeamTodoEstimate removes the estimate of a team todo
+func ClearTeamTodoEstimate(workloadService *workload.WorkloadService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        params := mux.Vars(r)
+        res, err := workloadService.ClearEstimate(context.Background(), params["teamId"], params["id"])
+        if err != nil {
+            writeWorkloadError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetTeamWorkload reports open, overdue and completed todos and estimate totals per member
+func GetTeamWorkload(workloadService *workload.WorkloadService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := workloadService.GetWorkload(context.Background(), mux.Vars(r)["teamId"], loc)
+        if err != nil {
+            writeWorkloadError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// Team Members Handlers
+
+func GetTeamMembers(teamMemberService *team_members.TeamMemberService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        params := mux.Vars(r)
+        
+        res, err := teamMemberService.GetTeamMembers(context.Background(), params["teamId"])
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+func AddTeamMember(teamMemberService *team_members.TeamMemberService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.AddTeamMemberRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Set the team ID from the URL parameters
+        params := mux.Vars(r)
+        req.TeamID = params["teamId"]
+        
+        res, err := teamMemberService.AddTeamMember(context.Background(), &req)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+func RemoveTeamMember(teamMemberService *team_members.TeamMemberService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        params := mux.Vars(r)
+        
+        res, err := teamMemberService.RemoveTeamMember(context.Background(), params["teamId"], params["userId"])
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+
+// Routine Handlers
+
+// CreateRoutine creates a new routine
+func CreateRoutine(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.CreateRoutineRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Set the user ID from the context
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        req.UserID = userID
+        
+        res, err := routineService.CreateRoutine(context.Background(), &req)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// UpdateRoutineStatus updates the active status of a routine
+func UpdateRoutineStatus(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
+        var req dto.UpdateRoutineStatusRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Set the ID from the URL parameter
+        req.ID = mux.Vars(r)["id"]
+        
+        res, err := routineService.UpdateRoutineStatus(context.Background(), req.ID, req.IsActive, version)
+        if err != nil {
+            writeVersionedError(w, err)
+            return
+        }
+        
+        if res.Version > 0 {
+            w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        }
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// UpdateRoutineDay updates the day of a routine
+func UpdateRoutineDay(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        version, ok := expectedVersion(w, r)
+        if !ok {
+            return
+        }
+        
+        var req dto.UpdateRoutineDayRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Set the ID from the URL parameter
+        req.ID = mux.Vars(r)["id"]
+        
+        res, err := routineService.UpdateRoutineDay(context.Background(), req.ID, req.Day, version)
+        if err != nil {
+            writeVersionedError(w, err)
+            return
+        }
+        
+        if res.Version > 0 {
+            w.Header().Set("ETag", middleware.VersionETag(res.Version))
+        }
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetRoutinesByTaskID gets routines for a specific task
+func GetRoutinesByTaskID(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        // Get the task ID from the URL parameter
+        taskID := mux.Vars(r)["taskId"]
+        
+        res, err := routineService.GetRoutinesByTaskID(context.Background(), taskID)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        writeConditionalJSON(w, r, res)
+    }
+}
+
+// GetDailyRoutines gets todos for a specific day and schedule type
+func GetDailyRoutines(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        // Get parameters from URL
+        params := mux.Vars(r)
+        day := params["day"]
+        scheduleType := params["scheduleType"]
+        
+        // Get the user ID from the context
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := routineService.GetDailyRoutines(context.Background(), day, scheduleType, userID, loc)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        // Format todos with proper date/time strings
+        formattedTodos := make([]map[string]interface{}, len(res.Todos))
+        for i, todo := range res.Todos {
+            dateStr := ""
+            timeStr := ""
+            
+            if !todo.Date.IsZero() {
+                dateStr = todo.Date.Format("2006-01-02")
+            }
+            
+            if !todo.Time.IsZero() {
+                timeStr = todo.Time.Format("15:04:05")
+            }
+            
+            formattedTodos[i] = map[string]interface{}{
+                "id":          todo.ID,
+                "task":        todo.Task,
+                "description": todo.Description,
+                "done":        todo.Done,
+                "important":   todo.Important,
+                "user_id":     todo.UserID,
+                "date":        dateStr,
+                "time":        timeStr,
+                "version":     todo.Version,
+            }
+        }
+        
+        writeConditionalJSON(w, r, formattedTodos)
+    }
+}
+
+// GetTodayRoutines gets todos for today by schedule type
+func GetTodayRoutines(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        // Get the schedule type from URL
+        scheduleType := mux.Vars(r)["scheduleType"]
+        
+        // Get the user ID from the context
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := routineService.GetTodayRoutines(context.Background(), scheduleType, userID, loc)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        writeConditionalJSON(w, r, res.Todos)
+    }
+}
+
+// GetTodayCheckins gets today's routine todos by schedule type with the routine
+// each belongs to and whether it was checked in today
+func GetTodayCheckins(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        scheduleType := mux.Vars(r)["scheduleType"]
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := routineService.GetTodayCheckins(context.Background(), scheduleType, userID, loc)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        writeConditionalJSON(w, r, res)
+    }
+}
+
+// DeleteRoutinesByTaskID deletes all routines for a task
+func DeleteRoutinesByTaskID(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        // Get the task ID from the URL parameter
+        taskID := mux.Vars(r)["taskId"]
+        
+        res, err := routineService.DeleteRoutinesByTaskID(context.Background(), taskID)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// CreateOrUpdateRoutines creates or updates routines for a task
+func CreateOrUpdateRoutines(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.CreateOrUpdateRoutinesRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Set the user ID from the context
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        req.UserID = userID
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := routineService.CreateOrUpdateRoutines(context.Background(), &req)
+        if err != nil {
+            writeRoutineSlotError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// writeRoutineCheckinError maps routine check-in errors to HTTP status codes
+func writeRoutineCheckinError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "invalid date"),
+        strings.Contains(err.Error(), "future date"),
+        strings.Contains(err.Error(), "not scheduled"),
+        strings.Contains(err.Error(), "not active"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// CheckInRoutine marks a routine done for today or the optional "date" in the body
+func CheckInRoutine(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.RoutineCheckinRequest
+        if r.ContentLength != 0 {
+            if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+                http.Error(w, "Invalid request payload", http.StatusBadRequest)
+                return
+            }
+        }
+        req.RoutineID = mux.Vars(r)["id"]
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := routineService.CheckIn(context.Background(), &req)
+        if err != nil {
+            writeRoutineCheckinError(w, err)
+            return
+        }
+        
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// UncheckRoutine removes the check-in of today or of the "date" query parameter
+func UncheckRoutine(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        req := dto.RoutineCheckinRequest{
+            RoutineID: mux.Vars(r)["id"],
+            UserID:    r.Context().Value(middleware.UserIDKey).(string),
+            Date:      r.URL.Query().Get("date"),
+        }
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := routineService.Uncheck(context.Background(), &req)
+        if err != nil {
+            writeRoutineCheckinError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetRoutineStreak returns the current and longest streak of a routine
+func GetRoutineStreak(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := routineService.GetStreak(context.Background(), mux.Vars(r)["id"], userID, loc)
+        if err != nil {
+            writeRoutineCheckinError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// writeRoutineSlotError maps routine slot errors to HTTP status codes
+func writeRoutineSlotError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "invalid slot name"),
+        strings.Contains(err.Error(), "invalid start time"),
+        strings.Contains(err.Error(), "unknown slot"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "already exists"),
+        strings.Contains(err.Error(), "is in use"),
+        strings.Contains(err.Error(), "last slot"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// GetRoutineSlots lists the user's routine slots, earliest first
+func GetRoutineSlots(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := routineService.GetSlots(context.Background(), userID)
+        if err != nil {
+            writeRoutineSlotError(w, err)
+            return
+        }
+        
+        writeConditionalJSON(w, r, res)
+    }
+}
+
+// CreateRoutineSlot adds a named time of day for routines
+func CreateRoutineSlot(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.RoutineSlotRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := routineService.CreateSlot(context.Background(), &req)
+        if err != nil {
+            writeRoutineSlotError(w, err)
+            return
+        }
+        
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// UpdateRoutineSlot renames or retimes a routine slot
+func UpdateRoutineSlot(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.RoutineSlotRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        req.ID = mux.Vars(r)["id"]
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := routineService.UpdateSlot(context.Background(), &req)
+        if err != nil {
+            writeRoutineSlotError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// DeleteRoutineSlot removes a routine slot that has no routines
+func DeleteRoutineSlot(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := routineService.DeleteSlot(context.Background(), mux.Vars(r)["id"], userID)
+        if err != nil {
+            writeRoutineSlotError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetDayRoutines gets the routines of a day in every slot, ordered by slot start time
+func GetDayRoutines(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := routineService.GetDayRoutines(context.Background(), mux.Vars(r)["day"], userID, loc)
+        if err != nil {
+            writeRoutineSlotError(w, err)
+            return
+        }
+        
+        writeConditionalJSON(w, r, res)
+    }
+}
+
+func writeWeeklyRoutineError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "invalid day"),
+        strings.Contains(err.Error(), "unknown slot"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// SetWeeklyRoutines replaces every day and slot a task repeats in
+func SetWeeklyRoutines(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.SetWeeklyScheduleRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        req.TaskID = mux.Vars(r)["taskId"]
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := routineService.SetWeeklySchedule(context.Background(), &req)
+        if err != nil {
+            writeWeeklyRoutineError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetRoutineWeek returns the user's routines for the whole week grouped by day and slot
+func GetRoutineWeek(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := routineService.GetWeek(context.Background(), userID)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        writeConditionalJSON(w, r, res)
+    }
+}
+
+func writeRoutinePauseError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "invalid start_date"),
+        strings.Contains(err.Error(), "invalid end_date"),
+        strings.Contains(err.Error(), "must not be before"),
+        strings.Contains(err.Error(), "too long"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// GetRoutinePauses lists the user's routine pauses
+func GetRoutinePauses(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := routineService.GetPauses(context.Background(), userID, loc)
+        if err != nil {
+            writeRoutinePauseError(w, err)
+            return
+        }
+        
+        writeConditionalJSON(w, r, res)
+    }
+}
+
+// CreateRoutinePause pauses one routine, or all of the user's routines, over a date range
+func CreateRoutinePause(routineService *routines.RoutineService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.CreateRoutinePauseRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        res, err := routineService.CreatePause(context.Background(), &req, loc)
+        if err != nil {
+            writeRoutinePauseError(w, err)
+            return
+        }
+        
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// DeleteRoutinePause ends a pause early by removing it
+func DeleteRoutinePause(routineService *routines.RoutineService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := routineService.DeletePause(context.Background(), mux.Vars(r)["id"], userID)
+        if err != nil {
+            writeRoutinePauseError(w, err)
+            return
+        }
+        
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetAgenda returns everything the user has on a day, today unless ?date= is given
+func GetAgenda(agendaService *agenda.AgendaService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        
+        req := &dto.AgendaRequest{
+            UserID:   r.Context().Value(middleware.UserIDKey).(string),
+            Date:     r.URL.Query().Get("date"),
+            Location: loc,
+        }
+        res, err := agendaService.GetAgenda(context.Background(), req)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid date") {
+                http.Error(w, err.Error(), http.StatusBadRequest)
+                return
+            }
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        writeConditionalJSON(w, r, res)
+    }
+}
+
+// writeBulkShareError maps errors of sharing with many recipients to status codes
+func writeBulkShareError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "invalid permission"), strings.Contains(err.Error(), "no recipients"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "failed to get todo"):
+        http.Error(w, "Todo not found", http.StatusNotFound)
+    case strings.Contains(err.Error(), "unauthorized"):
+        http.Error(w, "You can only share your own todos", http.StatusForbidden)
+    case strings.Contains(err.Error(), "not a member"):
+        http.Error(w, err.Error(), http.StatusForbidden)
+    default:
+        http.Error(w, "Error sharing todo: "+err.Error(), http.StatusInternalServerError)
+    }
+}
+
+// In handler/api/api.go
+// ShareTodo handles sharing a todo with another user
+func ShareTodo(sharedTodoService *shared_todos.SharedTodoService, userService *users.UserService, todoService *todos.TodoService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        // Parse request body
+        var request struct {
+            TaskId     string   `json:"taskId"`
+            Username   string   `json:"username"`
+            Usernames  []string `json:"usernames"`
+            TeamID     string   `json:"team_id"`
+            Permission string   `json:"permission"`
+        }
+        
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        
+        // Get the current user ID from context
+        currentUserID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        // Several usernames or a team are shared with at once and answered per recipient
+        if len(request.Usernames) > 0 || request.TeamID != "" {
+            usernames := request.Usernames
+            if request.Username != "" {
+                usernames = append(usernames, request.Username)
+            }
+            res, err := sharedTodoService.ShareTodoWithMany(context.Background(), &dto.ShareTodoRequest{
+                TodoID:     request.TaskId,
+                SharedBy:   currentUserID,
+                Usernames:  usernames,
+                TeamID:     request.TeamID,
+                Permission: request.Permission,
+            })
+            if err != nil {
+                writeBulkShareError(w, err)
+                return
+            }
+            json.NewEncoder(w).Encode(res)
+            return
+        }
+        
+        // Find the user by username
+        recipient, err := userService.GetUserByUsername(context.Background(), request.Username)
+        if err != nil {
+            http.Error(w, "User not found", http.StatusNotFound)
+            return
+        }
+        
+        // Cannot share with yourself
+        if recipient.ID == currentUserID {
+            http.Error(w, "Cannot share todo with yourself", http.StatusBadRequest)
+            return
+        }
+        
+        // Share the todo
+        err = sharedTodoService.ShareTodo(context.Background(), request.TaskId, recipient.ID, currentUserID, request.Permission)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid permission") {
+                http.Error(w, err.Error(), http.StatusBadRequest)
+                return
+            }
+            if strings.Contains(err.Error(), "does not accept shares") {
+                http.Error(w, "User does not accept shares from you", http.StatusForbidden)
+                return
+            }
+            if strings.Contains(err.Error(), "already shared") {
+                http.Error(w, "Todo is already shared with this user", http.StatusConflict)
+                return
+            }
+            if strings.Contains(err.Error(), "failed to get todo") {
+                http.Error(w, "Todo not found", http.StatusNotFound)
+                return
+            }
+            if strings.Contains(err.Error(), "unauthorized") {
+                http.Error(w, "You can only share your own todos", http.StatusForbidden)
+                return
+            }
+            http.Error(w, "Error sharing todo: "+err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        // Return success response
+        json.NewEncoder(w).Encode(map[string]string{"result": "success"})
+    }
+}
+
+
+// QuickAddTodo creates a todo from free-form text such as
+// "Pay rent tomorrow 9am !high #home @alice". With "dry_run" only the parse
+// result is returned.
+func QuickAddTodo(quickAddService *quick_add.QuickAddService, userService *users.UserService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        
+        var req dto.QuickAddRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            http.Error(w, "Invalid request payload", http.StatusBadRequest)
+            return
+        }
+        req.UserID = r.Context().Value(middleware.UserIDKey).(string)
+        
+        loc, err := userLocation(r, userService)
+        if err != nil {
+            writeTimezoneError(w, err)
+            return
+        }
+        req.Location = loc
+        
+        res, err := quickAddService.QuickAdd(context.Background(), &req)
+        if err != nil {
+            if strings.Contains(err.Error(), "failed to") {
+                http.Error(w, err.Error(), http.StatusInternalServerError)
+                return
+            }
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        
+        if !req.DryRun {
+            w.WriteHeader(http.StatusCreated)
+        }
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// maxImportBytes limits the size of an uploaded import file
+const maxImportBytes = 5 << 20
+
+// ExportData downloads all of the user's data as JSON or CSV
+func ExportData(transferService *transfer.TransferService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        format := r.URL.Query().Get("format")
+        if format == "" {
+            format = transfer.FormatJSON
+        }
+        
+        data, err := transferService.Export(context.Background(), userID, format)
+        if err != nil {
+            if strings.Contains(err.Error(), "unsupported format") {
+                http.Error(w, "Unsupported export format, use json or csv", http.StatusBadRequest)
+                return
+            }
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        if format == transfer.FormatCSV {
+            w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+        } else {
+            w.Header().Set("Content-Type", "application/json")
+        }
+        w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"todo-export.%s\"", format))
+        w.Write(data)
+    }
+}
+
+// ImportData restores data from a file produced by ExportData
+func ImportData(transferService *transfer.TransferService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        format := r.URL.Query().Get("format")
+        if format == "" {
+            format = transfer.FormatJSON
+            if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+                format = transfer.FormatCSV
+            }
+        }
+        
+        data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBytes))
+        if err != nil {
+            http.Error(w, "Import file is too large or unreadable", http.StatusBadRequest)
+            return
+        }
+        
+        req := dto.ImportRequest{
+            UserID: userID,
+            Format: format,
+            IDMode: r.URL.Query().Get("ids"),
+            Data:   data,
+        }
+        res, err := transferService.Import(context.Background(), &req)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid import") {
+                w.WriteHeader(http.StatusBadRequest)
+                if res != nil {
+                    json.NewEncoder(w).Encode(res)
+                } else {
+                    json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                }
+                return
+            }
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// CreateCalendarFeed issues a new secret iCalendar feed URL, revoking any previous one
+func CreateCalendarFeed(calendarService *calendar.CalendarService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := calendarService.CreateFeedToken(context.Background(), userID)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        scheme := "http"
+        if r.TLS != nil {
+            scheme = "https"
+        }
+        if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+            scheme = proto
+        }
+        res.URL = fmt.Sprintf("%s://%s/api/v1/calendar/feed/%s.ics", scheme, r.Host, res.Token)
+        
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// RevokeCalendarFeed disables the user's iCalendar feed URL
+func RevokeCalendarFeed(calendarService *calendar.CalendarService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        userID := r.Context().Value(middleware.UserIDKey).(string)
+        
+        res, err := calendarService.RevokeFeedToken(context.Background(), userID)
+        if err != nil {
+            if strings.Contains(err.Error(), "not found") {
+                http.Error(w, "No calendar feed to revoke", http.StatusNotFound)
+                return
+            }
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        json.NewEncoder(w).Encode(res)
+    }
+}
+
+// GetCalendarFeed serves the iCalendar feed. The secret token in the URL is the only
+// credential, since calendar apps cannot send an Authorization header.
+func GetCalendarFeed(calendarService *calendar.CalendarService) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        params := mux.Vars(r)
+        
+        data, err := calendarService.GetFeed(context.Background(), params["token"], r.URL.Query().Get("todos"))
+        if err != nil {
+            if strings.Contains(err.Error(), "unsupported entry mode") {
+                http.Error(w, "Unsupported todos mode, use todo or event", http.StatusBadRequest)
+                return
+            }
+            if strings.Contains(err.Error(), "not found") {
+                http.Error(w, "Calendar feed not found", http.StatusNotFound)
+                return
+            }
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        
+        w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+        w.Header().Set("Cache-Control", "private, max-age=300")
+        w.Write(data)
+    }
+}
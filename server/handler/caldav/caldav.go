@@ -0,0 +1,318 @@
+package caldav
+
+import (
+    "context"
+    "io"
+    "net/http"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/middleware"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/calendar"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/team_todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/teams"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+)
+
+// maxObjectBytes limits the size of a calendar object sent with PUT
+const maxObjectBytes = 1 << 20
+
+const allowedMethods = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT"
+
+// objectIDPattern restricts client-chosen object names to what fits the id columns
+var objectIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,36}$`)
+
+// Handler serves each user's todos and their teams' todos as CalDAV (RFC 4791)
+// calendar collections of VTODO objects:
+//
+//     {prefix}/                         service root
+//     {prefix}/{userID}/                principal and calendar home
+//     {prefix}/{userID}/todos/          the user's own todos
+//     {prefix}/{userID}/team-{teamID}/  todos of a team the user belongs to
+//     {prefix}/{userID}/{collection}/{todoID}.ics
+//
+// Clients authenticate with HTTP Basic using their app username and password.
+// ETags are the todo versions also used by the REST API, and all writes go
+// through the todo services.
+type Handler struct {
+    prefix          string
+    userService     *users.UserService
+    todoService     *todos.TodoService
+    teamService     *teams.TeamService
+    teamTodoService *team_todos.TeamTodoService
+}
+
+// NewHandler returns a CalDAV handler for requests under prefix, e.g. "/caldav"
+func NewHandler(
+    prefix string,
+    userService *users.UserService,
+    todoService *todos.TodoService,
+    teamService *teams.TeamService,
+    teamTodoService *team_todos.TeamTodoService,
+) *Handler {
+    return &Handler{
+        prefix:          strings.TrimSuffix(prefix, "/"),
+        userService:     userService,
+        todoService:     todoService,
+        teamService:     teamService,
+        teamTodoService: teamTodoService,
+    }
+}
+
+type targetKind int
+
+const (
+    targetRoot targetKind = iota
+    targetHome
+    targetCollection
+    targetObject
+)
+
+// target is the resource a request path points at
+type target struct {
+    kind       targetKind
+    userID     string
+    collection string
+    objectID   string
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    userID, ok := h.authenticate(r)
+    if !ok {
+        w.Header().Set("WWW-Authenticate", `Basic realm="Todos CalDAV", charset="UTF-8"`)
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    t, ok := h.parsePath(r.URL.Path)
+    if !ok {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+    if t.kind != targetRoot && t.userID != userID {
+        http.Error(w, "Forbidden", http.StatusForbidden)
+        return
+    }
+
+    switch r.Method {
+    case http.MethodOptions:
+        w.Header().Set("DAV", "1, 3, calendar-access")
+        w.Header().Set("Allow", allowedMethods)
+        w.WriteHeader(http.StatusOK)
+    case "PROPFIND":
+        h.propfind(w, r, userID, t)
+    case "REPORT":
+        h.report(w, r, userID, t)
+    case http.MethodGet, http.MethodHead:
+        h.get(w, r, userID, t)
+    case http.MethodPut:
+        h.put(w, r, userID, t)
+    case http.MethodDelete:
+        h.delete(w, r, userID, t)
+    default:
+        w.Header().Set("Allow", allowedMethods)
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+    }
+}
+
+// authenticate checks HTTP Basic credentials and returns the user ID
+func (h *Handler) authenticate(r *http.Request) (string, bool) {
+    username, password, ok := r.BasicAuth()
+    if !ok {
+        return "", false
+    }
+    user, err := h.userService.GetUserByUsername(context.Background(), username)
+    if err != nil {
+        return "", false
+    }
+    if err := h.userService.VerifyPassword(user.Password, password); err != nil {
+        return "", false
+    }
+    return user.ID, true
+}
+
+func (h *Handler) parsePath(path string) (target, bool) {
+    rest := strings.TrimPrefix(path, h.prefix)
+    if rest == path && h.prefix != "" {
+        return target{}, false
+    }
+    rest = strings.Trim(rest, "/")
+    if rest == "" {
+        return target{kind: targetRoot}, true
+    }
+
+    segments := strings.Split(rest, "/")
+    switch len(segments) {
+    case 1:
+        return target{kind: targetHome, userID: segments[0]}, true
+    case 2:
+        return target{kind: targetCollection, userID: segments[0], collection: segments[1]}, true
+    case 3:
+        id := strings.TrimSuffix(segments[2], ".ics")
+        if id == segments[2] || !objectIDPattern.MatchString(id) {
+            return target{}, false
+        }
+        return target{kind: targetObject, userID: segments[0], collection: segments[1], objectID: id}, true
+    }
+    return target{}, false
+}
+
+func (h *Handler) homeHref(userID string) string {
+    return h.prefix + "/" + userID + "/"
+}
+
+func (h *Handler) collectionHref(userID, name string) string {
+    return h.homeHref(userID) + name + "/"
+}
+
+func (h *Handler) objectHref(userID, name, id string) string {
+    return h.collectionHref(userID, name) + id + ".ics"
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, userID string, t target) {
+    if t.kind != targetObject {
+        w.Header().Set("Allow", "OPTIONS, PROPFIND, REPORT")
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    _, o, ok := h.lookupObject(w, userID, t)
+    if !ok {
+        return
+    }
+    if o == nil {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+
+    data := calendar.EncodeTodoResource(o.Todo, time.Now())
+    w.Header().Set("Content-Type", "text/calendar; charset=utf-8; component=VTODO")
+    w.Header().Set("ETag", middleware.VersionETag(o.Version))
+    w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+    if r.Method == http.MethodHead {
+        return
+    }
+    w.Write(data)
+}
+
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, userID string, t target) {
+    if t.kind != targetObject {
+        w.Header().Set("Allow", "OPTIONS, PROPFIND, REPORT")
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxObjectBytes))
+    if err != nil {
+        http.Error(w, "Calendar object is too large", http.StatusRequestEntityTooLarge)
+        return
+    }
+    todo, err := calendar.ParseTodoResource(body)
+    if err != nil {
+        http.Error(w, "Invalid calendar data: "+err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    c, existing, ok := h.lookupObject(w, userID, t)
+    if !ok {
+        return
+    }
+
+    // Conditional requests guard against overwriting changes made elsewhere
+    if r.Header.Get("If-None-Match") == "*" && existing != nil {
+        http.Error(w, "Object already exists", http.StatusPreconditionFailed)
+        return
+    }
+    expected, ok := middleware.IfMatchVersion(r)
+    if !ok || (r.Header.Get("If-Match") != "" && existing == nil) {
+        http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+        return
+    }
+
+    version, err := h.saveObject(context.Background(), userID, c, t.objectID, todo, existing, expected)
+    if err != nil {
+        writeStoreError(w, err)
+        return
+    }
+
+    w.Header().Set("ETag", middleware.VersionETag(version))
+    if existing == nil {
+        w.WriteHeader(http.StatusCreated)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, userID string, t target) {
+    if t.kind != targetObject {
+        w.Header().Set("Allow", "OPTIONS, PROPFIND, REPORT")
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    c, existing, ok := h.lookupObject(w, userID, t)
+    if !ok {
+        return
+    }
+    if existing == nil {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+    expected, ok := middleware.IfMatchVersion(r)
+    if !ok {
+        http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+        return
+    }
+
+    if err := h.deleteObject(context.Background(), userID, c, t.objectID, expected); err != nil {
+        writeStoreError(w, err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// lookupObject resolves the collection and object of an object target. It writes
+// the error response and returns false when the request cannot continue.
+func (h *Handler) lookupObject(w http.ResponseWriter, userID string, t target) (*collection, *object, bool) {
+    c, err := h.collection(context.Background(), userID, t.collection)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return nil, nil, false
+    }
+    if c == nil {
+        http.Error(w, "Collection not found", http.StatusNotFound)
+        return nil, nil, false
+    }
+
+    o, err := h.object(context.Background(), userID, c, t.objectID)
+    if err == errForbidden {
+        http.Error(w, "Forbidden", http.StatusForbidden)
+        return nil, nil, false
+    }
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return nil, nil, false
+    }
+    return c, o, true
+}
+
+// writeStoreError maps service errors from writes to status codes
+func writeStoreError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "version mismatch"):
+        http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, "Not found", http.StatusNotFound)
+    case strings.Contains(err.Error(), "invalid patch"), strings.Contains(err.Error(), "cannot be empty"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "Duplicate entry"):
+        http.Error(w, "Object ID is already in use", http.StatusConflict)
+    case strings.Contains(err.Error(), "blocked by open todos"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    default:
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+    }
+}
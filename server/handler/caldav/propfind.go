@@ -0,0 +1,415 @@
+package caldav
+
+import (
+    "bytes"
+    "context"
+    "encoding/xml"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "strings"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/middleware"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/calendar"
+)
+
+// XML namespaces and the prefixes they are written with
+const (
+    nsDAV            = "DAV:"
+    nsCalDAV         = "urn:ietf:params:xml:ns:caldav"
+    nsCalendarServer = "http://calendarserver.org/ns/"
+)
+
+var nsPrefixes = map[string]string{
+    nsDAV:            "D",
+    nsCalDAV:         "C",
+    nsCalendarServer: "CS",
+}
+
+// maxRequestBytes limits the size of PROPFIND and REPORT bodies
+const maxRequestBytes = 1 << 20
+
+var (
+    propResourceType         = xml.Name{Space: nsDAV, Local: "resourcetype"}
+    propDisplayName          = xml.Name{Space: nsDAV, Local: "displayname"}
+    propCurrentUserPrincipal = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+    propPrincipalURL         = xml.Name{Space: nsDAV, Local: "principal-URL"}
+    propPrivilegeSet         = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+    propSupportedReportSet   = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+    propGetETag              = xml.Name{Space: nsDAV, Local: "getetag"}
+    propGetContentType       = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+    propCalendarHomeSet      = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+    propSupportedComponents  = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+    propCalendarData         = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+    propGetCTag              = xml.Name{Space: nsCalendarServer, Local: "getctag"}
+)
+
+// propertySet maps property names to the inner XML of their values
+type propertySet map[xml.Name]string
+
+// davResponse is one <D:response> of a multistatus body
+type davResponse struct {
+    href   string
+    props  propertySet
+    status int // set instead of props when the resource itself is missing
+}
+
+// propRequest lists the properties a PROPFIND or REPORT asked for. A nil
+// request means allprop.
+type propRequest struct {
+    Names []xml.Name
+}
+
+type anyElement struct {
+    XMLName xml.Name
+}
+
+type propfindBody struct {
+    XMLName  xml.Name     `xml:"DAV: propfind"`
+    AllProp  *struct{}    `xml:"DAV: allprop"`
+    PropName *struct{}    `xml:"DAV: propname"`
+    Prop     *propElement `xml:"DAV: prop"`
+}
+
+type propElement struct {
+    Props []anyElement `xml:",any"`
+}
+
+type reportBody struct {
+    XMLName xml.Name
+    Prop    *propElement `xml:"DAV: prop"`
+    Hrefs   []string     `xml:"DAV: href"`
+    Filter  *compFilter  `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+}
+
+type compFilter struct {
+    Name    string       `xml:"name,attr"`
+    Filters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+func (h *Handler) propfind(w http.ResponseWriter, r *http.Request, userID string, t target) {
+    body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+    if err != nil {
+        http.Error(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+        return
+    }
+
+    var req *propRequest
+    namesOnly := false
+    if len(bytes.TrimSpace(body)) > 0 {
+        var parsed propfindBody
+        if err := xml.Unmarshal(body, &parsed); err != nil {
+            http.Error(w, "Invalid PROPFIND body", http.StatusBadRequest)
+            return
+        }
+        switch {
+        case parsed.Prop != nil:
+            req = newPropRequest(parsed.Prop)
+        case parsed.PropName != nil:
+            namesOnly = true
+        }
+    }
+
+    // Depth infinity is treated as 1, which covers everything below a collection
+    depth := r.Header.Get("Depth")
+    children := depth != "0"
+
+    ctx := context.Background()
+    var responses []davResponse
+    switch t.kind {
+    case targetRoot:
+        responses = append(responses, davResponse{href: h.prefix + "/", props: h.rootProps(userID)})
+        if children {
+            responses = append(responses, davResponse{href: h.homeHref(userID), props: h.homeProps(userID)})
+        }
+    case targetHome:
+        responses = append(responses, davResponse{href: h.homeHref(userID), props: h.homeProps(userID)})
+        if children {
+            collections, err := h.collections(ctx, userID)
+            if err != nil {
+                http.Error(w, err.Error(), http.StatusInternalServerError)
+                return
+            }
+            for _, c := range collections {
+                objects, err := h.objects(ctx, userID, &c)
+                if err != nil {
+                    http.Error(w, err.Error(), http.StatusInternalServerError)
+                    return
+                }
+                responses = append(responses, davResponse{href: h.collectionHref(userID, c.Name), props: h.collectionProps(userID, &c, objects)})
+            }
+        }
+    case targetCollection:
+        c, err := h.collection(ctx, userID, t.collection)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        if c == nil {
+            http.Error(w, "Collection not found", http.StatusNotFound)
+            return
+        }
+        objects, err := h.objects(ctx, userID, c)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        responses = append(responses, davResponse{href: h.collectionHref(userID, c.Name), props: h.collectionProps(userID, c, objects)})
+        if children {
+            for _, o := range objects {
+                responses = append(responses, davResponse{href: h.objectHref(userID, c.Name, o.ID), props: objectProps(o, req)})
+            }
+        }
+    case targetObject:
+        c, o, ok := h.lookupObject(w, userID, t)
+        if !ok {
+            return
+        }
+        if o == nil {
+            http.Error(w, "Not found", http.StatusNotFound)
+            return
+        }
+        responses = append(responses, davResponse{href: h.objectHref(userID, c.Name, o.ID), props: objectProps(*o, req)})
+    }
+
+    writeMultistatus(w, responses, req, namesOnly)
+}
+
+func (h *Handler) report(w http.ResponseWriter, r *http.Request, userID string, t target) {
+    if t.kind != targetCollection {
+        http.Error(w, "REPORT is only supported on calendar collections", http.StatusForbidden)
+        return
+    }
+
+    body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+    if err != nil {
+        http.Error(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+        return
+    }
+    var parsed reportBody
+    if err := xml.Unmarshal(body, &parsed); err != nil {
+        http.Error(w, "Invalid REPORT body", http.StatusBadRequest)
+        return
+    }
+    var req *propRequest
+    if parsed.Prop != nil {
+        req = newPropRequest(parsed.Prop)
+    }
+
+    ctx := context.Background()
+    c, err := h.collection(ctx, userID, t.collection)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    if c == nil {
+        http.Error(w, "Collection not found", http.StatusNotFound)
+        return
+    }
+    objects, err := h.objects(ctx, userID, c)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    var responses []davResponse
+    switch parsed.XMLName {
+    case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+        // Only VTODO objects exist, so a query for any other component matches nothing
+        if parsed.Filter != nil && !matchesTodos(*parsed.Filter) {
+            break
+        }
+        for _, o := range objects {
+            responses = append(responses, davResponse{href: h.objectHref(userID, c.Name, o.ID), props: objectProps(o, req)})
+        }
+    case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+        byID := make(map[string]object, len(objects))
+        for _, o := range objects {
+            byID[o.ID] = o
+        }
+        for _, href := range parsed.Hrefs {
+            path := href
+            if parsedURL, err := url.Parse(href); err == nil {
+                path = parsedURL.Path
+            }
+            ht, ok := h.parsePath(path)
+            o, found := byID[ht.objectID]
+            if !ok || ht.kind != targetObject || ht.userID != userID || ht.collection != c.Name || !found {
+                responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+                continue
+            }
+            responses = append(responses, davResponse{href: href, props: objectProps(o, req)})
+        }
+    default:
+        http.Error(w, "Unsupported report", http.StatusForbidden)
+        return
+    }
+
+    writeMultistatus(w, responses, req, false)
+}
+
+// matchesTodos reports whether a VCALENDAR comp-filter can match VTODO objects
+func matchesTodos(filter compFilter) bool {
+    if !strings.EqualFold(filter.Name, "VCALENDAR") {
+        return false
+    }
+    if len(filter.Filters) == 0 {
+        return true
+    }
+    for _, nested := range filter.Filters {
+        if strings.EqualFold(nested.Name, "VTODO") {
+            return true
+        }
+    }
+    return false
+}
+
+func newPropRequest(prop *propElement) *propRequest {
+    req := &propRequest{}
+    for _, p := range prop.Props {
+        req.Names = append(req.Names, p.XMLName)
+    }
+    return req
+}
+
+func (req *propRequest) wants(name xml.Name) bool {
+    if req == nil {
+        return false
+    }
+    for _, n := range req.Names {
+        if n == name {
+            return true
+        }
+    }
+    return false
+}
+
+func href(value string) string {
+    return "<D:href>" + escapeXML(value) + "</D:href>"
+}
+
+func (h *Handler) rootProps(userID string) propertySet {
+    return propertySet{
+        propResourceType:         "<D:collection/>",
+        propCurrentUserPrincipal: href(h.homeHref(userID)),
+    }
+}
+
+func (h *Handler) homeProps(userID string) propertySet {
+    return propertySet{
+        propResourceType:         "<D:collection/><D:principal/>",
+        propDisplayName:          "Todos",
+        propCurrentUserPrincipal: href(h.homeHref(userID)),
+        propPrincipalURL:         href(h.homeHref(userID)),
+        propCalendarHomeSet:      href(h.homeHref(userID)),
+    }
+}
+
+func (h *Handler) collectionProps(userID string, c *collection, objects []object) propertySet {
+    return propertySet{
+        propResourceType:         "<D:collection/><C:calendar/>",
+        propDisplayName:          escapeXML(c.DisplayName),
+        propCurrentUserPrincipal: href(h.homeHref(userID)),
+        propSupportedComponents:  `<C:comp name="VTODO"/>`,
+        propSupportedReportSet: "<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report>" +
+            "<D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>",
+        propPrivilegeSet: "<D:privilege><D:read/></D:privilege><D:privilege><D:write/></D:privilege>" +
+            "<D:privilege><D:write-content/></D:privilege><D:privilege><D:bind/></D:privilege>" +
+            "<D:privilege><D:unbind/></D:privilege>",
+        propGetCTag: escapeXML(collectionTag(objects)),
+    }
+}
+
+// objectProps lists an object's properties. The calendar data is only included
+// when asked for, as it is not part of allprop.
+func objectProps(o object, req *propRequest) propertySet {
+    props := propertySet{
+        propResourceType:   "",
+        propGetETag:        escapeXML(middleware.VersionETag(o.Version)),
+        propGetContentType: "text/calendar; charset=utf-8; component=VTODO",
+    }
+    if req.wants(propCalendarData) {
+        props[propCalendarData] = escapeXML(string(calendar.EncodeTodoResource(o.Todo, time.Now())))
+    }
+    return props
+}
+
+// writeMultistatus writes a 207 response. Requested properties a resource does
+// not have are reported in a 404 propstat, as RFC 4918 requires.
+func writeMultistatus(w http.ResponseWriter, responses []davResponse, req *propRequest, namesOnly bool) {
+    var buf bytes.Buffer
+    buf.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+    buf.WriteString(fmt.Sprintf(`<D:multistatus xmlns:D="%s" xmlns:C="%s" xmlns:CS="%s">`, nsDAV, nsCalDAV, nsCalendarServer))
+
+    for _, res := range responses {
+        buf.WriteString("<D:response>")
+        buf.WriteString(href(res.href))
+        if res.status != 0 {
+            buf.WriteString(statusLine(res.status))
+            buf.WriteString("</D:response>")
+            continue
+        }
+
+        var found, missing bytes.Buffer
+        if req == nil {
+            for name, value := range res.props {
+                if namesOnly {
+                    value = ""
+                }
+                writeProp(&found, name, value)
+            }
+        } else {
+            for _, name := range req.Names {
+                if value, ok := res.props[name]; ok {
+                    writeProp(&found, name, value)
+                } else {
+                    writeProp(&missing, name, "")
+                }
+            }
+        }
+
+        if found.Len() > 0 {
+            buf.WriteString("<D:propstat><D:prop>")
+            buf.Write(found.Bytes())
+            buf.WriteString("</D:prop>" + statusLine(http.StatusOK) + "</D:propstat>")
+        }
+        if missing.Len() > 0 {
+            buf.WriteString("<D:propstat><D:prop>")
+            buf.Write(missing.Bytes())
+            buf.WriteString("</D:prop>" + statusLine(http.StatusNotFound) + "</D:propstat>")
+        }
+        buf.WriteString("</D:response>")
+    }
+    buf.WriteString("</D:multistatus>")
+
+    w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+    w.WriteHeader(http.StatusMultiStatus)
+    w.Write(buf.Bytes())
+}
+
+func writeProp(buf *bytes.Buffer, name xml.Name, value string) {
+    prefix, known := nsPrefixes[name.Space]
+    if !known {
+        // Unknown namespaces are declared on the element itself
+        buf.WriteString(fmt.Sprintf(`<X:%s xmlns:X="%s"/>`, name.Local, escapeXML(name.Space)))
+        return
+    }
+    tag := prefix + ":" + name.Local
+    if value == "" {
+        buf.WriteString("<" + tag + "/>")
+        return
+    }
+    buf.WriteString("<" + tag + ">" + value + "</" + tag + ">")
+}
+
+func statusLine(status int) string {
+    return fmt.Sprintf("<D:status>HTTP/1.1 %d %s</D:status>", status, http.StatusText(status))
+}
+
+func escapeXML(value string) string {
+    var buf bytes.Buffer
+    xml.EscapeText(&buf, []byte(value))
+    return buf.String()
+}
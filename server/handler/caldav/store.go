@@ -0,0 +1,251 @@
+package caldav
+
+import (
+    "context"
+    "crypto/sha1"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/calendar"
+)
+
+// personalCollection is the collection name of the user's own todos. Team
+// collections are named teamCollectionPrefix + team ID.
+const (
+    personalCollection   = "todos"
+    teamCollectionPrefix = "team-"
+)
+
+// errForbidden is returned when an object ID belongs to another user
+var errForbidden = fmt.Errorf("forbidden")
+
+// collection is a calendar collection backed by the user's todos or a team's todos
+type collection struct {
+    Name        string
+    DisplayName string
+    TeamID      string // empty for the personal collection
+}
+
+// object is a single todo exposed as a calendar object resource
+type object struct {
+    ID      string
+    Version int
+    Todo    calendar.TodoResource
+}
+
+func (h *Handler) collections(ctx context.Context, userID string) ([]collection, error) {
+    collections := []collection{{Name: personalCollection, DisplayName: "My Todos"}}
+
+    teams, err := h.teamService.GetTeams(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    for _, team := range teams.Teams {
+        collections = append(collections, collection{
+            Name:        teamCollectionPrefix + team.ID,
+            DisplayName: team.Name,
+            TeamID:      team.ID,
+        })
+    }
+    return collections, nil
+}
+
+// collection returns the named collection, or nil if the user cannot see it
+func (h *Handler) collection(ctx context.Context, userID, name string) (*collection, error) {
+    if name != personalCollection && !strings.HasPrefix(name, teamCollectionPrefix) {
+        return nil, nil
+    }
+    collections, err := h.collections(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    for _, c := range collections {
+        if c.Name == name {
+            return &c, nil
+        }
+    }
+    return nil, nil
+}
+
+func (h *Handler) objects(ctx context.Context, userID string, c *collection) ([]object, error) {
+    var objects []object
+    if c.TeamID == "" {
+        res, err := h.todoService.GetTodosByUserID(ctx, userID)
+        if err != nil {
+            return nil, err
+        }
+        for _, todo := range res.Todos {
+            objects = append(objects, newObject(todo.ID, todo.Version, todo.Task, todo.Description, todo.Done, todo.Important, todo.Date, todo.Time))
+        }
+        return objects, nil
+    }
+
+    res, err := h.teamTodoService.GetTeamTodos(ctx, c.TeamID, nil)
+    if err != nil {
+        return nil, err
+    }
+    for _, todo := range res.Todos {
+        objects = append(objects, newObject(todo.ID, todo.Version, todo.Task, todo.Description, todo.Done, todo.Important, todo.Date, todo.Time))
+    }
+    return objects, nil
+}
+
+// object returns a single object, nil if it does not exist, or errForbidden
+// if the ID is taken by another user's todo
+func (h *Handler) object(ctx context.Context, userID string, c *collection, id string) (*object, error) {
+    if c.TeamID == "" {
+        todo, err := h.todoService.GetTodoByID(ctx, id)
+        if err != nil {
+            if strings.Contains(err.Error(), "not found") {
+                return nil, nil
+            }
+            return nil, err
+        }
+        if todo.UserID != userID {
+            return nil, errForbidden
+        }
+        o := newObject(todo.ID, todo.Version, todo.Task, todo.Description, todo.Done, todo.Important, todo.Date, todo.Time)
+        return &o, nil
+    }
+
+    todo, err := h.teamTodoService.GetTeamTodo(ctx, id, c.TeamID, nil)
+    if err != nil {
+        if strings.Contains(err.Error(), "not found") {
+            return nil, nil
+        }
+        return nil, err
+    }
+    o := newObject(todo.ID, todo.Version, todo.Task, todo.Description, todo.Done, todo.Important, todo.Date, todo.Time)
+    return &o, nil
+}
+
+// saveObject creates the object, or updates it when existing is set, and returns its new version
+func (h *Handler) saveObject(ctx context.Context, userID string, c *collection, id string, todo *calendar.TodoResource, existing *object, expectedVersion int) (int, error) {
+    date, timeValue := splitDue(todo)
+
+    if existing == nil {
+        if c.TeamID == "" {
+            _, err := h.todoService.CreateTodoWithID(ctx, id, &dto.CreateTodoRequest{
+                Task:        todo.Summary,
+                Description: todo.Description,
+                Done:        todo.Done,
+                Important:   todo.Important,
+                UserID:      userID,
+                Date:        date,
+                Time:        timeValue,
+            })
+            return 1, err
+        }
+        _, err := h.teamTodoService.CreateTeamTodoWithID(ctx, id, &dto.CreateTeamTodoRequest{
+            Task:        todo.Summary,
+            Description: todo.Description,
+            Done:        todo.Done,
+            Important:   todo.Important,
+            TeamID:      c.TeamID,
+            Date:        date,
+            Time:        timeValue,
+            CreatedBy:   userID,
+        })
+        return 1, err
+    }
+
+    fields, err := patchFields(todo, date, timeValue)
+    if err != nil {
+        return 0, err
+    }
+    if c.TeamID == "" {
+        res, err := h.todoService.PatchTodo(ctx, &dto.PatchTodoRequest{ID: id, UserID: userID, Fields: fields, Version: expectedVersion})
+        if err != nil {
+            return 0, err
+        }
+        return res.Version, nil
+    }
+    res, err := h.teamTodoService.PatchTeamTodo(ctx, &dto.PatchTeamTodoRequest{ID: id, TeamID: c.TeamID, UserID: userID, Fields: fields, Version: expectedVersion})
+    if err != nil {
+        return 0, err
+    }
+    return res.Version, nil
+}
+
+func (h *Handler) deleteObject(ctx context.Context, userID string, c *collection, id string, expectedVersion int) error {
+    if c.TeamID == "" {
+        _, err := h.todoService.DeleteTodo(ctx, id, userID, expectedVersion)
+        return err
+    }
+    _, err := h.teamTodoService.DeleteTeamTodo(ctx, id, c.TeamID, expectedVersion)
+    return err
+}
+
+func newObject(id string, version int, task, description string, done, important bool, date, timeValue time.Time) object {
+    todo := calendar.TodoResource{
+        UID:         id,
+        Summary:     task,
+        Description: description,
+        Done:        done,
+        Important:   important,
+    }
+    if !date.IsZero() {
+        todo.Due = date
+        todo.AllDay = timeValue.IsZero()
+        if !todo.AllDay {
+            hour, min, sec := timeValue.Clock()
+            todo.Due = time.Date(date.Year(), date.Month(), date.Day(), hour, min, sec, 0, time.UTC)
+        }
+    }
+    return object{ID: id, Version: version, Todo: todo}
+}
+
+// splitDue converts a DUE value into the app's separate date and time columns
+func splitDue(todo *calendar.TodoResource) (time.Time, time.Time) {
+    if todo.Due.IsZero() {
+        return time.Time{}, time.Time{}
+    }
+    date := time.Date(todo.Due.Year(), todo.Due.Month(), todo.Due.Day(), 0, 0, 0, 0, time.UTC)
+    if todo.AllDay {
+        return date, time.Time{}
+    }
+    hour, min, sec := todo.Due.Clock()
+    return date, time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
+}
+
+// patchFields builds a merge patch that replaces every field a VTODO carries
+func patchFields(todo *calendar.TodoResource, date, timeValue time.Time) (map[string]json.RawMessage, error) {
+    values := map[string]interface{}{
+        "task":        todo.Summary,
+        "description": todo.Description,
+        "done":        todo.Done,
+        "important":   todo.Important,
+        "date":        nil,
+        "time":        nil,
+    }
+    if !date.IsZero() {
+        values["date"] = date.Format("2006-01-02")
+    }
+    if !timeValue.IsZero() {
+        values["time"] = timeValue.Format("15:04:05")
+    }
+
+    fields := make(map[string]json.RawMessage, len(values))
+    for key, value := range values {
+        raw, err := json.Marshal(value)
+        if err != nil {
+            return nil, err
+        }
+        fields[key] = raw
+    }
+    return fields, nil
+}
+
+// collectionTag changes whenever any object in the collection is added, changed or removed
+func collectionTag(objects []object) string {
+    entries := make([]string, len(objects))
+    for i, o := range objects {
+        entries[i] = fmt.Sprintf("%s:%d", o.ID, o.Version)
+    }
+    sort.Strings(entries)
+    return fmt.Sprintf("\"%x\"", sha1.Sum([]byte(strings.Join(entries, ","))))
+}
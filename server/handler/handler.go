@@ -2,8 +2,10 @@ package handler
 
 import (
     "database/sql"
+    "net/http"
     "github.com/gorilla/mux"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/api"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/caldav"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/handler/middleware"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/users_repository"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/todos_repository"
@@ -12,6 +14,11 @@ import (
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/team_todos_repository"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/shared_todos_repository"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/routine_repository"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/calendar_repository"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dependencies_repository"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/workflow_repository"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/time_entries_repository"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/workload_repository"
 
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
@@ -20,6 +27,14 @@ import (
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/team_todos"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/shared_todos"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/transfer"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/calendar"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/workflow"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/time_entries"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/workload"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/agenda"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/quick_add"
 )
 
 func SetupRoutes(router *mux.Router, DB *sql.DB) {
@@ -31,22 +46,38 @@ func SetupRoutes(router *mux.Router, DB *sql.DB) {
     teamTodoRepo := team_todos_repository.NewTeamTodoRepository(DB)
     sharedTodoRepo := shared_todos_repository.NewSharedTodoRepository(DB)
     routineRepo := routine_repository.NewRoutineRepository(DB)
+    calendarFeedRepo := calendar_repository.NewCalendarFeedRepository(DB)
+    dependencyRepo := dependencies_repository.NewDependencyRepository(DB)
+    workflowRepo := workflow_repository.NewWorkflowRepository(DB)
+    timeEntryRepo := time_entries_repository.NewTimeEntryRepository(DB)
+    workloadRepo := workload_repository.NewWorkloadRepository(DB)
 
     // Initialize services
     userService := users.NewUserService(userRepo)
-    todoService := todos.NewTodoService(todoRepo)
+    dependencyService := dependencies.NewDependencyService(dependencyRepo, todoRepo, teamTodoRepo)
+    todoService := todos.NewTodoService(todoRepo, dependencyService)
     teamService := teams.NewTeamService(teamRepo)
     teamMemberService := team_members.NewTeamMemberService(teamMemberRepo)
-    teamTodoService := team_todos.NewTeamTodoService(teamTodoRepo)
-    sharedTodoService := shared_todos.NewSharedTodoService(sharedTodoRepo, todoRepo, userRepo)
-    routineService := routines.NewRoutineService(routineRepo)
+    teamTodoService := team_todos.NewTeamTodoService(teamTodoRepo, teamMemberRepo, dependencyService)
+    sharedTodoService := shared_todos.NewSharedTodoService(sharedTodoRepo, todoRepo, userRepo, teamMemberRepo)
+    routineService := routines.NewRoutineService(routineRepo, todoRepo)
+    transferService := transfer.NewTransferService(todoRepo, routineRepo, sharedTodoRepo, teamRepo, teamTodoRepo, teamMemberRepo)
+    calendarService := calendar.NewCalendarService(calendarFeedRepo, todoRepo, routineRepo, teamRepo, teamTodoRepo)
+    workflowService := workflow.NewWorkflowService(workflowRepo, teamTodoRepo, dependencyService)
+    timeEntryService := time_entries.NewTimeEntryService(timeEntryRepo, todoRepo, teamTodoRepo)
+    workloadService := workload.NewWorkloadService(workloadRepo, teamTodoRepo)
+    agendaService := agenda.NewAgendaService(todoRepo, routineService, sharedTodoRepo, teamRepo, teamTodoRepo)
+    quickAddService := quick_add.NewQuickAddService(todoRepo, todoService, routineService, sharedTodoService, userService)
 
     // Setup API v1 routes
-    setupV1Routes(router, userService, todoService, teamService, teamMemberService, teamTodoService, sharedTodoService, routineService)
+    setupV1Routes(router, userService, todoService, teamService, teamMemberService, teamTodoService, sharedTodoService, routineService, transferService, calendarService, dependencyService, workflowService, timeEntryService, workloadService, agendaService, quickAddService)
+    
+    // CalDAV endpoint for two-way sync with calendar and task clients
+    setupCalDAVRoutes(router, userService, todoService, teamService, teamTodoService)
     
     // For backward compatibility, maintain the existing API routes
     // This helps existing clients to continue working while new clients can use v1 API
-    setupLegacyRoutes(router, userService, todoService, teamService, teamMemberService, teamTodoService, sharedTodoService, routineService)
+    setupLegacyRoutes(router, userService, todoService, teamService, teamMemberService, teamTodoService, sharedTodoService, routineService, dependencyService, workloadService)
 }
 
 // setupV1Routes configures the versioned API endpoints
@@ -59,6 +90,14 @@ func setupV1Routes(
     teamTodoService *team_todos.TeamTodoService,
     sharedTodoService *shared_todos.SharedTodoService,
     routineService *routines.RoutineService,
+    transferService *transfer.TransferService,
+    calendarService *calendar.CalendarService,
+    dependencyService *dependencies.DependencyService,
+    workflowService *workflow.WorkflowService,
+    timeEntryService *time_entries.TimeEntryService,
+    workloadService *workload.WorkloadService,
+    agendaService *agenda.AgendaService,
+    quickAddService *quick_add.QuickAddService,
 ) {
     // API v1
     v1 := router.PathPrefix("/api/v1").Subrouter()
@@ -66,51 +105,143 @@ func setupV1Routes(
     // Public routes
     v1.HandleFunc("/register", api.Register(userService)).Methods("POST")
     v1.HandleFunc("/login", api.Login(userService)).Methods("POST")
+    v1.HandleFunc("/calendar/feed/{token:[0-9a-f]+}.ics", api.GetCalendarFeed(calendarService)).Methods("GET")
     
     // Protected routes
     v1Protected := v1.PathPrefix("").Subrouter()
     v1Protected.Use(middleware.AuthMiddleware)
     
+    // Writes to versioned resources must say which version they expect to change
+    ifMatch := middleware.RequireIfMatch
+    
     // Todo routes
-    v1Protected.HandleFunc("/todos", api.GetTodos(todoService)).Methods("GET")
-    v1Protected.HandleFunc("/todo", api.CreateTodo(todoService)).Methods("POST")
-    v1Protected.HandleFunc("/todo/{id}", api.UpdateTodo(todoService)).Methods("PUT")
-    v1Protected.HandleFunc("/todo/{id}", api.DeleteTodo(todoService)).Methods("DELETE")
-    v1Protected.HandleFunc("/todo/undo/{id}", api.UndoTodo(todoService)).Methods("PUT")
+    v1Protected.HandleFunc("/todos", api.GetTodos(todoService, dependencyService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/user/timezone", api.GetTimezone(userService)).Methods("GET")
+    v1Protected.HandleFunc("/user/timezone", api.SetTimezone(userService)).Methods("PUT")
+    v1Protected.HandleFunc("/todo", api.CreateTodo(todoService, userService)).Methods("POST")
+    v1Protected.HandleFunc("/todo/quick", api.QuickAddTodo(quickAddService, userService)).Methods("POST")
+    v1Protected.HandleFunc("/todo/{id}", api.GetTodo(todoService)).Methods("GET")
+    v1Protected.Handle("/todo/{id}", ifMatch(api.UpdateTodo(todoService))).Methods("PUT")
+    v1Protected.Handle("/todo/{id}", ifMatch(api.PatchTodo(todoService))).Methods("PATCH")
+    v1Protected.Handle("/todo/{id}", ifMatch(api.DeleteTodo(todoService))).Methods("DELETE")
+    v1Protected.Handle("/todo/undo/{id}", ifMatch(api.UndoTodo(todoService))).Methods("PUT")
+    v1Protected.HandleFunc("/todos/batch", api.BatchTodos(todoService, userService)).Methods("POST")
+    v1Protected.HandleFunc("/todo/{id}/dependencies", api.GetTodoDependencies(dependencyService)).Methods("GET")
+    v1Protected.HandleFunc("/todo/{id}/dependencies", api.AddTodoDependency(dependencyService)).Methods("POST")
+    v1Protected.HandleFunc("/todo/{id}/dependencies/{blockedById}", api.RemoveTodoDependency(dependencyService)).Methods("DELETE")
+    v1Protected.HandleFunc("/todo/{id}/time-entries", api.GetTodoTimeEntries(timeEntryService)).Methods("GET")
+    v1Protected.HandleFunc("/todo/{id}/time-entries", api.CreateTodoTimeEntry(timeEntryService)).Methods("POST")
     v1Protected.HandleFunc("/shared", api.GetSharedTodos(sharedTodoService)).Methods("GET")
+    v1Protected.HandleFunc("/share", api.ShareTodo(sharedTodoService, userService, todoService)).Methods("POST")
+    v1Protected.Handle("/shared/{id}", ifMatch(api.PatchSharedTodo(sharedTodoService, todoService))).Methods("PATCH")
+    v1Protected.Handle("/shared/{id}/complete", ifMatch(api.CompleteSharedTodo(sharedTodoService, todoService))).Methods("PUT")
+    v1Protected.HandleFunc("/shared/{id}", api.UpdateSharePermission(sharedTodoService)).Methods("PUT")
+    v1Protected.HandleFunc("/shared/{id}", api.RevokeShare(sharedTodoService)).Methods("DELETE")
+    v1Protected.HandleFunc("/shared/{id}/accept", api.AcceptShare(sharedTodoService)).Methods("POST")
+    v1Protected.HandleFunc("/shared/{id}/decline", api.DeclineShare(sharedTodoService)).Methods("POST")
+    v1Protected.HandleFunc("/shared/{id}/block", api.BlockShareSender(sharedTodoService)).Methods("POST")
+    v1Protected.HandleFunc("/shared/blocks", api.GetShareBlocks(sharedTodoService)).Methods("GET")
+    v1Protected.HandleFunc("/shared/blocks/{userId}", api.UnblockShareSender(sharedTodoService)).Methods("DELETE")
     
     // Team routes
     v1Protected.HandleFunc("/team", api.CreateTeam(teamService)).Methods("POST")
     v1Protected.HandleFunc("/teams", api.GetTeams(teamService)).Methods("GET")
-    v1Protected.HandleFunc("/team/{teamId}/todos", api.GetTeamTodos(teamTodoService)).Methods("GET")
-    v1Protected.HandleFunc("/team/{teamId}/todo", api.CreateTeamTodo(teamTodoService)).Methods("POST")
-    v1Protected.HandleFunc("/team/{teamId}/todo/{id}", api.UpdateTeamTodo(teamTodoService)).Methods("PUT")
-    v1Protected.HandleFunc("/team/{teamId}/todo/{id}", api.DeleteTeamTodo(teamTodoService)).Methods("DELETE")
+    v1Protected.HandleFunc("/team/{teamId}/todos", api.GetTeamTodos(teamTodoService, dependencyService, workloadService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/team/{teamId}/todo", api.CreateTeamTodo(teamTodoService, userService)).Methods("POST")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}", api.GetTeamTodo(teamTodoService, userService)).Methods("GET")
+    v1Protected.Handle("/team/{teamId}/todo/{id}", ifMatch(api.UpdateTeamTodo(teamTodoService))).Methods("PUT")
+    v1Protected.Handle("/team/{teamId}/todo/{id}", ifMatch(api.PatchTeamTodo(teamTodoService, userService))).Methods("PATCH")
+    v1Protected.Handle("/team/{teamId}/todo/{id}", ifMatch(api.DeleteTeamTodo(teamTodoService))).Methods("DELETE")
+    v1Protected.HandleFunc("/team/{teamId}/todos/batch", api.BatchTeamTodos(teamTodoService, userService)).Methods("POST")
+    v1Protected.Handle("/team/{teamId}/todo/{id}/assignee", ifMatch(api.ReassignTeamTodo(teamTodoService, userService))).Methods("PUT")
+    v1Protected.Handle("/team/{teamId}/todo/{id}/assignees", ifMatch(api.SetTeamTodoAssignees(teamTodoService, userService))).Methods("PUT")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/assignees/{userId}", api.SetAssigneeDone(teamTodoService, userService)).Methods("PUT")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/assignments", api.GetTeamTodoAssignments(teamTodoService)).Methods("GET")
+    v1Protected.HandleFunc("/team-todos/assigned", api.GetAssignedTeamTodos(teamTodoService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/team/{teamId}/deadlines", api.GetTeamDeadlines(teamTodoService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/dependencies", api.GetTeamTodoDependencies(dependencyService)).Methods("GET")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/dependencies", api.AddTeamTodoDependency(dependencyService)).Methods("POST")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/dependencies/{blockedById}", api.RemoveTeamTodoDependency(dependencyService)).Methods("DELETE")
+    v1Protected.HandleFunc("/team/{teamId}/statuses", api.GetTeamWorkflow(workflowService)).Methods("GET")
+    v1Protected.HandleFunc("/team/{teamId}/statuses", api.UpdateTeamWorkflow(workflowService)).Methods("PUT")
+    v1Protected.HandleFunc("/team/{teamId}/board", api.GetTeamBoard(workflowService, userService)).Methods("GET")
+    v1Protected.Handle("/team/{teamId}/todo/{id}/status", ifMatch(api.MoveTeamTodo(workflowService, userService))).Methods("PUT")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/time-entries", api.GetTeamTodoTimeEntries(timeEntryService)).Methods("GET")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/time-entries", api.CreateTeamTodoTimeEntry(timeEntryService)).Methods("POST")
+    v1Protected.HandleFunc("/team/{teamId}/time-entries", api.GetTeamTimeEntries(timeEntryService)).Methods("GET")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/estimate", api.SetTeamTodoEstimate(workloadService)).Methods("PUT")
+    v1Protected.HandleFunc("/team/{teamId}/todo/{id}/estimate", api.ClearTeamTodoEstimate(workloadService)).Methods("DELETE")
+    v1Protected.HandleFunc("/team/{teamId}/workload", api.GetTeamWorkload(workloadService, userService)).Methods("GET")
     v1Protected.HandleFunc("/team/{teamId}/members", api.GetTeamMembers(teamMemberService)).Methods("GET")
     v1Protected.HandleFunc("/team/{teamId}/member", api.AddTeamMember(teamMemberService)).Methods("POST")
     v1Protected.HandleFunc("/team/{teamId}/member/{userId}", api.RemoveTeamMember(teamMemberService)).Methods("DELETE")
 // Routine routes
-    v1Protected.HandleFunc("/routine", api.CreateOrUpdateRoutines(routineService)).Methods("POST")
+    v1Protected.HandleFunc("/routine", api.CreateOrUpdateRoutines(routineService, userService)).Methods("POST")
     v1Protected.HandleFunc("/routine/task/{taskId}", api.GetRoutinesByTaskID(routineService)).Methods("GET")
-    v1Protected.HandleFunc("/routine/today/{scheduleType}", api.GetTodayRoutines(routineService)).Methods("GET")
-    v1Protected.HandleFunc("/routine/day/{day}/{scheduleType}", api.GetDailyRoutines(routineService)).Methods("GET")
-    v1Protected.HandleFunc("/routine/{id}", api.UpdateRoutineDay(routineService)).Methods("PUT")
-    v1Protected.HandleFunc("/routine/{id}/status", api.UpdateRoutineStatus(routineService)).Methods("PUT")
+    v1Protected.HandleFunc("/routine/today/{scheduleType}", api.GetTodayRoutines(routineService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/routine/day/{day}/{scheduleType}", api.GetDailyRoutines(routineService, userService)).Methods("GET")
+    v1Protected.Handle("/routine/{id}", ifMatch(api.UpdateRoutineDay(routineService))).Methods("PUT")
+    v1Protected.Handle("/routine/{id}/status", ifMatch(api.UpdateRoutineStatus(routineService))).Methods("PUT")
     v1Protected.HandleFunc("/routine/task/{taskId}/delete", api.DeleteRoutinesByTaskID(routineService)).Methods("DELETE")
 
     // Routine routes
-    v1Protected.HandleFunc("/routine", api.CreateOrUpdateRoutines(routineService)).Methods("POST")
+    v1Protected.HandleFunc("/routine", api.CreateOrUpdateRoutines(routineService, userService)).Methods("POST")
     v1Protected.HandleFunc("/routine/task/{taskId}", api.GetRoutinesByTaskID(routineService)).Methods("GET")
-    v1Protected.HandleFunc("/routine/today/{scheduleType}", api.GetTodayRoutines(routineService)).Methods("GET")
-    v1Protected.HandleFunc("/routine/day/{day}/{scheduleType}", api.GetDailyRoutines(routineService)).Methods("GET")
-    v1Protected.HandleFunc("/routine/{id}", api.UpdateRoutineDay(routineService)).Methods("PUT")
-    v1Protected.HandleFunc("/routine/{id}/status", api.UpdateRoutineStatus(routineService)).Methods("PUT")
+    v1Protected.HandleFunc("/routine/today/{scheduleType}", api.GetTodayRoutines(routineService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/routine/day/{day}/{scheduleType}", api.GetDailyRoutines(routineService, userService)).Methods("GET")
+    v1Protected.Handle("/routine/{id}", ifMatch(api.UpdateRoutineDay(routineService))).Methods("PUT")
+    v1Protected.Handle("/routine/{id}/status", ifMatch(api.UpdateRoutineStatus(routineService))).Methods("PUT")
     v1Protected.HandleFunc("/routine/task/{taskId}/delete", api.DeleteRoutinesByTaskID(routineService)).Methods("DELETE")
+    v1Protected.HandleFunc("/routine/day/{day}", api.GetDayRoutines(routineService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/routine/slots", api.GetRoutineSlots(routineService)).Methods("GET")
+    v1Protected.HandleFunc("/routine/slots", api.CreateRoutineSlot(routineService)).Methods("POST")
+    v1Protected.HandleFunc("/routine/slots/{id}", api.UpdateRoutineSlot(routineService)).Methods("PUT")
+    v1Protected.HandleFunc("/routine/slots/{id}", api.DeleteRoutineSlot(routineService)).Methods("DELETE")
+    v1Protected.HandleFunc("/routine/pauses", api.GetRoutinePauses(routineService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/routine/pauses", api.CreateRoutinePause(routineService, userService)).Methods("POST")
+    v1Protected.HandleFunc("/routine/pauses/{id}", api.DeleteRoutinePause(routineService)).Methods("DELETE")
+    v1Protected.HandleFunc("/routine/today/{scheduleType}/checkins", api.GetTodayCheckins(routineService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/routine/{id}/checkin", api.CheckInRoutine(routineService, userService)).Methods("POST")
+    v1Protected.HandleFunc("/routine/{id}/checkin", api.UncheckRoutine(routineService, userService)).Methods("DELETE")
+    v1Protected.HandleFunc("/routine/{id}/streak", api.GetRoutineStreak(routineService, userService)).Methods("GET")
+    v1Protected.HandleFunc("/routine/week", api.GetRoutineWeek(routineService)).Methods("GET")
+    v1Protected.HandleFunc("/routine/task/{taskId}/week", api.SetWeeklyRoutines(routineService)).Methods("PUT")
+
+    // Agenda routes
+    v1Protected.HandleFunc("/agenda", api.GetAgenda(agendaService, userService)).Methods("GET")
+
+    // Time tracking routes
+    v1Protected.HandleFunc("/timer", api.GetRunningTimer(timeEntryService)).Methods("GET")
+    v1Protected.HandleFunc("/timer/start", api.StartTimer(timeEntryService)).Methods("POST")
+    v1Protected.HandleFunc("/timer/stop", api.StopTimer(timeEntryService)).Methods("POST")
+    v1Protected.HandleFunc("/time-entries/{id}", api.DeleteTimeEntry(timeEntryService)).Methods("DELETE")
+    v1Protected.HandleFunc("/time/report", api.GetTimeReport(timeEntryService)).Methods("GET")
 
+    // Import/export routes
+    v1Protected.HandleFunc("/export", api.ExportData(transferService)).Methods("GET")
+    v1Protected.HandleFunc("/import", api.ImportData(transferService)).Methods("POST")
+    
+    // Calendar feed routes
+    v1Protected.HandleFunc("/calendar/feed", api.CreateCalendarFeed(calendarService)).Methods("POST")
+    v1Protected.HandleFunc("/calendar/feed", api.RevokeCalendarFeed(calendarService)).Methods("DELETE")
 }
 
 
 
+// setupCalDAVRoutes mounts the CalDAV server. It uses its own Basic authentication
+// since calendar clients cannot obtain a JWT.
+func setupCalDAVRoutes(
+    router *mux.Router,
+    userService *users.UserService,
+    todoService *todos.TodoService,
+    teamService *teams.TeamService,
+    teamTodoService *team_todos.TeamTodoService,
+) {
+    calDAVHandler := caldav.NewHandler("/caldav", userService, todoService, teamService, teamTodoService)
+    router.Handle("/.well-known/caldav", http.RedirectHandler("/caldav/", http.StatusMovedPermanently))
+    router.PathPrefix("/caldav").Handler(calDAVHandler)
+}
+
 // setupLegacyRoutes maintains the original API endpoints for backward compatibility
 func setupLegacyRoutes(
     router *mux.Router,
@@ -121,6 +252,8 @@ func setupLegacyRoutes(
     teamTodoService *team_todos.TeamTodoService,
     sharedTodoService *shared_todos.SharedTodoService,
     routineService *routines.RoutineService,
+    dependencyService *dependencies.DependencyService,
+    workloadService *workload.WorkloadService,
 ) {
     // Public routes
     router.HandleFunc("/api/register", api.Register(userService)).Methods("POST")
@@ -130,33 +263,45 @@ func setupLegacyRoutes(
     apiRouter := router.PathPrefix("/api").Subrouter()
     apiRouter.Use(middleware.AuthMiddleware)
     
+    // The original routes write the same versioned records, so they need If-Match too
+    ifMatch := middleware.RequireIfMatch
+    
     // Todo routes
-    apiRouter.HandleFunc("/todos", api.GetTodos(todoService)).Methods("GET")
-    apiRouter.HandleFunc("/todo", api.CreateTodo(todoService)).Methods("POST")
-    apiRouter.HandleFunc("/todo/{id}", api.UpdateTodo(todoService)).Methods("PUT")
-    apiRouter.HandleFunc("/todo/{id}", api.DeleteTodo(todoService)).Methods("DELETE")
-    apiRouter.HandleFunc("/todo/undo/{id}", api.UndoTodo(todoService)).Methods("PUT")
+    apiRouter.HandleFunc("/todos", api.GetTodos(todoService, dependencyService, userService)).Methods("GET")
+    apiRouter.HandleFunc("/todo", api.CreateTodo(todoService, userService)).Methods("POST")
+    apiRouter.Handle("/todo/{id}", ifMatch(api.UpdateTodo(todoService))).Methods("PUT")
+    apiRouter.Handle("/todo/{id}", ifMatch(api.DeleteTodo(todoService))).Methods("DELETE")
+    apiRouter.Handle("/todo/undo/{id}", ifMatch(api.UndoTodo(todoService))).Methods("PUT")
     apiRouter.HandleFunc("/shared", api.GetSharedTodos(sharedTodoService)).Methods("GET")
     apiRouter.HandleFunc("/share", api.ShareTodo(sharedTodoService, userService, todoService)).Methods("POST")
+    apiRouter.HandleFunc("/shared/{id}", api.UpdateSharePermission(sharedTodoService)).Methods("PUT")
+    apiRouter.HandleFunc("/shared/{id}", api.RevokeShare(sharedTodoService)).Methods("DELETE")
+    apiRouter.Handle("/shared/{id}", ifMatch(api.PatchSharedTodo(sharedTodoService, todoService))).Methods("PATCH")
+    apiRouter.Handle("/shared/{id}/complete", ifMatch(api.CompleteSharedTodo(sharedTodoService, todoService))).Methods("PUT")
+    apiRouter.HandleFunc("/shared/{id}/accept", api.AcceptShare(sharedTodoService)).Methods("POST")
+    apiRouter.HandleFunc("/shared/{id}/decline", api.DeclineShare(sharedTodoService)).Methods("POST")
+    apiRouter.HandleFunc("/shared/{id}/block", api.BlockShareSender(sharedTodoService)).Methods("POST")
+    apiRouter.HandleFunc("/shared/blocks", api.GetShareBlocks(sharedTodoService)).Methods("GET")
+    apiRouter.HandleFunc("/shared/blocks/{userId}", api.UnblockShareSender(sharedTodoService)).Methods("DELETE")
 
     
     // Team routes
     apiRouter.HandleFunc("/team", api.CreateTeam(teamService)).Methods("POST")
     apiRouter.HandleFunc("/teams", api.GetTeams(teamService)).Methods("GET")
-    apiRouter.HandleFunc("/team/{teamId}/todos", api.GetTeamTodos(teamTodoService)).Methods("GET")
-    apiRouter.HandleFunc("/team/{teamId}/todo", api.CreateTeamTodo(teamTodoService)).Methods("POST")
-    apiRouter.HandleFunc("/team/{teamId}/todo/{id}", api.UpdateTeamTodo(teamTodoService)).Methods("PUT")
-    apiRouter.HandleFunc("/team/{teamId}/todo/{id}", api.DeleteTeamTodo(teamTodoService)).Methods("DELETE")
+    apiRouter.HandleFunc("/team/{teamId}/todos", api.GetTeamTodos(teamTodoService, dependencyService, workloadService, userService)).Methods("GET")
+    apiRouter.HandleFunc("/team/{teamId}/todo", api.CreateTeamTodo(teamTodoService, userService)).Methods("POST")
+    apiRouter.Handle("/team/{teamId}/todo/{id}", ifMatch(api.UpdateTeamTodo(teamTodoService))).Methods("PUT")
+    apiRouter.Handle("/team/{teamId}/todo/{id}", ifMatch(api.DeleteTeamTodo(teamTodoService))).Methods("DELETE")
     apiRouter.HandleFunc("/team/{teamId}/members", api.GetTeamMembers(teamMemberService)).Methods("GET")
     apiRouter.HandleFunc("/team/{teamId}/member", api.AddTeamMember(teamMemberService)).Methods("POST")
     apiRouter.HandleFunc("/team/{teamId}/member/{userId}", api.RemoveTeamMember(teamMemberService)).Methods("DELETE")
 
      // Routine routes
-     apiRouter.HandleFunc("/routine", api.CreateOrUpdateRoutines(routineService)).Methods("POST")
+     apiRouter.HandleFunc("/routine", api.CreateOrUpdateRoutines(routineService, userService)).Methods("POST")
      apiRouter.HandleFunc("/routine/task/{taskId}", api.GetRoutinesByTaskID(routineService)).Methods("GET")
-     apiRouter.HandleFunc("/routine/today/{scheduleType}", api.GetTodayRoutines(routineService)).Methods("GET")
-     apiRouter.HandleFunc("/routine/day/{day}/{scheduleType}", api.GetDailyRoutines(routineService)).Methods("GET")
-     apiRouter.HandleFunc("/routine/{id}", api.UpdateRoutineDay(routineService)).Methods("PUT")
-     apiRouter.HandleFunc("/routine/{id}/status", api.UpdateRoutineStatus(routineService)).Methods("PUT")
+     apiRouter.HandleFunc("/routine/today/{scheduleType}", api.GetTodayRoutines(routineService, userService)).Methods("GET")
+     apiRouter.HandleFunc("/routine/day/{day}/{scheduleType}", api.GetDailyRoutines(routineService, userService)).Methods("GET")
+     apiRouter.Handle("/routine/{id}", ifMatch(api.UpdateRoutineDay(routineService))).Methods("PUT")
+     apiRouter.Handle("/routine/{id}/status", ifMatch(api.UpdateRoutineStatus(routineService))).Methods("PUT")
      apiRouter.HandleFunc("/routine/task/{taskId}/delete", api.DeleteRoutinesByTaskID(routineService)).Methods("DELETE")
 }
\ No newline at end of file
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+    "net/http"
+    "strconv"
+    "strings"
+)
+
+// RequireIfMatch rejects writes that do not say which version of the resource they
+// expect to change, so concurrent editors cannot silently overwrite each other.
+// Clients that really want an unconditional write can send "If-Match: *".
+func RequireIfMatch(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("If-Match") == "" {
+            http.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+            return
+        }
+        next.ServeHTTP(w, r)
+    })
+}
+
+// VersionETag renders a resource version as a strong entity tag. /api and /caldav
+// both use it, so a tag from one is accepted by the other.
+func VersionETag(version int) string {
+    return "\"" + strconv.Itoa(version) + "\""
+}
+
+// IfMatchVersion reads the version a write expects from If-Match. A missing header
+// or "*" gives 0, which skips the version check. It returns false when the tag can
+// never match.
+func IfMatchVersion(r *http.Request) (int, bool) {
+    header := strings.TrimSpace(r.Header.Get("If-Match"))
+    if header == "" || header == "*" {
+        return 0, true
+    }
+    
+    // If-Match uses strong comparison, so weak tags never match
+    version, err := strconv.Atoi(strings.Trim(header, "\""))
+    if strings.HasPrefix(header, "W/") || err != nil || version < 1 {
+        return 0, false
+    }
+    return version, true
+}
@@ -58,59 +58,138 @@ func (ns NullRoutinesDay) Value() (driver.Value, error) {
 	return string(ns.RoutinesDay), nil
 }
 
-type RoutinesScheduletype string
+type TeamTodoEstimatesUnit string
 
 const (
-	RoutinesScheduletypeMorning RoutinesScheduletype = "morning"
-	RoutinesScheduletypeNoon    RoutinesScheduletype = "noon"
-	RoutinesScheduletypeEvening RoutinesScheduletype = "evening"
-	RoutinesScheduletypeNight   RoutinesScheduletype = "night"
+	TeamTodoEstimatesUnitHours  TeamTodoEstimatesUnit = "hours"
+	TeamTodoEstimatesUnitPoints TeamTodoEstimatesUnit = "points"
 )
 
-func (e *RoutinesScheduletype) Scan(src interface{}) error {
+func (e *TeamTodoEstimatesUnit) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = RoutinesScheduletype(s)
+		*e = TeamTodoEstimatesUnit(s)
 	case string:
-		*e = RoutinesScheduletype(s)
+		*e = TeamTodoEstimatesUnit(s)
 	default:
-		return fmt.Errorf("unsupported scan type for RoutinesScheduletype: %T", src)
+		return fmt.Errorf("unsupported scan type for TeamTodoEstimatesUnit: %T", src)
 	}
 	return nil
 }
 
-type NullRoutinesScheduletype struct {
-	RoutinesScheduletype RoutinesScheduletype
-	Valid                bool // Valid is true if RoutinesScheduletype is not NULL
+type NullTeamTodoEstimatesUnit struct {
+	TeamTodoEstimatesUnit TeamTodoEstimatesUnit
+	Valid                 bool // Valid is true if TeamTodoEstimatesUnit is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullRoutinesScheduletype) Scan(value interface{}) error {
+func (ns *NullTeamTodoEstimatesUnit) Scan(value interface{}) error {
 	if value == nil {
-		ns.RoutinesScheduletype, ns.Valid = "", false
+		ns.TeamTodoEstimatesUnit, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.RoutinesScheduletype.Scan(value)
+	return ns.TeamTodoEstimatesUnit.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullRoutinesScheduletype) Value() (driver.Value, error) {
+func (ns NullTeamTodoEstimatesUnit) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.RoutinesScheduletype), nil
+	return string(ns.TeamTodoEstimatesUnit), nil
+}
+
+type TodoRecurrencesFrequency string
+
+const (
+	TodoRecurrencesFrequencyDaily   TodoRecurrencesFrequency = "daily"
+	TodoRecurrencesFrequencyWeekly  TodoRecurrencesFrequency = "weekly"
+	TodoRecurrencesFrequencyMonthly TodoRecurrencesFrequency = "monthly"
+	TodoRecurrencesFrequencyYearly  TodoRecurrencesFrequency = "yearly"
+)
+
+func (e *TodoRecurrencesFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TodoRecurrencesFrequency(s)
+	case string:
+		*e = TodoRecurrencesFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TodoRecurrencesFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullTodoRecurrencesFrequency struct {
+	TodoRecurrencesFrequency TodoRecurrencesFrequency
+	Valid                    bool // Valid is true if TodoRecurrencesFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTodoRecurrencesFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.TodoRecurrencesFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TodoRecurrencesFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTodoRecurrencesFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TodoRecurrencesFrequency), nil
+}
+
+type CalendarFeed struct {
+	UserID    string
+	TokenHash string
+	CreatedAt time.Time
 }
 
 type Routine struct {
 	ID           string
 	Day          RoutinesDay
-	Scheduletype RoutinesScheduletype
+	Scheduletype string
 	Taskid       string
 	Userid       string
 	Createdat    time.Time
 	Updatedat    time.Time
 	Isactive     sql.NullBool
+	Version      int32
+}
+
+type RoutineCheckin struct {
+	RoutineID   string
+	UserID      string
+	Date        time.Time
+	CheckedInAt time.Time
+}
+
+type RoutinePause struct {
+	ID        string
+	UserID    string
+	RoutineID sql.NullString
+	StartDate time.Time
+	EndDate   time.Time
+	Reason    sql.NullString
+	CreatedAt time.Time
+}
+
+type RoutineSlot struct {
+	ID        string
+	UserID    string
+	Name      string
+	StartTime time.Time
+}
+
+type ShareBlock struct {
+	UserID        string
+	BlockedUserID string
+	CreatedAt     time.Time
 }
 
 type SharedTodo struct {
@@ -123,6 +202,9 @@ type SharedTodo struct {
 	Date        sql.NullTime
 	Time        sql.NullTime
 	SharedBy    sql.NullString
+	TodoID      sql.NullString
+	Permission  string
+	Status      string
 }
 
 type Team struct {
@@ -138,16 +220,82 @@ type TeamMember struct {
 	IsAdmin sql.NullBool
 }
 
+type TeamStatus struct {
+	ID         string
+	TeamID     string
+	Name       string
+	Position   int32
+	IsTerminal bool
+}
+
+type TeamStatusTransition struct {
+	TeamID       string
+	FromStatusID string
+	ToStatusID   string
+}
+
 type TeamTodo struct {
-	ID          string
-	Task        string
-	Description sql.NullString
-	Done        bool
-	Important   sql.NullBool
+	ID             string
+	Task           string
+	Description    sql.NullString
+	Done           bool
+	Important      sql.NullBool
+	TeamID         string
+	AssignedTo     sql.NullString
+	Date           sql.NullTime
+	Time           sql.NullTime
+	Version        int32
+	CompletionMode string
+}
+
+type TeamTodoAssignee struct {
+	TodoID string
+	TeamID string
+	UserID string
+	Done   bool
+}
+
+type TeamTodoAssignment struct {
+	ID               int64
+	TodoID           string
+	TeamID           string
+	AssignedTo       sql.NullString
+	PreviousAssignee sql.NullString
+	AssignedBy       string
+	AssignedAt       time.Time
+}
+
+type TeamTodoDependency struct {
 	TeamID      string
-	AssignedTo  sql.NullString
-	Date        sql.NullTime
-	Time        sql.NullTime
+	TodoID      string
+	BlockedByID string
+	CreatedAt   time.Time
+}
+
+type TeamTodoEstimate struct {
+	TodoID   string
+	TeamID   string
+	Estimate string
+	Unit     TeamTodoEstimatesUnit
+}
+
+type TeamTodoPlacement struct {
+	TodoID   string
+	TeamID   string
+	StatusID string
+	Position int32
+}
+
+type TimeEntry struct {
+	ID            string
+	UserID        string
+	TodoID        sql.NullString
+	TeamID        sql.NullString
+	TeamTodoID    sql.NullString
+	StartedAt     time.Time
+	EndedAt       sql.NullTime
+	Note          string
+	RunningUserID sql.NullString
 }
 
 type Todo struct {
@@ -159,10 +307,30 @@ type Todo struct {
 	UserID      sql.NullString
 	Date        sql.NullTime
 	Time        sql.NullTime
+	Version     int32
+}
+
+type TodoDependency struct {
+	TodoID      string
+	BlockedByID string
+	CreatedAt   time.Time
+}
+
+type TodoRecurrence struct {
+	TodoID        string
+	Frequency     TodoRecurrencesFrequency
+	IntervalCount int32
+	Weekdays      string
+}
+
+type TodoTag struct {
+	TodoID string
+	Tag    string
 }
 
 type User struct {
 	ID       string
 	Username string
 	Password string
+	Timezone string
 }
@@ -11,6 +11,25 @@ import (
 	"time"
 )
 
+const acceptSharedTodo = `-- name: AcceptSharedTodo :execrows
+UPDATE shared_todos
+SET status = 'accepted'
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */ AND status = 'pending'
+`
+
+type AcceptSharedTodoParams struct {
+	ID     string
+	UserID sql.NullString
+}
+
+func (q *Queries) AcceptSharedTodo(ctx context.Context, arg AcceptSharedTodoParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, acceptSharedTodo, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const addTeamMember = `-- name: AddTeamMember :exec
 
 INSERT INTO team_members (team_id, user_id, is_admin)
@@ -33,6 +52,210 @@ func (q *Queries) AddTeamMember(ctx context.Context, arg AddTeamMemberParams) er
 	return err
 }
 
+const addTeamStatusTransition = `-- name: AddTeamStatusTransition :exec
+INSERT INTO team_status_transitions (team_id, from_status_id, to_status_id)
+VALUES (
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(fromStatusID) */,
+  ? /* sqlc.arg(toStatusID) */
+)
+`
+
+type AddTeamStatusTransitionParams struct {
+	TeamID       string
+	FromStatusID string
+	ToStatusID   string
+}
+
+func (q *Queries) AddTeamStatusTransition(ctx context.Context, arg AddTeamStatusTransitionParams) error {
+	_, err := q.db.ExecContext(ctx, addTeamStatusTransition, arg.TeamID, arg.FromStatusID, arg.ToStatusID)
+	return err
+}
+
+const addTeamTodoAssignee = `-- name: AddTeamTodoAssignee :exec
+INSERT IGNORE INTO team_todo_assignees (todo_id, team_id, user_id)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(userID) */
+)
+`
+
+type AddTeamTodoAssigneeParams struct {
+	TodoID string
+	TeamID string
+	UserID string
+}
+
+func (q *Queries) AddTeamTodoAssignee(ctx context.Context, arg AddTeamTodoAssigneeParams) error {
+	_, err := q.db.ExecContext(ctx, addTeamTodoAssignee, arg.TodoID, arg.TeamID, arg.UserID)
+	return err
+}
+
+const addTeamTodoDependency = `-- name: AddTeamTodoDependency :exec
+INSERT INTO team_todo_dependencies (team_id, todo_id, blocked_by_id)
+VALUES (
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(blockedByID) */
+)
+`
+
+type AddTeamTodoDependencyParams struct {
+	TeamID      string
+	TodoID      string
+	BlockedByID string
+}
+
+func (q *Queries) AddTeamTodoDependency(ctx context.Context, arg AddTeamTodoDependencyParams) error {
+	_, err := q.db.ExecContext(ctx, addTeamTodoDependency, arg.TeamID, arg.TodoID, arg.BlockedByID)
+	return err
+}
+
+const addTodoDependency = `-- name: AddTodoDependency :exec
+INSERT INTO todo_dependencies (todo_id, blocked_by_id)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(blockedByID) */
+)
+`
+
+type AddTodoDependencyParams struct {
+	TodoID      string
+	BlockedByID string
+}
+
+func (q *Queries) AddTodoDependency(ctx context.Context, arg AddTodoDependencyParams) error {
+	_, err := q.db.ExecContext(ctx, addTodoDependency, arg.TodoID, arg.BlockedByID)
+	return err
+}
+
+const addTodoTag = `-- name: AddTodoTag :exec
+INSERT IGNORE INTO todo_tags (todo_id, tag)
+VALUES (? /* sqlc.arg(todoID) */, ? /* sqlc.arg(tag) */)
+`
+
+type AddTodoTagParams struct {
+	TodoID string
+	Tag    string
+}
+
+func (q *Queries) AddTodoTag(ctx context.Context, arg AddTodoTagParams) error {
+	_, err := q.db.ExecContext(ctx, addTodoTag, arg.TodoID, arg.Tag)
+	return err
+}
+
+const checkInRoutine = `-- name: CheckInRoutine :exec
+INSERT INTO routine_checkins (routine_id, user_id, date)
+VALUES (
+  ? /* sqlc.arg(routineID) */,
+  ? /* sqlc.arg(userID) */,
+  ? /* sqlc.arg(date) */
+)
+ON DUPLICATE KEY UPDATE routine_id = routine_id
+`
+
+type CheckInRoutineParams struct {
+	RoutineID string
+	UserID    string
+	Date      time.Time
+}
+
+func (q *Queries) CheckInRoutine(ctx context.Context, arg CheckInRoutineParams) error {
+	_, err := q.db.ExecContext(ctx, checkInRoutine, arg.RoutineID, arg.UserID, arg.Date)
+	return err
+}
+
+const completeTeamTodo = `-- name: CompleteTeamTodo :exec
+UPDATE team_todos
+SET done = true,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type CompleteTeamTodoParams struct {
+	ID     string
+	TeamID string
+}
+
+func (q *Queries) CompleteTeamTodo(ctx context.Context, arg CompleteTeamTodoParams) error {
+	_, err := q.db.ExecContext(ctx, completeTeamTodo, arg.ID, arg.TeamID)
+	return err
+}
+
+const completeTodo = `-- name: CompleteTodo :exec
+UPDATE todos
+SET done = true,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
+`
+
+type CompleteTodoParams struct {
+	ID     string
+	UserID sql.NullString
+}
+
+func (q *Queries) CompleteTodo(ctx context.Context, arg CompleteTodoParams) error {
+	_, err := q.db.ExecContext(ctx, completeTodo, arg.ID, arg.UserID)
+	return err
+}
+
+const countSlotRoutines = `-- name: CountSlotRoutines :one
+SELECT COUNT(*)
+FROM routines
+WHERE userId = ? /* sqlc.arg(userId) */ AND scheduleType = ? /* sqlc.arg(scheduleType) */
+`
+
+type CountSlotRoutinesParams struct {
+	Userid       string
+	Scheduletype string
+}
+
+func (q *Queries) CountSlotRoutines(ctx context.Context, arg CountSlotRoutinesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSlotRoutines, arg.Userid, arg.Scheduletype)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createLinkedSharedTodo = `-- name: CreateLinkedSharedTodo :exec
+INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by, todo_id, permission, status)
+SELECT
+  ? /* sqlc.arg(id) */,
+  task,
+  description,
+  done,
+  important,
+  ? /* sqlc.arg(userID) */,
+  date,
+  time,
+  ? /* sqlc.arg(sharedBy) */,
+  todos.id,
+  ? /* sqlc.arg(permission) */,
+  'pending'
+FROM todos
+WHERE todos.id = ? /* sqlc.arg(todoID) */
+`
+
+type CreateLinkedSharedTodoParams struct {
+	ID         string
+	UserID     sql.NullString
+	SharedBy   sql.NullString
+	Permission string
+	ID_2       string
+}
+
+func (q *Queries) CreateLinkedSharedTodo(ctx context.Context, arg CreateLinkedSharedTodoParams) error {
+	_, err := q.db.ExecContext(ctx, createLinkedSharedTodo,
+		arg.ID,
+		arg.UserID,
+		arg.SharedBy,
+		arg.Permission,
+		arg.ID_2,
+	)
+	return err
+}
+
 const createRoutine = `-- name: CreateRoutine :exec
 
 INSERT INTO routines (id, day, scheduleType, taskId, userId, createdAt, updatedAt, isActive)
@@ -51,7 +274,7 @@ VALUES (
 type CreateRoutineParams struct {
 	ID           string
 	Day          RoutinesDay
-	Scheduletype RoutinesScheduletype
+	Scheduletype string
 	Taskid       string
 	Userid       string
 	Createdat    time.Time
@@ -74,9 +297,84 @@ func (q *Queries) CreateRoutine(ctx context.Context, arg CreateRoutineParams) er
 	return err
 }
 
+const createRoutinePause = `-- name: CreateRoutinePause :exec
+INSERT INTO routine_pauses (id, user_id, routine_id, start_date, end_date, reason)
+VALUES (
+  ? /* sqlc.arg(id) */,
+  ? /* sqlc.arg(userId) */,
+  ? /* sqlc.arg(routineId) */,
+  ? /* sqlc.arg(startDate) */,
+  ? /* sqlc.arg(endDate) */,
+  ? /* sqlc.arg(reason) */
+)
+`
+
+type CreateRoutinePauseParams struct {
+	ID        string
+	UserID    string
+	RoutineID sql.NullString
+	StartDate time.Time
+	EndDate   time.Time
+	Reason    sql.NullString
+}
+
+func (q *Queries) CreateRoutinePause(ctx context.Context, arg CreateRoutinePauseParams) error {
+	_, err := q.db.ExecContext(ctx, createRoutinePause,
+		arg.ID,
+		arg.UserID,
+		arg.RoutineID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Reason,
+	)
+	return err
+}
+
+const createRoutineSlot = `-- name: CreateRoutineSlot :exec
+INSERT INTO routine_slots (id, user_id, name, start_time)
+VALUES (
+  ? /* sqlc.arg(id) */,
+  ? /* sqlc.arg(userId) */,
+  ? /* sqlc.arg(name) */,
+  ? /* sqlc.arg(startTime) */
+)
+`
+
+type CreateRoutineSlotParams struct {
+	ID        string
+	UserID    string
+	Name      string
+	StartTime time.Time
+}
+
+func (q *Queries) CreateRoutineSlot(ctx context.Context, arg CreateRoutineSlotParams) error {
+	_, err := q.db.ExecContext(ctx, createRoutineSlot,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.StartTime,
+	)
+	return err
+}
+
+const createShareBlock = `-- name: CreateShareBlock :exec
+INSERT IGNORE INTO share_blocks (user_id, blocked_user_id)
+VALUES (? /* sqlc.arg(userId) */, ? /* sqlc.arg(blockedUserId) */)
+`
+
+type CreateShareBlockParams struct {
+	UserID        string
+	BlockedUserID string
+}
+
+func (q *Queries) CreateShareBlock(ctx context.Context, arg CreateShareBlockParams) error {
+	_, err := q.db.ExecContext(ctx, createShareBlock, arg.UserID, arg.BlockedUserID)
+	return err
+}
+
 const createSharedTodo = `-- name: CreateSharedTodo :exec
 
-INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by)
+INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by, todo_id, permission)
 VALUES (
   ? /* sqlc.arg(id) */,
   ? /* sqlc.arg(task) */,
@@ -86,7 +384,10 @@ VALUES (
   ? /* sqlc.arg(userID) */,
   ? /* sqlc.arg(date) */,
   ? /* sqlc.arg(time) */,
-  ? /* sqlc.arg(sharedBy) */
+  ? /* sqlc.arg(sharedBy) */,
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(permission) */,
+  ? /* sqlc.arg(status) */
 )
 `
 
@@ -100,6 +401,9 @@ type CreateSharedTodoParams struct {
 	Date        sql.NullTime
 	Time        sql.NullTime
 	SharedBy    sql.NullString
+	TodoID      sql.NullString
+	Permission  string
+	Status      string
 }
 
 // Shared Todos Queries
@@ -114,6 +418,9 @@ func (q *Queries) CreateSharedTodo(ctx context.Context, arg CreateSharedTodoPara
 		arg.Date,
 		arg.Time,
 		arg.SharedBy,
+		arg.TodoID,
+		arg.Permission,
+		arg.Status,
 	)
 	return err
 }
@@ -191,6 +498,75 @@ func (q *Queries) CreateTeamTodo(ctx context.Context, arg CreateTeamTodoParams)
 	return err
 }
 
+const createTeamTodoAssignment = `-- name: CreateTeamTodoAssignment :exec
+INSERT INTO team_todo_assignments (todo_id, team_id, assigned_to, previous_assignee, assigned_by)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(assignedTo) */,
+  ? /* sqlc.arg(previousAssignee) */,
+  ? /* sqlc.arg(assignedBy) */
+)
+`
+
+type CreateTeamTodoAssignmentParams struct {
+	TodoID           string
+	TeamID           string
+	AssignedTo       sql.NullString
+	PreviousAssignee sql.NullString
+	AssignedBy       string
+}
+
+func (q *Queries) CreateTeamTodoAssignment(ctx context.Context, arg CreateTeamTodoAssignmentParams) error {
+	_, err := q.db.ExecContext(ctx, createTeamTodoAssignment,
+		arg.TodoID,
+		arg.TeamID,
+		arg.AssignedTo,
+		arg.PreviousAssignee,
+		arg.AssignedBy,
+	)
+	return err
+}
+
+const createTimeEntry = `-- name: CreateTimeEntry :exec
+INSERT INTO time_entries (id, user_id, todo_id, team_id, team_todo_id, started_at, ended_at, note)
+VALUES (
+  ? /* sqlc.arg(id) */,
+  ? /* sqlc.arg(userID) */,
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(teamTodoID) */,
+  ? /* sqlc.arg(startedAt) */,
+  ? /* sqlc.arg(endedAt) */,
+  ? /* sqlc.arg(note) */
+)
+`
+
+type CreateTimeEntryParams struct {
+	ID         string
+	UserID     string
+	TodoID     sql.NullString
+	TeamID     sql.NullString
+	TeamTodoID sql.NullString
+	StartedAt  time.Time
+	EndedAt    sql.NullTime
+	Note       string
+}
+
+func (q *Queries) CreateTimeEntry(ctx context.Context, arg CreateTimeEntryParams) error {
+	_, err := q.db.ExecContext(ctx, createTimeEntry,
+		arg.ID,
+		arg.UserID,
+		arg.TodoID,
+		arg.TeamID,
+		arg.TeamTodoID,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.Note,
+	)
+	return err
+}
+
 const createTodo = `-- name: CreateTodo :exec
 
 INSERT INTO todos (id, task, description, done, important, user_id, date, time)
@@ -254,119 +630,1439 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
 	return err
 }
 
-const deleteRoutinesByTaskID = `-- name: DeleteRoutinesByTaskID :exec
-DELETE FROM routines
-WHERE taskId = ? /* sqlc.arg(taskId) */
+const deleteCalendarFeedToken = `-- name: DeleteCalendarFeedToken :execrows
+DELETE FROM calendar_feeds
+WHERE user_id = ? /* sqlc.arg(userID) */
 `
 
-func (q *Queries) DeleteRoutinesByTaskID(ctx context.Context, taskid string) error {
-	_, err := q.db.ExecContext(ctx, deleteRoutinesByTaskID, taskid)
-	return err
+func (q *Queries) DeleteCalendarFeedToken(ctx context.Context, userID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCalendarFeedToken, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteTeamTodo = `-- name: DeleteTeamTodo :exec
-DELETE FROM team_todos
-WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+const deleteReceivedSharedTodo = `-- name: DeleteReceivedSharedTodo :execrows
+DELETE FROM shared_todos
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
 `
 
-type DeleteTeamTodoParams struct {
+type DeleteReceivedSharedTodoParams struct {
 	ID     string
-	TeamID string
+	UserID sql.NullString
 }
 
-func (q *Queries) DeleteTeamTodo(ctx context.Context, arg DeleteTeamTodoParams) error {
-	_, err := q.db.ExecContext(ctx, deleteTeamTodo, arg.ID, arg.TeamID)
-	return err
+func (q *Queries) DeleteReceivedSharedTodo(ctx context.Context, arg DeleteReceivedSharedTodoParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteReceivedSharedTodo, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteTodo = `-- name: DeleteTodo :exec
-DELETE FROM todos
-WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
+const deleteRoutinePause = `-- name: DeleteRoutinePause :execrows
+DELETE FROM routine_pauses
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userId) */
 `
 
-type DeleteTodoParams struct {
+type DeleteRoutinePauseParams struct {
 	ID     string
-	UserID sql.NullString
+	UserID string
 }
 
-func (q *Queries) DeleteTodo(ctx context.Context, arg DeleteTodoParams) error {
-	_, err := q.db.ExecContext(ctx, deleteTodo, arg.ID, arg.UserID)
-	return err
+func (q *Queries) DeleteRoutinePause(ctx context.Context, arg DeleteRoutinePauseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRoutinePause, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getDailyRoutines = `-- name: GetDailyRoutines :many
-SELECT t.id, t.task, t.description, t.done, t.important, t.user_id, t.date, t.time
-FROM todos t
-JOIN routines r ON t.id = r.taskId
-WHERE r.day = ? /* sqlc.arg(day) */ 
-  AND r.scheduleType = ? /* sqlc.arg(scheduleType) */ 
-  AND r.userId = ? /* sqlc.arg(userId) */ 
-  AND r.isActive = true
+const deleteRoutineSlot = `-- name: DeleteRoutineSlot :execrows
+DELETE FROM routine_slots
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userId) */
 `
 
-type GetDailyRoutinesParams struct {
-	Day          RoutinesDay
-	Scheduletype RoutinesScheduletype
-	Userid       string
+type DeleteRoutineSlotParams struct {
+	ID     string
+	UserID string
 }
 
-func (q *Queries) GetDailyRoutines(ctx context.Context, arg GetDailyRoutinesParams) ([]Todo, error) {
-	rows, err := q.db.QueryContext(ctx, getDailyRoutines, arg.Day, arg.Scheduletype, arg.Userid)
+func (q *Queries) DeleteRoutineSlot(ctx context.Context, arg DeleteRoutineSlotParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRoutineSlot, arg.ID, arg.UserID)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Todo
-	for rows.Next() {
-		var i Todo
-		if err := rows.Scan(
-			&i.ID,
-			&i.Task,
-			&i.Description,
-			&i.Done,
-			&i.Important,
-			&i.UserID,
-			&i.Date,
-			&i.Time,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return result.RowsAffected()
 }
 
-const getRoutinesByTaskID = `-- name: GetRoutinesByTaskID :many
-SELECT id, day, scheduleType, taskId, userId, createdAt, updatedAt, isActive
-FROM routines
+const deleteRoutinesByTaskID = `-- name: DeleteRoutinesByTaskID :exec
+DELETE FROM routines
 WHERE taskId = ? /* sqlc.arg(taskId) */
 `
 
-func (q *Queries) GetRoutinesByTaskID(ctx context.Context, taskid string) ([]Routine, error) {
-	rows, err := q.db.QueryContext(ctx, getRoutinesByTaskID, taskid)
+func (q *Queries) DeleteRoutinesByTaskID(ctx context.Context, taskid string) error {
+	_, err := q.db.ExecContext(ctx, deleteRoutinesByTaskID, taskid)
+	return err
+}
+
+const deleteShareBlock = `-- name: DeleteShareBlock :execrows
+DELETE FROM share_blocks
+WHERE user_id = ? /* sqlc.arg(userId) */ AND blocked_user_id = ? /* sqlc.arg(blockedUserId) */
+`
+
+type DeleteShareBlockParams struct {
+	UserID        string
+	BlockedUserID string
+}
+
+func (q *Queries) DeleteShareBlock(ctx context.Context, arg DeleteShareBlockParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteShareBlock, arg.UserID, arg.BlockedUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSharedTodo = `-- name: DeleteSharedTodo :execrows
+DELETE FROM shared_todos
+WHERE id = ? /* sqlc.arg(id) */ AND shared_by = ? /* sqlc.arg(sharedBy) */
+`
+
+type DeleteSharedTodoParams struct {
+	ID       string
+	SharedBy sql.NullString
+}
+
+func (q *Queries) DeleteSharedTodo(ctx context.Context, arg DeleteSharedTodoParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSharedTodo, arg.ID, arg.SharedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSharedTodosFromSender = `-- name: DeleteSharedTodosFromSender :exec
+DELETE FROM shared_todos
+WHERE user_id = ? /* sqlc.arg(userID) */ AND shared_by = ? /* sqlc.arg(sharedBy) */
+`
+
+type DeleteSharedTodosFromSenderParams struct {
+	UserID   sql.NullString
+	SharedBy sql.NullString
+}
+
+func (q *Queries) DeleteSharedTodosFromSender(ctx context.Context, arg DeleteSharedTodosFromSenderParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSharedTodosFromSender, arg.UserID, arg.SharedBy)
+	return err
+}
+
+const deleteTeamStatus = `-- name: DeleteTeamStatus :exec
+DELETE FROM team_statuses
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type DeleteTeamStatusParams struct {
+	ID     string
+	TeamID string
+}
+
+func (q *Queries) DeleteTeamStatus(ctx context.Context, arg DeleteTeamStatusParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamStatus, arg.ID, arg.TeamID)
+	return err
+}
+
+const deleteTeamStatusTransitions = `-- name: DeleteTeamStatusTransitions :exec
+DELETE FROM team_status_transitions
+WHERE team_id = ? /* sqlc.arg(teamID) */
+`
+
+func (q *Queries) DeleteTeamStatusTransitions(ctx context.Context, teamID string) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamStatusTransitions, teamID)
+	return err
+}
+
+const deleteTeamTodo = `-- name: DeleteTeamTodo :exec
+DELETE FROM team_todos
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type DeleteTeamTodoParams struct {
+	ID     string
+	TeamID string
+}
+
+func (q *Queries) DeleteTeamTodo(ctx context.Context, arg DeleteTeamTodoParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamTodo, arg.ID, arg.TeamID)
+	return err
+}
+
+const deleteTeamTodoAssignee = `-- name: DeleteTeamTodoAssignee :exec
+DELETE FROM team_todo_assignees
+WHERE todo_id = ? /* sqlc.arg(todoID) */ AND user_id = ? /* sqlc.arg(userID) */
+`
+
+type DeleteTeamTodoAssigneeParams struct {
+	TodoID string
+	UserID string
+}
+
+func (q *Queries) DeleteTeamTodoAssignee(ctx context.Context, arg DeleteTeamTodoAssigneeParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamTodoAssignee, arg.TodoID, arg.UserID)
+	return err
+}
+
+const deleteTeamTodoDependency = `-- name: DeleteTeamTodoDependency :execrows
+DELETE FROM team_todo_dependencies
+WHERE team_id = ? /* sqlc.arg(teamID) */ AND todo_id = ? /* sqlc.arg(todoID) */ AND blocked_by_id = ? /* sqlc.arg(blockedByID) */
+`
+
+type DeleteTeamTodoDependencyParams struct {
+	TeamID      string
+	TodoID      string
+	BlockedByID string
+}
+
+func (q *Queries) DeleteTeamTodoDependency(ctx context.Context, arg DeleteTeamTodoDependencyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTeamTodoDependency, arg.TeamID, arg.TodoID, arg.BlockedByID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTeamTodoEstimate = `-- name: DeleteTeamTodoEstimate :execrows
+DELETE FROM team_todo_estimates
+WHERE todo_id = ? /* sqlc.arg(todoID) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type DeleteTeamTodoEstimateParams struct {
+	TodoID string
+	TeamID string
+}
+
+func (q *Queries) DeleteTeamTodoEstimate(ctx context.Context, arg DeleteTeamTodoEstimateParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTeamTodoEstimate, arg.TodoID, arg.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTimeEntry = `-- name: DeleteTimeEntry :execrows
+DELETE FROM time_entries
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
+`
+
+type DeleteTimeEntryParams struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) DeleteTimeEntry(ctx context.Context, arg DeleteTimeEntryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTimeEntry, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTodo = `-- name: DeleteTodo :exec
+DELETE FROM todos
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
+`
+
+type DeleteTodoParams struct {
+	ID     string
+	UserID sql.NullString
+}
+
+func (q *Queries) DeleteTodo(ctx context.Context, arg DeleteTodoParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTodo, arg.ID, arg.UserID)
+	return err
+}
+
+const deleteTodoDependency = `-- name: DeleteTodoDependency :execrows
+DELETE FROM todo_dependencies
+WHERE todo_id = ? /* sqlc.arg(todoID) */ AND blocked_by_id = ? /* sqlc.arg(blockedByID) */
+`
+
+type DeleteTodoDependencyParams struct {
+	TodoID      string
+	BlockedByID string
+}
+
+func (q *Queries) DeleteTodoDependency(ctx context.Context, arg DeleteTodoDependencyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTodoDependency, arg.TodoID, arg.BlockedByID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const endTimeEntry = `-- name: EndTimeEntry :exec
+UPDATE time_entries
+SET ended_at = ? /* sqlc.arg(endedAt) */
+WHERE id = ? /* sqlc.arg(id) */
+`
+
+type EndTimeEntryParams struct {
+	EndedAt sql.NullTime
+	ID      string
+}
+
+func (q *Queries) EndTimeEntry(ctx context.Context, arg EndTimeEntryParams) error {
+	_, err := q.db.ExecContext(ctx, endTimeEntry, arg.EndedAt, arg.ID)
+	return err
+}
+
+const getAssignedTeamTodos = `-- name: GetAssignedTeamTodos :many
+SELECT t.id, t.task, t.description, t.done, t.important, t.team_id, t.assigned_to,
+  CAST(t.date AS CHAR) AS date,
+  CAST(t.time AS CHAR) AS time,
+  t.version, tm.name AS team_name
+FROM team_todo_assignees a
+JOIN team_todos t ON t.id = a.todo_id
+JOIN team_members m ON m.team_id = a.team_id AND m.user_id = a.user_id
+JOIN teams tm ON tm.id = a.team_id
+WHERE a.user_id = ? /* sqlc.arg(userID) */
+ORDER BY t.done, t.date IS NULL, t.date, t.time, t.task
+`
+
+type GetAssignedTeamTodosRow struct {
+	ID          string
+	Task        string
+	Description sql.NullString
+	Done        bool
+	Important   sql.NullBool
+	TeamID      string
+	AssignedTo  sql.NullString
+	Date        interface{}
+	Time        interface{}
+	Version     int32
+	TeamName    string
+}
+
+func (q *Queries) GetAssignedTeamTodos(ctx context.Context, userID string) ([]GetAssignedTeamTodosRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAssignedTeamTodos, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAssignedTeamTodosRow
+	for rows.Next() {
+		var i GetAssignedTeamTodosRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Task,
+			&i.Description,
+			&i.Done,
+			&i.Important,
+			&i.TeamID,
+			&i.AssignedTo,
+			&i.Date,
+			&i.Time,
+			&i.Version,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCalendarFeedUserID = `-- name: GetCalendarFeedUserID :one
+SELECT user_id
+FROM calendar_feeds
+WHERE token_hash = ? /* sqlc.arg(tokenHash) */
+`
+
+func (q *Queries) GetCalendarFeedUserID(ctx context.Context, tokenHash string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCalendarFeedUserID, tokenHash)
+	var user_id string
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getDailyRoutineCheckins = `-- name: GetDailyRoutineCheckins :many
+SELECT r.id, r.taskId, c.routine_id IS NOT NULL AS checked_in
+FROM routines r
+LEFT JOIN routine_checkins c ON c.routine_id = r.id AND c.date = ? /* sqlc.arg(date) */
+WHERE r.day = ? /* sqlc.arg(day) */
+  AND r.scheduleType = ? /* sqlc.arg(scheduleType) */
+  AND r.userId = ? /* sqlc.arg(userId) */
+  AND r.isActive = true
+`
+
+type GetDailyRoutineCheckinsParams struct {
+	Date         time.Time
+	Day          RoutinesDay
+	Scheduletype string
+	Userid       string
+}
+
+type GetDailyRoutineCheckinsRow struct {
+	ID        string
+	Taskid    string
+	CheckedIn bool
+}
+
+func (q *Queries) GetDailyRoutineCheckins(ctx context.Context, arg GetDailyRoutineCheckinsParams) ([]GetDailyRoutineCheckinsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDailyRoutineCheckins, arg.Date, arg.Day, arg.Scheduletype, arg.Userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDailyRoutineCheckinsRow
+	for rows.Next() {
+		var i GetDailyRoutineCheckinsRow
+		if err := rows.Scan(&i.ID, &i.Taskid, &i.CheckedIn); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDailyRoutines = `-- name: GetDailyRoutines :many
+SELECT t.id, t.task, t.description, t.done, t.important, t.user_id, t.date, t.time, t.version
+FROM todos t
+JOIN routines r ON t.id = r.taskId
+WHERE r.day = ? /* sqlc.arg(day) */ 
+  AND r.scheduleType = ? /* sqlc.arg(scheduleType) */ 
+  AND r.userId = ? /* sqlc.arg(userId) */ 
+  AND r.isActive = true
+`
+
+type GetDailyRoutinesParams struct {
+	Day          RoutinesDay
+	Scheduletype string
+	Userid       string
+}
+
+func (q *Queries) GetDailyRoutines(ctx context.Context, arg GetDailyRoutinesParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, getDailyRoutines, arg.Day, arg.Scheduletype, arg.Userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Task,
+			&i.Description,
+			&i.Done,
+			&i.Important,
+			&i.UserID,
+			&i.Date,
+			&i.Time,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDependencyTeamTodosForUpdate = `-- name: GetDependencyTeamTodosForUpdate :many
+SELECT id, task, done
+FROM team_todos
+WHERE team_id = ? /* sqlc.arg(teamID) */
+ORDER BY id
+FOR UPDATE
+`
+
+type GetDependencyTeamTodosForUpdateRow struct {
+	ID   string
+	Task string
+	Done bool
+}
+
+func (q *Queries) GetDependencyTeamTodosForUpdate(ctx context.Context, teamID string) ([]GetDependencyTeamTodosForUpdateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDependencyTeamTodosForUpdate, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDependencyTeamTodosForUpdateRow
+	for rows.Next() {
+		var i GetDependencyTeamTodosForUpdateRow
+		if err := rows.Scan(&i.ID, &i.Task, &i.Done); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDependencyTodosForUpdate = `-- name: GetDependencyTodosForUpdate :many
+SELECT id, task, done
+FROM todos
+WHERE user_id = ? /* sqlc.arg(userID) */
+ORDER BY id
+FOR UPDATE
+`
+
+type GetDependencyTodosForUpdateRow struct {
+	ID   string
+	Task string
+	Done bool
+}
+
+func (q *Queries) GetDependencyTodosForUpdate(ctx context.Context, userID string) ([]GetDependencyTodosForUpdateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDependencyTodosForUpdate, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDependencyTodosForUpdateRow
+	for rows.Next() {
+		var i GetDependencyTodosForUpdateRow
+		if err := rows.Scan(&i.ID, &i.Task, &i.Done); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoutineByID = `-- name: GetRoutineByID :one
+SELECT id, day, scheduleType, taskId, userId, isActive, version
+FROM routines
+WHERE id = ? /* sqlc.arg(id) */
+`
+
+type GetRoutineByIDRow struct {
+	ID           string
+	Day          RoutinesDay
+	Scheduletype string
+	Taskid       string
+	Userid       string
+	Isactive     sql.NullBool
+	Version      int32
+}
+
+func (q *Queries) GetRoutineByID(ctx context.Context, id string) (GetRoutineByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getRoutineByID, id)
+	var i GetRoutineByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Day,
+		&i.Scheduletype,
+		&i.Taskid,
+		&i.Userid,
+		&i.Isactive,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getRoutineCheckinDates = `-- name: GetRoutineCheckinDates :many
+SELECT CAST(c.date AS CHAR) AS date
+FROM routine_checkins c
+JOIN routines r ON r.id = c.routine_id
+WHERE r.taskId = ? /* sqlc.arg(taskId) */
+  AND r.scheduleType = ? /* sqlc.arg(scheduleType) */
+  AND r.userId = ? /* sqlc.arg(userId) */
+ORDER BY c.date
+`
+
+type GetRoutineCheckinDatesParams struct {
+	Taskid       string
+	Scheduletype string
+	Userid       string
+}
+
+func (q *Queries) GetRoutineCheckinDates(ctx context.Context, arg GetRoutineCheckinDatesParams) ([]interface{}, error) {
+	rows, err := q.db.QueryContext(ctx, getRoutineCheckinDates, arg.Taskid, arg.Scheduletype, arg.Userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []interface{}
+	for rows.Next() {
+		var date interface{}
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		items = append(items, date)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoutinePauses = `-- name: GetRoutinePauses :many
+SELECT p.id, p.routine_id, CAST(p.start_date AS CHAR) AS start_date, CAST(p.end_date AS CHAR) AS end_date,
+  p.reason, r.day, r.taskId, r.scheduleType
+FROM routine_pauses p
+LEFT JOIN routines r ON r.id = p.routine_id
+WHERE p.user_id = ? /* sqlc.arg(userId) */
+ORDER BY p.start_date, p.end_date, p.id
+`
+
+type GetRoutinePausesRow struct {
+	ID           string
+	RoutineID    sql.NullString
+	StartDate    interface{}
+	EndDate      interface{}
+	Reason       sql.NullString
+	Day          NullRoutinesDay
+	Taskid       sql.NullString
+	Scheduletype sql.NullString
+}
+
+func (q *Queries) GetRoutinePauses(ctx context.Context, userid string) ([]GetRoutinePausesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRoutinePauses, userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoutinePausesRow
+	for rows.Next() {
+		var i GetRoutinePausesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoutineID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Reason,
+			&i.Day,
+			&i.Taskid,
+			&i.Scheduletype,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoutineScheduleDays = `-- name: GetRoutineScheduleDays :many
+SELECT day
+FROM routines
+WHERE taskId = ? /* sqlc.arg(taskId) */
+  AND scheduleType = ? /* sqlc.arg(scheduleType) */
+  AND userId = ? /* sqlc.arg(userId) */
+  AND isActive = true
+`
+
+type GetRoutineScheduleDaysParams struct {
+	Taskid       string
+	Scheduletype string
+	Userid       string
+}
+
+func (q *Queries) GetRoutineScheduleDays(ctx context.Context, arg GetRoutineScheduleDaysParams) ([]RoutinesDay, error) {
+	rows, err := q.db.QueryContext(ctx, getRoutineScheduleDays, arg.Taskid, arg.Scheduletype, arg.Userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoutinesDay
+	for rows.Next() {
+		var day RoutinesDay
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		items = append(items, day)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoutineSlots = `-- name: GetRoutineSlots :many
+SELECT id, user_id, name, CAST(start_time AS CHAR) AS start_time
+FROM routine_slots
+WHERE user_id = ? /* sqlc.arg(userId) */
+ORDER BY start_time, name
+`
+
+type GetRoutineSlotsRow struct {
+	ID        string
+	UserID    string
+	Name      string
+	StartTime interface{}
+}
+
+func (q *Queries) GetRoutineSlots(ctx context.Context, userid string) ([]GetRoutineSlotsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRoutineSlots, userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoutineSlotsRow
+	for rows.Next() {
+		var i GetRoutineSlotsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.StartTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoutineVersionForUpdate = `-- name: GetRoutineVersionForUpdate :one
+SELECT version
+FROM routines
+WHERE id = ? /* sqlc.arg(id) */
+FOR UPDATE
+`
+
+func (q *Queries) GetRoutineVersionForUpdate(ctx context.Context, id string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getRoutineVersionForUpdate, id)
+	var version int32
+	err := row.Scan(&version)
+	return version, err
+}
+
+const getRoutinesByTaskID = `-- name: GetRoutinesByTaskID :many
+SELECT id, day, scheduleType, taskId, userId, createdAt, updatedAt, isActive, version
+FROM routines
+WHERE taskId = ? /* sqlc.arg(taskId) */
+`
+
+func (q *Queries) GetRoutinesByTaskID(ctx context.Context, taskid string) ([]Routine, error) {
+	rows, err := q.db.QueryContext(ctx, getRoutinesByTaskID, taskid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Routine
+	for rows.Next() {
+		var i Routine
+		if err := rows.Scan(
+			&i.ID,
+			&i.Day,
+			&i.Scheduletype,
+			&i.Taskid,
+			&i.Userid,
+			&i.Createdat,
+			&i.Updatedat,
+			&i.Isactive,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRunningTimeEntryForUpdate = `-- name: GetRunningTimeEntryForUpdate :one
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.user_id = ? /* sqlc.arg(userID) */ AND e.ended_at IS NULL
+FOR UPDATE
+`
+
+type GetRunningTimeEntryForUpdateRow struct {
+	ID         string
+	UserID     string
+	Username   string
+	TodoID     sql.NullString
+	TeamID     sql.NullString
+	TeamTodoID sql.NullString
+	Task       string
+	StartedAt  interface{}
+	EndedAt    interface{}
+	Note       string
+}
+
+func (q *Queries) GetRunningTimeEntryForUpdate(ctx context.Context, userID string) (GetRunningTimeEntryForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getRunningTimeEntryForUpdate, userID)
+	var i GetRunningTimeEntryForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Username,
+		&i.TodoID,
+		&i.TeamID,
+		&i.TeamTodoID,
+		&i.Task,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Note,
+	)
+	return i, err
+}
+
+const getShareBlocks = `-- name: GetShareBlocks :many
+SELECT b.blocked_user_id, u.username
+FROM share_blocks b
+JOIN users u ON u.id = b.blocked_user_id
+WHERE b.user_id = ? /* sqlc.arg(userId) */
+ORDER BY u.username
+`
+
+type GetShareBlocksRow struct {
+	BlockedUserID string
+	Username      string
+}
+
+func (q *Queries) GetShareBlocks(ctx context.Context, userid string) ([]GetShareBlocksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getShareBlocks, userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetShareBlocksRow
+	for rows.Next() {
+		var i GetShareBlocksRow
+		if err := rows.Scan(&i.BlockedUserID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSharedByMeTodos = `-- name: GetSharedByMeTodos :many
+SELECT id, task, description, done, important, user_id, date, time, shared_by, todo_id, permission, status
+FROM shared_todos
+WHERE shared_by = ? /* sqlc.arg(sharedBy) */
+`
+
+func (q *Queries) GetSharedByMeTodos(ctx context.Context, sharedBy sql.NullString) ([]SharedTodo, error) {
+	rows, err := q.db.QueryContext(ctx, getSharedByMeTodos, sharedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SharedTodo
+	for rows.Next() {
+		var i SharedTodo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Task,
+			&i.Description,
+			&i.Done,
+			&i.Important,
+			&i.UserID,
+			&i.Date,
+			&i.Time,
+			&i.SharedBy,
+			&i.TodoID,
+			&i.Permission,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSharedTodos = `-- name: GetSharedTodos :many
+SELECT id, task, description, done, important, user_id, date, time, shared_by, todo_id, permission, status
+FROM shared_todos
+WHERE user_id = ? /* sqlc.arg(userID) */
+`
+
+func (q *Queries) GetSharedTodos(ctx context.Context, userID sql.NullString) ([]SharedTodo, error) {
+	rows, err := q.db.QueryContext(ctx, getSharedTodos, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SharedTodo
+	for rows.Next() {
+		var i SharedTodo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Task,
+			&i.Description,
+			&i.Done,
+			&i.Important,
+			&i.UserID,
+			&i.Date,
+			&i.Time,
+			&i.SharedBy,
+			&i.TodoID,
+			&i.Permission,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTaskRoutinesForUpdate = `-- name: GetTaskRoutinesForUpdate :many
+SELECT id, day, scheduleType, isActive
+FROM routines
+WHERE taskId = ? /* sqlc.arg(taskId) */ AND userId = ? /* sqlc.arg(userId) */
+ORDER BY day, scheduleType
+FOR UPDATE
+`
+
+type GetTaskRoutinesForUpdateParams struct {
+	Taskid string
+	Userid string
+}
+
+type GetTaskRoutinesForUpdateRow struct {
+	ID           string
+	Day          RoutinesDay
+	Scheduletype string
+	Isactive     sql.NullBool
+}
+
+func (q *Queries) GetTaskRoutinesForUpdate(ctx context.Context, arg GetTaskRoutinesForUpdateParams) ([]GetTaskRoutinesForUpdateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTaskRoutinesForUpdate, arg.Taskid, arg.Userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTaskRoutinesForUpdateRow
+	for rows.Next() {
+		var i GetTaskRoutinesForUpdateRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Day,
+			&i.Scheduletype,
+			&i.Isactive,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamAssignees = `-- name: GetTeamAssignees :many
+SELECT a.todo_id, a.user_id, u.username, a.done
+FROM team_todo_assignees a
+JOIN users u ON u.id = a.user_id
+WHERE a.team_id = ? /* sqlc.arg(teamID) */
+ORDER BY u.username
+`
+
+type GetTeamAssigneesRow struct {
+	TodoID   string
+	UserID   string
+	Username string
+	Done     bool
+}
+
+func (q *Queries) GetTeamAssignees(ctx context.Context, teamID string) ([]GetTeamAssigneesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamAssignees, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamAssigneesRow
+	for rows.Next() {
+		var i GetTeamAssigneesRow
+		if err := rows.Scan(
+			&i.TodoID,
+			&i.UserID,
+			&i.Username,
+			&i.Done,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamByID = `-- name: GetTeamByID :one
+SELECT id, name, password, admin_id
+FROM teams
+WHERE id = ? /* sqlc.arg(teamID) */
+`
+
+func (q *Queries) GetTeamByID(ctx context.Context, id string) (Team, error) {
+	row := q.db.QueryRowContext(ctx, getTeamByID, id)
+	var i Team
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Password,
+		&i.AdminID,
+	)
+	return i, err
+}
+
+const getTeamForUpdate = `-- name: GetTeamForUpdate :one
+SELECT id
+FROM teams
+WHERE id = ? /* sqlc.arg(teamID) */
+FOR UPDATE
+`
+
+func (q *Queries) GetTeamForUpdate(ctx context.Context, teamID string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getTeamForUpdate, teamID)
+	var id string
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getTeamMemberDetails = `-- name: GetTeamMemberDetails :many
+SELECT u.id, u.username, tm.is_admin
+FROM users u
+JOIN team_members tm ON u.id = tm.user_id
+WHERE tm.team_id = ? /* sqlc.arg(teamID) */
+`
+
+type GetTeamMemberDetailsRow struct {
+	ID       string
+	Username string
+	IsAdmin  sql.NullBool
+}
+
+func (q *Queries) GetTeamMemberDetails(ctx context.Context, teamID string) ([]GetTeamMemberDetailsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamMemberDetails, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamMemberDetailsRow
+	for rows.Next() {
+		var i GetTeamMemberDetailsRow
+		if err := rows.Scan(&i.ID, &i.Username, &i.IsAdmin); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamMembers = `-- name: GetTeamMembers :many
+SELECT team_id, user_id, is_admin
+FROM team_members
+WHERE team_id = ? /* sqlc.arg(teamID) */
+`
+
+func (q *Queries) GetTeamMembers(ctx context.Context, teamID string) ([]TeamMember, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamMembers, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamMember
+	for rows.Next() {
+		var i TeamMember
+		if err := rows.Scan(&i.TeamID, &i.UserID, &i.IsAdmin); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamStatusTransitions = `-- name: GetTeamStatusTransitions :many
+SELECT team_id, from_status_id, to_status_id
+FROM team_status_transitions
+WHERE team_id = ? /* sqlc.arg(teamID) */
+`
+
+func (q *Queries) GetTeamStatusTransitions(ctx context.Context, teamID string) ([]TeamStatusTransition, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamStatusTransitions, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamStatusTransition
+	for rows.Next() {
+		var i TeamStatusTransition
+		if err := rows.Scan(&i.TeamID, &i.FromStatusID, &i.ToStatusID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamStatuses = `-- name: GetTeamStatuses :many
+SELECT id, team_id, name, position, is_terminal
+FROM team_statuses
+WHERE team_id = ? /* sqlc.arg(teamID) */
+ORDER BY position
+`
+
+func (q *Queries) GetTeamStatuses(ctx context.Context, teamID string) ([]TeamStatus, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamStatuses, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamStatus
+	for rows.Next() {
+		var i TeamStatus
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamID,
+			&i.Name,
+			&i.Position,
+			&i.IsTerminal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamTimeEntries = `-- name: GetTeamTimeEntries :many
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.team_id = ? /* sqlc.arg(teamID) */
+  AND e.started_at >= ? /* sqlc.arg(fromTime) */
+  AND e.started_at < ? /* sqlc.arg(toTime) */
+ORDER BY e.started_at
+`
+
+type GetTeamTimeEntriesParams struct {
+	TeamID   sql.NullString
+	FromTime time.Time
+	ToTime   time.Time
+}
+
+type GetTeamTimeEntriesRow struct {
+	ID         string
+	UserID     string
+	Username   string
+	TodoID     sql.NullString
+	TeamID     sql.NullString
+	TeamTodoID sql.NullString
+	Task       string
+	StartedAt  interface{}
+	EndedAt    interface{}
+	Note       string
+}
+
+func (q *Queries) GetTeamTimeEntries(ctx context.Context, arg GetTeamTimeEntriesParams) ([]GetTeamTimeEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamTimeEntries, arg.TeamID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamTimeEntriesRow
+	for rows.Next() {
+		var i GetTeamTimeEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Username,
+			&i.TodoID,
+			&i.TeamID,
+			&i.TeamTodoID,
+			&i.Task,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamTodoAssignee = `-- name: GetTeamTodoAssignee :one
+SELECT assigned_to
+FROM team_todos
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type GetTeamTodoAssigneeParams struct {
+	ID     string
+	TeamID string
+}
+
+func (q *Queries) GetTeamTodoAssignee(ctx context.Context, arg GetTeamTodoAssigneeParams) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, getTeamTodoAssignee, arg.ID, arg.TeamID)
+	var assigned_to sql.NullString
+	err := row.Scan(&assigned_to)
+	return assigned_to, err
+}
+
+const getTeamTodoAssignees = `-- name: GetTeamTodoAssignees :many
+SELECT a.user_id, u.username, a.done
+FROM team_todo_assignees a
+JOIN users u ON u.id = a.user_id
+WHERE a.todo_id = ? /* sqlc.arg(todoID) */
+ORDER BY u.username
+`
+
+type GetTeamTodoAssigneesRow struct {
+	UserID   string
+	Username string
+	Done     bool
+}
+
+func (q *Queries) GetTeamTodoAssignees(ctx context.Context, todoID string) ([]GetTeamTodoAssigneesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamTodoAssignees, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamTodoAssigneesRow
+	for rows.Next() {
+		var i GetTeamTodoAssigneesRow
+		if err := rows.Scan(&i.UserID, &i.Username, &i.Done); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamTodoAssignments = `-- name: GetTeamTodoAssignments :many
+SELECT a.id, a.assigned_to, ua.username AS assigned_to_username,
+  a.previous_assignee, up.username AS previous_assignee_username,
+  a.assigned_by, ub.username AS assigned_by_username,
+  CAST(a.assigned_at AS CHAR) AS assigned_at
+FROM team_todo_assignments a
+LEFT JOIN users ua ON ua.id = a.assigned_to
+LEFT JOIN users up ON up.id = a.previous_assignee
+LEFT JOIN users ub ON ub.id = a.assigned_by
+WHERE a.todo_id = ? /* sqlc.arg(todoID) */ AND a.team_id = ? /* sqlc.arg(teamID) */
+ORDER BY a.id
+`
+
+type GetTeamTodoAssignmentsParams struct {
+	TodoID string
+	TeamID string
+}
+
+type GetTeamTodoAssignmentsRow struct {
+	ID                       int64
+	AssignedTo               sql.NullString
+	AssignedToUsername       sql.NullString
+	PreviousAssignee         sql.NullString
+	PreviousAssigneeUsername sql.NullString
+	AssignedBy               string
+	AssignedByUsername       sql.NullString
+	AssignedAt               interface{}
+}
+
+func (q *Queries) GetTeamTodoAssignments(ctx context.Context, arg GetTeamTodoAssignmentsParams) ([]GetTeamTodoAssignmentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamTodoAssignments, arg.TodoID, arg.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamTodoAssignmentsRow
+	for rows.Next() {
+		var i GetTeamTodoAssignmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AssignedTo,
+			&i.AssignedToUsername,
+			&i.PreviousAssignee,
+			&i.PreviousAssigneeUsername,
+			&i.AssignedBy,
+			&i.AssignedByUsername,
+			&i.AssignedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamTodoCompletion = `-- name: GetTeamTodoCompletion :one
+SELECT done, completion_mode
+FROM team_todos
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type GetTeamTodoCompletionParams struct {
+	ID     string
+	TeamID string
+}
+
+type GetTeamTodoCompletionRow struct {
+	Done           bool
+	CompletionMode string
+}
+
+func (q *Queries) GetTeamTodoCompletion(ctx context.Context, arg GetTeamTodoCompletionParams) (GetTeamTodoCompletionRow, error) {
+	row := q.db.QueryRowContext(ctx, getTeamTodoCompletion, arg.ID, arg.TeamID)
+	var i GetTeamTodoCompletionRow
+	err := row.Scan(&i.Done, &i.CompletionMode)
+	return i, err
+}
+
+const getTeamTodoDependencies = `-- name: GetTeamTodoDependencies :many
+SELECT todo_id, blocked_by_id
+FROM team_todo_dependencies
+WHERE team_id = ? /* sqlc.arg(teamID) */
+`
+
+type GetTeamTodoDependenciesRow struct {
+	TodoID      string
+	BlockedByID string
+}
+
+func (q *Queries) GetTeamTodoDependencies(ctx context.Context, teamID string) ([]GetTeamTodoDependenciesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamTodoDependencies, teamID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Routine
+	var items []GetTeamTodoDependenciesRow
 	for rows.Next() {
-		var i Routine
-		if err := rows.Scan(
-			&i.ID,
-			&i.Day,
-			&i.Scheduletype,
-			&i.Taskid,
-			&i.Userid,
-			&i.Createdat,
-			&i.Updatedat,
-			&i.Isactive,
-		); err != nil {
+		var i GetTeamTodoDependenciesRow
+		if err := rows.Scan(&i.TodoID, &i.BlockedByID); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -380,32 +2076,28 @@ func (q *Queries) GetRoutinesByTaskID(ctx context.Context, taskid string) ([]Rou
 	return items, nil
 }
 
-const getSharedByMeTodos = `-- name: GetSharedByMeTodos :many
-SELECT id, task, description, done, important, user_id, date, time, shared_by
-FROM shared_todos
-WHERE shared_by = ? /* sqlc.arg(sharedBy) */
+const getTeamTodoDependenciesForUpdate = `-- name: GetTeamTodoDependenciesForUpdate :many
+SELECT todo_id, blocked_by_id
+FROM team_todo_dependencies
+WHERE team_id = ? /* sqlc.arg(teamID) */
+FOR UPDATE
 `
 
-func (q *Queries) GetSharedByMeTodos(ctx context.Context, sharedBy sql.NullString) ([]SharedTodo, error) {
-	rows, err := q.db.QueryContext(ctx, getSharedByMeTodos, sharedBy)
+type GetTeamTodoDependenciesForUpdateRow struct {
+	TodoID      string
+	BlockedByID string
+}
+
+func (q *Queries) GetTeamTodoDependenciesForUpdate(ctx context.Context, teamID string) ([]GetTeamTodoDependenciesForUpdateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamTodoDependenciesForUpdate, teamID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []SharedTodo
+	var items []GetTeamTodoDependenciesForUpdateRow
 	for rows.Next() {
-		var i SharedTodo
-		if err := rows.Scan(
-			&i.ID,
-			&i.Task,
-			&i.Description,
-			&i.Done,
-			&i.Important,
-			&i.UserID,
-			&i.Date,
-			&i.Time,
-			&i.SharedBy,
-		); err != nil {
+		var i GetTeamTodoDependenciesForUpdateRow
+		if err := rows.Scan(&i.TodoID, &i.BlockedByID); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -419,31 +2111,26 @@ func (q *Queries) GetSharedByMeTodos(ctx context.Context, sharedBy sql.NullStrin
 	return items, nil
 }
 
-const getSharedTodos = `-- name: GetSharedTodos :many
-SELECT id, task, description, done, important, user_id, date, time, shared_by
-FROM shared_todos
-WHERE user_id = ? /* sqlc.arg(userID) */
+const getTeamTodoEstimates = `-- name: GetTeamTodoEstimates :many
+SELECT todo_id, team_id, estimate, unit
+FROM team_todo_estimates
+WHERE team_id = ? /* sqlc.arg(teamID) */
 `
 
-func (q *Queries) GetSharedTodos(ctx context.Context, userID sql.NullString) ([]SharedTodo, error) {
-	rows, err := q.db.QueryContext(ctx, getSharedTodos, userID)
+func (q *Queries) GetTeamTodoEstimates(ctx context.Context, teamID string) ([]TeamTodoEstimate, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamTodoEstimates, teamID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []SharedTodo
+	var items []TeamTodoEstimate
 	for rows.Next() {
-		var i SharedTodo
+		var i TeamTodoEstimate
 		if err := rows.Scan(
-			&i.ID,
-			&i.Task,
-			&i.Description,
-			&i.Done,
-			&i.Important,
-			&i.UserID,
-			&i.Date,
-			&i.Time,
-			&i.SharedBy,
+			&i.TodoID,
+			&i.TeamID,
+			&i.Estimate,
+			&i.Unit,
 		); err != nil {
 			return nil, err
 		}
@@ -458,47 +2145,27 @@ func (q *Queries) GetSharedTodos(ctx context.Context, userID sql.NullString) ([]
 	return items, nil
 }
 
-const getTeamByID = `-- name: GetTeamByID :one
-SELECT id, name, password, admin_id
-FROM teams
-WHERE id = ? /* sqlc.arg(teamID) */
-`
-
-func (q *Queries) GetTeamByID(ctx context.Context, id string) (Team, error) {
-	row := q.db.QueryRowContext(ctx, getTeamByID, id)
-	var i Team
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Password,
-		&i.AdminID,
-	)
-	return i, err
-}
-
-const getTeamMemberDetails = `-- name: GetTeamMemberDetails :many
-SELECT u.id, u.username, tm.is_admin
-FROM users u
-JOIN team_members tm ON u.id = tm.user_id
-WHERE tm.team_id = ? /* sqlc.arg(teamID) */
+const getTeamTodoPlacements = `-- name: GetTeamTodoPlacements :many
+SELECT todo_id, team_id, status_id, position
+FROM team_todo_placements
+WHERE team_id = ? /* sqlc.arg(teamID) */
 `
 
-type GetTeamMemberDetailsRow struct {
-	ID       string
-	Username string
-	IsAdmin  sql.NullBool
-}
-
-func (q *Queries) GetTeamMemberDetails(ctx context.Context, teamID string) ([]GetTeamMemberDetailsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTeamMemberDetails, teamID)
+func (q *Queries) GetTeamTodoPlacements(ctx context.Context, teamID string) ([]TeamTodoPlacement, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamTodoPlacements, teamID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTeamMemberDetailsRow
+	var items []TeamTodoPlacement
 	for rows.Next() {
-		var i GetTeamMemberDetailsRow
-		if err := rows.Scan(&i.ID, &i.Username, &i.IsAdmin); err != nil {
+		var i TeamTodoPlacement
+		if err := rows.Scan(
+			&i.TodoID,
+			&i.TeamID,
+			&i.StatusID,
+			&i.Position,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -512,22 +2179,59 @@ func (q *Queries) GetTeamMemberDetails(ctx context.Context, teamID string) ([]Ge
 	return items, nil
 }
 
-const getTeamMembers = `-- name: GetTeamMembers :many
-SELECT team_id, user_id, is_admin
-FROM team_members
-WHERE team_id = ? /* sqlc.arg(teamID) */
+const getTeamTodoTimeEntries = `-- name: GetTeamTodoTimeEntries :many
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.team_id = ? /* sqlc.arg(teamID) */ AND e.team_todo_id = ? /* sqlc.arg(teamTodoID) */
+ORDER BY e.started_at
 `
 
-func (q *Queries) GetTeamMembers(ctx context.Context, teamID string) ([]TeamMember, error) {
-	rows, err := q.db.QueryContext(ctx, getTeamMembers, teamID)
+type GetTeamTodoTimeEntriesParams struct {
+	TeamID     sql.NullString
+	TeamTodoID sql.NullString
+}
+
+type GetTeamTodoTimeEntriesRow struct {
+	ID         string
+	UserID     string
+	Username   string
+	TodoID     sql.NullString
+	TeamID     sql.NullString
+	TeamTodoID sql.NullString
+	Task       string
+	StartedAt  interface{}
+	EndedAt    interface{}
+	Note       string
+}
+
+func (q *Queries) GetTeamTodoTimeEntries(ctx context.Context, arg GetTeamTodoTimeEntriesParams) ([]GetTeamTodoTimeEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamTodoTimeEntries, arg.TeamID, arg.TeamTodoID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TeamMember
+	var items []GetTeamTodoTimeEntriesRow
 	for rows.Next() {
-		var i TeamMember
-		if err := rows.Scan(&i.TeamID, &i.UserID, &i.IsAdmin); err != nil {
+		var i GetTeamTodoTimeEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Username,
+			&i.TodoID,
+			&i.TeamID,
+			&i.TeamTodoID,
+			&i.Task,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Note,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -541,8 +2245,27 @@ func (q *Queries) GetTeamMembers(ctx context.Context, teamID string) ([]TeamMemb
 	return items, nil
 }
 
+const getTeamTodoVersionForUpdate = `-- name: GetTeamTodoVersionForUpdate :one
+SELECT version
+FROM team_todos
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+FOR UPDATE
+`
+
+type GetTeamTodoVersionForUpdateParams struct {
+	ID     string
+	TeamID string
+}
+
+func (q *Queries) GetTeamTodoVersionForUpdate(ctx context.Context, arg GetTeamTodoVersionForUpdateParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getTeamTodoVersionForUpdate, arg.ID, arg.TeamID)
+	var version int32
+	err := row.Scan(&version)
+	return version, err
+}
+
 const getTeamTodos = `-- name: GetTeamTodos :many
-SELECT id, task, description, done, important, team_id, assigned_to, date, time
+SELECT id, task, description, done, important, team_id, assigned_to, date, time, version, completion_mode
 FROM team_todos
 WHERE team_id = ? /* sqlc.arg(teamID) */
 `
@@ -566,6 +2289,64 @@ func (q *Queries) GetTeamTodos(ctx context.Context, teamID string) ([]TeamTodo,
 			&i.AssignedTo,
 			&i.Date,
 			&i.Time,
+			&i.Version,
+			&i.CompletionMode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamWorkloadTodos = `-- name: GetTeamWorkloadTodos :many
+SELECT t.id, (t.done OR COALESCE(a.done, FALSE)) AS done,
+  COALESCE(a.user_id, t.assigned_to) AS assigned_to, u.username,
+  CAST(t.date AS CHAR) AS date,
+  CAST(t.time AS CHAR) AS time,
+  e.estimate, e.unit
+FROM team_todos t
+LEFT JOIN team_todo_assignees a ON a.todo_id = t.id
+LEFT JOIN users u ON u.id = COALESCE(a.user_id, t.assigned_to)
+LEFT JOIN team_todo_estimates e ON e.todo_id = t.id
+WHERE t.team_id = ? /* sqlc.arg(teamID) */
+`
+
+type GetTeamWorkloadTodosRow struct {
+	ID         string
+	Done       bool
+	AssignedTo sql.NullString
+	Username   sql.NullString
+	Date       interface{}
+	Time       interface{}
+	Estimate   sql.NullString
+	Unit       NullTeamTodoEstimatesUnit
+}
+
+func (q *Queries) GetTeamWorkloadTodos(ctx context.Context, teamID string) ([]GetTeamWorkloadTodosRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTeamWorkloadTodos, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamWorkloadTodosRow
+	for rows.Next() {
+		var i GetTeamWorkloadTodosRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Done,
+			&i.AssignedTo,
+			&i.Username,
+			&i.Date,
+			&i.Time,
+			&i.Estimate,
+			&i.Unit,
 		); err != nil {
 			return nil, err
 		}
@@ -649,6 +2430,157 @@ func (q *Queries) GetTeamsByAdminID(ctx context.Context, adminID string) ([]Team
 	return items, nil
 }
 
+const getTodoDependencies = `-- name: GetTodoDependencies :many
+SELECT d.todo_id, d.blocked_by_id
+FROM todo_dependencies d
+JOIN todos t ON t.id = d.todo_id
+WHERE t.user_id = ? /* sqlc.arg(userID) */
+`
+
+type GetTodoDependenciesRow struct {
+	TodoID      string
+	BlockedByID string
+}
+
+func (q *Queries) GetTodoDependencies(ctx context.Context, userID string) ([]GetTodoDependenciesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTodoDependencies, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTodoDependenciesRow
+	for rows.Next() {
+		var i GetTodoDependenciesRow
+		if err := rows.Scan(&i.TodoID, &i.BlockedByID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodoDependenciesForUpdate = `-- name: GetTodoDependenciesForUpdate :many
+SELECT d.todo_id, d.blocked_by_id
+FROM todo_dependencies d
+JOIN todos t ON t.id = d.todo_id
+WHERE t.user_id = ? /* sqlc.arg(userID) */
+FOR UPDATE
+`
+
+type GetTodoDependenciesForUpdateRow struct {
+	TodoID      string
+	BlockedByID string
+}
+
+func (q *Queries) GetTodoDependenciesForUpdate(ctx context.Context, userID string) ([]GetTodoDependenciesForUpdateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTodoDependenciesForUpdate, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTodoDependenciesForUpdateRow
+	for rows.Next() {
+		var i GetTodoDependenciesForUpdateRow
+		if err := rows.Scan(&i.TodoID, &i.BlockedByID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodoTimeEntries = `-- name: GetTodoTimeEntries :many
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.todo_id = ? /* sqlc.arg(todoID) */
+ORDER BY e.started_at
+`
+
+type GetTodoTimeEntriesRow struct {
+	ID         string
+	UserID     string
+	Username   string
+	TodoID     sql.NullString
+	TeamID     sql.NullString
+	TeamTodoID sql.NullString
+	Task       string
+	StartedAt  interface{}
+	EndedAt    interface{}
+	Note       string
+}
+
+func (q *Queries) GetTodoTimeEntries(ctx context.Context, todoID sql.NullString) ([]GetTodoTimeEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTodoTimeEntries, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTodoTimeEntriesRow
+	for rows.Next() {
+		var i GetTodoTimeEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Username,
+			&i.TodoID,
+			&i.TeamID,
+			&i.TeamTodoID,
+			&i.Task,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodoVersionForUpdate = `-- name: GetTodoVersionForUpdate :one
+SELECT version
+FROM todos
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
+FOR UPDATE
+`
+
+type GetTodoVersionForUpdateParams struct {
+	ID     string
+	UserID sql.NullString
+}
+
+func (q *Queries) GetTodoVersionForUpdate(ctx context.Context, arg GetTodoVersionForUpdateParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getTodoVersionForUpdate, arg.ID, arg.UserID)
+	var version int32
+	err := row.Scan(&version)
+	return version, err
+}
+
 const getTodosByUserID = `-- name: GetTodosByUserID :many
 SELECT 
   id, 
@@ -658,7 +2590,8 @@ SELECT
   important, 
   user_id, 
   CAST(date AS CHAR) AS date, 
-  CAST(time AS CHAR) AS time
+  CAST(time AS CHAR) AS time,
+  version
 FROM todos
 WHERE user_id = ? /* sqlc.arg(userID) */
 `
@@ -672,6 +2605,7 @@ type GetTodosByUserIDRow struct {
 	UserID      sql.NullString
 	Date        interface{}
 	Time        interface{}
+	Version     int32
 }
 
 func (q *Queries) GetTodosByUserID(ctx context.Context, userID sql.NullString) ([]GetTodosByUserIDRow, error) {
@@ -692,6 +2626,7 @@ func (q *Queries) GetTodosByUserID(ctx context.Context, userID sql.NullString) (
 			&i.UserID,
 			&i.Date,
 			&i.Time,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -707,7 +2642,7 @@ func (q *Queries) GetTodosByUserID(ctx context.Context, userID sql.NullString) (
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, password
+SELECT id, username, password, timezone
 FROM users
 WHERE username = ? /* sqlc.arg(username) */
 `
@@ -715,10 +2650,163 @@ WHERE username = ? /* sqlc.arg(username) */
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
 	row := q.db.QueryRowContext(ctx, getUserByUsername, username)
 	var i User
-	err := row.Scan(&i.ID, &i.Username, &i.Password)
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Password,
+		&i.Timezone,
+	)
 	return i, err
 }
 
+const getUserTimeEntries = `-- name: GetUserTimeEntries :many
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.user_id = ? /* sqlc.arg(userID) */
+  AND e.started_at >= ? /* sqlc.arg(fromTime) */
+  AND e.started_at < ? /* sqlc.arg(toTime) */
+ORDER BY e.started_at
+`
+
+type GetUserTimeEntriesParams struct {
+	UserID   string
+	FromTime time.Time
+	ToTime   time.Time
+}
+
+type GetUserTimeEntriesRow struct {
+	ID         string
+	UserID     string
+	Username   string
+	TodoID     sql.NullString
+	TeamID     sql.NullString
+	TeamTodoID sql.NullString
+	Task       string
+	StartedAt  interface{}
+	EndedAt    interface{}
+	Note       string
+}
+
+func (q *Queries) GetUserTimeEntries(ctx context.Context, arg GetUserTimeEntriesParams) ([]GetUserTimeEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserTimeEntries, arg.UserID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserTimeEntriesRow
+	for rows.Next() {
+		var i GetUserTimeEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Username,
+			&i.TodoID,
+			&i.TeamID,
+			&i.TeamTodoID,
+			&i.Task,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserTimezone = `-- name: GetUserTimezone :one
+SELECT timezone
+FROM users
+WHERE id = ? /* sqlc.arg(id) */
+`
+
+func (q *Queries) GetUserTimezone(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserTimezone, id)
+	var timezone string
+	err := row.Scan(&timezone)
+	return timezone, err
+}
+
+const getWeekRoutines = `-- name: GetWeekRoutines :many
+SELECT r.id, r.day, r.scheduleType, t.id AS task_id, t.task, t.description, t.important
+FROM routines r
+JOIN todos t ON t.id = r.taskId
+WHERE r.userId = ? /* sqlc.arg(userId) */ AND r.isActive = true
+ORDER BY r.day, t.task
+`
+
+type GetWeekRoutinesRow struct {
+	ID           string
+	Day          RoutinesDay
+	Scheduletype string
+	TaskID       string
+	Task         string
+	Description  sql.NullString
+	Important    bool
+}
+
+func (q *Queries) GetWeekRoutines(ctx context.Context, userid string) ([]GetWeekRoutinesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWeekRoutines, userid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWeekRoutinesRow
+	for rows.Next() {
+		var i GetWeekRoutinesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Day,
+			&i.Scheduletype,
+			&i.TaskID,
+			&i.Task,
+			&i.Description,
+			&i.Important,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isShareBlocked = `-- name: IsShareBlocked :one
+SELECT COUNT(*) FROM share_blocks
+WHERE user_id = ? /* sqlc.arg(userId) */ AND blocked_user_id = ? /* sqlc.arg(blockedUserId) */
+`
+
+type IsShareBlockedParams struct {
+	UserID        string
+	BlockedUserID string
+}
+
+func (q *Queries) IsShareBlocked(ctx context.Context, arg IsShareBlockedParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, isShareBlocked, arg.UserID, arg.BlockedUserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const joinTeam = `-- name: JoinTeam :exec
 INSERT INTO team_members (team_id, user_id, is_admin)
 SELECT 
@@ -755,8 +2843,253 @@ func (q *Queries) RemoveTeamMember(ctx context.Context, arg RemoveTeamMemberPara
 	return err
 }
 
+const renameSlotRoutines = `-- name: RenameSlotRoutines :exec
+UPDATE routines
+SET scheduleType = ? /* sqlc.arg(newName) */
+WHERE userId = ? /* sqlc.arg(userId) */ AND scheduleType = ? /* sqlc.arg(oldName) */
+`
+
+type RenameSlotRoutinesParams struct {
+	NewName string
+	Userid  string
+	OldName string
+}
+
+func (q *Queries) RenameSlotRoutines(ctx context.Context, arg RenameSlotRoutinesParams) error {
+	_, err := q.db.ExecContext(ctx, renameSlotRoutines, arg.NewName, arg.Userid, arg.OldName)
+	return err
+}
+
+const setCalendarFeedToken = `-- name: SetCalendarFeedToken :exec
+INSERT INTO calendar_feeds (user_id, token_hash)
+VALUES (
+  ? /* sqlc.arg(userID) */,
+  ? /* sqlc.arg(tokenHash) */
+)
+ON DUPLICATE KEY UPDATE token_hash = VALUES(token_hash), created_at = CURRENT_TIMESTAMP
+`
+
+type SetCalendarFeedTokenParams struct {
+	UserID    string
+	TokenHash string
+}
+
+func (q *Queries) SetCalendarFeedToken(ctx context.Context, arg SetCalendarFeedTokenParams) error {
+	_, err := q.db.ExecContext(ctx, setCalendarFeedToken, arg.UserID, arg.TokenHash)
+	return err
+}
+
+const setTeamTodoAssignee = `-- name: SetTeamTodoAssignee :exec
+UPDATE team_todos
+SET assigned_to = ? /* sqlc.arg(assignedTo) */,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type SetTeamTodoAssigneeParams struct {
+	AssignedTo sql.NullString
+	ID         string
+	TeamID     string
+}
+
+func (q *Queries) SetTeamTodoAssignee(ctx context.Context, arg SetTeamTodoAssigneeParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamTodoAssignee, arg.AssignedTo, arg.ID, arg.TeamID)
+	return err
+}
+
+const setTeamTodoAssigneeDone = `-- name: SetTeamTodoAssigneeDone :execrows
+UPDATE team_todo_assignees
+SET done = ? /* sqlc.arg(done) */
+WHERE todo_id = ? /* sqlc.arg(todoID) */ AND user_id = ? /* sqlc.arg(userID) */
+`
+
+type SetTeamTodoAssigneeDoneParams struct {
+	Done   bool
+	TodoID string
+	UserID string
+}
+
+func (q *Queries) SetTeamTodoAssigneeDone(ctx context.Context, arg SetTeamTodoAssigneeDoneParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setTeamTodoAssigneeDone, arg.Done, arg.TodoID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setTeamTodoAssigneesDone = `-- name: SetTeamTodoAssigneesDone :exec
+UPDATE team_todo_assignees
+SET done = ? /* sqlc.arg(done) */
+WHERE todo_id = ? /* sqlc.arg(todoID) */
+`
+
+type SetTeamTodoAssigneesDoneParams struct {
+	Done   bool
+	TodoID string
+}
+
+func (q *Queries) SetTeamTodoAssigneesDone(ctx context.Context, arg SetTeamTodoAssigneesDoneParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamTodoAssigneesDone, arg.Done, arg.TodoID)
+	return err
+}
+
+const setTeamTodoDate = `-- name: SetTeamTodoDate :exec
+UPDATE team_todos
+SET date = ? /* sqlc.arg(date) */
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type SetTeamTodoDateParams struct {
+	Date   sql.NullTime
+	ID     string
+	TeamID string
+}
+
+func (q *Queries) SetTeamTodoDate(ctx context.Context, arg SetTeamTodoDateParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamTodoDate, arg.Date, arg.ID, arg.TeamID)
+	return err
+}
+
+const setTeamTodoDone = `-- name: SetTeamTodoDone :exec
+UPDATE team_todos
+SET done = ? /* sqlc.arg(done) */,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type SetTeamTodoDoneParams struct {
+	Done   bool
+	ID     string
+	TeamID string
+}
+
+func (q *Queries) SetTeamTodoDone(ctx context.Context, arg SetTeamTodoDoneParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamTodoDone, arg.Done, arg.ID, arg.TeamID)
+	return err
+}
+
+const setTeamTodoEstimate = `-- name: SetTeamTodoEstimate :exec
+INSERT INTO team_todo_estimates (todo_id, team_id, estimate, unit)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(estimate) */,
+  ? /* sqlc.arg(unit) */
+)
+ON DUPLICATE KEY UPDATE estimate = VALUES(estimate), unit = VALUES(unit)
+`
+
+type SetTeamTodoEstimateParams struct {
+	TodoID   string
+	TeamID   string
+	Estimate string
+	Unit     TeamTodoEstimatesUnit
+}
+
+func (q *Queries) SetTeamTodoEstimate(ctx context.Context, arg SetTeamTodoEstimateParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamTodoEstimate,
+		arg.TodoID,
+		arg.TeamID,
+		arg.Estimate,
+		arg.Unit,
+	)
+	return err
+}
+
+const setTeamTodoPlacement = `-- name: SetTeamTodoPlacement :exec
+INSERT INTO team_todo_placements (todo_id, team_id, status_id, position)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(statusID) */,
+  ? /* sqlc.arg(position) */
+)
+ON DUPLICATE KEY UPDATE status_id = VALUES(status_id), position = VALUES(position)
+`
+
+type SetTeamTodoPlacementParams struct {
+	TodoID   string
+	TeamID   string
+	StatusID string
+	Position int32
+}
+
+func (q *Queries) SetTeamTodoPlacement(ctx context.Context, arg SetTeamTodoPlacementParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamTodoPlacement,
+		arg.TodoID,
+		arg.TeamID,
+		arg.StatusID,
+		arg.Position,
+	)
+	return err
+}
+
+const setUserTimezone = `-- name: SetUserTimezone :execrows
+UPDATE users
+SET timezone = ? /* sqlc.arg(timezone) */
+WHERE id = ? /* sqlc.arg(id) */
+`
+
+type SetUserTimezoneParams struct {
+	Timezone string
+	ID       string
+}
+
+func (q *Queries) SetUserTimezone(ctx context.Context, arg SetUserTimezoneParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserTimezone, arg.Timezone, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setTeamTodoTime = `-- name: SetTeamTodoTime :exec
+UPDATE team_todos
+SET time = ? /* sqlc.arg(time) */
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type SetTeamTodoTimeParams struct {
+	Time   sql.NullTime
+	ID     string
+	TeamID string
+}
+
+func (q *Queries) SetTeamTodoTime(ctx context.Context, arg SetTeamTodoTimeParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamTodoTime, arg.Time, arg.ID, arg.TeamID)
+	return err
+}
+
+const setTodoRecurrence = `-- name: SetTodoRecurrence :exec
+INSERT INTO todo_recurrences (todo_id, frequency, interval_count, weekdays)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(frequency) */,
+  ? /* sqlc.arg(intervalCount) */,
+  ? /* sqlc.arg(weekdays) */
+)
+ON DUPLICATE KEY UPDATE frequency = VALUES(frequency), interval_count = VALUES(interval_count), weekdays = VALUES(weekdays)
+`
+
+type SetTodoRecurrenceParams struct {
+	TodoID        string
+	Frequency     TodoRecurrencesFrequency
+	IntervalCount int32
+	Weekdays      string
+}
+
+func (q *Queries) SetTodoRecurrence(ctx context.Context, arg SetTodoRecurrenceParams) error {
+	_, err := q.db.ExecContext(ctx, setTodoRecurrence,
+		arg.TodoID,
+		arg.Frequency,
+		arg.IntervalCount,
+		arg.Weekdays,
+	)
+	return err
+}
+
 const shareTodoWithUser = `-- name: ShareTodoWithUser :exec
-INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by)
+INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by, todo_id)
 SELECT 
   ? /* sqlc.arg(newID) */,
   task, 
@@ -766,7 +3099,8 @@ SELECT
   (SELECT id FROM users WHERE username = ? /* sqlc.arg(receiverUsername) */), 
   date, 
   time, 
-  ? /* sqlc.arg(senderID) */
+  ? /* sqlc.arg(senderID) */,
+  todos.id
 FROM todos
 WHERE todos.id = ? /* sqlc.arg(todoID) */
 `
@@ -788,9 +3122,28 @@ func (q *Queries) ShareTodoWithUser(ctx context.Context, arg ShareTodoWithUserPa
 	return err
 }
 
+const uncheckRoutine = `-- name: UncheckRoutine :execrows
+DELETE FROM routine_checkins
+WHERE routine_id = ? /* sqlc.arg(routineID) */ AND date = ? /* sqlc.arg(date) */
+`
+
+type UncheckRoutineParams struct {
+	RoutineID string
+	Date      time.Time
+}
+
+func (q *Queries) UncheckRoutine(ctx context.Context, arg UncheckRoutineParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, uncheckRoutine, arg.RoutineID, arg.Date)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const undoTodo = `-- name: UndoTodo :exec
 UPDATE todos
-SET done = false
+SET done = false,
+    version = version + 1
 WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
 `
 
@@ -807,7 +3160,8 @@ func (q *Queries) UndoTodo(ctx context.Context, arg UndoTodoParams) error {
 const updateRoutineDay = `-- name: UpdateRoutineDay :exec
 UPDATE routines
 SET day = ? /* sqlc.arg(day) */,
-    updatedAt = ? /* sqlc.arg(updatedAt) */
+    updatedAt = ? /* sqlc.arg(updatedAt) */,
+    version = version + 1
 WHERE id = ? /* sqlc.arg(id) */
 `
 
@@ -822,10 +3176,35 @@ func (q *Queries) UpdateRoutineDay(ctx context.Context, arg UpdateRoutineDayPara
 	return err
 }
 
+const updateRoutineSlot = `-- name: UpdateRoutineSlot :exec
+UPDATE routine_slots
+SET name = ? /* sqlc.arg(name) */,
+    start_time = ? /* sqlc.arg(startTime) */
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userId) */
+`
+
+type UpdateRoutineSlotParams struct {
+	Name      string
+	StartTime time.Time
+	ID        string
+	UserID    string
+}
+
+func (q *Queries) UpdateRoutineSlot(ctx context.Context, arg UpdateRoutineSlotParams) error {
+	_, err := q.db.ExecContext(ctx, updateRoutineSlot,
+		arg.Name,
+		arg.StartTime,
+		arg.ID,
+		arg.UserID,
+	)
+	return err
+}
+
 const updateRoutineStatus = `-- name: UpdateRoutineStatus :exec
 UPDATE routines
 SET isActive = ? /* sqlc.arg(isActive) */,
-    updatedAt = ? /* sqlc.arg(updatedAt) */
+    updatedAt = ? /* sqlc.arg(updatedAt) */,
+    version = version + 1
 WHERE id = ? /* sqlc.arg(id) */
 `
 
@@ -840,6 +3219,26 @@ func (q *Queries) UpdateRoutineStatus(ctx context.Context, arg UpdateRoutineStat
 	return err
 }
 
+const updateSharedTodoPermission = `-- name: UpdateSharedTodoPermission :execrows
+UPDATE shared_todos
+SET permission = ? /* sqlc.arg(permission) */
+WHERE id = ? /* sqlc.arg(id) */ AND shared_by = ? /* sqlc.arg(sharedBy) */
+`
+
+type UpdateSharedTodoPermissionParams struct {
+	Permission string
+	ID         string
+	SharedBy   sql.NullString
+}
+
+func (q *Queries) UpdateSharedTodoPermission(ctx context.Context, arg UpdateSharedTodoPermissionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateSharedTodoPermission, arg.Permission, arg.ID, arg.SharedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateTeamTodo = `-- name: UpdateTeamTodo :exec
 UPDATE team_todos
 SET 
@@ -847,7 +3246,8 @@ SET
   description = ? /* sqlc.arg(description) */,
   done = ? /* sqlc.arg(done) */,
   important = ? /* sqlc.arg(important) */,
-  assigned_to = ? /* sqlc.arg(assignedTo) */
+  assigned_to = ? /* sqlc.arg(assignedTo) */,
+  version = version + 1
 WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
 `
 
@@ -874,12 +3274,38 @@ func (q *Queries) UpdateTeamTodo(ctx context.Context, arg UpdateTeamTodoParams)
 	return err
 }
 
+const updateTeamTodoAssignment = `-- name: UpdateTeamTodoAssignment :exec
+UPDATE team_todos
+SET assigned_to = ? /* sqlc.arg(assignedTo) */,
+    completion_mode = ? /* sqlc.arg(completionMode) */,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+`
+
+type UpdateTeamTodoAssignmentParams struct {
+	AssignedTo     sql.NullString
+	CompletionMode string
+	ID             string
+	TeamID         string
+}
+
+func (q *Queries) UpdateTeamTodoAssignment(ctx context.Context, arg UpdateTeamTodoAssignmentParams) error {
+	_, err := q.db.ExecContext(ctx, updateTeamTodoAssignment,
+		arg.AssignedTo,
+		arg.CompletionMode,
+		arg.ID,
+		arg.TeamID,
+	)
+	return err
+}
+
 const updateTodo = `-- name: UpdateTodo :exec
 UPDATE todos
 SET task = ? /* sqlc.arg(task) */,
     description = ? /* sqlc.arg(description) */,
     done = ? /* sqlc.arg(done) */,
-    important = ? /* sqlc.arg(important) */
+    important = ? /* sqlc.arg(important) */,
+    version = version + 1
 WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
 `
 
@@ -903,3 +3329,34 @@ func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) error {
 	)
 	return err
 }
+
+const upsertTeamStatus = `-- name: UpsertTeamStatus :exec
+INSERT INTO team_statuses (id, team_id, name, position, is_terminal)
+VALUES (
+  ? /* sqlc.arg(id) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(name) */,
+  ? /* sqlc.arg(position) */,
+  ? /* sqlc.arg(isTerminal) */
+)
+ON DUPLICATE KEY UPDATE name = VALUES(name), position = VALUES(position), is_terminal = VALUES(is_terminal)
+`
+
+type UpsertTeamStatusParams struct {
+	ID         string
+	TeamID     string
+	Name       string
+	Position   int32
+	IsTerminal bool
+}
+
+func (q *Queries) UpsertTeamStatus(ctx context.Context, arg UpsertTeamStatusParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTeamStatus,
+		arg.ID,
+		arg.TeamID,
+		arg.Name,
+		arg.Position,
+		arg.IsTerminal,
+	)
+	return err
+}
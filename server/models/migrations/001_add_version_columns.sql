@@ -0,0 +1,6 @@
+-- Optimistic concurrency control: every update bumps the row version,
+-- which is exposed to clients as an ETag.
+
+ALTER TABLE todos ADD COLUMN version int NOT NULL DEFAULT 1;
+ALTER TABLE team_todos ADD COLUMN version int NOT NULL DEFAULT 1;
+ALTER TABLE routines ADD COLUMN version int NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+-- Secret iCalendar feed URLs. Only a SHA-256 hash of each token is stored,
+-- and a user has at most one active token.
+
+CREATE TABLE calendar_feeds (
+  user_id varchar(36) NOT NULL,
+  token_hash char(64) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id),
+  UNIQUE KEY token_hash (token_hash),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,23 @@
+-- Blocked-by links. A row means todo_id cannot be completed until
+-- blocked_by_id is done. Team links only join todos of the same team.
+
+CREATE TABLE todo_dependencies (
+  todo_id varchar(36) NOT NULL,
+  blocked_by_id varchar(36) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (todo_id, blocked_by_id),
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (blocked_by_id) REFERENCES todos(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_todo_dependencies (
+  team_id varchar(36) NOT NULL,
+  todo_id varchar(36) NOT NULL,
+  blocked_by_id varchar(36) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (todo_id, blocked_by_id),
+  KEY team_id (team_id),
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (blocked_by_id) REFERENCES team_todos(id) ON DELETE CASCADE
+);
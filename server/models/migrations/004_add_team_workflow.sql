@@ -0,0 +1,36 @@
+-- Per-team workflow statuses for the Kanban board. team_todos.done is kept
+-- for older clients and follows the is_terminal flag of the todo's status.
+-- A team without transition rows allows moves between any statuses.
+
+CREATE TABLE team_statuses (
+  id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  name varchar(64) NOT NULL,
+  position int NOT NULL,
+  is_terminal BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (id),
+  UNIQUE KEY team_status_name (team_id, name),
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_status_transitions (
+  team_id varchar(36) NOT NULL,
+  from_status_id varchar(36) NOT NULL,
+  to_status_id varchar(36) NOT NULL,
+  PRIMARY KEY (from_status_id, to_status_id),
+  KEY team_id (team_id),
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY (from_status_id) REFERENCES team_statuses(id) ON DELETE CASCADE,
+  FOREIGN KEY (to_status_id) REFERENCES team_statuses(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_todo_placements (
+  todo_id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  status_id varchar(36) NOT NULL,
+  position int NOT NULL DEFAULT 0,
+  PRIMARY KEY (todo_id),
+  KEY team_status (team_id, status_id),
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (status_id) REFERENCES team_statuses(id) ON DELETE CASCADE
+);
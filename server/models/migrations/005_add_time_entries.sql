@@ -0,0 +1,24 @@
+-- Time entries against todos or team todos. Exactly one of todo_id and
+-- team_todo_id is set; team_id accompanies team_todo_id. A row with no
+-- ended_at is a running timer, and the unique running_user_id column allows
+-- at most one of those per user.
+
+CREATE TABLE time_entries (
+  id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  todo_id varchar(36) DEFAULT NULL,
+  team_id varchar(36) DEFAULT NULL,
+  team_todo_id varchar(36) DEFAULT NULL,
+  started_at DATETIME NOT NULL,
+  ended_at DATETIME DEFAULT NULL,
+  note varchar(255) NOT NULL DEFAULT '',
+  running_user_id varchar(36) GENERATED ALWAYS AS (IF(ended_at IS NULL, user_id, NULL)) STORED,
+  PRIMARY KEY (id),
+  UNIQUE KEY running_timer (running_user_id),
+  KEY user_started (user_id, started_at),
+  KEY team_started (team_id, started_at),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_todo_id) REFERENCES team_todos(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+-- Optional effort estimates on team todos, in hours or story points. A todo
+-- has at most one estimate; the workload report totals them per assignee.
+
+CREATE TABLE team_todo_estimates (
+  todo_id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  estimate DECIMAL(8,2) NOT NULL,
+  unit ENUM('hours', 'points') NOT NULL,
+  PRIMARY KEY (todo_id),
+  KEY team_id (team_id),
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
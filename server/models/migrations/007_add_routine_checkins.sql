@@ -0,0 +1,13 @@
+-- Per-date completion log for routines. A row means the routine was done on
+-- that date; the underlying todo's done flag is no longer used for routines.
+
+CREATE TABLE routine_checkins (
+  routine_id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  date DATE NOT NULL,
+  checked_in_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (routine_id, date),
+  KEY user_date (user_id, date),
+  FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,26 @@
+-- User-defined time-of-day slots for routines. routines.scheduleType becomes the
+-- name of one of the user's slots instead of a fixed ENUM.
+
+CREATE TABLE routine_slots (
+  id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  name varchar(50) NOT NULL,
+  start_time TIME NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY user_slot_name (user_id, name),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+ALTER TABLE routines MODIFY scheduleType varchar(50) NOT NULL;
+
+-- Existing users get the four slots the ENUM used to provide. Users created
+-- later receive them the first time their slots are read.
+INSERT INTO routine_slots (id, user_id, name, start_time)
+SELECT UUID(), u.id, s.name, s.start_time
+FROM users u
+CROSS JOIN (
+  SELECT 'morning' AS name, '08:00:00' AS start_time
+  UNION ALL SELECT 'noon', '12:00:00'
+  UNION ALL SELECT 'evening', '18:00:00'
+  UNION ALL SELECT 'night', '21:00:00'
+) s;
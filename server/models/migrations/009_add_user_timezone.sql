@@ -0,0 +1,4 @@
+-- IANA timezone of each user, used to work out "today" for routines and todo
+-- date defaults. Existing users start on UTC.
+
+ALTER TABLE users ADD COLUMN timezone varchar(64) NOT NULL DEFAULT 'UTC' AFTER password;
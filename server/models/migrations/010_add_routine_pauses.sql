@@ -0,0 +1,17 @@
+-- Vacation mode: date ranges in which routines are skipped. A pause without
+-- routine_id covers all of the user's routines. Routines resume on their own
+-- once end_date has passed.
+
+CREATE TABLE routine_pauses (
+  id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  routine_id varchar(36) DEFAULT NULL,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+  reason varchar(255) DEFAULT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY user_dates (user_id, start_date),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE
+);
@@ -0,0 +1,21 @@
+-- Shared todos reference the original todo instead of being snapshot copies.
+-- Reads take task, done, dates etc. from the linked todo so edits by either
+-- side are visible to both; the copied columns only remain for old rows that
+-- cannot be linked.
+
+ALTER TABLE shared_todos
+  ADD COLUMN todo_id varchar(36) DEFAULT NULL,
+  ADD KEY todo_recipient (todo_id, user_id),
+  ADD FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE;
+
+-- Link existing copies back to the sharer's todo with the same task. Copies
+-- whose task matches several of the sharer's todos stay unlinked.
+UPDATE shared_todos s
+JOIN (
+  SELECT user_id, task, MIN(id) AS id
+  FROM todos
+  GROUP BY user_id, task
+  HAVING COUNT(*) = 1
+) t ON t.user_id = s.shared_by AND t.task = s.task
+SET s.todo_id = t.id
+WHERE s.todo_id IS NULL;
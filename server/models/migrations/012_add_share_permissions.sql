@@ -0,0 +1,6 @@
+-- Each share carries a permission level. Recipients with "view" only see the
+-- todo; "edit" also lets them update and complete it. Existing shares start
+-- out read-only and the owner can raise them.
+
+ALTER TABLE shared_todos
+  ADD COLUMN permission varchar(10) NOT NULL DEFAULT 'view';
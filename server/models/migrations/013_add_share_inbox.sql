@@ -0,0 +1,19 @@
+-- Shares start as pending invitations in the recipient's inbox and only show
+-- up in the recipient's lists once accepted. Shares made before the inbox
+-- existed were already visible, so they count as accepted.
+
+ALTER TABLE shared_todos
+  ADD COLUMN status varchar(10) NOT NULL DEFAULT 'pending',
+  ADD KEY user_status (user_id, status);
+
+UPDATE shared_todos SET status = 'accepted';
+
+-- Senders a user no longer takes shares from
+CREATE TABLE share_blocks (
+  user_id varchar(36) NOT NULL,
+  blocked_user_id varchar(36) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, blocked_user_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (blocked_user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+-- Team todos used to store an empty assignee as '' instead of NULL
+UPDATE team_todos SET assigned_to = NULL WHERE assigned_to = '';
+
+-- Every change of a team todo's assignee, oldest first
+CREATE TABLE team_todo_assignments (
+  id bigint NOT NULL AUTO_INCREMENT,
+  todo_id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  assigned_to varchar(36) DEFAULT NULL,
+  previous_assignee varchar(36) DEFAULT NULL,
+  assigned_by varchar(36) NOT NULL,
+  assigned_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY todo_id (todo_id),
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
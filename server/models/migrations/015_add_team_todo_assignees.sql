@@ -0,0 +1,24 @@
+-- A team todo can be assigned to several members. assigned_to keeps the
+-- primary assignee for clients that only know about one. Each assignee marks
+-- their own part done; completion_mode decides whether the todo is done once
+-- all of them or any of them have.
+
+ALTER TABLE team_todos
+  ADD COLUMN completion_mode varchar(3) NOT NULL DEFAULT 'all';
+
+CREATE TABLE team_todo_assignees (
+  todo_id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  done BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (todo_id, user_id),
+  KEY team_user (team_id, user_id),
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO team_todo_assignees (todo_id, team_id, user_id, done)
+SELECT id, team_id, assigned_to, done
+FROM team_todos
+WHERE assigned_to IS NOT NULL;
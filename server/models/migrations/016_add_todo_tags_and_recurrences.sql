@@ -0,0 +1,18 @@
+-- Tags written as #tag when quick-adding a todo
+CREATE TABLE todo_tags (
+  todo_id varchar(36) NOT NULL,
+  tag varchar(50) NOT NULL,
+  PRIMARY KEY (todo_id, tag),
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+);
+
+-- Repeat rules routines cannot express, such as "every month" or "every 2 weeks".
+-- Weekdays is a comma-separated list of day names for weekly rules.
+CREATE TABLE todo_recurrences (
+  todo_id varchar(36) NOT NULL,
+  frequency ENUM('daily', 'weekly', 'monthly', 'yearly') NOT NULL,
+  interval_count int NOT NULL DEFAULT 1,
+  weekdays varchar(64) NOT NULL DEFAULT '',
+  PRIMARY KEY (todo_id),
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+);
@@ -9,10 +9,20 @@ VALUES (
 );
 
 -- name: GetUserByUsername :one
-SELECT id, username, password
+SELECT id, username, password, timezone
 FROM users
 WHERE username = ? /* sqlc.arg(username) */;
 
+-- name: GetUserTimezone :one
+SELECT timezone
+FROM users
+WHERE id = ? /* sqlc.arg(id) */;
+
+-- name: SetUserTimezone :execrows
+UPDATE users
+SET timezone = ? /* sqlc.arg(timezone) */
+WHERE id = ? /* sqlc.arg(id) */;
+
 -- Todos Queries
 
 -- name: CreateTodo :exec
@@ -37,7 +47,8 @@ SELECT
   important, 
   user_id, 
   CAST(date AS CHAR) AS date, 
-  CAST(time AS CHAR) AS time
+  CAST(time AS CHAR) AS time,
+  version
 FROM todos
 WHERE user_id = ? /* sqlc.arg(userID) */;
 
@@ -46,7 +57,8 @@ UPDATE todos
 SET task = ? /* sqlc.arg(task) */,
     description = ? /* sqlc.arg(description) */,
     done = ? /* sqlc.arg(done) */,
-    important = ? /* sqlc.arg(important) */
+    important = ? /* sqlc.arg(important) */,
+    version = version + 1
 WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */;
 
 -- name: DeleteTodo :exec
@@ -55,13 +67,40 @@ WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */;
 
 -- name: UndoTodo :exec
 UPDATE todos
-SET done = false
+SET done = false,
+    version = version + 1
 WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */;
 
+-- name: CompleteTodo :exec
+UPDATE todos
+SET done = true,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */;
+
+-- name: GetTodoVersionForUpdate :one
+SELECT version
+FROM todos
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */
+FOR UPDATE;
+
+-- name: AddTodoTag :exec
+INSERT IGNORE INTO todo_tags (todo_id, tag)
+VALUES (? /* sqlc.arg(todoID) */, ? /* sqlc.arg(tag) */);
+
+-- name: SetTodoRecurrence :exec
+INSERT INTO todo_recurrences (todo_id, frequency, interval_count, weekdays)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(frequency) */,
+  ? /* sqlc.arg(intervalCount) */,
+  ? /* sqlc.arg(weekdays) */
+)
+ON DUPLICATE KEY UPDATE frequency = VALUES(frequency), interval_count = VALUES(interval_count), weekdays = VALUES(weekdays);
+
 -- Shared Todos Queries
 
 -- name: CreateSharedTodo :exec
-INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by)
+INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by, todo_id, permission, status)
 VALUES (
   ? /* sqlc.arg(id) */,
   ? /* sqlc.arg(task) */,
@@ -71,21 +110,24 @@ VALUES (
   ? /* sqlc.arg(userID) */,
   ? /* sqlc.arg(date) */,
   ? /* sqlc.arg(time) */,
-  ? /* sqlc.arg(sharedBy) */
+  ? /* sqlc.arg(sharedBy) */,
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(permission) */,
+  ? /* sqlc.arg(status) */
 );
 
 -- name: GetSharedTodos :many
-SELECT id, task, description, done, important, user_id, date, time, shared_by
+SELECT id, task, description, done, important, user_id, date, time, shared_by, todo_id, permission, status
 FROM shared_todos
 WHERE user_id = ? /* sqlc.arg(userID) */;
 
 -- name: GetSharedByMeTodos :many
-SELECT id, task, description, done, important, user_id, date, time, shared_by
+SELECT id, task, description, done, important, user_id, date, time, shared_by, todo_id, permission, status
 FROM shared_todos
 WHERE shared_by = ? /* sqlc.arg(sharedBy) */;
 
 -- name: ShareTodoWithUser :exec
-INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by)
+INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by, todo_id)
 SELECT 
   ? /* sqlc.arg(newID) */,
   task, 
@@ -95,10 +137,70 @@ SELECT
   (SELECT id FROM users WHERE username = ? /* sqlc.arg(receiverUsername) */), 
   date, 
   time, 
-  ? /* sqlc.arg(senderID) */
+  ? /* sqlc.arg(senderID) */,
+  todos.id
+FROM todos
+WHERE todos.id = ? /* sqlc.arg(todoID) */;
+
+-- name: CreateLinkedSharedTodo :exec
+INSERT INTO shared_todos (id, task, description, done, important, user_id, date, time, shared_by, todo_id, permission, status)
+SELECT
+  ? /* sqlc.arg(id) */,
+  task,
+  description,
+  done,
+  important,
+  ? /* sqlc.arg(userID) */,
+  date,
+  time,
+  ? /* sqlc.arg(sharedBy) */,
+  todos.id,
+  ? /* sqlc.arg(permission) */,
+  'pending'
 FROM todos
 WHERE todos.id = ? /* sqlc.arg(todoID) */;
 
+-- name: UpdateSharedTodoPermission :execrows
+UPDATE shared_todos
+SET permission = ? /* sqlc.arg(permission) */
+WHERE id = ? /* sqlc.arg(id) */ AND shared_by = ? /* sqlc.arg(sharedBy) */;
+
+-- name: DeleteSharedTodo :execrows
+DELETE FROM shared_todos
+WHERE id = ? /* sqlc.arg(id) */ AND shared_by = ? /* sqlc.arg(sharedBy) */;
+
+-- name: AcceptSharedTodo :execrows
+UPDATE shared_todos
+SET status = 'accepted'
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */ AND status = 'pending';
+
+-- name: DeleteReceivedSharedTodo :execrows
+DELETE FROM shared_todos
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */;
+
+-- name: DeleteSharedTodosFromSender :exec
+DELETE FROM shared_todos
+WHERE user_id = ? /* sqlc.arg(userID) */ AND shared_by = ? /* sqlc.arg(sharedBy) */;
+
+-- name: CreateShareBlock :exec
+INSERT IGNORE INTO share_blocks (user_id, blocked_user_id)
+VALUES (? /* sqlc.arg(userId) */, ? /* sqlc.arg(blockedUserId) */);
+
+-- name: DeleteShareBlock :execrows
+DELETE FROM share_blocks
+WHERE user_id = ? /* sqlc.arg(userId) */ AND blocked_user_id = ? /* sqlc.arg(blockedUserId) */;
+
+-- name: GetShareBlocks :many
+SELECT b.blocked_user_id, u.username
+FROM share_blocks b
+JOIN users u ON u.id = b.blocked_user_id
+WHERE b.user_id = ? /* sqlc.arg(userId) */
+ORDER BY u.username;
+
+-- name: IsShareBlocked :one
+SELECT COUNT(*) FROM share_blocks
+WHERE user_id = ? /* sqlc.arg(userId) */ AND blocked_user_id = ? /* sqlc.arg(blockedUserId) */;
+
 -- Teams Queries
 
 -- name: CreateTeam :exec
@@ -168,7 +270,7 @@ VALUES (
 );
 
 -- name: GetTeamTodos :many
-SELECT id, task, description, done, important, team_id, assigned_to, date, time
+SELECT id, task, description, done, important, team_id, assigned_to, date, time, version, completion_mode
 FROM team_todos
 WHERE team_id = ? /* sqlc.arg(teamID) */;
 
@@ -179,13 +281,26 @@ SET
   description = ? /* sqlc.arg(description) */,
   done = ? /* sqlc.arg(done) */,
   important = ? /* sqlc.arg(important) */,
-  assigned_to = ? /* sqlc.arg(assignedTo) */
+  assigned_to = ? /* sqlc.arg(assignedTo) */,
+  version = version + 1
 WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
 
 -- name: DeleteTeamTodo :exec
 DELETE FROM team_todos
 WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
 
+-- name: CompleteTeamTodo :exec
+UPDATE team_todos
+SET done = true,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: GetTeamTodoVersionForUpdate :one
+SELECT version
+FROM team_todos
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */
+FOR UPDATE;
+
 -- name: JoinTeam :exec
 INSERT INTO team_members (team_id, user_id, is_admin)
 SELECT 
@@ -213,22 +328,30 @@ VALUES (
 -- name: UpdateRoutineStatus :exec
 UPDATE routines
 SET isActive = ? /* sqlc.arg(isActive) */,
-    updatedAt = ? /* sqlc.arg(updatedAt) */
+    updatedAt = ? /* sqlc.arg(updatedAt) */,
+    version = version + 1
 WHERE id = ? /* sqlc.arg(id) */;
 
 -- name: UpdateRoutineDay :exec
 UPDATE routines
 SET day = ? /* sqlc.arg(day) */,
-    updatedAt = ? /* sqlc.arg(updatedAt) */
+    updatedAt = ? /* sqlc.arg(updatedAt) */,
+    version = version + 1
 WHERE id = ? /* sqlc.arg(id) */;
 
+-- name: GetRoutineVersionForUpdate :one
+SELECT version
+FROM routines
+WHERE id = ? /* sqlc.arg(id) */
+FOR UPDATE;
+
 -- name: GetRoutinesByTaskID :many
-SELECT id, day, scheduleType, taskId, userId, createdAt, updatedAt, isActive
+SELECT id, day, scheduleType, taskId, userId, createdAt, updatedAt, isActive, version
 FROM routines
 WHERE taskId = ? /* sqlc.arg(taskId) */;
 
 -- name: GetDailyRoutines :many
-SELECT t.id, t.task, t.description, t.done, t.important, t.user_id, t.date, t.time
+SELECT t.id, t.task, t.description, t.done, t.important, t.user_id, t.date, t.time, t.version
 FROM todos t
 JOIN routines r ON t.id = r.taskId
 WHERE r.day = ? /* sqlc.arg(day) */ 
@@ -238,4 +361,509 @@ WHERE r.day = ? /* sqlc.arg(day) */
 
 -- name: DeleteRoutinesByTaskID :exec
 DELETE FROM routines
-WHERE taskId = ? /* sqlc.arg(taskId) */;
\ No newline at end of file
+WHERE taskId = ? /* sqlc.arg(taskId) */;
+-- Calendar Feed Queries
+
+-- name: SetCalendarFeedToken :exec
+INSERT INTO calendar_feeds (user_id, token_hash)
+VALUES (
+  ? /* sqlc.arg(userID) */,
+  ? /* sqlc.arg(tokenHash) */
+)
+ON DUPLICATE KEY UPDATE token_hash = VALUES(token_hash), created_at = CURRENT_TIMESTAMP;
+
+-- name: GetCalendarFeedUserID :one
+SELECT user_id
+FROM calendar_feeds
+WHERE token_hash = ? /* sqlc.arg(tokenHash) */;
+
+-- name: DeleteCalendarFeedToken :execrows
+DELETE FROM calendar_feeds
+WHERE user_id = ? /* sqlc.arg(userID) */;
+
+-- Dependency Queries
+
+-- name: AddTodoDependency :exec
+INSERT INTO todo_dependencies (todo_id, blocked_by_id)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(blockedByID) */
+);
+
+-- name: DeleteTodoDependency :execrows
+DELETE FROM todo_dependencies
+WHERE todo_id = ? /* sqlc.arg(todoID) */ AND blocked_by_id = ? /* sqlc.arg(blockedByID) */;
+
+-- name: GetTodoDependencies :many
+SELECT d.todo_id, d.blocked_by_id
+FROM todo_dependencies d
+JOIN todos t ON t.id = d.todo_id
+WHERE t.user_id = ? /* sqlc.arg(userID) */;
+
+-- name: GetDependencyTodosForUpdate :many
+SELECT id, task, done
+FROM todos
+WHERE user_id = ? /* sqlc.arg(userID) */
+ORDER BY id
+FOR UPDATE;
+
+-- name: GetTodoDependenciesForUpdate :many
+SELECT d.todo_id, d.blocked_by_id
+FROM todo_dependencies d
+JOIN todos t ON t.id = d.todo_id
+WHERE t.user_id = ? /* sqlc.arg(userID) */
+FOR UPDATE;
+
+-- name: AddTeamTodoDependency :exec
+INSERT INTO team_todo_dependencies (team_id, todo_id, blocked_by_id)
+VALUES (
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(blockedByID) */
+);
+
+-- name: DeleteTeamTodoDependency :execrows
+DELETE FROM team_todo_dependencies
+WHERE team_id = ? /* sqlc.arg(teamID) */ AND todo_id = ? /* sqlc.arg(todoID) */ AND blocked_by_id = ? /* sqlc.arg(blockedByID) */;
+
+-- name: GetTeamTodoDependencies :many
+SELECT todo_id, blocked_by_id
+FROM team_todo_dependencies
+WHERE team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: GetDependencyTeamTodosForUpdate :many
+SELECT id, task, done
+FROM team_todos
+WHERE team_id = ? /* sqlc.arg(teamID) */
+ORDER BY id
+FOR UPDATE;
+
+-- name: GetTeamTodoDependenciesForUpdate :many
+SELECT todo_id, blocked_by_id
+FROM team_todo_dependencies
+WHERE team_id = ? /* sqlc.arg(teamID) */
+FOR UPDATE;
+
+-- Team Workflow Queries
+
+-- name: GetTeamForUpdate :one
+SELECT id
+FROM teams
+WHERE id = ? /* sqlc.arg(teamID) */
+FOR UPDATE;
+
+-- name: GetTeamStatuses :many
+SELECT id, team_id, name, position, is_terminal
+FROM team_statuses
+WHERE team_id = ? /* sqlc.arg(teamID) */
+ORDER BY position;
+
+-- name: UpsertTeamStatus :exec
+INSERT INTO team_statuses (id, team_id, name, position, is_terminal)
+VALUES (
+  ? /* sqlc.arg(id) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(name) */,
+  ? /* sqlc.arg(position) */,
+  ? /* sqlc.arg(isTerminal) */
+)
+ON DUPLICATE KEY UPDATE name = VALUES(name), position = VALUES(position), is_terminal = VALUES(is_terminal);
+
+-- name: DeleteTeamStatus :exec
+DELETE FROM team_statuses
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: GetTeamStatusTransitions :many
+SELECT team_id, from_status_id, to_status_id
+FROM team_status_transitions
+WHERE team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: DeleteTeamStatusTransitions :exec
+DELETE FROM team_status_transitions
+WHERE team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: AddTeamStatusTransition :exec
+INSERT INTO team_status_transitions (team_id, from_status_id, to_status_id)
+VALUES (
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(fromStatusID) */,
+  ? /* sqlc.arg(toStatusID) */
+);
+
+-- name: GetTeamTodoPlacements :many
+SELECT todo_id, team_id, status_id, position
+FROM team_todo_placements
+WHERE team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: SetTeamTodoPlacement :exec
+INSERT INTO team_todo_placements (todo_id, team_id, status_id, position)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(statusID) */,
+  ? /* sqlc.arg(position) */
+)
+ON DUPLICATE KEY UPDATE status_id = VALUES(status_id), position = VALUES(position);
+
+-- name: SetTeamTodoDone :exec
+UPDATE team_todos
+SET done = ? /* sqlc.arg(done) */,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: SetTeamTodoDate :exec
+UPDATE team_todos
+SET date = ? /* sqlc.arg(date) */
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: SetTeamTodoTime :exec
+UPDATE team_todos
+SET time = ? /* sqlc.arg(time) */
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- Time Entry Queries
+
+-- name: CreateTimeEntry :exec
+INSERT INTO time_entries (id, user_id, todo_id, team_id, team_todo_id, started_at, ended_at, note)
+VALUES (
+  ? /* sqlc.arg(id) */,
+  ? /* sqlc.arg(userID) */,
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(teamTodoID) */,
+  ? /* sqlc.arg(startedAt) */,
+  ? /* sqlc.arg(endedAt) */,
+  ? /* sqlc.arg(note) */
+);
+
+-- name: GetRunningTimeEntryForUpdate :one
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.user_id = ? /* sqlc.arg(userID) */ AND e.ended_at IS NULL
+FOR UPDATE;
+
+-- name: EndTimeEntry :exec
+UPDATE time_entries
+SET ended_at = ? /* sqlc.arg(endedAt) */
+WHERE id = ? /* sqlc.arg(id) */;
+
+-- name: DeleteTimeEntry :execrows
+DELETE FROM time_entries
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userID) */;
+
+-- name: GetTodoTimeEntries :many
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.todo_id = ? /* sqlc.arg(todoID) */
+ORDER BY e.started_at;
+
+-- name: GetTeamTodoTimeEntries :many
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.team_id = ? /* sqlc.arg(teamID) */ AND e.team_todo_id = ? /* sqlc.arg(teamTodoID) */
+ORDER BY e.started_at;
+
+-- name: GetUserTimeEntries :many
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.user_id = ? /* sqlc.arg(userID) */
+  AND e.started_at >= ? /* sqlc.arg(fromTime) */
+  AND e.started_at < ? /* sqlc.arg(toTime) */
+ORDER BY e.started_at;
+
+-- name: GetTeamTimeEntries :many
+SELECT e.id, e.user_id, u.username, e.todo_id, e.team_id, e.team_todo_id,
+  COALESCE(t.task, tt.task, '') AS task,
+  CAST(e.started_at AS CHAR) AS started_at,
+  CAST(e.ended_at AS CHAR) AS ended_at,
+  e.note
+FROM time_entries e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN todos t ON t.id = e.todo_id
+LEFT JOIN team_todos tt ON tt.id = e.team_todo_id
+WHERE e.team_id = ? /* sqlc.arg(teamID) */
+  AND e.started_at >= ? /* sqlc.arg(fromTime) */
+  AND e.started_at < ? /* sqlc.arg(toTime) */
+ORDER BY e.started_at;
+
+-- Team Todo Estimate Queries
+
+-- name: SetTeamTodoEstimate :exec
+INSERT INTO team_todo_estimates (todo_id, team_id, estimate, unit)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(estimate) */,
+  ? /* sqlc.arg(unit) */
+)
+ON DUPLICATE KEY UPDATE estimate = VALUES(estimate), unit = VALUES(unit);
+
+-- name: DeleteTeamTodoEstimate :execrows
+DELETE FROM team_todo_estimates
+WHERE todo_id = ? /* sqlc.arg(todoID) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: GetTeamTodoEstimates :many
+SELECT todo_id, team_id, estimate, unit
+FROM team_todo_estimates
+WHERE team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: GetTeamWorkloadTodos :many
+SELECT t.id, (t.done OR COALESCE(a.done, FALSE)) AS done,
+  COALESCE(a.user_id, t.assigned_to) AS assigned_to, u.username,
+  CAST(t.date AS CHAR) AS date,
+  CAST(t.time AS CHAR) AS time,
+  e.estimate, e.unit
+FROM team_todos t
+LEFT JOIN team_todo_assignees a ON a.todo_id = t.id
+LEFT JOIN users u ON u.id = COALESCE(a.user_id, t.assigned_to)
+LEFT JOIN team_todo_estimates e ON e.todo_id = t.id
+WHERE t.team_id = ? /* sqlc.arg(teamID) */;
+
+-- Routine Check-in Queries
+
+-- name: GetRoutineByID :one
+SELECT id, day, scheduleType, taskId, userId, isActive, version
+FROM routines
+WHERE id = ? /* sqlc.arg(id) */;
+
+-- name: CheckInRoutine :exec
+INSERT INTO routine_checkins (routine_id, user_id, date)
+VALUES (
+  ? /* sqlc.arg(routineID) */,
+  ? /* sqlc.arg(userID) */,
+  ? /* sqlc.arg(date) */
+)
+ON DUPLICATE KEY UPDATE routine_id = routine_id;
+
+-- name: UncheckRoutine :execrows
+DELETE FROM routine_checkins
+WHERE routine_id = ? /* sqlc.arg(routineID) */ AND date = ? /* sqlc.arg(date) */;
+
+-- name: GetDailyRoutineCheckins :many
+SELECT r.id, r.taskId, c.routine_id IS NOT NULL AS checked_in
+FROM routines r
+LEFT JOIN routine_checkins c ON c.routine_id = r.id AND c.date = ? /* sqlc.arg(date) */
+WHERE r.day = ? /* sqlc.arg(day) */
+  AND r.scheduleType = ? /* sqlc.arg(scheduleType) */
+  AND r.userId = ? /* sqlc.arg(userId) */
+  AND r.isActive = true;
+
+-- name: GetRoutineScheduleDays :many
+SELECT day
+FROM routines
+WHERE taskId = ? /* sqlc.arg(taskId) */
+  AND scheduleType = ? /* sqlc.arg(scheduleType) */
+  AND userId = ? /* sqlc.arg(userId) */
+  AND isActive = true;
+
+-- name: GetRoutineCheckinDates :many
+SELECT CAST(c.date AS CHAR) AS date
+FROM routine_checkins c
+JOIN routines r ON r.id = c.routine_id
+WHERE r.taskId = ? /* sqlc.arg(taskId) */
+  AND r.scheduleType = ? /* sqlc.arg(scheduleType) */
+  AND r.userId = ? /* sqlc.arg(userId) */
+ORDER BY c.date;
+
+-- Routine Slot Queries
+
+-- name: GetRoutineSlots :many
+SELECT id, user_id, name, CAST(start_time AS CHAR) AS start_time
+FROM routine_slots
+WHERE user_id = ? /* sqlc.arg(userId) */
+ORDER BY start_time, name;
+
+-- name: CreateRoutineSlot :exec
+INSERT INTO routine_slots (id, user_id, name, start_time)
+VALUES (
+  ? /* sqlc.arg(id) */,
+  ? /* sqlc.arg(userId) */,
+  ? /* sqlc.arg(name) */,
+  ? /* sqlc.arg(startTime) */
+);
+
+-- name: UpdateRoutineSlot :exec
+UPDATE routine_slots
+SET name = ? /* sqlc.arg(name) */,
+    start_time = ? /* sqlc.arg(startTime) */
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userId) */;
+
+-- name: RenameSlotRoutines :exec
+UPDATE routines
+SET scheduleType = ? /* sqlc.arg(newName) */
+WHERE userId = ? /* sqlc.arg(userId) */ AND scheduleType = ? /* sqlc.arg(oldName) */;
+
+-- name: DeleteRoutineSlot :execrows
+DELETE FROM routine_slots
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userId) */;
+
+-- name: CountSlotRoutines :one
+SELECT COUNT(*)
+FROM routines
+WHERE userId = ? /* sqlc.arg(userId) */ AND scheduleType = ? /* sqlc.arg(scheduleType) */;
+
+-- Weekly Routine Planner Queries
+
+-- name: GetTaskRoutinesForUpdate :many
+SELECT id, day, scheduleType, isActive
+FROM routines
+WHERE taskId = ? /* sqlc.arg(taskId) */ AND userId = ? /* sqlc.arg(userId) */
+ORDER BY day, scheduleType
+FOR UPDATE;
+
+-- name: GetWeekRoutines :many
+SELECT r.id, r.day, r.scheduleType, t.id AS task_id, t.task, t.description, t.important
+FROM routines r
+JOIN todos t ON t.id = r.taskId
+WHERE r.userId = ? /* sqlc.arg(userId) */ AND r.isActive = true
+ORDER BY r.day, t.task;
+
+-- Routine Pause Queries
+
+-- name: CreateRoutinePause :exec
+INSERT INTO routine_pauses (id, user_id, routine_id, start_date, end_date, reason)
+VALUES (
+  ? /* sqlc.arg(id) */,
+  ? /* sqlc.arg(userId) */,
+  ? /* sqlc.arg(routineId) */,
+  ? /* sqlc.arg(startDate) */,
+  ? /* sqlc.arg(endDate) */,
+  ? /* sqlc.arg(reason) */
+);
+
+-- name: GetRoutinePauses :many
+SELECT p.id, p.routine_id, CAST(p.start_date AS CHAR) AS start_date, CAST(p.end_date AS CHAR) AS end_date,
+  p.reason, r.day, r.taskId, r.scheduleType
+FROM routine_pauses p
+LEFT JOIN routines r ON r.id = p.routine_id
+WHERE p.user_id = ? /* sqlc.arg(userId) */
+ORDER BY p.start_date, p.end_date, p.id;
+
+-- name: DeleteRoutinePause :execrows
+DELETE FROM routine_pauses
+WHERE id = ? /* sqlc.arg(id) */ AND user_id = ? /* sqlc.arg(userId) */;
+
+-- Team Todo Assignment Queries
+
+-- name: GetTeamTodoAssignee :one
+SELECT assigned_to
+FROM team_todos
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: SetTeamTodoAssignee :exec
+UPDATE team_todos
+SET assigned_to = ? /* sqlc.arg(assignedTo) */,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: CreateTeamTodoAssignment :exec
+INSERT INTO team_todo_assignments (todo_id, team_id, assigned_to, previous_assignee, assigned_by)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(assignedTo) */,
+  ? /* sqlc.arg(previousAssignee) */,
+  ? /* sqlc.arg(assignedBy) */
+);
+
+-- name: GetTeamTodoAssignments :many
+SELECT a.id, a.assigned_to, ua.username AS assigned_to_username,
+  a.previous_assignee, up.username AS previous_assignee_username,
+  a.assigned_by, ub.username AS assigned_by_username,
+  CAST(a.assigned_at AS CHAR) AS assigned_at
+FROM team_todo_assignments a
+LEFT JOIN users ua ON ua.id = a.assigned_to
+LEFT JOIN users up ON up.id = a.previous_assignee
+LEFT JOIN users ub ON ub.id = a.assigned_by
+WHERE a.todo_id = ? /* sqlc.arg(todoID) */ AND a.team_id = ? /* sqlc.arg(teamID) */
+ORDER BY a.id;
+
+-- name: GetAssignedTeamTodos :many
+SELECT t.id, t.task, t.description, t.done, t.important, t.team_id, t.assigned_to,
+  CAST(t.date AS CHAR) AS date,
+  CAST(t.time AS CHAR) AS time,
+  t.version, tm.name AS team_name
+FROM team_todo_assignees a
+JOIN team_todos t ON t.id = a.todo_id
+JOIN team_members m ON m.team_id = a.team_id AND m.user_id = a.user_id
+JOIN teams tm ON tm.id = a.team_id
+WHERE a.user_id = ? /* sqlc.arg(userID) */
+ORDER BY t.done, t.date IS NULL, t.date, t.time, t.task;
+
+-- Team Todo Assignee Queries
+
+-- name: AddTeamTodoAssignee :exec
+INSERT IGNORE INTO team_todo_assignees (todo_id, team_id, user_id)
+VALUES (
+  ? /* sqlc.arg(todoID) */,
+  ? /* sqlc.arg(teamID) */,
+  ? /* sqlc.arg(userID) */
+);
+
+-- name: DeleteTeamTodoAssignee :exec
+DELETE FROM team_todo_assignees
+WHERE todo_id = ? /* sqlc.arg(todoID) */ AND user_id = ? /* sqlc.arg(userID) */;
+
+-- name: GetTeamTodoAssignees :many
+SELECT a.user_id, u.username, a.done
+FROM team_todo_assignees a
+JOIN users u ON u.id = a.user_id
+WHERE a.todo_id = ? /* sqlc.arg(todoID) */
+ORDER BY u.username;
+
+-- name: GetTeamAssignees :many
+SELECT a.todo_id, a.user_id, u.username, a.done
+FROM team_todo_assignees a
+JOIN users u ON u.id = a.user_id
+WHERE a.team_id = ? /* sqlc.arg(teamID) */
+ORDER BY u.username;
+
+-- name: SetTeamTodoAssigneeDone :execrows
+UPDATE team_todo_assignees
+SET done = ? /* sqlc.arg(done) */
+WHERE todo_id = ? /* sqlc.arg(todoID) */ AND user_id = ? /* sqlc.arg(userID) */;
+
+-- name: SetTeamTodoAssigneesDone :exec
+UPDATE team_todo_assignees
+SET done = ? /* sqlc.arg(done) */
+WHERE todo_id = ? /* sqlc.arg(todoID) */;
+
+-- name: GetTeamTodoCompletion :one
+SELECT done, completion_mode
+FROM team_todos
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
+
+-- name: UpdateTeamTodoAssignment :exec
+UPDATE team_todos
+SET assigned_to = ? /* sqlc.arg(assignedTo) */,
+    completion_mode = ? /* sqlc.arg(completionMode) */,
+    version = version + 1
+WHERE id = ? /* sqlc.arg(id) */ AND team_id = ? /* sqlc.arg(teamID) */;
@@ -2,6 +2,7 @@ CREATE TABLE users (
   id varchar(36) NOT NULL,
   username varchar(255) NOT NULL,
   password varchar(255) NOT NULL,
+  timezone varchar(64) NOT NULL DEFAULT 'UTC', -- IANA name used for "today"
   PRIMARY KEY (id),
   UNIQUE KEY username (username)
 );
@@ -15,6 +16,7 @@ CREATE TABLE todos (
   user_id varchar(36) DEFAULT NULL,
   date date DEFAULT NULL,
   time time DEFAULT NULL,
+  version int NOT NULL DEFAULT 1,
   PRIMARY KEY (id),
   FOREIGN KEY (user_id) REFERENCES users(id)
 );
@@ -29,8 +31,23 @@ CREATE TABLE shared_todos (
   date DATE,
   time TIME,
   shared_by varchar(36),
+  todo_id varchar(36) DEFAULT NULL,
+  permission varchar(10) NOT NULL DEFAULT 'view',
+  status varchar(10) NOT NULL DEFAULT 'pending',
+  KEY todo_recipient (todo_id, user_id),
+  KEY user_status (user_id, status),
   FOREIGN KEY (user_id) REFERENCES users(id),
-  FOREIGN KEY (shared_by) REFERENCES users(id)
+  FOREIGN KEY (shared_by) REFERENCES users(id),
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+);
+
+CREATE TABLE share_blocks (
+  user_id varchar(36) NOT NULL,
+  blocked_user_id varchar(36) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, blocked_user_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (blocked_user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
 CREATE TABLE teams (
@@ -61,21 +78,195 @@ CREATE TABLE team_todos (
   assigned_to varchar(36),
   date DATE DEFAULT NULL,
   time TIME DEFAULT NULL,
+  version int NOT NULL DEFAULT 1,
+  completion_mode varchar(3) NOT NULL DEFAULT 'all', -- 'all' or 'any' assignees finish the todo
   PRIMARY KEY (id),
   FOREIGN KEY (team_id) REFERENCES teams(id),
   FOREIGN KEY (assigned_to) REFERENCES users(id)
 );
 
+CREATE TABLE team_todo_assignees (
+  todo_id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  done BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (todo_id, user_id),
+  KEY team_user (team_id, user_id),
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_todo_assignments (
+  id bigint NOT NULL AUTO_INCREMENT,
+  todo_id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  assigned_to varchar(36) DEFAULT NULL,
+  previous_assignee varchar(36) DEFAULT NULL,
+  assigned_by varchar(36) NOT NULL,
+  assigned_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY todo_id (todo_id),
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
 CREATE TABLE routines (
   id varchar(36) NOT NULL,
   day ENUM('sunday', 'monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday') NOT NULL,
-  scheduleType ENUM('morning', 'noon', 'evening', 'night') NOT NULL,
+  scheduleType varchar(50) NOT NULL, -- name of one of the user's routine_slots
   taskId varchar(36) NOT NULL,
   userId varchar(36) NOT NULL,
   createdAt DATE NOT NULL,
   updatedAt DATE NOT NULL,
   isActive BOOLEAN DEFAULT TRUE,
+  version int NOT NULL DEFAULT 1,
   PRIMARY KEY (id),
   FOREIGN KEY (taskId) REFERENCES todos(id) ON DELETE CASCADE,
   FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE
-);
\ No newline at end of file
+);
+
+CREATE TABLE calendar_feeds (
+  user_id varchar(36) NOT NULL,
+  token_hash char(64) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id),
+  UNIQUE KEY token_hash (token_hash),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE todo_dependencies (
+  todo_id varchar(36) NOT NULL,
+  blocked_by_id varchar(36) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (todo_id, blocked_by_id),
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (blocked_by_id) REFERENCES todos(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_todo_dependencies (
+  team_id varchar(36) NOT NULL,
+  todo_id varchar(36) NOT NULL,
+  blocked_by_id varchar(36) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (todo_id, blocked_by_id),
+  KEY team_id (team_id),
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (blocked_by_id) REFERENCES team_todos(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_statuses (
+  id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  name varchar(64) NOT NULL,
+  position int NOT NULL,
+  is_terminal BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (id),
+  UNIQUE KEY team_status_name (team_id, name),
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_status_transitions (
+  team_id varchar(36) NOT NULL,
+  from_status_id varchar(36) NOT NULL,
+  to_status_id varchar(36) NOT NULL,
+  PRIMARY KEY (from_status_id, to_status_id),
+  KEY team_id (team_id),
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY (from_status_id) REFERENCES team_statuses(id) ON DELETE CASCADE,
+  FOREIGN KEY (to_status_id) REFERENCES team_statuses(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_todo_placements (
+  todo_id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  status_id varchar(36) NOT NULL,
+  position int NOT NULL DEFAULT 0,
+  PRIMARY KEY (todo_id),
+  KEY team_status (team_id, status_id),
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (status_id) REFERENCES team_statuses(id) ON DELETE CASCADE
+);
+
+CREATE TABLE time_entries (
+  id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  todo_id varchar(36) DEFAULT NULL,
+  team_id varchar(36) DEFAULT NULL,
+  team_todo_id varchar(36) DEFAULT NULL,
+  started_at DATETIME NOT NULL,
+  ended_at DATETIME DEFAULT NULL,
+  note varchar(255) NOT NULL DEFAULT '',
+  running_user_id varchar(36) GENERATED ALWAYS AS (IF(ended_at IS NULL, user_id, NULL)) STORED,
+  PRIMARY KEY (id),
+  UNIQUE KEY running_timer (running_user_id),
+  KEY user_started (user_id, started_at),
+  KEY team_started (team_id, started_at),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_todo_id) REFERENCES team_todos(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_todo_estimates (
+  todo_id varchar(36) NOT NULL,
+  team_id varchar(36) NOT NULL,
+  estimate DECIMAL(8,2) NOT NULL,
+  unit ENUM('hours', 'points') NOT NULL,
+  PRIMARY KEY (todo_id),
+  KEY team_id (team_id),
+  FOREIGN KEY (todo_id) REFERENCES team_todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE TABLE routine_checkins (
+  routine_id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  date DATE NOT NULL,
+  checked_in_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (routine_id, date),
+  KEY user_date (user_id, date),
+  FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE routine_pauses (
+  id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  routine_id varchar(36) DEFAULT NULL,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+  reason varchar(255) DEFAULT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY user_dates (user_id, start_date),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE
+);
+
+CREATE TABLE routine_slots (
+  id varchar(36) NOT NULL,
+  user_id varchar(36) NOT NULL,
+  name varchar(50) NOT NULL,
+  start_time TIME NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY user_slot_name (user_id, name),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE todo_tags (
+  todo_id varchar(36) NOT NULL,
+  tag varchar(50) NOT NULL,
+  PRIMARY KEY (todo_id, tag),
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+);
+
+CREATE TABLE todo_recurrences (
+  todo_id varchar(36) NOT NULL,
+  frequency ENUM('daily', 'weekly', 'monthly', 'yearly') NOT NULL,
+  interval_count int NOT NULL DEFAULT 1,
+  weekdays varchar(64) NOT NULL DEFAULT '',
+  PRIMARY KEY (todo_id),
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+);
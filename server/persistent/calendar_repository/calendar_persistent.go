@@ -0,0 +1,43 @@
+package calendar_repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+// Ensure CalendarFeedRepository implements domain.CalendarFeedRepository
+var _ domain.CalendarFeedRepository = (*CalendarFeedRepository)(nil)
+
+type CalendarFeedRepository struct {
+    querier *db.Queries
+}
+
+func (r *CalendarFeedRepository) SetCalendarFeedToken(ctx context.Context, userID, tokenHash string) error {
+    return r.querier.SetCalendarFeedToken(ctx, db.SetCalendarFeedTokenParams{
+        UserID:    userID,
+        TokenHash: tokenHash,
+    })
+}
+
+func (r *CalendarFeedRepository) GetCalendarFeedUserID(ctx context.Context, tokenHash string) (string, error) {
+    userID, err := r.querier.GetCalendarFeedUserID(ctx, tokenHash)
+    if err == sql.ErrNoRows {
+        return "", fmt.Errorf("calendar feed not found")
+    }
+    if err != nil {
+        return "", err
+    }
+    return userID, nil
+}
+
+func (r *CalendarFeedRepository) DeleteCalendarFeedToken(ctx context.Context, userID string) (bool, error) {
+    rows, err := r.querier.DeleteCalendarFeedToken(ctx, userID)
+    if err != nil {
+        return false, err
+    }
+    return rows > 0, nil
+}
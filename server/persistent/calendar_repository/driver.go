@@ -0,0 +1,11 @@
+package calendar_repository
+
+import (
+    "database/sql"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+func NewCalendarFeedRepository(DB *sql.DB) *CalendarFeedRepository {
+    querier := db.New(DB)
+    return &CalendarFeedRepository{querier: querier}
+}
@@ -0,0 +1,143 @@
+package dependencies_repository
+
+import (
+    "context"
+    "database/sql"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+// Ensure DependencyRepository implements domain.DependencyRepository
+var _ domain.DependencyRepository = (*DependencyRepository)(nil)
+
+type DependencyRepository struct {
+    querier *db.Queries
+    db      *sql.DB
+}
+
+func (r *DependencyRepository) AddTodoDependency(ctx context.Context, userID, todoID, blockedByID string, check domain.DependencyCheck) error {
+    return r.runLocked(ctx, check, func(qtx *db.Queries) ([]domain.DependencyNode, []domain.Dependency, error) {
+        todos, err := qtx.GetDependencyTodosForUpdate(ctx, userID)
+        if err != nil {
+            return nil, nil, err
+        }
+        rows, err := qtx.GetTodoDependenciesForUpdate(ctx, userID)
+        if err != nil {
+            return nil, nil, err
+        }
+        nodes := make([]domain.DependencyNode, len(todos))
+        for i, todo := range todos {
+            nodes[i] = domain.DependencyNode{ID: todo.ID, Task: todo.Task, Done: todo.Done}
+        }
+        edges := make([]domain.Dependency, len(rows))
+        for i, row := range rows {
+            edges[i] = domain.Dependency{TodoID: row.TodoID, BlockedByID: row.BlockedByID}
+        }
+        return nodes, edges, nil
+    }, func(qtx *db.Queries) error {
+        return qtx.AddTodoDependency(ctx, db.AddTodoDependencyParams{
+            TodoID:      todoID,
+            BlockedByID: blockedByID,
+        })
+    })
+}
+
+// runLocked loads a dependency graph with its rows locked, checks it and inserts
+// the new link in one transaction. Locking the todos as well as the links keeps
+// two requests on a graph without links from both passing the check.
+func (r *DependencyRepository) runLocked(ctx context.Context, check domain.DependencyCheck, load func(qtx *db.Queries) ([]domain.DependencyNode, []domain.Dependency, error), insert func(qtx *db.Queries) error) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    
+    qtx := r.querier.WithTx(tx)
+    nodes, edges, err := load(qtx)
+    if err != nil {
+        return err
+    }
+    if err := check(nodes, edges); err != nil {
+        return err
+    }
+    if err := insert(qtx); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+func (r *DependencyRepository) RemoveTodoDependency(ctx context.Context, todoID, blockedByID string) (bool, error) {
+    rows, err := r.querier.DeleteTodoDependency(ctx, db.DeleteTodoDependencyParams{
+        TodoID:      todoID,
+        BlockedByID: blockedByID,
+    })
+    if err != nil {
+        return false, err
+    }
+    return rows > 0, nil
+}
+
+func (r *DependencyRepository) GetTodoDependencies(ctx context.Context, userID string) ([]domain.Dependency, error) {
+    rows, err := r.querier.GetTodoDependencies(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    dependencies := make([]domain.Dependency, len(rows))
+    for i, row := range rows {
+        dependencies[i] = domain.Dependency{TodoID: row.TodoID, BlockedByID: row.BlockedByID}
+    }
+    return dependencies, nil
+}
+
+func (r *DependencyRepository) AddTeamTodoDependency(ctx context.Context, teamID, todoID, blockedByID string, check domain.DependencyCheck) error {
+    return r.runLocked(ctx, check, func(qtx *db.Queries) ([]domain.DependencyNode, []domain.Dependency, error) {
+        todos, err := qtx.GetDependencyTeamTodosForUpdate(ctx, teamID)
+        if err != nil {
+            return nil, nil, err
+        }
+        rows, err := qtx.GetTeamTodoDependenciesForUpdate(ctx, teamID)
+        if err != nil {
+            return nil, nil, err
+        }
+        nodes := make([]domain.DependencyNode, len(todos))
+        for i, todo := range todos {
+            nodes[i] = domain.DependencyNode{ID: todo.ID, Task: todo.Task, Done: todo.Done}
+        }
+        edges := make([]domain.Dependency, len(rows))
+        for i, row := range rows {
+            edges[i] = domain.Dependency{TodoID: row.TodoID, BlockedByID: row.BlockedByID}
+        }
+        return nodes, edges, nil
+    }, func(qtx *db.Queries) error {
+        return qtx.AddTeamTodoDependency(ctx, db.AddTeamTodoDependencyParams{
+            TeamID:      teamID,
+            TodoID:      todoID,
+            BlockedByID: blockedByID,
+        })
+    })
+}
+
+func (r *DependencyRepository) RemoveTeamTodoDependency(ctx context.Context, teamID, todoID, blockedByID string) (bool, error) {
+    rows, err := r.querier.DeleteTeamTodoDependency(ctx, db.DeleteTeamTodoDependencyParams{
+        TeamID:      teamID,
+        TodoID:      todoID,
+        BlockedByID: blockedByID,
+    })
+    if err != nil {
+        return false, err
+    }
+    return rows > 0, nil
+}
+
+func (r *DependencyRepository) GetTeamTodoDependencies(ctx context.Context, teamID string) ([]domain.Dependency, error) {
+    rows, err := r.querier.GetTeamTodoDependencies(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    dependencies := make([]domain.Dependency, len(rows))
+    for i, row := range rows {
+        dependencies[i] = domain.Dependency{TodoID: row.TodoID, BlockedByID: row.BlockedByID}
+    }
+    return dependencies, nil
+}
@@ -0,0 +1,11 @@
+package dependencies_repository
+
+import (
+    "database/sql"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+func NewDependencyRepository(DB *sql.DB) *DependencyRepository {
+    querier := db.New(DB)
+    return &DependencyRepository{querier: querier, db: DB}
+}
@@ -2,8 +2,11 @@ package dto
 
 import (
     "database/sql"
+    "encoding/json"
+    "fmt"
     "time"
     "github.com/google/uuid"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
 )
 
@@ -32,6 +35,8 @@ func (req *CreateSharedTodoRequest) ConvertCreateSharedTodoDomainRequestToPersis
         SharedBy:    sql.NullString{String: req.SharedBy, Valid: true},
         Date:        sql.NullTime{Time: time.Now(), Valid: true},
         Time:        sql.NullTime{Time: time.Now(), Valid: true},
+        Permission:  domain.SharePermissionView,
+        Status:      domain.ShareStatusPending,
     }
 }
 
@@ -63,6 +68,9 @@ type CreateTeamTodoRequest struct {
     Time        time.Time `json:"-"`                
     DateString  string    `json:"date"`              
     TimeString  string    `json:"time"`              
+    CreatedBy   string    `json:"-"` // the member creating the todo
+    // Location is the user's timezone for the today/now defaults; nil means the server's
+    Location    *time.Location `json:"-"`
 }
 
 func (req *CreateTeamTodoRequest) ConvertCreateTeamTodoDomainRequestToPersistentRequest() *db.CreateTeamTodoParams {
@@ -73,20 +81,92 @@ func (req *CreateTeamTodoRequest) ConvertCreateTeamTodoDomainRequestToPersistent
         Done:        req.Done,
         Important:   sql.NullBool{Bool: req.Important, Valid: true},
         TeamID:      req.TeamID,
-        AssignedTo:  sql.NullString{String: req.AssignedTo, Valid: true},
-        Date:        sql.NullTime{Time: time.Now(), Valid: true},
-        Time:        sql.NullTime{Time: time.Now(), Valid: true},
+        AssignedTo:  sql.NullString{String: req.AssignedTo, Valid: req.AssignedTo != ""},
+        Date:        sql.NullTime{Time: req.Date, Valid: !req.Date.IsZero()},
+        Time:        sql.NullTime{Time: req.Time, Valid: !req.Time.IsZero()},
+    }
+}
+
+// PatchTeamTodoRequest carries a JSON Merge Patch (RFC 7396) document for a team todo
+type PatchTeamTodoRequest struct {
+    ID       string                     `json:"-"`
+    TeamID   string                     `json:"-"`
+    UserID   string                     `json:"-"` // the member making the change
+    Fields   map[string]json.RawMessage `json:"-"`
+    Version  int                        `json:"-"`
+    Force    bool                       `json:"-"` // complete despite open blockers
+    Location *time.Location             `json:"-"` // timezone the todo is flagged overdue in
+}
+
+func (req *PatchTeamTodoRequest) ConvertPatchTeamTodoRequestToDomainPatch() (domain.TeamTodoPatch, error) {
+    var patch domain.TeamTodoPatch
+    for key, raw := range req.Fields {
+        if key != "assigned_to" {
+            if err := decodeTodoPatchField(key, raw, &patch.TodoPatch); err != nil {
+                return patch, err
+            }
+            continue
+        }
+        if isJSONNull(raw) {
+            patch.ClearAssignedTo = true
+            continue
+        }
+        var assignedTo string
+        if err := json.Unmarshal(raw, &assignedTo); err != nil {
+            return patch, fmt.Errorf("assigned_to must be a string")
+        }
+        patch.AssignedTo = &assignedTo
     }
+    return patch, nil
 }
 
 type UpdateTeamTodoRequest struct {
-    ID          string `json:"id"`
-    Task        string `json:"task"`
-    Description string `json:"description"`
-    Done        bool   `json:"done"`
-    Important   bool   `json:"important"`
-    TeamID      string `json:"team_id"`
-    AssignedTo  string `json:"assigned_to"`
+    ID          string  `json:"id"`
+    Task        string  `json:"task"`
+    Description string  `json:"description"`
+    Done        bool    `json:"done"`
+    Important   bool    `json:"important"`
+    TeamID      string  `json:"team_id"`
+    AssignedTo  string  `json:"assigned_to"`
+    DateString  *string `json:"date"` // omitted keeps the current date, empty clears it
+    TimeString  *string `json:"time"` // omitted keeps the current time, empty clears it
+    Version     int     `json:"-"`    // taken from the If-Match header
+    UpdatedBy   string  `json:"-"`    // the member making the change
+    Force       bool    `json:"-"`    // complete despite open blockers
+}
+
+// ReassignTeamTodoRequest changes the assignee of a team todo. An empty or null
+// assigned_to unassigns it.
+type ReassignTeamTodoRequest struct {
+    ID         string         `json:"-"`
+    TeamID     string         `json:"-"`
+    AssignedTo string         `json:"assigned_to"`
+    AssignedBy string         `json:"-"`
+    Version    int            `json:"-"` // taken from the If-Match header
+    Location   *time.Location `json:"-"` // timezone the todo is flagged overdue in
+}
+
+// SetTeamTodoAssigneesRequest replaces the assignees of a team todo. An empty
+// completion_mode keeps the todo's current mode.
+type SetTeamTodoAssigneesRequest struct {
+    ID             string         `json:"-"`
+    TeamID         string         `json:"-"`
+    Assignees      []string       `json:"assignees"`
+    CompletionMode string         `json:"completion_mode"` // "all" or "any"
+    ChangedBy      string         `json:"-"`
+    Version        int            `json:"-"` // taken from the If-Match header
+    Location       *time.Location `json:"-"` // timezone the todo is flagged overdue in
+}
+
+// SetAssigneeDoneRequest marks an assignee's part of a team todo as done or not done
+type SetAssigneeDoneRequest struct {
+    ID       string         `json:"-"`
+    TeamID   string         `json:"-"`
+    UserID   string         `json:"-"` // the assignee whose part changes
+    CallerID string         `json:"-"`
+    Done     bool           `json:"done"`
+    Force    bool           `json:"-"` // complete the todo despite open blockers
+    Location *time.Location `json:"-"` // timezone the todo is flagged overdue in
 }
 
 func (req *UpdateTeamTodoRequest) ConvertUpdateTeamTodoDomainRequestToPersistentRequest() *db.UpdateTeamTodoParams {
@@ -97,7 +177,7 @@ func (req *UpdateTeamTodoRequest) ConvertUpdateTeamTodoDomainRequestToPersistent
         Done:        req.Done,
         Important:   sql.NullBool{Bool: req.Important, Valid: true},
         TeamID:      req.TeamID,
-        AssignedTo:  sql.NullString{String: req.AssignedTo, Valid: true},
+        AssignedTo:  sql.NullString{String: req.AssignedTo, Valid: req.AssignedTo != ""},
     }
 }
 
@@ -129,6 +209,8 @@ type CreateTodoRequest struct {
     Time        time.Time `json:"-"`        // For internal use
     DateString  string    `json:"date"`     // For JSON parsing
     TimeString  string    `json:"time"`     // For JSON parsing
+    // Location is the user's timezone for the today/now defaults; nil means the server's
+    Location    *time.Location `json:"-"`
 }
 
 func (req *CreateTodoRequest) ConvertCreateTodoDomainRequestToPersistentRequest() *db.CreateTodoParams {
@@ -151,6 +233,8 @@ type UpdateTodoRequest struct {
     Done        bool   `json:"done"`
     Important   bool   `json:"important"`
     UserID      string `json:"user_id"`
+    Version     int    `json:"-"` // taken from the If-Match header
+    Force       bool   `json:"-"` // complete despite open blockers
 }
 
 func (req *UpdateTodoRequest) ConvertUpdateTodoDomainRequestToPersistentRequest() *db.UpdateTodoParams {
@@ -164,6 +248,122 @@ func (req *UpdateTodoRequest) ConvertUpdateTodoDomainRequestToPersistentRequest(
     }
 }
 
+// Batch
+type BatchOperationRequest struct {
+    Op          string `json:"op"`
+    ID          string `json:"id,omitempty"`
+    Task        string `json:"task"`
+    Description string `json:"description"`
+    Done        bool   `json:"done"`
+    Important   bool   `json:"important"`
+    AssignedTo  string `json:"assigned_to,omitempty"`
+    DateString  string `json:"date"`
+    TimeString  string `json:"time"`
+    Version     int    `json:"version,omitempty"`
+}
+
+type BatchRequest struct {
+    Mode       string                  `json:"mode"` // "atomic" (default) or "partial"
+    Operations []BatchOperationRequest `json:"operations"`
+    UserID     string                  `json:"user_id,omitempty"`
+    TeamID     string                  `json:"team_id,omitempty"`
+    Location   *time.Location          `json:"-"` // timezone for date defaults of created todos
+    Force      bool                    `json:"-"` // complete despite open blockers
+}
+
+// PatchTodoRequest carries a JSON Merge Patch (RFC 7396) document for a todo
+type PatchTodoRequest struct {
+    ID      string                     `json:"-"`
+    UserID  string                     `json:"-"`
+    Fields  map[string]json.RawMessage `json:"-"`
+    Version int                        `json:"-"`
+    Force   bool                       `json:"-"` // complete despite open blockers
+}
+
+func (req *PatchTodoRequest) ConvertPatchTodoRequestToDomainPatch() (domain.TodoPatch, error) {
+    var patch domain.TodoPatch
+    for key, raw := range req.Fields {
+        if err := decodeTodoPatchField(key, raw, &patch); err != nil {
+            return patch, err
+        }
+    }
+    return patch, nil
+}
+
+// decodeTodoPatchField applies one merge patch member to the patch. A JSON null
+// clears nullable columns and is rejected for required ones.
+func decodeTodoPatchField(key string, raw json.RawMessage, patch *domain.TodoPatch) error {
+    null := isJSONNull(raw)
+    switch key {
+    case "task":
+        var task string
+        if null || json.Unmarshal(raw, &task) != nil {
+            return fmt.Errorf("task must be a string")
+        }
+        if task == "" {
+            return fmt.Errorf("task cannot be empty")
+        }
+        patch.Task = &task
+    case "description":
+        if null {
+            patch.ClearDescription = true
+            return nil
+        }
+        var description string
+        if err := json.Unmarshal(raw, &description); err != nil {
+            return fmt.Errorf("description must be a string")
+        }
+        patch.Description = &description
+    case "done", "important":
+        var value bool
+        if null || json.Unmarshal(raw, &value) != nil {
+            return fmt.Errorf("%s must be a boolean", key)
+        }
+        if key == "done" {
+            patch.Done = &value
+        } else {
+            patch.Important = &value
+        }
+    case "date":
+        if null {
+            patch.ClearDate = true
+            return nil
+        }
+        var dateString string
+        if err := json.Unmarshal(raw, &dateString); err != nil {
+            return fmt.Errorf("date must be a string")
+        }
+        date, err := time.Parse("2006-01-02", dateString)
+        if err != nil {
+            return fmt.Errorf("invalid date format, use YYYY-MM-DD")
+        }
+        patch.Date = &date
+    case "time":
+        if null {
+            patch.ClearTime = true
+            return nil
+        }
+        var timeString string
+        if err := json.Unmarshal(raw, &timeString); err != nil {
+            return fmt.Errorf("time must be a string")
+        }
+        parsed, err := time.Parse("15:04:05", timeString)
+        if err != nil {
+            return fmt.Errorf("invalid time format, use HH:MM:SS")
+        }
+        hour, min, sec := parsed.Clock()
+        timeValue := time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
+        patch.Time = &timeValue
+    default:
+        return fmt.Errorf("unknown field %q", key)
+    }
+    return nil
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+    return string(raw) == "null"
+}
+
 // Users
 type CreateUserRequest struct {
     Username string `json:"username"`
@@ -193,7 +393,7 @@ func (req *CreateRoutineRequest) ConvertCreateRoutineDomainRequestToPersistentRe
     return &db.CreateRoutineParams{
         ID:           uuid.New().String(),
         Day:          db.RoutinesDay(req.Day),
-        Scheduletype: db.RoutinesScheduletype(req.ScheduleType),
+        Scheduletype: req.ScheduleType,
         Taskid:       req.TaskID,
         Userid:       req.UserID,
         Createdat:    currentTime,
@@ -233,4 +433,153 @@ type CreateOrUpdateRoutinesRequest struct {
     Schedules []string `json:"schedules"`
     Day       string   `json:"day"`
     UserID    string   `json:"userId"`
+    Location  *time.Location `json:"-"` // timezone the default day is taken in
+}
+
+// Import
+type ImportRequest struct {
+    UserID string
+    Format string // "json" or "csv"
+    IDMode string // "preserve" (default) or "remap"
+    Data   []byte
+}
+
+// AddDependencyRequest marks TodoID as blocked by BlockedByID
+type AddDependencyRequest struct {
+    TodoID      string `json:"-"`
+    BlockedByID string `json:"blocked_by"`
+    UserID      string `json:"-"`
+    TeamID      string `json:"-"` // set for links between team todos
+}
+
+// Team workflow
+type TeamStatusRequest struct {
+    Name     string `json:"name"`
+    Terminal bool   `json:"terminal"`
+}
+
+// StatusTransitionRequest allows moves between two statuses, given by name
+type StatusTransitionRequest struct {
+    From string `json:"from"`
+    To   string `json:"to"`
+}
+
+type UpdateWorkflowRequest struct {
+    TeamID      string                    `json:"-"`
+    Statuses    []TeamStatusRequest       `json:"statuses"`
+    Transitions []StatusTransitionRequest `json:"transitions"`
+}
+
+// MoveTeamTodoRequest moves a team todo to a status, optionally at a position in its column
+type MoveTeamTodoRequest struct {
+    ID       string         `json:"-"`
+    TeamID   string         `json:"-"`
+    Status   string         `json:"status"`   // status ID or name
+    Position *int           `json:"position"` // zero-based; the end of the column when omitted
+    Version  int            `json:"-"`
+    Force    bool           `json:"-"` // complete despite open blockers
+    Location *time.Location `json:"-"` // timezone the todo is flagged overdue in
+}
+
+// Time tracking
+type StartTimerRequest struct {
+    TodoID string `json:"todo_id"`
+    TeamID string `json:"team_id"` // set when timing a team todo
+    Note   string `json:"note"`
+    UserID string `json:"-"`
+}
+
+// CreateTimeEntryRequest logs time after the fact. The end is given either as
+// ended_at or as a number of minutes after started_at.
+type CreateTimeEntryRequest struct {
+    TodoID    string     `json:"-"`
+    TeamID    string     `json:"-"` // set for team todos
+    UserID    string     `json:"-"`
+    StartedAt time.Time  `json:"started_at"`
+    EndedAt   *time.Time `json:"ended_at"`
+    Minutes   int        `json:"minutes"`
+    Note      string     `json:"note"`
+}
+
+// TimeReportRequest selects entries started between From and To (exclusive).
+// Entries are the user's own unless TeamID is set.
+type TimeReportRequest struct {
+    UserID  string
+    TeamID  string
+    From    time.Time
+    To      time.Time
+    GroupBy []string // any of "day", "user" and "todo"
+}
+
+// SetEstimateRequest sets the expected effort of a team todo
+type SetEstimateRequest struct {
+    TodoID string  `json:"-"`
+    TeamID string  `json:"-"`
+    Value  float64 `json:"value"`
+    Unit   string  `json:"unit"` // "hours" (default) or "points"
+}
+
+// RoutineCheckinRequest marks a routine done or not done on Date ("2006-01-02"),
+// defaulting to today
+type RoutineCheckinRequest struct {
+    RoutineID string         `json:"-"`
+    UserID    string         `json:"-"`
+    Date      string         `json:"date"`
+    Location  *time.Location `json:"-"` // timezone "today" is taken in
+}
+
+// RoutineSlotRequest creates or updates a named time of day for routines
+type RoutineSlotRequest struct {
+    ID        string `json:"-"`
+    UserID    string `json:"-"`
+    Name      string `json:"name"`
+    StartTime string `json:"start_time"` // "15:04"
+}
+
+type SetTimezoneRequest struct {
+    UserID   string `json:"-"`
+    Timezone string `json:"timezone"`
+}
+
+// SetWeeklyScheduleRequest replaces every day and slot a task repeats in.
+// Schedule maps a weekday to slot names; days that are left out have no routine.
+type SetWeeklyScheduleRequest struct {
+    TaskID   string              `json:"-"`
+    UserID   string              `json:"-"`
+    Schedule map[string][]string `json:"schedule"`
+}
+
+// CreateRoutinePauseRequest pauses one routine, or every routine of the user
+// when RoutineID is empty, from StartDate through EndDate
+type CreateRoutinePauseRequest struct {
+    UserID    string `json:"-"`
+    RoutineID string `json:"routine_id"`
+    StartDate string `json:"start_date"`
+    EndDate   string `json:"end_date"`
+    Reason    string `json:"reason"`
+}
+
+// AgendaRequest asks for the user's agenda of one day. An empty Date means
+// today in Location.
+type AgendaRequest struct {
+    UserID   string
+    Date     string
+    Location *time.Location
+}
+
+// QuickAddRequest creates a todo from free-form text
+type QuickAddRequest struct {
+    Text     string         `json:"text"`
+    DryRun   bool           `json:"dry_run"`
+    UserID   string         `json:"-"`
+    Location *time.Location `json:"-"`
+}
+
+// ShareTodoRequest shares a todo with several users and/or every other member of a team
+type ShareTodoRequest struct {
+    TodoID     string   `json:"taskId"`
+    SharedBy   string   `json:"-"`
+    Usernames  []string `json:"usernames"`
+    TeamID     string   `json:"team_id"`
+    Permission string   `json:"permission"`
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package dto
 
 import (
     "time"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
 )
 
@@ -16,13 +17,41 @@ type SharedTodoResponse struct {
     Date        time.Time `json:"date"`
     Time        time.Time `json:"time"`
     SharedBy    string    `json:"shared_by"`
+    TodoID      string    `json:"todo_id,omitempty"`
+    Version     int       `json:"version,omitempty"`
+    Permission  string    `json:"permission"`
+    Status      string    `json:"status"`
 }
 
+// SharedTodosResponse lists accepted shares under Received and the recipient's
+// inbox of shares still waiting for an answer under Pending
 type SharedTodosResponse struct {
     Received []SharedTodoResponse `json:"received"`
+    Pending  []SharedTodoResponse `json:"pending"`
     Shared   []SharedTodoResponse `json:"shared"`
 }
 
+type ShareBlockResponse struct {
+    UserID   string `json:"user_id"`
+    Username string `json:"username"`
+}
+
+type ShareBlocksResponse struct {
+    Blocked []ShareBlockResponse `json:"blocked"`
+}
+
+// ShareResultResponse is the outcome of sharing with one recipient. Team members
+// are identified by user ID only.
+type ShareResultResponse struct {
+    Username string `json:"username,omitempty"`
+    UserID   string `json:"user_id,omitempty"`
+    Result   string `json:"result"`
+}
+
+type ShareTodoResponse struct {
+    Results []ShareResultResponse `json:"results"`
+}
+
 // Team Members Responses
 type TeamMemberResponse struct {
     TeamID  string `json:"team_id"`
@@ -36,21 +65,67 @@ type TeamMembersResponse struct {
 
 // Team Todos Responses
 type TeamTodoResponse struct {
-    ID          string    `json:"id"`
-    Task        string    `json:"task"`
-    Description string    `json:"description"`
-    Done        bool      `json:"done"`
-    Important   bool      `json:"important"`
-    TeamID      string    `json:"team_id"`
-    AssignedTo  string    `json:"assigned_to"`
-    Date        time.Time `json:"date"`
-    Time        time.Time `json:"time"`
+    ID             string                     `json:"id"`
+    Task           string                     `json:"task"`
+    Description    string                     `json:"description"`
+    Done           bool                       `json:"done"`
+    Important      bool                       `json:"important"`
+    TeamID         string                     `json:"team_id"`
+    AssignedTo     string                     `json:"assigned_to"`
+    Date           time.Time                  `json:"date"`
+    Time           time.Time                  `json:"time"`
+    Version        int                        `json:"version"`
+    CompletionMode string                     `json:"completion_mode"`
+    Assignees      []TeamTodoAssigneeResponse `json:"assignees"`
+    Due            time.Time                  `json:"due"` // zero for undated todos
+    Overdue        bool                       `json:"overdue"`
+}
+
+// TeamTodoAssigneeResponse is one assignee of a team todo and whether they finished their part
+type TeamTodoAssigneeResponse struct {
+    UserID   string `json:"user_id"`
+    Username string `json:"username"`
+    Done     bool   `json:"done"`
 }
 
 type TeamTodosResponse struct {
     Todos []TeamTodoResponse `json:"todos"`
 }
 
+// TeamDeadlinesResponse lists a team's open todos that are overdue and those
+// falling due between From and Until
+type TeamDeadlinesResponse struct {
+    From     time.Time          `json:"from"`
+    Until    time.Time          `json:"until"`
+    Overdue  []TeamTodoResponse `json:"overdue"`
+    Upcoming []TeamTodoResponse `json:"upcoming"`
+}
+
+// AssignedTeamTodoResponse is a team todo assigned to the caller, with its team's name
+type AssignedTeamTodoResponse struct {
+    TeamTodoResponse
+    TeamName string `json:"team_name"`
+}
+
+type AssignedTeamTodosResponse struct {
+    Todos []AssignedTeamTodoResponse `json:"todos"`
+}
+
+// TeamTodoAssignmentResponse is one entry of a team todo's assignment history
+type TeamTodoAssignmentResponse struct {
+    AssignedTo               string    `json:"assigned_to,omitempty"`
+    AssignedToUsername       string    `json:"assigned_to_username,omitempty"`
+    PreviousAssignee         string    `json:"previous_assignee,omitempty"`
+    PreviousAssigneeUsername string    `json:"previous_assignee_username,omitempty"`
+    AssignedBy               string    `json:"assigned_by"`
+    AssignedByUsername       string    `json:"assigned_by_username,omitempty"`
+    AssignedAt               time.Time `json:"assigned_at"`
+}
+
+type TeamTodoAssignmentsResponse struct {
+    Assignments []TeamTodoAssignmentResponse `json:"assignments"`
+}
+
 // Teams Responses
 type TeamResponse struct {
     ID       string `json:"id"`
@@ -73,6 +148,7 @@ type TodoResponse struct {
     UserID      string    `json:"user_id"`
     Date        time.Time `json:"date"`
     Time        time.Time `json:"time"`
+    Version     int       `json:"version"`
 }
 
 type TodosResponse struct {
@@ -84,12 +160,14 @@ type UserResponse struct {
     ID       string `json:"id"`
     Username string `json:"username"`
     Password string `json:"password,omitempty"`
+    Timezone string `json:"timezone,omitempty"`
 }
 
 // Success Responses
 type SuccessResponse struct {
     Success bool   `json:"success"`
     Message string `json:"message,omitempty"`
+    Version int    `json:"version,omitempty"` // the new version after a write to a versioned record
 }
 
 // Routine Responses
@@ -102,6 +180,7 @@ type RoutineResponse struct {
     CreatedAt    time.Time `json:"created_at"`
     UpdatedAt    time.Time `json:"updated_at"`
     IsActive     bool      `json:"is_active"`
+    Version      int       `json:"version"`
 }
 
 type RoutinesResponse struct {
@@ -112,6 +191,21 @@ type CreateResponse struct {
     ID string `json:"id"`
 }
 
+// Batch Responses
+type BatchResultResponse struct {
+    Index  int    `json:"index"`
+    Op     string `json:"op"`
+    ID     string `json:"id,omitempty"`
+    Status string `json:"status"`
+    Error  string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+    Mode      string                `json:"mode"`
+    Committed bool                  `json:"committed"`
+    Results   []BatchResultResponse `json:"results"`
+}
+
 // Converters
 func NewSharedTodoResponse(todo *db.SharedTodo) *SharedTodoResponse {
     return &SharedTodoResponse{
@@ -124,6 +218,9 @@ func NewSharedTodoResponse(todo *db.SharedTodo) *SharedTodoResponse {
         Date:        todo.Date.Time,
         Time:        todo.Time.Time,
         SharedBy:    todo.SharedBy.String,
+        TodoID:      todo.TodoID.String,
+        Permission:  todo.Permission,
+        Status:      todo.Status,
     }
 }
 
@@ -162,6 +259,33 @@ func NewTeamTodoResponse(todo *db.TeamTodo) *TeamTodoResponse {
         AssignedTo:  todo.AssignedTo.String,
         Date:        todo.Date.Time,
         Time:        todo.Time.Time,
+        Version:     int(todo.Version),
+    }
+}
+
+// NewTeamTodoResponseFromDomain converts a team todo with its assignees, flagging
+// it overdue by now, a wall clock time labelled UTC like the stored dates
+func NewTeamTodoResponseFromDomain(todo domain.TeamTodo, now time.Time) TeamTodoResponse {
+    assignees := make([]TeamTodoAssigneeResponse, len(todo.Assignees))
+    for i, assignee := range todo.Assignees {
+        assignees[i] = TeamTodoAssigneeResponse{UserID: assignee.UserID, Username: assignee.Username, Done: assignee.Done}
+    }
+    due, _ := todo.Due()
+    return TeamTodoResponse{
+        ID:             todo.ID,
+        Task:           todo.Task,
+        Description:    todo.Description,
+        Done:           todo.Done,
+        Important:      todo.Important,
+        TeamID:         todo.TeamID,
+        AssignedTo:     todo.AssignedTo,
+        Date:           todo.Date,
+        Time:           todo.Time,
+        Version:        todo.Version,
+        CompletionMode: todo.CompletionMode,
+        Assignees:      assignees,
+        Due:            due,
+        Overdue:        todo.Overdue(now),
     }
 }
 
@@ -200,6 +324,7 @@ func NewTodoResponse(todo *db.Todo) *TodoResponse {
         UserID:      todo.UserID.String,
         Date:        todo.Date.Time,
         Time:        todo.Time.Time,
+        Version:     int(todo.Version),
     }
 }
 
@@ -216,6 +341,7 @@ func NewUserResponse(user *db.User) *UserResponse {
         ID:       user.ID,
         Username: user.Username,
         Password: user.Password,
+        Timezone: user.Timezone,
     }
 }
 
@@ -224,19 +350,401 @@ func NewRoutineResponse(routine *db.Routine) *RoutineResponse {
     return &RoutineResponse{
         ID:           routine.ID,
         Day:          string(routine.Day),
-        ScheduleType: string(routine.Scheduletype),
+        ScheduleType: routine.Scheduletype,
         TaskID:       routine.Taskid,
         UserID:       routine.Userid,
         CreatedAt:    routine.Createdat,
         UpdatedAt:    routine.Updatedat,
         IsActive:     routine.Isactive.Bool,
+        Version:      int(routine.Version),
     }
 }
 
+func NewBatchResponse(mode string, committed bool, results []domain.BatchResult) *BatchResponse {
+    response := BatchResponse{Mode: mode, Committed: committed}
+    for _, result := range results {
+        response.Results = append(response.Results, BatchResultResponse{
+            Index:  result.Index,
+            Op:     result.Op,
+            ID:     result.ID,
+            Status: result.Status,
+            Error:  result.Error,
+        })
+    }
+    return &response
+}
+
 func NewRoutinesResponse(routines []db.Routine) *RoutinesResponse {
     var response RoutinesResponse
     for _, routine := range routines {
         response.Routines = append(response.Routines, *NewRoutineResponse(&routine))
     }
     return &response
-}
\ No newline at end of file
+}
+
+// Export / Import Documents
+// The same document is produced by an export and accepted by an import.
+type ExportTodo struct {
+    ID          string `json:"id"`
+    Task        string `json:"task"`
+    Description string `json:"description"`
+    Done        bool   `json:"done"`
+    Important   bool   `json:"important"`
+    Date        string `json:"date"`
+    Time        string `json:"time"`
+}
+
+type ExportRoutine struct {
+    ID           string `json:"id"`
+    TaskID       string `json:"task_id"`
+    Day          string `json:"day"`
+    ScheduleType string `json:"schedule_type"`
+    IsActive     bool   `json:"is_active"`
+}
+
+type ExportSharedTodo struct {
+    ID          string `json:"id"`
+    Task        string `json:"task"`
+    Description string `json:"description"`
+    Done        bool   `json:"done"`
+    Important   bool   `json:"important"`
+    Date        string `json:"date"`
+    Time        string `json:"time"`
+    SharedBy    string `json:"shared_by"`
+    SharedWith  string `json:"shared_with"`
+}
+
+type ExportTeamTodo struct {
+    ID          string `json:"id"`
+    TeamID      string `json:"team_id"`
+    Task        string `json:"task"`
+    Description string `json:"description"`
+    Done        bool   `json:"done"`
+    Important   bool   `json:"important"`
+    AssignedTo  string `json:"assigned_to"`
+    Date        string `json:"date"`
+    Time        string `json:"time"`
+}
+
+type ExportDocument struct {
+    FormatVersion int                `json:"format_version"`
+    ExportedAt    time.Time          `json:"exported_at"`
+    UserID        string             `json:"user_id"`
+    Todos         []ExportTodo       `json:"todos"`
+    Routines      []ExportRoutine    `json:"routines"`
+    SharedTodos   []ExportSharedTodo `json:"shared_todos"`
+    TeamTodos     []ExportTeamTodo   `json:"team_todos"`
+}
+
+type ImportErrorResponse struct {
+    Type  string `json:"type"`
+    Index int    `json:"index"`
+    ID    string `json:"id,omitempty"`
+    Error string `json:"error"`
+}
+
+type ImportResponse struct {
+    IDMode  string                `json:"id_mode"`
+    Created int                   `json:"created"`
+    Updated int                   `json:"updated"`
+    Skipped int                   `json:"skipped"`
+    IDMap   map[string]string     `json:"id_map,omitempty"` // imported ID -> stored ID, when they differ
+    Errors  []ImportErrorResponse `json:"errors,omitempty"`
+}
+
+type CalendarFeedResponse struct {
+    Token string `json:"token"`
+    URL   string `json:"url"`
+}
+
+type QuickAddRecurrenceResponse struct {
+    Frequency string   `json:"frequency"`
+    Interval  int      `json:"interval"`
+    Weekdays  []string `json:"weekdays,omitempty"`
+}
+
+// QuickAddResponse reports what was parsed from quick-add text and what was created from it
+type QuickAddResponse struct {
+    ID         string                      `json:"id,omitempty"` // empty on a dry run
+    Task       string                      `json:"task"`
+    Date       string                      `json:"date,omitempty"`
+    Time       string                      `json:"time,omitempty"`
+    Priority   string                      `json:"priority,omitempty"`
+    Tags       []string                    `json:"tags,omitempty"`
+    ShareWith  []string                    `json:"share_with,omitempty"`
+    Recurrence *QuickAddRecurrenceResponse `json:"recurrence,omitempty"`
+    RoutineIDs []string                    `json:"routine_ids,omitempty"`
+    Warnings   []string                    `json:"warnings,omitempty"`
+}
+
+// DependencyResponse is one todo on either end of a blocked-by link
+type DependencyResponse struct {
+    ID   string `json:"id"`
+    Task string `json:"task"`
+    Done bool   `json:"done"`
+}
+
+type DependenciesResponse struct {
+    TodoID    string               `json:"todo_id"`
+    Blocked   bool                 `json:"blocked"` // true while any blocker is open
+    BlockedBy []DependencyResponse `json:"blocked_by"`
+    Blocks    []DependencyResponse `json:"blocks"`
+}
+
+// BlockState summarises a todo's blockers for list responses
+type BlockState struct {
+    BlockedBy []string
+    Blocked   bool
+}
+
+type TeamStatusResponse struct {
+    ID       string `json:"id"`
+    Name     string `json:"name"`
+    Position int    `json:"position"`
+    Terminal bool   `json:"terminal"`
+}
+
+type StatusTransitionResponse struct {
+    From string `json:"from"`
+    To   string `json:"to"`
+}
+
+type WorkflowResponse struct {
+    Statuses    []TeamStatusResponse       `json:"statuses"`
+    Transitions []StatusTransitionResponse `json:"transitions"` // empty when every move is allowed
+}
+
+type BoardColumnResponse struct {
+    Status TeamStatusResponse
+    Todos  []TeamTodoResponse
+}
+
+type BoardResponse struct {
+    TeamID  string
+    Columns []BoardColumnResponse
+}
+
+type MoveTeamTodoResponse struct {
+    Todo     TeamTodoResponse
+    Status   TeamStatusResponse
+    Position int
+}
+
+type TimeEntryResponse struct {
+    ID        string     `json:"id"`
+    UserID    string     `json:"user_id"`
+    Username  string     `json:"username"`
+    TodoID    string     `json:"todo_id"`
+    TeamID    string     `json:"team_id,omitempty"`
+    Task      string     `json:"task"`
+    StartedAt time.Time  `json:"started_at"`
+    EndedAt   *time.Time `json:"ended_at"` // null while the timer runs
+    Seconds   int64      `json:"seconds"`
+    Running   bool       `json:"running"`
+    Note      string     `json:"note"`
+}
+
+type TimeEntriesResponse struct {
+    TodoID       string              `json:"todo_id,omitempty"`
+    TeamID       string              `json:"team_id,omitempty"`
+    TotalSeconds int64               `json:"total_seconds"`
+    Entries      []TimeEntryResponse `json:"entries"`
+}
+
+// TimeReportRow totals the time for one combination of the grouped fields;
+// fields that are not grouped on are left empty
+type TimeReportRow struct {
+    Day      string `json:"day,omitempty"`
+    UserID   string `json:"user_id,omitempty"`
+    Username string `json:"username,omitempty"`
+    TodoID   string `json:"todo_id,omitempty"`
+    TeamID   string `json:"team_id,omitempty"`
+    Task     string `json:"task,omitempty"`
+    Seconds  int64  `json:"seconds"`
+}
+
+type TimeReportResponse struct {
+    From         string          `json:"from"`
+    To           string          `json:"to"`
+    GroupBy      []string        `json:"group_by"`
+    TotalSeconds int64           `json:"total_seconds"`
+    Rows         []TimeReportRow `json:"rows"`
+}
+
+type EstimateResponse struct {
+    TodoID string  `json:"todo_id"`
+    Value  float64 `json:"value"`
+    Unit   string  `json:"unit"`
+}
+
+type EstimateTotals struct {
+    Hours  float64 `json:"hours"`
+    Points float64 `json:"points"`
+}
+
+// MemberWorkloadResponse counts the team todos assigned to one user. Users who
+// have left the team but still hold assignments are listed with IsMember false.
+type MemberWorkloadResponse struct {
+    UserID            string         `json:"user_id"`
+    Username          string         `json:"username"`
+    IsMember          bool           `json:"is_member"`
+    IsAdmin           bool           `json:"is_admin"`
+    Open              int            `json:"open"`
+    Overdue           int            `json:"overdue"`
+    Completed         int            `json:"completed"`
+    Unestimated       int            `json:"unestimated"` // open todos without an estimate
+    OpenEstimate      EstimateTotals `json:"open_estimate"`
+    CompletedEstimate EstimateTotals `json:"completed_estimate"`
+}
+
+type WorkloadResponse struct {
+    TeamID     string                   `json:"team_id"`
+    Members    []MemberWorkloadResponse `json:"members"`
+    Unassigned MemberWorkloadResponse   `json:"unassigned"`
+}
+
+// RoutineTodoResponse is a todo scheduled by a routine; Done reports whether the
+// routine was checked in on the listed date rather than the todo's own flag
+type RoutineTodoResponse struct {
+    TodoResponse
+    RoutineID string `json:"routine_id"`
+    CheckedIn bool   `json:"checked_in"`
+}
+
+type RoutineTodosResponse struct {
+    Date  string                `json:"date"`
+    Todos []RoutineTodoResponse `json:"todos"`
+}
+
+type RoutineSlotCheckinsResponse struct {
+    Slot  RoutineSlotResponse   `json:"slot"`
+    Todos []RoutineTodoResponse `json:"todos"`
+}
+
+type RoutineCheckinResponse struct {
+    RoutineID string `json:"routine_id"`
+    Date      string `json:"date"`
+    CheckedIn bool   `json:"checked_in"`
+}
+
+// RoutineStreakResponse counts consecutive scheduled days that were checked in.
+// Days the routine is not scheduled on neither extend nor break a streak.
+type RoutineStreakResponse struct {
+    RoutineID     string   `json:"routine_id"`
+    TaskID        string   `json:"task_id"`
+    ScheduleType  string   `json:"schedule_type"`
+    ScheduledDays []string `json:"scheduled_days"`
+    CurrentStreak int      `json:"current_streak"`
+    LongestStreak int      `json:"longest_streak"`
+    TotalCheckins int      `json:"total_checkins"`
+    LastCheckin   string   `json:"last_checkin,omitempty"`
+    History       []string `json:"history"`
+}
+
+type RoutineSlotResponse struct {
+    ID        string `json:"id"`
+    Name      string `json:"name"`
+    StartTime string `json:"start_time"`
+}
+
+type RoutineSlotsResponse struct {
+    Slots []RoutineSlotResponse `json:"slots"`
+}
+
+type RoutineSlotTodosResponse struct {
+    Slot  RoutineSlotResponse `json:"slot"`
+    Todos []TodoResponse      `json:"todos"`
+}
+
+// RoutineDayResponse lists a day's routines grouped by slot, earliest slot first
+type RoutineDayResponse struct {
+    Day   string                     `json:"day"`
+    Slots []RoutineSlotTodosResponse `json:"slots"`
+}
+
+type TimezoneResponse struct {
+    Timezone string `json:"timezone"`
+}
+
+type WeeklyScheduleResponse struct {
+    TaskID      string              `json:"task_id"`
+    Schedule    map[string][]string `json:"schedule"`
+    Created     int                 `json:"created"`
+    Reactivated int                 `json:"reactivated"`
+    Deactivated int                 `json:"deactivated"`
+}
+
+type RoutineWeekEntryResponse struct {
+    RoutineID   string `json:"routine_id"`
+    TaskID      string `json:"task_id"`
+    Task        string `json:"task"`
+    Description string `json:"description"`
+    Important   bool   `json:"important"`
+}
+
+type RoutineWeekSlotResponse struct {
+    Slot     RoutineSlotResponse        `json:"slot"`
+    Routines []RoutineWeekEntryResponse `json:"routines"`
+}
+
+type RoutineWeekDayResponse struct {
+    Day   string                    `json:"day"`
+    Slots []RoutineWeekSlotResponse `json:"slots"`
+}
+
+// RoutineWeekResponse lists every weekday from Sunday with all of the user's
+// slots in start time order, so empty cells of the week are included
+type RoutineWeekResponse struct {
+    Days []RoutineWeekDayResponse `json:"days"`
+}
+
+type RoutinePauseResponse struct {
+    ID        string `json:"id"`
+    RoutineID string `json:"routine_id,omitempty"`
+    StartDate string `json:"start_date"`
+    EndDate   string `json:"end_date"`
+    Reason    string `json:"reason,omitempty"`
+    Active    bool   `json:"active"`
+}
+
+type RoutinePausesResponse struct {
+    Pauses []RoutinePauseResponse `json:"pauses"`
+}
+
+// AgendaItemResponse is a todo on the agenda. Kind tells whether it is a
+// personal, shared or team todo.
+type AgendaItemResponse struct {
+    ID          string `json:"id"`
+    Kind        string `json:"kind"`
+    Task        string `json:"task"`
+    Description string `json:"description"`
+    Done        bool   `json:"done"`
+    Important   bool   `json:"important"`
+    Date        string `json:"date,omitempty"`
+    Time        string `json:"time,omitempty"`
+    Overdue     bool   `json:"overdue"`
+    SharedBy    string `json:"shared_by,omitempty"`
+    TeamID      string `json:"team_id,omitempty"`
+    TeamName    string `json:"team_name,omitempty"`
+    Version     int    `json:"version,omitempty"`
+}
+
+type AgendaRoutineResponse struct {
+    RoutineID   string `json:"routine_id"`
+    TaskID      string `json:"task_id"`
+    Task        string `json:"task"`
+    Description string `json:"description"`
+    Important   bool   `json:"important"`
+    CheckedIn   bool   `json:"checked_in"`
+}
+
+type AgendaSlotResponse struct {
+    Slot     RoutineSlotResponse     `json:"slot"`
+    Routines []AgendaRoutineResponse `json:"routines"`
+}
+
+type AgendaResponse struct {
+    Date     string               `json:"date"`
+    Items    []AgendaItemResponse `json:"items"`
+    Routines []AgendaSlotResponse `json:"routines"`
+}
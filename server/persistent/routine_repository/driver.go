@@ -7,5 +7,8 @@ import (
 
 func NewRoutineRepository(DB *sql.DB) *RoutineRepository {
     querier := db.New(DB)
-    return &RoutineRepository{querier: querier}
+    return &RoutineRepository{
+        querier: querier,
+        db:      DB,
+    }
 }
\ No newline at end of file
@@ -2,6 +2,8 @@ package routine_repository
 
 import (
     "context"
+    "database/sql"
+    "fmt"
     "time"
     "strings"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
@@ -14,6 +16,7 @@ var _ domain.RoutineRepository = (*RoutineRepository)(nil)
 
 type RoutineRepository struct {
     querier *db.Queries
+    db      *sql.DB
 }
 
 // Implement domain.RoutineRepository interface methods
@@ -35,24 +38,55 @@ func (r *RoutineRepository) CreateRoutine(ctx context.Context, day, scheduleType
     return params.ID, nil
 }
 
-func (r *RoutineRepository) UpdateRoutineStatus(ctx context.Context, id string, isActive bool) error {
+func (r *RoutineRepository) UpdateRoutineStatus(ctx context.Context, id string, isActive bool, expectedVersion int) error {
     req := &dto.UpdateRoutineStatusRequest{
         ID:       id,
         IsActive: isActive,
     }
     
     params := req.ConvertUpdateRoutineStatusDomainRequestToPersistentRequest()
-    return r.querier.UpdateRoutineStatus(ctx, *params)
+    return r.runVersioned(ctx, id, expectedVersion, func(qtx *db.Queries) error {
+        return qtx.UpdateRoutineStatus(ctx, *params)
+    })
 }
 
-func (r *RoutineRepository) UpdateRoutineDay(ctx context.Context, id, day string) error {
+func (r *RoutineRepository) UpdateRoutineDay(ctx context.Context, id, day string, expectedVersion int) error {
     req := &dto.UpdateRoutineDayRequest{
         ID:  id,
         Day: day,
     }
     
     params := req.ConvertUpdateRoutineDayDomainRequestToPersistentRequest()
-    return r.querier.UpdateRoutineDay(ctx, *params)
+    return r.runVersioned(ctx, id, expectedVersion, func(qtx *db.Queries) error {
+        return qtx.UpdateRoutineDay(ctx, *params)
+    })
+}
+
+// runVersioned locks the routine row in a transaction, compares its version with
+// expectedVersion and then runs fn in the same transaction. 0 skips the comparison.
+func (r *RoutineRepository) runVersioned(ctx context.Context, id string, expectedVersion int, fn func(qtx *db.Queries) error) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    
+    qtx := r.querier.WithTx(tx)
+    version, err := qtx.GetRoutineVersionForUpdate(ctx, id)
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("routine not found")
+    }
+    if err != nil {
+        return err
+    }
+    if expectedVersion != 0 && int(version) != expectedVersion {
+        return fmt.Errorf("version mismatch: current version is %d", version)
+    }
+    
+    if err := fn(qtx); err != nil {
+        return err
+    }
+    return tx.Commit()
 }
 
 func (r *RoutineRepository) GetRoutinesByTaskID(ctx context.Context, taskID string) ([]domain.Routine, error) {
@@ -66,12 +100,13 @@ func (r *RoutineRepository) GetRoutinesByTaskID(ctx context.Context, taskID stri
         result = append(result, domain.Routine{
             ID:           routine.ID,
             Day:          string(routine.Day),
-            ScheduleType: string(routine.Scheduletype),
+            ScheduleType: routine.Scheduletype,
             TaskID:       routine.Taskid,
             UserID:       routine.Userid,
             CreatedAt:    routine.Createdat,
             UpdatedAt:    routine.Updatedat,
             IsActive:     routine.Isactive.Bool,
+            Version:      int(routine.Version),
         })
     }
     
@@ -81,7 +116,7 @@ func (r *RoutineRepository) GetRoutinesByTaskID(ctx context.Context, taskID stri
 func (r *RoutineRepository) GetDailyRoutines(ctx context.Context, day, scheduleType, userID string) ([]domain.Todo, error) {
     todos, err := r.querier.GetDailyRoutines(ctx, db.GetDailyRoutinesParams{
         Day:          db.RoutinesDay(day),
-        Scheduletype: db.RoutinesScheduletype(scheduleType),
+        Scheduletype: scheduleType,
         Userid:       userID,
     })
     if err != nil {
@@ -99,6 +134,7 @@ func (r *RoutineRepository) GetDailyRoutines(ctx context.Context, day, scheduleT
             UserID:      todo.UserID.String,
             Date:        todo.Date.Time,
             Time:        todo.Time.Time,
+            Version:     int(todo.Version),
         })
     }
     
@@ -156,20 +192,22 @@ func (r *RoutineRepository) CreateOrUpdateRoutines(ctx context.Context, taskID s
             // Schedule type is requested, check if day needs updating
             if routine.Day != day {
                 // Update the day
-                err := r.UpdateRoutineDay(ctx, routine.ID, day)
+                err := r.UpdateRoutineDay(ctx, routine.ID, day, 0)
                 if err != nil {
                     return nil, err
                 }
                 routine.Day = day
+                routine.Version++
             }
             
             // Make sure the routine is active
             if !routine.IsActive {
-                err := r.UpdateRoutineStatus(ctx, routine.ID, true)
+                err := r.UpdateRoutineStatus(ctx, routine.ID, true, 0)
                 if err != nil {
                     return nil, err
                 }
                 routine.IsActive = true
+                routine.Version++
             }
             
             // Mark this schedule type as processed
@@ -177,7 +215,7 @@ func (r *RoutineRepository) CreateOrUpdateRoutines(ctx context.Context, taskID s
             updatedRoutines = append(updatedRoutines, routine)
         } else if routine.Day == day {
             // Not requested for this day, deactivate it
-            err := r.UpdateRoutineStatus(ctx, routine.ID, false)
+            err := r.UpdateRoutineStatus(ctx, routine.ID, false, 0)
             if err != nil {
                 return nil, err
             }
@@ -211,6 +249,341 @@ func (r *RoutineRepository) CreateOrUpdateRoutines(ctx context.Context, taskID s
     return updatedRoutines, nil
 }
 
+func (r *RoutineRepository) GetRoutineByID(ctx context.Context, id string) (*domain.Routine, error) {
+    row, err := r.querier.GetRoutineByID(ctx, id)
+    if err == sql.ErrNoRows {
+        return nil, fmt.Errorf("routine not found")
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &domain.Routine{
+        ID:           row.ID,
+        Day:          string(row.Day),
+        ScheduleType: row.Scheduletype,
+        TaskID:       row.Taskid,
+        UserID:       row.Userid,
+        IsActive:     row.Isactive.Bool,
+        Version:      int(row.Version),
+    }, nil
+}
+
+func (r *RoutineRepository) CheckInRoutine(ctx context.Context, routineID, userID string, date time.Time) error {
+    return r.querier.CheckInRoutine(ctx, db.CheckInRoutineParams{
+        RoutineID: routineID,
+        UserID:    userID,
+        Date:      calendarDate(date),
+    })
+}
+
+func (r *RoutineRepository) UncheckRoutine(ctx context.Context, routineID string, date time.Time) (bool, error) {
+    affected, err := r.querier.UncheckRoutine(ctx, db.UncheckRoutineParams{RoutineID: routineID, Date: calendarDate(date)})
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+func (r *RoutineRepository) GetDailyRoutineCheckins(ctx context.Context, day, scheduleType, userID string, date time.Time) ([]domain.RoutineCheckin, error) {
+    rows, err := r.querier.GetDailyRoutineCheckins(ctx, db.GetDailyRoutineCheckinsParams{
+        Date:         calendarDate(date),
+        Day:          db.RoutinesDay(day),
+        Scheduletype: scheduleType,
+        Userid:       userID,
+    })
+    if err != nil {
+        return nil, err
+    }
+    checkins := make([]domain.RoutineCheckin, len(rows))
+    for i, row := range rows {
+        checkins[i] = domain.RoutineCheckin{RoutineID: row.ID, TaskID: row.Taskid, CheckedIn: row.CheckedIn}
+    }
+    return checkins, nil
+}
+
+func (r *RoutineRepository) GetRoutineScheduleDays(ctx context.Context, taskID, scheduleType, userID string) ([]string, error) {
+    rows, err := r.querier.GetRoutineScheduleDays(ctx, db.GetRoutineScheduleDaysParams{
+        Taskid:       taskID,
+        Scheduletype: scheduleType,
+        Userid:       userID,
+    })
+    if err != nil {
+        return nil, err
+    }
+    days := make([]string, len(rows))
+    for i, day := range rows {
+        days[i] = string(day)
+    }
+    return days, nil
+}
+
+func (r *RoutineRepository) GetRoutineCheckinDates(ctx context.Context, taskID, scheduleType, userID string) ([]time.Time, error) {
+    rows, err := r.querier.GetRoutineCheckinDates(ctx, db.GetRoutineCheckinDatesParams{
+        Taskid:       taskID,
+        Scheduletype: scheduleType,
+        Userid:       userID,
+    })
+    if err != nil {
+        return nil, err
+    }
+    dates := make([]time.Time, 0, len(rows))
+    for _, row := range rows {
+        if parsed, err := time.Parse("2006-01-02", scannedString(row)); err == nil {
+            dates = append(dates, parsed)
+        }
+    }
+    return dates, nil
+}
+
+func (r *RoutineRepository) GetRoutineSlots(ctx context.Context, userID string) ([]domain.RoutineSlot, error) {
+    rows, err := r.querier.GetRoutineSlots(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    slots := make([]domain.RoutineSlot, len(rows))
+    for i, row := range rows {
+        var startTime string
+        switch v := row.StartTime.(type) {
+        case []byte:
+            startTime = string(v)
+        case string:
+            startTime = v
+        }
+        // TIME values read as "15:04:05"
+        if len(startTime) > 5 {
+            startTime = startTime[:5]
+        }
+        slots[i] = domain.RoutineSlot{ID: row.ID, UserID: row.UserID, Name: row.Name, StartTime: startTime}
+    }
+    return slots, nil
+}
+
+func (r *RoutineRepository) CreateRoutineSlots(ctx context.Context, slots []domain.RoutineSlot) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    qtx := r.querier.WithTx(tx)
+
+    for _, slot := range slots {
+        startTime, err := time.Parse("15:04", slot.StartTime)
+        if err != nil {
+            return fmt.Errorf("invalid start time %q", slot.StartTime)
+        }
+        err = qtx.CreateRoutineSlot(ctx, db.CreateRoutineSlotParams{
+            ID:        slot.ID,
+            UserID:    slot.UserID,
+            Name:      slot.Name,
+            StartTime: startTime,
+        })
+        if err != nil && strings.Contains(err.Error(), "user_slot_name") {
+            return fmt.Errorf("slot already exists")
+        }
+        if err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
+}
+
+func (r *RoutineRepository) UpdateRoutineSlot(ctx context.Context, slot domain.RoutineSlot, oldName string) error {
+    startTime, err := time.Parse("15:04", slot.StartTime)
+    if err != nil {
+        return fmt.Errorf("invalid start time %q", slot.StartTime)
+    }
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    qtx := r.querier.WithTx(tx)
+
+    err = qtx.UpdateRoutineSlot(ctx, db.UpdateRoutineSlotParams{
+        Name:      slot.Name,
+        StartTime: startTime,
+        ID:        slot.ID,
+        UserID:    slot.UserID,
+    })
+    if err != nil && strings.Contains(err.Error(), "user_slot_name") {
+        return fmt.Errorf("slot already exists")
+    }
+    if err != nil {
+        return err
+    }
+    if slot.Name != oldName {
+        err = qtx.RenameSlotRoutines(ctx, db.RenameSlotRoutinesParams{
+            NewName: slot.Name,
+            Userid:  slot.UserID,
+            OldName: oldName,
+        })
+        if err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
+}
+
+func (r *RoutineRepository) DeleteRoutineSlot(ctx context.Context, id, userID string) (bool, error) {
+    affected, err := r.querier.DeleteRoutineSlot(ctx, db.DeleteRoutineSlotParams{ID: id, UserID: userID})
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+func (r *RoutineRepository) CountSlotRoutines(ctx context.Context, userID, name string) (int, error) {
+    count, err := r.querier.CountSlotRoutines(ctx, db.CountSlotRoutinesParams{Userid: userID, Scheduletype: name})
+    if err != nil {
+        return 0, err
+    }
+    return int(count), nil
+}
+
+func (r *RoutineRepository) SetWeeklyRoutines(ctx context.Context, taskID, userID string, schedule []domain.RoutineSchedule) (domain.RoutineScheduleChanges, error) {
+    var changes domain.RoutineScheduleChanges
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return changes, err
+    }
+    defer tx.Rollback()
+    qtx := r.querier.WithTx(tx)
+
+    existing, err := qtx.GetTaskRoutinesForUpdate(ctx, db.GetTaskRoutinesForUpdateParams{Taskid: taskID, Userid: userID})
+    if err != nil {
+        return changes, err
+    }
+
+    wanted := make(map[domain.RoutineSchedule]bool, len(schedule))
+    for _, entry := range schedule {
+        wanted[entry] = true
+    }
+
+    // Keep one routine per wanted day and slot; duplicates and unwanted ones are deactivated
+    kept := make(map[domain.RoutineSchedule]bool, len(schedule))
+    for _, routine := range existing {
+        key := domain.RoutineSchedule{Day: string(routine.Day), ScheduleType: routine.Scheduletype}
+        active := routine.Isactive.Bool
+        keep := wanted[key] && !kept[key]
+        if keep {
+            kept[key] = true
+        }
+        if keep == active {
+            continue
+        }
+        req := &dto.UpdateRoutineStatusRequest{ID: routine.ID, IsActive: keep}
+        if err := qtx.UpdateRoutineStatus(ctx, *req.ConvertUpdateRoutineStatusDomainRequestToPersistentRequest()); err != nil {
+            return changes, err
+        }
+        if keep {
+            changes.Reactivated++
+        } else {
+            changes.Deactivated++
+        }
+    }
+
+    for _, entry := range schedule {
+        if kept[entry] {
+            continue
+        }
+        req := &dto.CreateRoutineRequest{
+            Day:          entry.Day,
+            ScheduleType: entry.ScheduleType,
+            TaskID:       taskID,
+            UserID:       userID,
+            IsActive:     true,
+        }
+        if err := qtx.CreateRoutine(ctx, *req.ConvertCreateRoutineDomainRequestToPersistentRequest()); err != nil {
+            return changes, err
+        }
+        kept[entry] = true
+        changes.Created++
+    }
+
+    if err := tx.Commit(); err != nil {
+        return domain.RoutineScheduleChanges{}, err
+    }
+    return changes, nil
+}
+
+func (r *RoutineRepository) GetWeekRoutines(ctx context.Context, userID string) ([]domain.WeekRoutine, error) {
+    rows, err := r.querier.GetWeekRoutines(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    routines := make([]domain.WeekRoutine, len(rows))
+    for i, row := range rows {
+        routines[i] = domain.WeekRoutine{
+            RoutineID:    row.ID,
+            Day:          string(row.Day),
+            ScheduleType: row.Scheduletype,
+            TaskID:       row.TaskID,
+            Task:         row.Task,
+            Description:  row.Description.String,
+            Important:    row.Important,
+        }
+    }
+    return routines, nil
+}
+
+func (r *RoutineRepository) CreateRoutinePause(ctx context.Context, pause domain.RoutinePause) error {
+    return r.querier.CreateRoutinePause(ctx, db.CreateRoutinePauseParams{
+        ID:        pause.ID,
+        UserID:    pause.UserID,
+        RoutineID: sql.NullString{String: pause.RoutineID, Valid: pause.RoutineID != ""},
+        StartDate: calendarDate(pause.StartDate),
+        EndDate:   calendarDate(pause.EndDate),
+        Reason:    sql.NullString{String: pause.Reason, Valid: pause.Reason != ""},
+    })
+}
+
+func (r *RoutineRepository) GetRoutinePauses(ctx context.Context, userID string) ([]domain.RoutinePause, error) {
+    rows, err := r.querier.GetRoutinePauses(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    pauses := make([]domain.RoutinePause, 0, len(rows))
+    for _, row := range rows {
+        start, err := time.Parse("2006-01-02", scannedString(row.StartDate))
+        if err != nil {
+            return nil, err
+        }
+        end, err := time.Parse("2006-01-02", scannedString(row.EndDate))
+        if err != nil {
+            return nil, err
+        }
+        pauses = append(pauses, domain.RoutinePause{
+            ID:           row.ID,
+            UserID:       userID,
+            RoutineID:    row.RoutineID.String,
+            Day:          string(row.Day.RoutinesDay),
+            TaskID:       row.Taskid.String,
+            ScheduleType: row.Scheduletype.String,
+            StartDate:    start,
+            EndDate:      end,
+            Reason:       row.Reason.String,
+        })
+    }
+    return pauses, nil
+}
+
+func (r *RoutineRepository) DeleteRoutinePause(ctx context.Context, id, userID string) (bool, error) {
+    affected, err := r.querier.DeleteRoutinePause(ctx, db.DeleteRoutinePauseParams{ID: id, UserID: userID})
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+// calendarDate keeps the day of date as UTC midnight so the driver's UTC
+// conversion cannot move it to a neighbouring day
+func calendarDate(date time.Time) time.Time {
+    year, month, day := date.Date()
+    return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
 // Original methods for backward compatibility
 func (r *RoutineRepository) CreateRoutineWithDTO(ctx context.Context, req *dto.CreateRoutineRequest) (*dto.CreateResponse, error) {
     params := req.ConvertCreateRoutineDomainRequestToPersistentRequest()
@@ -250,7 +623,7 @@ func (r *RoutineRepository) GetRoutinesByTaskIDWithDTO(ctx context.Context, task
 func (r *RoutineRepository) GetDailyRoutinesWithDTO(ctx context.Context, day, scheduleType, userID string) (*dto.TodosResponse, error) {
     todos, err := r.querier.GetDailyRoutines(ctx, db.GetDailyRoutinesParams{
         Day:          db.RoutinesDay(day),
-        Scheduletype: db.RoutinesScheduletype(scheduleType),
+        Scheduletype: scheduleType,
         Userid:       userID,
     })
     if err != nil {
@@ -302,8 +675,20 @@ func (r *RoutineRepository) CreateOrUpdateRoutinesWithDTO(ctx context.Context, r
             CreatedAt:    routine.CreatedAt,
             UpdatedAt:    routine.UpdatedAt,
             IsActive:     routine.IsActive,
+            Version:      routine.Version,
         })
     }
     
     return &dto.RoutinesResponse{Routines: routineResponses}, nil
-}
\ No newline at end of file
+}
+
+// scannedString reads a CAST(... AS CHAR) column scanned into an interface{}
+func scannedString(value interface{}) string {
+    switch v := value.(type) {
+    case []byte:
+        return string(v)
+    case string:
+        return v
+    }
+    return ""
+}
@@ -4,6 +4,7 @@ import (
     "context"
     "database/sql"
     "fmt"
+    "strings"
     "time"
     "github.com/google/uuid"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
@@ -36,6 +37,8 @@ func (r *SharedTodoRepository) CreateSharedTodo(ctx context.Context, task, descr
         Date:        nullDate,
         Time:        nullTime,
         SharedBy:    sql.NullString{String: sharedBy, Valid: true},
+        Permission:  domain.SharePermissionView,
+        Status:      domain.ShareStatusPending,
     })
     
     if err != nil {
@@ -45,83 +48,48 @@ func (r *SharedTodoRepository) CreateSharedTodo(ctx context.Context, task, descr
     return id, nil
 }
 
+// sharedTodoSelect reads shares through their linked todo so both sides see the
+// same task, done flag and dates. Shares without a link show their own copy.
+const sharedTodoSelect = `SELECT s.id,
+    IF(t.id IS NULL, s.task, t.task),
+    IF(t.id IS NULL, s.description, t.description),
+    IF(t.id IS NULL, s.done, t.done),
+    IF(t.id IS NULL, s.important, t.important),
+    s.user_id,
+    CAST(IF(t.id IS NULL, s.date, t.date) AS CHAR),
+    CAST(IF(t.id IS NULL, s.time, t.time) AS CHAR),
+    s.shared_by,
+    s.todo_id,
+    COALESCE(t.version, 0),
+    s.permission,
+    s.status
+FROM shared_todos s
+LEFT JOIN todos t ON t.id = s.todo_id`
+
 func (r *SharedTodoRepository) GetSharedTodos(ctx context.Context, userID string) ([]domain.SharedTodo, error) {
-    // Cast date and time to CHAR to get them as strings
-    rows, err := r.db.QueryContext(ctx, 
-        "SELECT id, task, description, done, important, user_id, CAST(date AS CHAR), CAST(time AS CHAR), shared_by FROM shared_todos WHERE user_id = ?", 
-        userID)
+    return r.querySharedTodos(ctx, sharedTodoSelect+" WHERE s.user_id = ?", userID)
+}
+
+
+func (r *SharedTodoRepository) GetSharedByMeTodos(ctx context.Context, sharedBy string) ([]domain.SharedTodo, error) {
+    return r.querySharedTodos(ctx, sharedTodoSelect+" WHERE s.shared_by = ?", sharedBy)
+}
+
+// GetSharedTodoByID returns a todo shared with userID
+func (r *SharedTodoRepository) GetSharedTodoByID(ctx context.Context, id, userID string) (*domain.SharedTodo, error) {
+    todos, err := r.querySharedTodos(ctx, sharedTodoSelect+" WHERE s.id = ? AND s.user_id = ?", id, userID)
     if err != nil {
         return nil, err
     }
-    defer rows.Close()
-    
-    var todos []domain.SharedTodo
-    for rows.Next() {
-        var todo domain.SharedTodo
-        var description, dateStr, timeStr sql.NullString
-        
-        if err := rows.Scan(
-            &todo.ID,
-            &todo.Task,
-            &description,
-            &todo.Done,
-            &todo.Important,
-            &todo.UserID,
-            &dateStr,
-            &timeStr,
-            &todo.SharedBy,
-        ); err != nil {
-            return nil, err
-        }
-        
-        todo.Description = description.String
-        
-        // Parse date
-        if dateStr.Valid && dateStr.String != "" {
-            parsed, err := time.Parse("2006-01-02", dateStr.String)
-            if err == nil {
-                todo.Date = parsed
-            } else {
-                todo.Date = time.Now() // Default to today if parsing fails
-            }
-        } else {
-            todo.Date = time.Now() // Default to today if no date
-        }
-        
-        // Parse time
-        if timeStr.Valid && timeStr.String != "" {
-            parsed, err := time.Parse("15:04:05", timeStr.String)
-            if err == nil {
-                // Use a valid year for the time
-                hour, min, sec := parsed.Clock()
-                todo.Time = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
-            } else {
-                // Default to current time if parsing fails
-                now := time.Now()
-                todo.Time = time.Date(2000, 1, 1, now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
-            }
-        } else {
-            // Default to current time if no time
-            now := time.Now()
-            todo.Time = time.Date(2000, 1, 1, now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
-        }
-        
-        todos = append(todos, todo)
-    }
-    
-    if err = rows.Err(); err != nil {
-        return nil, err
+    if len(todos) == 0 {
+        return nil, fmt.Errorf("shared todo not found")
     }
-    
-    return todos, nil
+    return &todos[0], nil
 }
 
-
-func (r *SharedTodoRepository) GetSharedByMeTodos(ctx context.Context, sharedBy string) ([]domain.SharedTodo, error) {
+func (r *SharedTodoRepository) querySharedTodos(ctx context.Context, query string, args ...interface{}) ([]domain.SharedTodo, error) {
     // Cast date and time to CHAR to get them as strings
-    rows, err := r.db.QueryContext(ctx, 
-        "SELECT id, task, description, done, important, user_id, CAST(date AS CHAR), CAST(time AS CHAR), shared_by FROM shared_todos WHERE shared_by = ?", 
-        sharedBy)
+    rows, err := r.db.QueryContext(ctx, query, args...)
     if err != nil {
         return nil, err
     }
@@ -130,7 +98,7 @@ func (r *SharedTodoRepository) GetSharedByMeTodos(ctx context.Context, sharedBy
     var todos []domain.SharedTodo
     for rows.Next() {
         var todo domain.SharedTodo
-        var description, dateStr, timeStr sql.NullString
+        var description, dateStr, timeStr, todoID sql.NullString
         
         if err := rows.Scan(
             &todo.ID,
@@ -142,11 +110,16 @@ func (r *SharedTodoRepository) GetSharedByMeTodos(ctx context.Context, sharedBy
             &dateStr,
             &timeStr,
             &todo.SharedBy,
+            &todoID,
+            &todo.Version,
+            &todo.Permission,
+            &todo.Status,
         ); err != nil {
             return nil, err
         }
         
         todo.Description = description.String
+        todo.TodoID = todoID.String
         
         // Parse date
         if dateStr.Valid && dateStr.String != "" {
@@ -214,8 +187,9 @@ func (r *SharedTodoRepository) GetSharedByMeTodosWithDTO(ctx context.Context, sh
     return dto.NewSharedTodosResponse(todos), nil
 }
 
-// ShareTodo shares a todo with another user
-func (r *SharedTodoRepository) ShareTodo(ctx context.Context, todoID string, recipientUserID string, sharedBy string) error {
+// ShareTodo shares a todo with another user. The share links to the todo; the
+// copied fields are only kept as a fallback.
+func (r *SharedTodoRepository) ShareTodo(ctx context.Context, todoID string, recipientUserID string, sharedBy string, permission string) error {
     // First get the original todo
     var task string
     var description sql.NullString
@@ -283,21 +257,207 @@ func (r *SharedTodoRepository) ShareTodo(ctx context.Context, todoID string, rec
         Date:        nullDate,
         Time:        nullTime,
         SharedBy:    sql.NullString{String: sharedBy, Valid: true},
+        TodoID:      sql.NullString{String: todoID, Valid: true},
+        Permission:  permission,
+        Status:      domain.ShareStatusPending,
     })
     
     return err
 }
 
+// ShareTodoWithUsers shares a todo with all recipients at once. Recipients who
+// already have the todo, including as an old unlinked copy, or who blocked the
+// sharer are skipped.
+func (r *SharedTodoRepository) ShareTodoWithUsers(ctx context.Context, todoID string, recipientUserIDs []string, sharedBy, permission string) (map[string]string, error) {
+    results := make(map[string]string, len(recipientUserIDs))
+    if len(recipientUserIDs) == 0 {
+        return results, nil
+    }
+    
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+    qtx := r.querier.WithTx(tx)
+    
+    placeholders := strings.TrimSuffix(strings.Repeat("?,", len(recipientUserIDs)), ",")
+    recipients := make([]interface{}, len(recipientUserIDs))
+    for i, id := range recipientUserIDs {
+        recipients[i] = id
+    }
+    
+    collect := func(result, query string, args ...interface{}) error {
+        rows, err := tx.QueryContext(ctx, query, args...)
+        if err != nil {
+            return err
+        }
+        defer rows.Close()
+        for rows.Next() {
+            var userID string
+            if err := rows.Scan(&userID); err != nil {
+                return err
+            }
+            results[userID] = result
+        }
+        return rows.Err()
+    }
+    
+    err = collect(domain.ShareResultAlreadyShared,
+        "SELECT user_id FROM shared_todos WHERE user_id IN ("+placeholders+") AND (todo_id = ? OR (todo_id IS NULL AND task IN (SELECT task FROM todos WHERE id = ?))) FOR UPDATE",
+        append(recipients, todoID, todoID)...)
+    if err != nil {
+        return nil, err
+    }
+    err = collect(domain.ShareResultBlocked,
+        "SELECT user_id FROM share_blocks WHERE blocked_user_id = ? AND user_id IN ("+placeholders+")",
+        append([]interface{}{sharedBy}, recipients...)...)
+    if err != nil {
+        return nil, err
+    }
+    
+    for _, userID := range recipientUserIDs {
+        if _, skipped := results[userID]; skipped {
+            continue
+        }
+        err := qtx.CreateLinkedSharedTodo(ctx, db.CreateLinkedSharedTodoParams{
+            ID:         uuid.New().String(),
+            UserID:     sql.NullString{String: userID, Valid: true},
+            SharedBy:   sql.NullString{String: sharedBy, Valid: true},
+            Permission: permission,
+            ID_2:       todoID,
+        })
+        if err != nil {
+            return nil, err
+        }
+        results[userID] = domain.ShareResultShared
+    }
+    
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return results, nil
+}
+
 // IsSharedWithUser checks if a todo is already shared with a user
 func (r *SharedTodoRepository) IsSharedWithUser(ctx context.Context, todoID string, userID string) (bool, error) {
     var count int
     err := r.db.QueryRowContext(ctx, 
-        "SELECT COUNT(*) FROM shared_todos WHERE task IN (SELECT task FROM todos WHERE id = ?) AND user_id = ?", 
-        todoID, userID).Scan(&count)
+        "SELECT COUNT(*) FROM shared_todos WHERE user_id = ? AND (todo_id = ? OR (todo_id IS NULL AND task IN (SELECT task FROM todos WHERE id = ?)))", 
+        userID, todoID, todoID).Scan(&count)
     
     if err != nil {
         return false, err
     }
     
+    return count > 0, nil
+}
+
+// UpdateSharePermission changes the permission of a share made by sharedBy
+func (r *SharedTodoRepository) UpdateSharePermission(ctx context.Context, id, sharedBy, permission string) (bool, error) {
+    affected, err := r.querier.UpdateSharedTodoPermission(ctx, db.UpdateSharedTodoPermissionParams{
+        Permission: permission,
+        ID:         id,
+        SharedBy:   sql.NullString{String: sharedBy, Valid: true},
+    })
+    if err != nil {
+        return false, err
+    }
+    if affected > 0 {
+        return true, nil
+    }
+    // MySQL reports no affected rows when the value is unchanged, so check
+    // whether the share exists at all
+    var count int
+    err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM shared_todos WHERE id = ? AND shared_by = ?", id, sharedBy).Scan(&count)
+    if err != nil {
+        return false, err
+    }
+    return count > 0, nil
+}
+
+// DeleteSharedTodo revokes a share made by sharedBy
+func (r *SharedTodoRepository) DeleteSharedTodo(ctx context.Context, id, sharedBy string) (bool, error) {
+    affected, err := r.querier.DeleteSharedTodo(ctx, db.DeleteSharedTodoParams{
+        ID:       id,
+        SharedBy: sql.NullString{String: sharedBy, Valid: true},
+    })
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+// AcceptSharedTodo moves a pending share into the recipient's lists
+func (r *SharedTodoRepository) AcceptSharedTodo(ctx context.Context, id, userID string) (bool, error) {
+    affected, err := r.querier.AcceptSharedTodo(ctx, db.AcceptSharedTodoParams{
+        ID:     id,
+        UserID: sql.NullString{String: userID, Valid: true},
+    })
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+// DeclineSharedTodo removes a share made to userID
+func (r *SharedTodoRepository) DeclineSharedTodo(ctx context.Context, id, userID string) (bool, error) {
+    affected, err := r.querier.DeleteReceivedSharedTodo(ctx, db.DeleteReceivedSharedTodoParams{
+        ID:     id,
+        UserID: sql.NullString{String: userID, Valid: true},
+    })
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+// BlockSender records the block and drops every share from the sender in one transaction
+func (r *SharedTodoRepository) BlockSender(ctx context.Context, userID, senderID string) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    qtx := r.querier.WithTx(tx)
+
+    if err := qtx.CreateShareBlock(ctx, db.CreateShareBlockParams{UserID: userID, BlockedUserID: senderID}); err != nil {
+        return err
+    }
+    err = qtx.DeleteSharedTodosFromSender(ctx, db.DeleteSharedTodosFromSenderParams{
+        UserID:   sql.NullString{String: userID, Valid: true},
+        SharedBy: sql.NullString{String: senderID, Valid: true},
+    })
+    if err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+func (r *SharedTodoRepository) UnblockSender(ctx context.Context, userID, senderID string) (bool, error) {
+    affected, err := r.querier.DeleteShareBlock(ctx, db.DeleteShareBlockParams{UserID: userID, BlockedUserID: senderID})
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+func (r *SharedTodoRepository) GetBlockedSenders(ctx context.Context, userID string) ([]domain.ShareBlock, error) {
+    rows, err := r.querier.GetShareBlocks(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    blocks := make([]domain.ShareBlock, len(rows))
+    for i, row := range rows {
+        blocks[i] = domain.ShareBlock{UserID: row.BlockedUserID, Username: row.Username}
+    }
+    return blocks, nil
+}
+
+func (r *SharedTodoRepository) IsSenderBlocked(ctx context.Context, userID, senderID string) (bool, error) {
+    count, err := r.querier.IsShareBlocked(ctx, db.IsShareBlockedParams{UserID: userID, BlockedUserID: senderID})
+    if err != nil {
+        return false, err
+    }
     return count > 0, nil
 }
\ No newline at end of file
@@ -7,5 +7,8 @@ import (
 
 func NewTeamTodoRepository(DB *sql.DB) *TeamTodoRepository {
     querier := db.New(DB)
-    return &TeamTodoRepository{querier: querier}
+    return &TeamTodoRepository{
+        querier: querier,
+        db:      DB,
+    }
 }
\ No newline at end of file
@@ -0,0 +1,313 @@
+package team_todos_repository
+
+import (
+    "context"
+    "database/sql"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+// nullAssignee stores an empty assignee as NULL so unassigned todos never reference ''
+func nullAssignee(assignedTo string) sql.NullString {
+    return sql.NullString{String: assignedTo, Valid: assignedTo != ""}
+}
+
+// castString reads a column selected with CAST(... AS CHAR)
+func castString(value interface{}) string {
+    switch v := value.(type) {
+    case []byte:
+        return string(v)
+    case string:
+        return v
+    }
+    return ""
+}
+
+// currentAssignee reads the assignee of a team todo inside a transaction
+func currentAssignee(ctx context.Context, qtx *db.Queries, id, teamID string) (string, error) {
+    assignedTo, err := qtx.GetTeamTodoAssignee(ctx, db.GetTeamTodoAssigneeParams{ID: id, TeamID: teamID})
+    if err != nil {
+        return "", err
+    }
+    return assignedTo.String, nil
+}
+
+// replaceAssignee applies a change of the single assigned_to field: the todo is
+// then assigned to that member alone, or to nobody when assignedTo is empty
+func replaceAssignee(ctx context.Context, qtx *db.Queries, id, teamID, previous, assignedTo, changedBy string) error {
+    if previous == assignedTo {
+        return nil
+    }
+    var userIDs []string
+    if assignedTo != "" {
+        userIDs = []string{assignedTo}
+    }
+    return setAssignees(ctx, qtx, id, teamID, userIDs, changedBy)
+}
+
+// setAssignees makes userIDs the assignees of a todo. Members who stay assigned
+// keep their progress. Added and removed members are recorded in the history.
+func setAssignees(ctx context.Context, qtx *db.Queries, id, teamID string, userIDs []string, changedBy string) error {
+    rows, err := qtx.GetTeamTodoAssignees(ctx, id)
+    if err != nil {
+        return err
+    }
+    current := make(map[string]bool, len(rows))
+    for _, row := range rows {
+        current[row.UserID] = true
+    }
+    
+    next := make(map[string]bool, len(userIDs))
+    var added, removed []string
+    for _, userID := range userIDs {
+        next[userID] = true
+        if !current[userID] {
+            added = append(added, userID)
+        }
+    }
+    for _, row := range rows {
+        if !next[row.UserID] {
+            removed = append(removed, row.UserID)
+        }
+    }
+    
+    for _, userID := range removed {
+        if err := qtx.DeleteTeamTodoAssignee(ctx, db.DeleteTeamTodoAssigneeParams{TodoID: id, UserID: userID}); err != nil {
+            return err
+        }
+    }
+    for _, userID := range added {
+        if err := qtx.AddTeamTodoAssignee(ctx, db.AddTeamTodoAssigneeParams{TodoID: id, TeamID: teamID, UserID: userID}); err != nil {
+            return err
+        }
+    }
+    return recordAssignments(ctx, qtx, id, teamID, removed, added, changedBy)
+}
+
+// recordAssignments adds assignee changes to the history. Replacing one assignee
+// with another is recorded as a single reassignment.
+func recordAssignments(ctx context.Context, qtx *db.Queries, id, teamID string, removed, added []string, assignedBy string) error {
+    record := func(assignedTo, previous string) error {
+        return qtx.CreateTeamTodoAssignment(ctx, db.CreateTeamTodoAssignmentParams{
+            TodoID:           id,
+            TeamID:           teamID,
+            AssignedTo:       nullAssignee(assignedTo),
+            PreviousAssignee: nullAssignee(previous),
+            AssignedBy:       assignedBy,
+        })
+    }
+    
+    if len(removed) == 1 && len(added) == 1 {
+        return record(added[0], removed[0])
+    }
+    for _, userID := range removed {
+        if err := record("", userID); err != nil {
+            return err
+        }
+    }
+    for _, userID := range added {
+        if err := record(userID, ""); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// ReassignTeamTodo makes assignedTo the only assignee of a team todo and records the change.
+// An empty assignedTo unassigns the todo.
+func (r *TeamTodoRepository) ReassignTeamTodo(ctx context.Context, id, teamID, assignedTo, assignedBy string, expectedVersion int) (bool, error) {
+    err := r.runVersioned(ctx, id, teamID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        previous, err := currentAssignee(ctx, qtx, id, teamID)
+        if err != nil {
+            return err
+        }
+        // Nothing to change
+        if previous == assignedTo {
+            return nil
+        }
+        if err := qtx.SetTeamTodoAssignee(ctx, db.SetTeamTodoAssigneeParams{
+            AssignedTo: nullAssignee(assignedTo),
+            ID:         id,
+            TeamID:     teamID,
+        }); err != nil {
+            return err
+        }
+        return replaceAssignee(ctx, qtx, id, teamID, previous, assignedTo, assignedBy)
+    })
+    if err != nil {
+        return false, err
+    }
+    return true, nil
+}
+
+// SetTeamTodoAssignees replaces the assignees of a team todo. The primary assignee
+// stays the same while still assigned, otherwise the first of userIDs takes over.
+// An empty completionMode keeps the current one.
+func (r *TeamTodoRepository) SetTeamTodoAssignees(ctx context.Context, id, teamID string, userIDs []string, completionMode, changedBy string, expectedVersion int) (bool, error) {
+    err := r.runVersioned(ctx, id, teamID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        previous, err := currentAssignee(ctx, qtx, id, teamID)
+        if err != nil {
+            return err
+        }
+        if completionMode == "" {
+            completion, err := qtx.GetTeamTodoCompletion(ctx, db.GetTeamTodoCompletionParams{ID: id, TeamID: teamID})
+            if err != nil {
+                return err
+            }
+            completionMode = completion.CompletionMode
+        }
+        
+        var primary string
+        for _, userID := range userIDs {
+            if userID == previous {
+                primary = previous
+                break
+            }
+        }
+        if primary == "" && len(userIDs) > 0 {
+            primary = userIDs[0]
+        }
+        
+        if err := setAssignees(ctx, qtx, id, teamID, userIDs, changedBy); err != nil {
+            return err
+        }
+        return qtx.UpdateTeamTodoAssignment(ctx, db.UpdateTeamTodoAssignmentParams{
+            AssignedTo:     nullAssignee(primary),
+            CompletionMode: completionMode,
+            ID:             id,
+            TeamID:         teamID,
+        })
+    })
+    if err != nil {
+        return false, err
+    }
+    return true, nil
+}
+
+// SetAssigneeDone marks one assignee's part of a team todo as done or not done.
+// When that decides the todo under its completion mode, the todo's done flag
+// follows. It returns false when the user is not assigned to the todo.
+func (r *TeamTodoRepository) SetAssigneeDone(ctx context.Context, id, teamID, userID string, done bool) (bool, error) {
+    found := false
+    err := r.runVersioned(ctx, id, teamID, 0, func(qtx *db.Queries, tx *sql.Tx) error {
+        before, err := teamTodoAssignees(ctx, qtx, id)
+        if err != nil {
+            return err
+        }
+        after := make([]domain.TeamTodoAssignee, len(before))
+        copy(after, before)
+        for i := range after {
+            if after[i].UserID == userID {
+                found = true
+                after[i].Done = done
+            }
+        }
+        if !found {
+            return nil
+        }
+        
+        if _, err := qtx.SetTeamTodoAssigneeDone(ctx, db.SetTeamTodoAssigneeDoneParams{Done: done, TodoID: id, UserID: userID}); err != nil {
+            return err
+        }
+        
+        completion, err := qtx.GetTeamTodoCompletion(ctx, db.GetTeamTodoCompletionParams{ID: id, TeamID: teamID})
+        if err != nil {
+            return err
+        }
+        // Only a change of the combined state moves the todo
+        todoDone := completion.Done
+        wasDone := domain.AssigneesDone(completion.CompletionMode, before)
+        if isDone := domain.AssigneesDone(completion.CompletionMode, after); isDone != wasDone {
+            todoDone = isDone
+        }
+        // Always bump the version since the assignees are part of the todo
+        if err := qtx.SetTeamTodoDone(ctx, db.SetTeamTodoDoneParams{Done: todoDone, ID: id, TeamID: teamID}); err != nil {
+            return err
+        }
+        if todoDone == completion.Done {
+            return nil
+        }
+        // The parts decided the todo, so only the board placement follows
+        return placeForDone(ctx, qtx, id, teamID, todoDone)
+    })
+    if err != nil {
+        return false, err
+    }
+    return found, nil
+}
+
+// teamTodoAssignees reads the assignees of a todo
+func teamTodoAssignees(ctx context.Context, qtx *db.Queries, id string) ([]domain.TeamTodoAssignee, error) {
+    rows, err := qtx.GetTeamTodoAssignees(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    assignees := make([]domain.TeamTodoAssignee, len(rows))
+    for i, row := range rows {
+        assignees[i] = domain.TeamTodoAssignee{UserID: row.UserID, Username: row.Username, Done: row.Done}
+    }
+    return assignees, nil
+}
+
+// GetTeamTodoAssignments returns the assignment history of a team todo, oldest first
+func (r *TeamTodoRepository) GetTeamTodoAssignments(ctx context.Context, id, teamID string) ([]domain.TeamTodoAssignment, error) {
+    rows, err := r.querier.GetTeamTodoAssignments(ctx, db.GetTeamTodoAssignmentsParams{TodoID: id, TeamID: teamID})
+    if err != nil {
+        return nil, err
+    }
+    assignments := make([]domain.TeamTodoAssignment, len(rows))
+    for i, row := range rows {
+        assignments[i] = domain.TeamTodoAssignment{
+            ID:                   row.ID,
+            TodoID:               id,
+            TeamID:               teamID,
+            AssignedTo:           row.AssignedTo.String,
+            AssignedToName:       row.AssignedToUsername.String,
+            PreviousAssignee:     row.PreviousAssignee.String,
+            PreviousAssigneeName: row.PreviousAssigneeUsername.String,
+            AssignedBy:           row.AssignedBy,
+            AssignedByName:       row.AssignedByUsername.String,
+        }
+        if at := castString(row.AssignedAt); at != "" {
+            assignments[i].AssignedAt, _ = time.Parse("2006-01-02 15:04:05", at)
+        }
+    }
+    return assignments, nil
+}
+
+// GetAssignedTeamTodos returns the todos assigned to the user in every team they belong to
+func (r *TeamTodoRepository) GetAssignedTeamTodos(ctx context.Context, userID string) ([]domain.AssignedTeamTodo, error) {
+    rows, err := r.querier.GetAssignedTeamTodos(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    todos := make([]domain.AssignedTeamTodo, len(rows))
+    for i, row := range rows {
+        todo := domain.AssignedTeamTodo{
+            TeamTodo: domain.TeamTodo{
+                ID:          row.ID,
+                Task:        row.Task,
+                Description: row.Description.String,
+                Done:        row.Done,
+                Important:   row.Important.Bool,
+                TeamID:      row.TeamID,
+                AssignedTo:  row.AssignedTo.String,
+                Version:     int(row.Version),
+            },
+            TeamName: row.TeamName,
+        }
+        if d := castString(row.Date); d != "" {
+            todo.Date, _ = time.Parse("2006-01-02", d)
+        }
+        if t := castString(row.Time); t != "" {
+            if parsed, err := time.Parse("15:04:05", t); err == nil {
+                hour, min, sec := parsed.Clock()
+                todo.Time = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
+            }
+        }
+        todos[i] = todo
+    }
+    return todos, nil
+}
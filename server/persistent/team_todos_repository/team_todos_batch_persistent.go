@@ -0,0 +1,95 @@
+package team_todos_repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+
+    "github.com/google/uuid"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+// ExecuteBatch runs a list of team todo operations inside one transaction.
+// It returns the per-operation results and whether the transaction was committed.
+func (r *TeamTodoRepository) ExecuteBatch(ctx context.Context, teamID, userID string, ops []domain.BatchOperation, atomic bool) ([]domain.BatchResult, bool, error) {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, false, err
+    }
+    defer tx.Rollback()
+
+    qtx := r.querier.WithTx(tx)
+    results, commit := domain.RunBatch(ops, atomic, func(op domain.BatchOperation) (string, error) {
+        return r.applyBatchOperation(ctx, qtx, teamID, userID, op)
+    })
+    if !commit {
+        return results, false, nil
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, false, err
+    }
+    return results, true, nil
+}
+
+func (r *TeamTodoRepository) applyBatchOperation(ctx context.Context, qtx *db.Queries, teamID, userID string, op domain.BatchOperation) (string, error) {
+    if op.Op == domain.BatchOpCreate {
+        id := uuid.New().String()
+        err := qtx.CreateTeamTodo(ctx, db.CreateTeamTodoParams{
+            ID:          id,
+            Task:        op.Task,
+            Description: sql.NullString{String: op.Description, Valid: true},
+            Done:        op.Done,
+            Important:   sql.NullBool{Bool: op.Important, Valid: true},
+            TeamID:      teamID,
+            AssignedTo:  nullAssignee(op.AssignedTo),
+            Date:        sql.NullTime{Time: op.Date, Valid: true},
+            Time:        sql.NullTime{Time: op.Time, Valid: true},
+        })
+        if err != nil {
+            return id, err
+        }
+        return id, replaceAssignee(ctx, qtx, id, teamID, "", op.AssignedTo, userID)
+    }
+
+    // Every other operation targets an existing todo in the team
+    if err := checkTeamTodoVersion(ctx, qtx, op.ID, teamID, op.Version); err != nil {
+        return op.ID, err
+    }
+
+    var err error
+    switch op.Op {
+    case domain.BatchOpUpdate:
+        var previous string
+        if previous, err = currentAssignee(ctx, qtx, op.ID, teamID); err != nil {
+            return op.ID, err
+        }
+        var completion db.GetTeamTodoCompletionRow
+        if completion, err = qtx.GetTeamTodoCompletion(ctx, db.GetTeamTodoCompletionParams{ID: op.ID, TeamID: teamID}); err != nil {
+            return op.ID, err
+        }
+        err = qtx.UpdateTeamTodo(ctx, db.UpdateTeamTodoParams{
+            ID:          op.ID,
+            Task:        op.Task,
+            Description: sql.NullString{String: op.Description, Valid: true},
+            Done:        op.Done,
+            Important:   sql.NullBool{Bool: op.Important, Valid: true},
+            TeamID:      teamID,
+            AssignedTo:  nullAssignee(op.AssignedTo),
+        })
+        if err == nil {
+            err = syncTeamTodoDone(ctx, qtx, op.ID, teamID, completion.Done, op.Done)
+        }
+        if err == nil {
+            err = replaceAssignee(ctx, qtx, op.ID, teamID, previous, op.AssignedTo, userID)
+        }
+    case domain.BatchOpComplete:
+        err = setTeamTodoDone(ctx, qtx, op.ID, teamID, true)
+    case domain.BatchOpDelete:
+        err = qtx.DeleteTeamTodo(ctx, db.DeleteTeamTodoParams{ID: op.ID, TeamID: teamID})
+    default:
+        err = fmt.Errorf("unsupported operation %q", op.Op)
+    }
+    return op.ID, err
+}
@@ -0,0 +1,95 @@
+package team_todos_repository
+
+import (
+    "context"
+    "database/sql"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+// setTeamTodoDone changes the done flag of a team todo inside a transaction and
+// bumps its version. The assignees and the board placement follow the change.
+func setTeamTodoDone(ctx context.Context, qtx *db.Queries, id, teamID string, done bool) error {
+    completion, err := qtx.GetTeamTodoCompletion(ctx, db.GetTeamTodoCompletionParams{ID: id, TeamID: teamID})
+    if err != nil {
+        return err
+    }
+    if err := qtx.SetTeamTodoDone(ctx, db.SetTeamTodoDoneParams{Done: done, ID: id, TeamID: teamID}); err != nil {
+        return err
+    }
+    return syncTeamTodoDone(ctx, qtx, id, teamID, completion.Done, done)
+}
+
+// syncTeamTodoDone runs after the done flag of a team todo was set directly. When
+// it changed, every assignee's part is set to match so the completion mode agrees
+// with the todo, and the todo is placed in a board status that matches.
+func syncTeamTodoDone(ctx context.Context, qtx *db.Queries, id, teamID string, wasDone, done bool) error {
+    if wasDone == done {
+        return nil
+    }
+    if err := qtx.SetTeamTodoAssigneesDone(ctx, db.SetTeamTodoAssigneesDoneParams{Done: done, TodoID: id}); err != nil {
+        return err
+    }
+    return placeForDone(ctx, qtx, id, teamID, done)
+}
+
+// placeForDone moves a todo whose board status no longer matches done to the
+// status the board shows it in. Teams without statuses have no board yet.
+func placeForDone(ctx context.Context, qtx *db.Queries, id, teamID string, done bool) error {
+    statusRows, err := qtx.GetTeamStatuses(ctx, teamID)
+    if err != nil || len(statusRows) == 0 {
+        return err
+    }
+    placementRows, err := qtx.GetTeamTodoPlacements(ctx, teamID)
+    if err != nil {
+        return err
+    }
+
+    statuses := make([]domain.TeamStatus, len(statusRows))
+    for i, row := range statusRows {
+        statuses[i] = domain.TeamStatus{ID: row.ID, TeamID: row.TeamID, Name: row.Name, Position: int(row.Position), Terminal: row.IsTerminal}
+    }
+    placements := make([]domain.TeamTodoPlacement, len(placementRows))
+    for i, row := range placementRows {
+        placements[i] = domain.TeamTodoPlacement{TodoID: row.TodoID, StatusID: row.StatusID, Position: int(row.Position)}
+    }
+
+    place, ok := domain.PlaceForDone(statuses, placements, id, done)
+    if !ok {
+        return nil
+    }
+    return qtx.SetTeamTodoPlacement(ctx, db.SetTeamTodoPlacementParams{
+        TodoID:   id,
+        TeamID:   teamID,
+        StatusID: place.StatusID,
+        Position: int32(place.Position),
+    })
+}
+
+// MoveTeamTodo puts a team todo into a board status and renumbers that status's
+// column in the given order. The done flag follows the status like any other
+// completion of the todo.
+func (r *TeamTodoRepository) MoveTeamTodo(ctx context.Context, id, teamID, statusID string, done bool, order []string, expectedVersion int) (bool, error) {
+    err := r.runVersioned(ctx, id, teamID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        if err := setTeamTodoDone(ctx, qtx, id, teamID, done); err != nil {
+            return err
+        }
+        for position, todoID := range order {
+            err := qtx.SetTeamTodoPlacement(ctx, db.SetTeamTodoPlacementParams{
+                TodoID:   todoID,
+                TeamID:   teamID,
+                StatusID: statusID,
+                Position: int32(position),
+            })
+            if err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return false, err
+    }
+    return true, nil
+}
@@ -3,6 +3,8 @@ package team_todos_repository
 import (
     "context"
     "database/sql"
+    "fmt"
+    "strings"
     "time"
     "github.com/google/uuid"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
@@ -15,27 +17,24 @@ var _ domain.TeamTodoRepository = (*TeamTodoRepository)(nil)
 
 type TeamTodoRepository struct {
     querier *db.Queries
+    db      *sql.DB
 }
 
 // Implement domain.TeamTodoRepository interface methods
-func (r *TeamTodoRepository) CreateTeamTodo(ctx context.Context, task, description string, done, important bool, teamID, assignedTo string) (string, error) {
+func (r *TeamTodoRepository) CreateTeamTodo(ctx context.Context, task, description string, done, important bool, teamID, assignedTo, createdBy string, date, todoTime time.Time) (string, error) {
     id := uuid.New().String()
     
-    // Use current date and time if none provided
-    nullDate := sql.NullTime{Time: time.Now(), Valid: true}
-    nullTime := sql.NullTime{Time: time.Now(), Valid: true}
-    
-    err := r.querier.CreateTeamTodo(ctx, db.CreateTeamTodoParams{
+    err := r.createTeamTodo(ctx, db.CreateTeamTodoParams{
         ID:          id,
         Task:        task,
         Description: sql.NullString{String: description, Valid: true},
         Done:        done,
         Important:   sql.NullBool{Bool: important, Valid: true},
         TeamID:      teamID,
-        AssignedTo:  sql.NullString{String: assignedTo, Valid: true},
-        Date:        nullDate,
-        Time:        nullTime,
-    })
+        AssignedTo:  nullAssignee(assignedTo),
+        Date:        sql.NullTime{Time: date, Valid: !date.IsZero()},
+        Time:        sql.NullTime{Time: todoTime, Valid: !todoTime.IsZero()},
+    }, createdBy)
     
     if err != nil {
         return "", err
@@ -43,32 +42,153 @@ func (r *TeamTodoRepository) CreateTeamTodo(ctx context.Context, task, descripti
     
     return id, nil
 }
+
+// CreateTeamTodoWithID inserts a team todo keeping the given ID and date/time as they are
+func (r *TeamTodoRepository) CreateTeamTodoWithID(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo, createdBy string, date, todoTime time.Time) error {
+    return r.createTeamTodo(ctx, db.CreateTeamTodoParams{
+        ID:          id,
+        Task:        task,
+        Description: sql.NullString{String: description, Valid: true},
+        Done:        done,
+        Important:   sql.NullBool{Bool: important, Valid: true},
+        TeamID:      teamID,
+        AssignedTo:  nullAssignee(assignedTo),
+        Date:        sql.NullTime{Time: date, Valid: !date.IsZero()},
+        Time:        sql.NullTime{Time: todoTime, Valid: !todoTime.IsZero()},
+    }, createdBy)
+}
+
+// createTeamTodo inserts the todo and its initial assignment in one transaction
+func (r *TeamTodoRepository) createTeamTodo(ctx context.Context, params db.CreateTeamTodoParams, createdBy string) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    
+    qtx := r.querier.WithTx(tx)
+    if err := qtx.CreateTeamTodo(ctx, params); err != nil {
+        return err
+    }
+    if err := replaceAssignee(ctx, qtx, params.ID, params.TeamID, "", params.AssignedTo.String, createdBy); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
 func (r *TeamTodoRepository) GetTeamTodos(ctx context.Context, teamID string) ([]domain.TeamTodo, error) {
     todos, err := r.querier.GetTeamTodos(ctx, teamID)
     if err != nil {
         return nil, err
     }
     
+    assignees, err := r.querier.GetTeamAssignees(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    byTodo := make(map[string][]domain.TeamTodoAssignee)
+    for _, a := range assignees {
+        byTodo[a.TodoID] = append(byTodo[a.TodoID], domain.TeamTodoAssignee{UserID: a.UserID, Username: a.Username, Done: a.Done})
+    }
+    
     // Convert db.TeamTodo to domain.TeamTodo
     domainTodos := make([]domain.TeamTodo, len(todos))
     for i, todo := range todos {
         domainTodos[i] = domain.TeamTodo{
-            ID:          todo.ID,
-            Task:        todo.Task,
-            Description: todo.Description.String,
-            Done:        todo.Done,
-            Important:   todo.Important.Bool,
-            TeamID:      todo.TeamID,
-            AssignedTo:  todo.AssignedTo.String,
-            Date:        todo.Date.Time,
-            Time:        todo.Time.Time,
+            ID:             todo.ID,
+            Task:           todo.Task,
+            Description:    todo.Description.String,
+            Done:           todo.Done,
+            Important:      todo.Important.Bool,
+            TeamID:         todo.TeamID,
+            AssignedTo:     todo.AssignedTo.String,
+            Date:           todo.Date.Time,
+            Time:           todo.Time.Time,
+            Version:        int(todo.Version),
+            CompletionMode: todo.CompletionMode,
+            Assignees:      byTodo[todo.ID],
         }
     }
     
     return domainTodos, nil
 }
 
-func (r *TeamTodoRepository) UpdateTeamTodo(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo string) (bool, error) {
+// GetTeamTodoByID fetches a single todo belonging to the given team
+func (r *TeamTodoRepository) GetTeamTodoByID(ctx context.Context, id, teamID string) (*domain.TeamTodo, error) {
+    var task string
+    var description sql.NullString
+    var done bool
+    var important sql.NullBool
+    var assignedTo sql.NullString
+    var date sql.NullString
+    var timeValue sql.NullString
+    var version int
+    var completionMode string
+    
+    err := r.db.QueryRowContext(ctx, "SELECT task, description, done, important, assigned_to, CAST(date AS CHAR), CAST(time AS CHAR), version, completion_mode FROM team_todos WHERE id = ? AND team_id = ?", id, teamID).
+        Scan(&task, &description, &done, &important, &assignedTo, &date, &timeValue, &version, &completionMode)
+    
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, fmt.Errorf("team todo not found")
+        }
+        return nil, err
+    }
+    
+    // Parse date
+    var dateTime time.Time
+    if date.Valid {
+        parsedDate, err := time.Parse("2006-01-02", date.String)
+        if err == nil {
+            dateTime = parsedDate
+        }
+    }
+    
+    // Parse time
+    var timeVal time.Time
+    if timeValue.Valid {
+        parsedTime, err := time.Parse("15:04:05", timeValue.String)
+        if err == nil {
+            hour, min, sec := parsedTime.Clock()
+            timeVal = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
+        }
+    }
+    
+    assignees, err := teamTodoAssignees(ctx, r.querier, id)
+    if err != nil {
+        return nil, err
+    }
+    
+    return &domain.TeamTodo{
+        ID:             id,
+        Task:           task,
+        Description:    description.String,
+        Done:           done,
+        Important:      important.Bool,
+        TeamID:         teamID,
+        AssignedTo:     assignedTo.String,
+        Date:           dateTime,
+        Time:           timeVal,
+        Version:        version,
+        CompletionMode: completionMode,
+        Assignees:      assignees,
+    }, nil
+}
+
+// GetTeamTodoTeamID returns the ID of the team that owns a todo without filtering by team
+func (r *TeamTodoRepository) GetTeamTodoTeamID(ctx context.Context, id string) (string, error) {
+    var teamID string
+    err := r.db.QueryRowContext(ctx, "SELECT team_id FROM team_todos WHERE id = ?", id).Scan(&teamID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return "", fmt.Errorf("team todo not found")
+        }
+        return "", err
+    }
+    return teamID, nil
+}
+
+func (r *TeamTodoRepository) UpdateTeamTodo(ctx context.Context, id, task, description string, done, important bool, teamID, assignedTo, updatedBy string, due domain.TeamTodoDue, expectedVersion int) (bool, error) {
     // Use your existing DTO and converter
     req := &dto.UpdateTeamTodoRequest{
         ID:          id,
@@ -81,17 +201,186 @@ func (r *TeamTodoRepository) UpdateTeamTodo(ctx context.Context, id, task, descr
     }
     
     params := req.ConvertUpdateTeamTodoDomainRequestToPersistentRequest()
-    err := r.querier.UpdateTeamTodo(ctx, *params)
+    err := r.runVersioned(ctx, id, teamID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        previous, err := currentAssignee(ctx, qtx, id, teamID)
+        if err != nil {
+            return err
+        }
+        completion, err := qtx.GetTeamTodoCompletion(ctx, db.GetTeamTodoCompletionParams{ID: id, TeamID: teamID})
+        if err != nil {
+            return err
+        }
+        if err := qtx.UpdateTeamTodo(ctx, *params); err != nil {
+            return err
+        }
+        if err := syncTeamTodoDone(ctx, qtx, id, teamID, completion.Done, done); err != nil {
+            return err
+        }
+        if err := setTeamTodoDue(ctx, qtx, id, teamID, due); err != nil {
+            return err
+        }
+        return replaceAssignee(ctx, qtx, id, teamID, previous, assignedTo, updatedBy)
+    })
     if err != nil {
         return false, err
     }
     return true, nil
 }
 
-func (r *TeamTodoRepository) DeleteTeamTodo(ctx context.Context, id, teamID string) (bool, error) {
-    err := r.querier.DeleteTeamTodo(ctx, db.DeleteTeamTodoParams{
-        ID:     id,
-        TeamID: teamID,
+// setTeamTodoDue writes the due date and time supplied in due, leaving the others as they are
+func setTeamTodoDue(ctx context.Context, qtx *db.Queries, id, teamID string, due domain.TeamTodoDue) error {
+    if due.ClearDate || due.Date != nil {
+        date := sql.NullTime{}
+        if !due.ClearDate {
+            date = sql.NullTime{Time: *due.Date, Valid: true}
+        }
+        if err := qtx.SetTeamTodoDate(ctx, db.SetTeamTodoDateParams{Date: date, ID: id, TeamID: teamID}); err != nil {
+            return err
+        }
+    }
+    if due.ClearTime || due.Time != nil {
+        todoTime := sql.NullTime{}
+        if !due.ClearTime {
+            todoTime = sql.NullTime{Time: *due.Time, Valid: true}
+        }
+        if err := qtx.SetTeamTodoTime(ctx, db.SetTeamTodoTimeParams{Time: todoTime, ID: id, TeamID: teamID}); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (r *TeamTodoRepository) DeleteTeamTodo(ctx context.Context, id, teamID string, expectedVersion int) (bool, error) {
+    err := r.runVersioned(ctx, id, teamID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        return qtx.DeleteTeamTodo(ctx, db.DeleteTeamTodoParams{
+            ID:     id,
+            TeamID: teamID,
+        })
+    })
+    if err != nil {
+        return false, err
+    }
+    return true, nil
+}
+
+// runVersioned locks the team todo row in a transaction, checks its version and then runs fn
+// in the same transaction so two members cannot overwrite each other's changes
+func (r *TeamTodoRepository) runVersioned(ctx context.Context, id, teamID string, expectedVersion int, fn func(qtx *db.Queries, tx *sql.Tx) error) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    
+    qtx := r.querier.WithTx(tx)
+    if err := checkTeamTodoVersion(ctx, qtx, id, teamID, expectedVersion); err != nil {
+        return err
+    }
+    if err := fn(qtx, tx); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// checkTeamTodoVersion locks the team todo row and compares its version with expectedVersion.
+// An expectedVersion of 0 only checks that the todo exists.
+func checkTeamTodoVersion(ctx context.Context, qtx *db.Queries, id, teamID string, expectedVersion int) error {
+    version, err := qtx.GetTeamTodoVersionForUpdate(ctx, db.GetTeamTodoVersionForUpdateParams{ID: id, TeamID: teamID})
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("team todo not found")
+    }
+    if err != nil {
+        return err
+    }
+    if expectedVersion != 0 && int(version) != expectedVersion {
+        return fmt.Errorf("version mismatch: current version is %d", version)
+    }
+    return nil
+}
+
+// PatchTeamTodo updates only the fields present in the patch
+func (r *TeamTodoRepository) PatchTeamTodo(ctx context.Context, id, teamID, updatedBy string, patch domain.TeamTodoPatch, expectedVersion int) (bool, error) {
+    // Build the SET clause from the supplied fields only
+    var assignments []string
+    var args []interface{}
+    if patch.Task != nil {
+        assignments = append(assignments, "task = ?")
+        args = append(args, *patch.Task)
+    }
+    if patch.ClearDescription {
+        assignments = append(assignments, "description = NULL")
+    } else if patch.Description != nil {
+        assignments = append(assignments, "description = ?")
+        args = append(args, *patch.Description)
+    }
+    if patch.Done != nil {
+        assignments = append(assignments, "done = ?")
+        args = append(args, *patch.Done)
+    }
+    if patch.Important != nil {
+        assignments = append(assignments, "important = ?")
+        args = append(args, *patch.Important)
+    }
+    // An empty assignee unassigns the todo like an explicit null
+    reassigned := patch.ClearAssignedTo || patch.AssignedTo != nil
+    var assignedTo string
+    if patch.AssignedTo != nil && !patch.ClearAssignedTo {
+        assignedTo = *patch.AssignedTo
+    }
+    if reassigned {
+        assignments = append(assignments, "assigned_to = ?")
+        args = append(args, nullAssignee(assignedTo))
+    }
+    if patch.ClearDate {
+        assignments = append(assignments, "date = NULL")
+    } else if patch.Date != nil {
+        assignments = append(assignments, "date = ?")
+        args = append(args, patch.Date.Format("2006-01-02"))
+    }
+    if patch.ClearTime {
+        assignments = append(assignments, "time = NULL")
+    } else if patch.Time != nil {
+        assignments = append(assignments, "time = ?")
+        args = append(args, patch.Time.Format("15:04:05"))
+    }
+    
+    err := r.runVersioned(ctx, id, teamID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        // Nothing to change
+        if len(assignments) == 0 {
+            return nil
+        }
+        
+        var previous string
+        if reassigned {
+            var err error
+            if previous, err = currentAssignee(ctx, qtx, id, teamID); err != nil {
+                return err
+            }
+        }
+        var wasDone bool
+        if patch.Done != nil {
+            completion, err := qtx.GetTeamTodoCompletion(ctx, db.GetTeamTodoCompletionParams{ID: id, TeamID: teamID})
+            if err != nil {
+                return err
+            }
+            wasDone = completion.Done
+        }
+        
+        assignments = append(assignments, "version = version + 1")
+        query := "UPDATE team_todos SET " + strings.Join(assignments, ", ") + " WHERE id = ? AND team_id = ?"
+        args = append(args, id, teamID)
+        if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+            return err
+        }
+        if patch.Done != nil {
+            if err := syncTeamTodoDone(ctx, qtx, id, teamID, wasDone, *patch.Done); err != nil {
+                return err
+            }
+        }
+        if !reassigned {
+            return nil
+        }
+        return replaceAssignee(ctx, qtx, id, teamID, previous, assignedTo, updatedBy)
     })
     if err != nil {
         return false, err
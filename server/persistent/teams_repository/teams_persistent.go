@@ -66,6 +66,25 @@ func (r *TeamRepository) GetTeamByID(ctx context.Context, id string) (domain.Tea
     }, nil
 }
 
+func (r *TeamRepository) GetTeams(ctx context.Context, userID string) ([]domain.Team, error) {
+    teams, err := r.querier.GetTeams(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    
+    domainTeams := make([]domain.Team, len(teams))
+    for i, team := range teams {
+        domainTeams[i] = domain.Team{
+            ID:       team.ID,
+            Name:     team.Name,
+            Password: team.Password,
+            AdminID:  team.AdminID,
+        }
+    }
+    
+    return domainTeams, nil
+}
+
 // Original methods for backward compatibility
 func (r *TeamRepository) CreateTeamWithDTO(ctx context.Context, req *dto.CreateTeamRequest) (*dto.CreateResponse, error) {
     params := req.ConvertCreateTeamDomainRequestToPersistentRequest()
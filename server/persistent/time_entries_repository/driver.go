@@ -0,0 +1,14 @@
+package time_entries_repository
+
+import (
+    "database/sql"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+func NewTimeEntryRepository(DB *sql.DB) *TimeEntryRepository {
+    querier := db.New(DB)
+    return &TimeEntryRepository{
+        querier: querier,
+        db:      DB,
+    }
+}
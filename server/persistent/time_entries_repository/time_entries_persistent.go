@@ -0,0 +1,194 @@
+package time_entries_repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+// Ensure TimeEntryRepository implements domain.TimeEntryRepository
+var _ domain.TimeEntryRepository = (*TimeEntryRepository)(nil)
+
+type TimeEntryRepository struct {
+    querier *db.Queries
+    db      *sql.DB
+}
+
+// timeEntryRow has the column set shared by every time entry query; the
+// generated row types convert to it directly
+type timeEntryRow db.GetUserTimeEntriesRow
+
+func nullString(s string) sql.NullString {
+    return sql.NullString{String: s, Valid: s != ""}
+}
+
+// parseDateTime reads a DATETIME selected with CAST(... AS CHAR)
+func parseDateTime(value interface{}) time.Time {
+    var s string
+    switch v := value.(type) {
+    case time.Time:
+        return v
+    case []byte:
+        s = string(v)
+    case string:
+        s = v
+    }
+    parsed, _ := time.Parse("2006-01-02 15:04:05", s)
+    return parsed
+}
+
+func toDomainTimeEntry(row timeEntryRow) domain.TimeEntry {
+    entry := domain.TimeEntry{
+        ID:        row.ID,
+        UserID:    row.UserID,
+        Username:  row.Username,
+        TodoID:    row.TodoID.String,
+        Task:      row.Task,
+        StartedAt: parseDateTime(row.StartedAt),
+        EndedAt:   parseDateTime(row.EndedAt),
+        Note:      row.Note,
+    }
+    if row.TeamTodoID.Valid {
+        entry.TodoID = row.TeamTodoID.String
+        entry.TeamID = row.TeamID.String
+    }
+    return entry
+}
+
+func (r *TimeEntryRepository) CreateTimeEntry(ctx context.Context, entry domain.TimeEntry) error {
+    params := db.CreateTimeEntryParams{
+        ID:        entry.ID,
+        UserID:    entry.UserID,
+        StartedAt: entry.StartedAt.UTC(),
+        EndedAt:   sql.NullTime{Time: entry.EndedAt.UTC(), Valid: !entry.EndedAt.IsZero()},
+        Note:      entry.Note,
+    }
+    if entry.TeamID != "" {
+        params.TeamID = nullString(entry.TeamID)
+        params.TeamTodoID = nullString(entry.TodoID)
+    } else {
+        params.TodoID = nullString(entry.TodoID)
+    }
+
+    err := r.querier.CreateTimeEntry(ctx, params)
+    if err != nil && strings.Contains(err.Error(), "running_timer") {
+        return fmt.Errorf("timer already running")
+    }
+    return err
+}
+
+func (r *TimeEntryRepository) StopTimer(ctx context.Context, userID string, endedAt time.Time) (*domain.TimeEntry, error) {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+    qtx := r.querier.WithTx(tx)
+
+    row, err := qtx.GetRunningTimeEntryForUpdate(ctx, userID)
+    if err == sql.ErrNoRows {
+        return nil, fmt.Errorf("no running timer")
+    }
+    if err != nil {
+        return nil, err
+    }
+    entry := toDomainTimeEntry(timeEntryRow(row))
+    // A timer cannot end before it started, whatever the clocks say
+    if endedAt.Before(entry.StartedAt) {
+        endedAt = entry.StartedAt
+    }
+    endedAt = endedAt.UTC().Truncate(time.Second)
+
+    if err := qtx.EndTimeEntry(ctx, db.EndTimeEntryParams{EndedAt: sql.NullTime{Time: endedAt, Valid: true}, ID: entry.ID}); err != nil {
+        return nil, err
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    entry.EndedAt = endedAt
+    return &entry, nil
+}
+
+func (r *TimeEntryRepository) GetRunningTimer(ctx context.Context, userID string) (*domain.TimeEntry, error) {
+    row, err := r.querier.GetRunningTimeEntryForUpdate(ctx, userID)
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    entry := toDomainTimeEntry(timeEntryRow(row))
+    return &entry, nil
+}
+
+func (r *TimeEntryRepository) DeleteTimeEntry(ctx context.Context, id, userID string) (bool, error) {
+    affected, err := r.querier.DeleteTimeEntry(ctx, db.DeleteTimeEntryParams{ID: id, UserID: userID})
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+func (r *TimeEntryRepository) GetTodoTimeEntries(ctx context.Context, todoID string) ([]domain.TimeEntry, error) {
+    rows, err := r.querier.GetTodoTimeEntries(ctx, nullString(todoID))
+    if err != nil {
+        return nil, err
+    }
+    entries := make([]domain.TimeEntry, len(rows))
+    for i, row := range rows {
+        entries[i] = toDomainTimeEntry(timeEntryRow(row))
+    }
+    return entries, nil
+}
+
+func (r *TimeEntryRepository) GetTeamTodoTimeEntries(ctx context.Context, teamID, todoID string) ([]domain.TimeEntry, error) {
+    rows, err := r.querier.GetTeamTodoTimeEntries(ctx, db.GetTeamTodoTimeEntriesParams{
+        TeamID:     nullString(teamID),
+        TeamTodoID: nullString(todoID),
+    })
+    if err != nil {
+        return nil, err
+    }
+    entries := make([]domain.TimeEntry, len(rows))
+    for i, row := range rows {
+        entries[i] = toDomainTimeEntry(timeEntryRow(row))
+    }
+    return entries, nil
+}
+
+func (r *TimeEntryRepository) GetUserTimeEntries(ctx context.Context, userID string, from, to time.Time) ([]domain.TimeEntry, error) {
+    rows, err := r.querier.GetUserTimeEntries(ctx, db.GetUserTimeEntriesParams{
+        UserID:   userID,
+        FromTime: from.UTC(),
+        ToTime:   to.UTC(),
+    })
+    if err != nil {
+        return nil, err
+    }
+    entries := make([]domain.TimeEntry, len(rows))
+    for i, row := range rows {
+        entries[i] = toDomainTimeEntry(timeEntryRow(row))
+    }
+    return entries, nil
+}
+
+func (r *TimeEntryRepository) GetTeamTimeEntries(ctx context.Context, teamID string, from, to time.Time) ([]domain.TimeEntry, error) {
+    rows, err := r.querier.GetTeamTimeEntries(ctx, db.GetTeamTimeEntriesParams{
+        TeamID:   nullString(teamID),
+        FromTime: from.UTC(),
+        ToTime:   to.UTC(),
+    })
+    if err != nil {
+        return nil, err
+    }
+    entries := make([]domain.TimeEntry, len(rows))
+    for i, row := range rows {
+        entries[i] = toDomainTimeEntry(timeEntryRow(row))
+    }
+    return entries, nil
+}
@@ -0,0 +1,78 @@
+package todos_repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+
+    "github.com/google/uuid"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+// ExecuteBatch runs a list of todo operations for a user inside one transaction.
+// It returns the per-operation results and whether the transaction was committed.
+func (r *TodoRepository) ExecuteBatch(ctx context.Context, userID string, ops []domain.BatchOperation, atomic bool) ([]domain.BatchResult, bool, error) {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, false, err
+    }
+    defer tx.Rollback()
+
+    qtx := r.querier.WithTx(tx)
+    results, commit := domain.RunBatch(ops, atomic, func(op domain.BatchOperation) (string, error) {
+        return r.applyBatchOperation(ctx, qtx, userID, op)
+    })
+    if !commit {
+        return results, false, nil
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, false, err
+    }
+    return results, true, nil
+}
+
+func (r *TodoRepository) applyBatchOperation(ctx context.Context, qtx *db.Queries, userID string, op domain.BatchOperation) (string, error) {
+    owner := sql.NullString{String: userID, Valid: true}
+
+    if op.Op == domain.BatchOpCreate {
+        id := uuid.New().String()
+        err := qtx.CreateTodo(ctx, db.CreateTodoParams{
+            ID:          id,
+            Task:        op.Task,
+            Description: sql.NullString{String: op.Description, Valid: true},
+            Done:        op.Done,
+            Important:   op.Important,
+            UserID:      owner,
+            Date:        sql.NullTime{Time: op.Date, Valid: true},
+            Time:        sql.NullTime{Time: op.Time, Valid: true},
+        })
+        return id, err
+    }
+
+    // Every other operation targets an existing todo owned by the user
+    if err := checkTodoVersion(ctx, qtx, op.ID, userID, op.Version); err != nil {
+        return op.ID, err
+    }
+
+    var err error
+    switch op.Op {
+    case domain.BatchOpUpdate:
+        err = qtx.UpdateTodo(ctx, db.UpdateTodoParams{
+            ID:          op.ID,
+            Task:        op.Task,
+            Description: sql.NullString{String: op.Description, Valid: true},
+            Done:        op.Done,
+            Important:   op.Important,
+            UserID:      owner,
+        })
+    case domain.BatchOpComplete:
+        err = qtx.CompleteTodo(ctx, db.CompleteTodoParams{ID: op.ID, UserID: owner})
+    case domain.BatchOpDelete:
+        err = qtx.DeleteTodo(ctx, db.DeleteTodoParams{ID: op.ID, UserID: owner})
+    default:
+        err = fmt.Errorf("unsupported operation %q", op.Op)
+    }
+    return op.ID, err
+}
@@ -6,6 +6,7 @@ import (
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
     "time"
     "fmt"
+    "strings"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
     "github.com/google/uuid"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
@@ -59,6 +60,20 @@ func (r *TodoRepository) CreateTodo(ctx context.Context, task, description strin
     return id, nil
 }   
 
+// CreateTodoWithID inserts a todo keeping the given ID and date/time as they are
+func (r *TodoRepository) CreateTodoWithID(ctx context.Context, id, task, description string, done, important bool, userID string, date, todoTime time.Time) error {
+    return r.querier.CreateTodo(ctx, db.CreateTodoParams{
+        ID:          id,
+        Task:        task,
+        Description: sql.NullString{String: description, Valid: true},
+        Done:        done,
+        Important:   important,
+        UserID:      sql.NullString{String: userID, Valid: true},
+        Date:        sql.NullTime{Time: date, Valid: !date.IsZero()},
+        Time:        sql.NullTime{Time: todoTime, Valid: !todoTime.IsZero()},
+    })
+}
+
 func (r *TodoRepository) GetTodoByID(ctx context.Context, id string) (*domain.Todo, error) {
     // Using a query to get a todo by ID
     var task string
@@ -69,8 +84,10 @@ func (r *TodoRepository) GetTodoByID(ctx context.Context, id string) (*domain.To
     var date sql.NullString
     var timeValue sql.NullString
     
-    err := r.db.QueryRowContext(ctx, "SELECT task, description, done, important, user_id, CAST(date AS CHAR), CAST(time AS CHAR) FROM todos WHERE id = ?", id).
-        Scan(&task, &description, &done, &important, &userID, &date, &timeValue)
+    var version int
+    
+    err := r.db.QueryRowContext(ctx, "SELECT task, description, done, important, user_id, CAST(date AS CHAR), CAST(time AS CHAR), version FROM todos WHERE id = ?", id).
+        Scan(&task, &description, &done, &important, &userID, &date, &timeValue, &version)
     
     if err != nil {
         if err == sql.ErrNoRows {
@@ -108,6 +125,7 @@ func (r *TodoRepository) GetTodoByID(ctx context.Context, id string) (*domain.To
         UserID:      userID.String,
         Date:        dateTime,
         Time:        timeVal,
+        Version:     version,
     }, nil
 }
 
@@ -133,6 +151,7 @@ func (r *TodoRepository) GetTodosByUserID(ctx context.Context, userID string) ([
             Done:        todo.Done,
             Important:   todo.Important,
             UserID:      todo.UserID.String,
+            Version:     int(todo.Version),
             // Skip date/time for now to see if code works otherwise
         }
         
@@ -157,7 +176,7 @@ func (r *TodoRepository) GetTodosByUserID(ctx context.Context, userID string) ([
     return domainTodos, nil
 }
 
-func (r *TodoRepository) UpdateTodo(ctx context.Context, id, task, description string, done, important bool, userID string) (bool, error) {
+func (r *TodoRepository) UpdateTodo(ctx context.Context, id, task, description string, done, important bool, userID string, expectedVersion int) (bool, error) {
     // Use your existing DTO and converter
     req := &dto.UpdateTodoRequest{
         ID:          id,
@@ -169,17 +188,21 @@ func (r *TodoRepository) UpdateTodo(ctx context.Context, id, task, description s
     }
     
     params := req.ConvertUpdateTodoDomainRequestToPersistentRequest()
-    err := r.querier.UpdateTodo(ctx, *params)
+    err := r.runVersioned(ctx, id, userID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        return qtx.UpdateTodo(ctx, *params)
+    })
     if err != nil {
         return false, err
     }
     return true, nil
 }
 
-func (r *TodoRepository) DeleteTodo(ctx context.Context, id, userID string) (bool, error) {
-    err := r.querier.DeleteTodo(ctx, db.DeleteTodoParams{
-        ID:     id,
-        UserID: sql.NullString{String: userID, Valid: true},
+func (r *TodoRepository) DeleteTodo(ctx context.Context, id, userID string, expectedVersion int) (bool, error) {
+    err := r.runVersioned(ctx, id, userID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        return qtx.DeleteTodo(ctx, db.DeleteTodoParams{
+            ID:     id,
+            UserID: sql.NullString{String: userID, Valid: true},
+        })
     })
     if err != nil {
         return false, err
@@ -187,10 +210,12 @@ func (r *TodoRepository) DeleteTodo(ctx context.Context, id, userID string) (boo
     return true, nil
 }
 
-func (r *TodoRepository) UndoTodo(ctx context.Context, id, userID string) (bool, error) {
-    err := r.querier.UndoTodo(ctx, db.UndoTodoParams{
-        ID:     id,
-        UserID: sql.NullString{String: userID, Valid: true},
+func (r *TodoRepository) UndoTodo(ctx context.Context, id, userID string, expectedVersion int) (bool, error) {
+    err := r.runVersioned(ctx, id, userID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        return qtx.UndoTodo(ctx, db.UndoTodoParams{
+            ID:     id,
+            UserID: sql.NullString{String: userID, Valid: true},
+        })
     })
     if err != nil {
         return false, err
@@ -198,6 +223,72 @@ func (r *TodoRepository) UndoTodo(ctx context.Context, id, userID string) (bool,
     return true, nil
 }
 
+// runVersioned locks the todo row in a transaction, checks its version and then runs fn
+// in the same transaction so no other writer can slip in between the check and the write
+func (r *TodoRepository) runVersioned(ctx context.Context, id, userID string, expectedVersion int, fn func(qtx *db.Queries, tx *sql.Tx) error) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    
+    qtx := r.querier.WithTx(tx)
+    if err := checkTodoVersion(ctx, qtx, id, userID, expectedVersion); err != nil {
+        return err
+    }
+    if err := fn(qtx, tx); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// SetTodoDetails stores the tags and, unless nil, the recurrence of a todo in one transaction
+func (r *TodoRepository) SetTodoDetails(ctx context.Context, todoID string, tags []string, recurrence *domain.TodoRecurrence) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    
+    qtx := r.querier.WithTx(tx)
+    for _, tag := range tags {
+        if err := qtx.AddTodoTag(ctx, db.AddTodoTagParams{TodoID: todoID, Tag: tag}); err != nil {
+            return err
+        }
+    }
+    if recurrence != nil {
+        err := qtx.SetTodoRecurrence(ctx, db.SetTodoRecurrenceParams{
+            TodoID:        todoID,
+            Frequency:     db.TodoRecurrencesFrequency(recurrence.Frequency),
+            IntervalCount: int32(recurrence.Interval),
+            Weekdays:      strings.Join(recurrence.Weekdays, ","),
+        })
+        if err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
+}
+
+// checkTodoVersion locks the todo row and compares its version with expectedVersion.
+// An expectedVersion of 0 only checks that the todo exists.
+func checkTodoVersion(ctx context.Context, qtx *db.Queries, id, userID string, expectedVersion int) error {
+    version, err := qtx.GetTodoVersionForUpdate(ctx, db.GetTodoVersionForUpdateParams{
+        ID:     id,
+        UserID: sql.NullString{String: userID, Valid: true},
+    })
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("todo not found")
+    }
+    if err != nil {
+        return err
+    }
+    if expectedVersion != 0 && int(version) != expectedVersion {
+        return fmt.Errorf("version mismatch: current version is %d", version)
+    }
+    return nil
+}
+
 // Original methods for backward compatibility - you can keep these if you want
 func (r *TodoRepository) CreateTodoWithDTO(ctx context.Context, req *dto.CreateTodoRequest) (*dto.CreateResponse, error) {
     params := req.ConvertCreateTodoDomainRequestToPersistentRequest()
@@ -283,4 +374,57 @@ func (r *TodoRepository) UndoTodoWithDTO(ctx context.Context, id, userID string)
         return nil, err
     }
     return &dto.SuccessResponse{Success: true}, nil
-}
\ No newline at end of file
+}
+// PatchTodo updates only the fields present in the patch
+func (r *TodoRepository) PatchTodo(ctx context.Context, id, userID string, patch domain.TodoPatch, expectedVersion int) (bool, error) {
+    // Build the SET clause from the supplied fields only
+    var assignments []string
+    var args []interface{}
+    if patch.Task != nil {
+        assignments = append(assignments, "task = ?")
+        args = append(args, *patch.Task)
+    }
+    if patch.ClearDescription {
+        assignments = append(assignments, "description = NULL")
+    } else if patch.Description != nil {
+        assignments = append(assignments, "description = ?")
+        args = append(args, *patch.Description)
+    }
+    if patch.Done != nil {
+        assignments = append(assignments, "done = ?")
+        args = append(args, *patch.Done)
+    }
+    if patch.Important != nil {
+        assignments = append(assignments, "important = ?")
+        args = append(args, *patch.Important)
+    }
+    if patch.ClearDate {
+        assignments = append(assignments, "date = NULL")
+    } else if patch.Date != nil {
+        assignments = append(assignments, "date = ?")
+        args = append(args, patch.Date.Format("2006-01-02"))
+    }
+    if patch.ClearTime {
+        assignments = append(assignments, "time = NULL")
+    } else if patch.Time != nil {
+        assignments = append(assignments, "time = ?")
+        args = append(args, patch.Time.Format("15:04:05"))
+    }
+    
+    err := r.runVersioned(ctx, id, userID, expectedVersion, func(qtx *db.Queries, tx *sql.Tx) error {
+        // Nothing to change
+        if len(assignments) == 0 {
+            return nil
+        }
+        
+        assignments = append(assignments, "version = version + 1")
+        query := "UPDATE todos SET " + strings.Join(assignments, ", ") + " WHERE id = ? AND user_id = ?"
+        args = append(args, id, userID)
+        _, err := tx.ExecContext(ctx, query, args...)
+        return err
+    })
+    if err != nil {
+        return false, err
+    }
+    return true, nil
+}
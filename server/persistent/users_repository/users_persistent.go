@@ -2,6 +2,8 @@ package users_repository
 
 import (
     "context"
+    "database/sql"
+    "fmt"
     
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
@@ -41,9 +43,33 @@ func (r *UserRepository) GetUserByUsername(ctx context.Context, username string)
         ID:       user.ID,
         Username: user.Username,
         Password: user.Password,
+        Timezone: user.Timezone,
     }, nil
 }
 
+func (r *UserRepository) GetUserTimezone(ctx context.Context, userID string) (string, error) {
+    timezone, err := r.querier.GetUserTimezone(ctx, userID)
+    if err == sql.ErrNoRows {
+        return "", fmt.Errorf("user not found")
+    }
+    return timezone, err
+}
+
+func (r *UserRepository) SetUserTimezone(ctx context.Context, userID, timezone string) error {
+    affected, err := r.querier.SetUserTimezone(ctx, db.SetUserTimezoneParams{Timezone: timezone, ID: userID})
+    if err != nil {
+        return err
+    }
+    // MySQL reports no affected rows when the value is unchanged, so only a
+    // missing user is treated as an error
+    if affected == 0 {
+        if _, err := r.GetUserTimezone(ctx, userID); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
 // Original methods for backward compatibility
 func (r *UserRepository) CreateUserWithDTO(ctx context.Context, req *dto.CreateUserRequest) (*dto.CreateResponse, error) {
     params := req.ConvertCreateUserDomainRequestToPersistentRequest()
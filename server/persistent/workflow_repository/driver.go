@@ -0,0 +1,14 @@
+package workflow_repository
+
+import (
+    "database/sql"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+func NewWorkflowRepository(DB *sql.DB) *WorkflowRepository {
+    querier := db.New(DB)
+    return &WorkflowRepository{
+        querier: querier,
+        db:      DB,
+    }
+}
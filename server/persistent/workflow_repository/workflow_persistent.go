@@ -0,0 +1,160 @@
+package workflow_repository
+
+import (
+    "context"
+    "database/sql"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+// Ensure WorkflowRepository implements domain.WorkflowRepository
+var _ domain.WorkflowRepository = (*WorkflowRepository)(nil)
+
+type WorkflowRepository struct {
+    querier *db.Queries
+    db      *sql.DB
+}
+
+func (r *WorkflowRepository) GetTeamStatuses(ctx context.Context, teamID string) ([]domain.TeamStatus, error) {
+    rows, err := r.querier.GetTeamStatuses(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    statuses := make([]domain.TeamStatus, len(rows))
+    for i, row := range rows {
+        statuses[i] = domain.TeamStatus{
+            ID:       row.ID,
+            TeamID:   row.TeamID,
+            Name:     row.Name,
+            Position: int(row.Position),
+            Terminal: row.IsTerminal,
+        }
+    }
+    return statuses, nil
+}
+
+func (r *WorkflowRepository) GetStatusTransitions(ctx context.Context, teamID string) ([]domain.StatusTransition, error) {
+    rows, err := r.querier.GetTeamStatusTransitions(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    transitions := make([]domain.StatusTransition, len(rows))
+    for i, row := range rows {
+        transitions[i] = domain.StatusTransition{FromStatusID: row.FromStatusID, ToStatusID: row.ToStatusID}
+    }
+    return transitions, nil
+}
+
+func (r *WorkflowRepository) ReplaceTeamWorkflow(ctx context.Context, teamID string, statuses []domain.TeamStatus, transitions []domain.StatusTransition) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+    qtx := r.querier.WithTx(tx)
+
+    if _, err := qtx.GetTeamForUpdate(ctx, teamID); err != nil {
+        return err
+    }
+    existing, err := qtx.GetTeamStatuses(ctx, teamID)
+    if err != nil {
+        return err
+    }
+    keep := make(map[string]bool, len(statuses))
+    for _, status := range statuses {
+        keep[status.ID] = true
+    }
+
+    // Removed statuses go first so their names can be reused by new ones
+    if err := qtx.DeleteTeamStatusTransitions(ctx, teamID); err != nil {
+        return err
+    }
+    for _, status := range existing {
+        if keep[status.ID] {
+            continue
+        }
+        if err := qtx.DeleteTeamStatus(ctx, db.DeleteTeamStatusParams{ID: status.ID, TeamID: teamID}); err != nil {
+            return err
+        }
+    }
+    for _, status := range statuses {
+        err := qtx.UpsertTeamStatus(ctx, db.UpsertTeamStatusParams{
+            ID:         status.ID,
+            TeamID:     teamID,
+            Name:       status.Name,
+            Position:   int32(status.Position),
+            IsTerminal: status.Terminal,
+        })
+        if err != nil {
+            return err
+        }
+    }
+    for _, transition := range transitions {
+        err := qtx.AddTeamStatusTransition(ctx, db.AddTeamStatusTransitionParams{
+            TeamID:       teamID,
+            FromStatusID: transition.FromStatusID,
+            ToStatusID:   transition.ToStatusID,
+        })
+        if err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
+}
+
+func (r *WorkflowRepository) EnsureTeamWorkflow(ctx context.Context, teamID string, statuses []domain.TeamStatus) ([]domain.TeamStatus, error) {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+    qtx := r.querier.WithTx(tx)
+
+    // The team row serializes first writes; a status row may not exist yet to lock
+    if _, err := qtx.GetTeamForUpdate(ctx, teamID); err != nil {
+        return nil, err
+    }
+    rows, err := qtx.GetTeamStatuses(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    if len(rows) > 0 {
+        existing := make([]domain.TeamStatus, len(rows))
+        for i, row := range rows {
+            existing[i] = domain.TeamStatus{
+                ID:       row.ID,
+                TeamID:   row.TeamID,
+                Name:     row.Name,
+                Position: int(row.Position),
+                Terminal: row.IsTerminal,
+            }
+        }
+        return existing, tx.Commit()
+    }
+    for _, status := range statuses {
+        err := qtx.UpsertTeamStatus(ctx, db.UpsertTeamStatusParams{
+            ID:         status.ID,
+            TeamID:     teamID,
+            Name:       status.Name,
+            Position:   int32(status.Position),
+            IsTerminal: status.Terminal,
+        })
+        if err != nil {
+            return nil, err
+        }
+    }
+    return statuses, tx.Commit()
+}
+
+func (r *WorkflowRepository) GetTeamTodoPlacements(ctx context.Context, teamID string) ([]domain.TeamTodoPlacement, error) {
+    rows, err := r.querier.GetTeamTodoPlacements(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    placements := make([]domain.TeamTodoPlacement, len(rows))
+    for i, row := range rows {
+        placements[i] = domain.TeamTodoPlacement{TodoID: row.TodoID, StatusID: row.StatusID, Position: int(row.Position)}
+    }
+    return placements, nil
+}
@@ -0,0 +1,11 @@
+package workload_repository
+
+import (
+    "database/sql"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+)
+
+func NewWorkloadRepository(DB *sql.DB) *WorkloadRepository {
+    querier := db.New(DB)
+    return &WorkloadRepository{querier: querier}
+}
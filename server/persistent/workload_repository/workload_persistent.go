@@ -0,0 +1,98 @@
+package workload_repository
+
+import (
+    "context"
+    "strconv"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/models/db"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+// Ensure WorkloadRepository implements domain.WorkloadRepository
+var _ domain.WorkloadRepository = (*WorkloadRepository)(nil)
+
+type WorkloadRepository struct {
+    querier *db.Queries
+}
+
+// castString reads a column selected with CAST(... AS CHAR)
+func castString(value interface{}) string {
+    switch v := value.(type) {
+    case []byte:
+        return string(v)
+    case string:
+        return v
+    }
+    return ""
+}
+
+func (r *WorkloadRepository) SetTeamTodoEstimate(ctx context.Context, teamID string, estimate domain.Estimate) error {
+    return r.querier.SetTeamTodoEstimate(ctx, db.SetTeamTodoEstimateParams{
+        TodoID:   estimate.TodoID,
+        TeamID:   teamID,
+        Estimate: strconv.FormatFloat(estimate.Value, 'f', 2, 64),
+        Unit:     db.TeamTodoEstimatesUnit(estimate.Unit),
+    })
+}
+
+func (r *WorkloadRepository) DeleteTeamTodoEstimate(ctx context.Context, teamID, todoID string) (bool, error) {
+    affected, err := r.querier.DeleteTeamTodoEstimate(ctx, db.DeleteTeamTodoEstimateParams{TodoID: todoID, TeamID: teamID})
+    if err != nil {
+        return false, err
+    }
+    return affected > 0, nil
+}
+
+func (r *WorkloadRepository) GetTeamTodoEstimates(ctx context.Context, teamID string) ([]domain.Estimate, error) {
+    rows, err := r.querier.GetTeamTodoEstimates(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    estimates := make([]domain.Estimate, len(rows))
+    for i, row := range rows {
+        value, _ := strconv.ParseFloat(row.Estimate, 64)
+        estimates[i] = domain.Estimate{TodoID: row.TodoID, Value: value, Unit: string(row.Unit)}
+    }
+    return estimates, nil
+}
+
+func (r *WorkloadRepository) GetTeamWorkloadTodos(ctx context.Context, teamID string) ([]domain.WorkloadTodo, error) {
+    rows, err := r.querier.GetTeamWorkloadTodos(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    todos := make([]domain.WorkloadTodo, len(rows))
+    for i, row := range rows {
+        todo := domain.WorkloadTodo{
+            ID:           row.ID,
+            Done:         row.Done,
+            AssignedTo:   row.AssignedTo.String,
+            AssigneeName: row.Username.String,
+        }
+        if d := castString(row.Date); d != "" {
+            todo.Date, _ = time.Parse("2006-01-02", d)
+        }
+        if t := castString(row.Time); t != "" {
+            todo.Time, _ = time.Parse("15:04:05", t)
+        }
+        if row.Estimate.Valid {
+            value, _ := strconv.ParseFloat(row.Estimate.String, 64)
+            todo.Estimate = &domain.Estimate{TodoID: row.ID, Value: value, Unit: string(row.Unit.TeamTodoEstimatesUnit)}
+        }
+        todos[i] = todo
+    }
+    return todos, nil
+}
+
+func (r *WorkloadRepository) GetTeamMemberDetails(ctx context.Context, teamID string) ([]domain.TeamMemberDetails, error) {
+    rows, err := r.querier.GetTeamMemberDetails(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    members := make([]domain.TeamMemberDetails, len(rows))
+    for i, row := range rows {
+        members[i] = domain.TeamMemberDetails{UserID: row.ID, Username: row.Username, IsAdmin: row.IsAdmin.Bool}
+    }
+    return members, nil
+}
@@ -0,0 +1,486 @@
+// Package quickadd parses free-form todo text such as
+// "Pay rent tomorrow 9am !high #home @alice every month" into its parts.
+package quickadd
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Priority levels, written as "!high", "!medium" or "!low" (also "!1".."!3" or "!!")
+const (
+    PriorityHigh   = "high"
+    PriorityMedium = "medium"
+    PriorityLow    = "low"
+)
+
+// Recurrence frequencies
+const (
+    FrequencyDaily   = "daily"
+    FrequencyWeekly  = "weekly"
+    FrequencyMonthly = "monthly"
+    FrequencyYearly  = "yearly"
+)
+
+// Recurrence describes a repeat rule such as "every 2 weeks" or "every monday and friday"
+type Recurrence struct {
+    Frequency string
+    Interval  int
+    Weekdays  []string // lowercase day names, only for weekly rules
+}
+
+// Result is what Parse recognised in the text
+type Result struct {
+    Task       string
+    Date       time.Time // midnight of the due date, zero when no date was given
+    Time       time.Time // clock time on 2000-01-01 UTC, zero when no time was given
+    Priority   string
+    Tags       []string
+    Mentions   []string // usernames written as @name
+    Recurrence *Recurrence
+}
+
+// matcher tries to recognise a phrase at the start of tokens and returns how many
+// tokens it consumed, or 0 when it does not apply
+type matcher func(p *parser, tokens []string) int
+
+type parser struct {
+    now    time.Time
+    result Result
+}
+
+var priorityWords = map[string]string{
+    "!":          PriorityHigh,
+    "!!":         PriorityHigh,
+    "!!!":        PriorityHigh,
+    "!high":      PriorityHigh,
+    "!h":         PriorityHigh,
+    "!1":         PriorityHigh,
+    "!urgent":    PriorityHigh,
+    "!important": PriorityHigh,
+    "!medium":    PriorityMedium,
+    "!med":       PriorityMedium,
+    "!m":         PriorityMedium,
+    "!2":         PriorityMedium,
+    "!low":       PriorityLow,
+    "!l":         PriorityLow,
+    "!3":         PriorityLow,
+}
+
+var weekdays = map[string]time.Weekday{
+    "sunday": time.Sunday, "sun": time.Sunday,
+    "monday": time.Monday, "mon": time.Monday,
+    "tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+    "wednesday": time.Wednesday, "wed": time.Wednesday,
+    "thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+    "friday": time.Friday, "fri": time.Friday,
+    "saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+    "january": time.January, "jan": time.January,
+    "february": time.February, "feb": time.February,
+    "march": time.March, "mar": time.March,
+    "april": time.April, "apr": time.April,
+    "may": time.May,
+    "june": time.June, "jun": time.June,
+    "july": time.July, "jul": time.July,
+    "august": time.August, "aug": time.August,
+    "september": time.September, "sep": time.September, "sept": time.September,
+    "october": time.October, "oct": time.October,
+    "november": time.November, "nov": time.November,
+    "december": time.December, "dec": time.December,
+}
+
+var (
+    clockPattern  = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+    ordinalSuffix = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+)
+
+// Parse splits text into a todo title and the date, time, priority, tags,
+// mentions and recurrence written in it. Relative dates are resolved against
+// now, in now's location. Words that are not recognised stay in the title.
+func Parse(text string, now time.Time) (*Result, error) {
+    p := &parser{now: now}
+    matchers := []matcher{
+        matchPriority,
+        matchTag,
+        matchMention,
+        matchRecurrence,
+        matchDate,
+        matchTime,
+    }
+
+    var words []string
+    tokens := strings.Fields(text)
+    for i := 0; i < len(tokens); {
+        consumed := 0
+        for _, match := range matchers {
+            if consumed = match(p, tokens[i:]); consumed > 0 {
+                break
+            }
+        }
+        if consumed == 0 {
+            words = append(words, tokens[i])
+            consumed = 1
+        }
+        i += consumed
+    }
+
+    p.result.Task = strings.TrimSpace(strings.Join(words, " "))
+    if p.result.Task == "" {
+        return nil, fmt.Errorf("task cannot be empty")
+    }
+
+    // "every monday" without a date starts on the next monday
+    if r := p.result.Recurrence; r != nil && p.result.Date.IsZero() && len(r.Weekdays) > 0 {
+        p.result.Date = p.nextWeekday(weekdays[r.Weekdays[0]], true)
+    }
+    return &p.result, nil
+}
+
+// normalize lowercases a token and drops trailing punctuation
+func normalize(token string) string {
+    return strings.ToLower(strings.TrimRight(token, ",.;"))
+}
+
+func matchPriority(p *parser, tokens []string) int {
+    priority, ok := priorityWords[normalize(tokens[0])]
+    if !ok {
+        return 0
+    }
+    p.result.Priority = priority
+    return 1
+}
+
+func matchTag(p *parser, tokens []string) int {
+    tag := strings.TrimRight(tokens[0], ",.;")
+    if len(tag) < 2 || tag[0] != '#' {
+        return 0
+    }
+    p.result.Tags = append(p.result.Tags, tag[1:])
+    return 1
+}
+
+func matchMention(p *parser, tokens []string) int {
+    mention := strings.TrimRight(tokens[0], ",.;")
+    if len(mention) < 2 || mention[0] != '@' {
+        return 0
+    }
+    p.result.Mentions = append(p.result.Mentions, mention[1:])
+    return 1
+}
+
+func matchRecurrence(p *parser, tokens []string) int {
+    switch normalize(tokens[0]) {
+    case "daily":
+        p.result.Recurrence = &Recurrence{Frequency: FrequencyDaily, Interval: 1}
+        return 1
+    case "weekly":
+        p.result.Recurrence = &Recurrence{Frequency: FrequencyWeekly, Interval: 1}
+        return 1
+    case "monthly":
+        p.result.Recurrence = &Recurrence{Frequency: FrequencyMonthly, Interval: 1}
+        return 1
+    case "yearly", "annually":
+        p.result.Recurrence = &Recurrence{Frequency: FrequencyYearly, Interval: 1}
+        return 1
+    case "every":
+    default:
+        return 0
+    }
+    if len(tokens) < 2 {
+        return 0
+    }
+
+    next := normalize(tokens[1])
+    if next == "weekday" || next == "weekdays" {
+        p.result.Recurrence = &Recurrence{
+            Frequency: FrequencyWeekly,
+            Interval:  1,
+            Weekdays:  []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+        }
+        return 2
+    }
+
+    // every monday [and friday], every mon, wed and fri, every mon,wed,fri
+    if days, consumed := weekdayList(tokens[1:]); consumed > 0 {
+        p.result.Recurrence = &Recurrence{Frequency: FrequencyWeekly, Interval: 1, Weekdays: days}
+        return consumed + 1
+    }
+
+    // every [N] day(s)|week(s)|month(s)|year(s)
+    interval, consumed := 1, 1
+    if n, err := strconv.Atoi(next); err == nil && n > 0 && len(tokens) > 2 {
+        interval, consumed = n, 2
+    }
+    frequency, ok := unitFrequency(normalize(tokens[consumed]))
+    if !ok {
+        return 0
+    }
+    p.result.Recurrence = &Recurrence{Frequency: frequency, Interval: interval}
+    return consumed + 1
+}
+
+// weekdayList reads weekdays separated by commas or "and" from the start of
+// tokens and returns their names and how many tokens they span
+func weekdayList(tokens []string) ([]string, int) {
+    days, ok := weekdayToken(tokens[0])
+    if !ok {
+        return nil, 0
+    }
+    consumed := 1
+    for consumed < len(tokens) {
+        next := consumed
+        if normalize(tokens[next]) == "and" {
+            next++
+        } else if !strings.HasSuffix(tokens[consumed-1], ",") {
+            break
+        }
+        if next >= len(tokens) {
+            break
+        }
+        more, ok := weekdayToken(tokens[next])
+        if !ok {
+            break
+        }
+        days = append(days, more...)
+        consumed = next + 1
+    }
+    return days, consumed
+}
+
+// weekdayToken reads a token holding one weekday or several joined by commas
+func weekdayToken(token string) ([]string, bool) {
+    var days []string
+    for _, part := range strings.Split(strings.TrimRight(token, ",.;"), ",") {
+        day, ok := weekdays[strings.ToLower(part)]
+        if !ok {
+            return nil, false
+        }
+        days = append(days, strings.ToLower(day.String()))
+    }
+    return days, true
+}
+
+func unitFrequency(unit string) (string, bool) {
+    switch strings.TrimSuffix(unit, "s") {
+    case "day":
+        return FrequencyDaily, true
+    case "week":
+        return FrequencyWeekly, true
+    case "month":
+        return FrequencyMonthly, true
+    case "year":
+        return FrequencyYearly, true
+    }
+    return "", false
+}
+
+func matchDate(p *parser, tokens []string) int {
+    // Connectors are only consumed together with the date that follows them
+    switch normalize(tokens[0]) {
+    case "on", "by", "due":
+        if len(tokens) > 1 {
+            if n := p.parseDate(tokens[1:]); n > 0 {
+                return n + 1
+            }
+        }
+        return 0
+    }
+    return p.parseDate(tokens)
+}
+
+func (p *parser) parseDate(tokens []string) int {
+    today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+    first := normalize(tokens[0])
+    second := ""
+    if len(tokens) > 1 {
+        second = normalize(tokens[1])
+    }
+
+    switch first {
+    case "today":
+        p.result.Date = today
+        return 1
+    case "tonight":
+        p.result.Date = today
+        if p.result.Time.IsZero() {
+            p.result.Time = clock(20, 0)
+        }
+        return 1
+    case "tomorrow", "tmrw", "tmr":
+        p.result.Date = today.AddDate(0, 0, 1)
+        return 1
+    case "next":
+        switch second {
+        case "week":
+            p.result.Date = today.AddDate(0, 0, 7)
+            return 2
+        case "month":
+            p.result.Date = today.AddDate(0, 1, 0)
+            return 2
+        }
+        if day, ok := weekdays[second]; ok {
+            p.result.Date = p.nextWeekday(day, false)
+            return 2
+        }
+        return 0
+    case "this":
+        if day, ok := weekdays[second]; ok {
+            p.result.Date = p.nextWeekday(day, true)
+            return 2
+        }
+        return 0
+    case "in":
+        // in N days|weeks|months, or in a week
+        if len(tokens) < 3 {
+            return 0
+        }
+        n, err := strconv.Atoi(second)
+        if second == "a" || second == "an" {
+            n, err = 1, nil
+        }
+        if err != nil || n < 1 {
+            return 0
+        }
+        switch strings.TrimSuffix(normalize(tokens[2]), "s") {
+        case "day":
+            p.result.Date = today.AddDate(0, 0, n)
+        case "week":
+            p.result.Date = today.AddDate(0, 0, 7*n)
+        case "month":
+            p.result.Date = today.AddDate(0, n, 0)
+        default:
+            return 0
+        }
+        return 3
+    }
+
+    if day, ok := weekdays[first]; ok {
+        p.result.Date = p.nextWeekday(day, false)
+        return 1
+    }
+    if date, err := time.ParseInLocation("2006-01-02", first, p.now.Location()); err == nil {
+        p.result.Date = date
+        return 1
+    }
+
+    // may 5 or 5 may
+    if month, ok := months[first]; ok && second != "" {
+        if day, ok := dayOfMonth(second); ok {
+            p.result.Date = p.nextDate(month, day)
+            return 2
+        }
+    }
+    if day, ok := dayOfMonth(first); ok {
+        if month, ok := months[second]; ok {
+            p.result.Date = p.nextDate(month, day)
+            return 2
+        }
+    }
+    return 0
+}
+
+func matchTime(p *parser, tokens []string) int {
+    if normalize(tokens[0]) == "at" {
+        if len(tokens) > 1 {
+            if n := p.parseTime(tokens[1:]); n > 0 {
+                return n + 1
+            }
+        }
+        return 0
+    }
+    return p.parseTime(tokens)
+}
+
+func (p *parser) parseTime(tokens []string) int {
+    first := normalize(tokens[0])
+    switch first {
+    case "noon", "midday":
+        p.result.Time = clock(12, 0)
+        return 1
+    case "midnight":
+        p.result.Time = clock(0, 0)
+        return 1
+    }
+
+    // "9 am" is written as two tokens
+    consumed := 1
+    if len(tokens) > 1 {
+        if next := normalize(tokens[1]); next == "am" || next == "pm" {
+            first += next
+            consumed = 2
+        }
+    }
+
+    m := clockPattern.FindStringSubmatch(first)
+    if m == nil {
+        return 0
+    }
+    // A bare number is not a time; it needs a colon or am/pm
+    if m[2] == "" && m[3] == "" {
+        return 0
+    }
+    hour, _ := strconv.Atoi(m[1])
+    minute := 0
+    if m[2] != "" {
+        minute, _ = strconv.Atoi(m[2])
+    }
+    switch m[3] {
+    case "am", "pm":
+        if hour < 1 || hour > 12 {
+            return 0
+        }
+        hour %= 12
+        if m[3] == "pm" {
+            hour += 12
+        }
+    default:
+        if hour > 23 {
+            return 0
+        }
+    }
+    if minute > 59 {
+        return 0
+    }
+    p.result.Time = clock(hour, minute)
+    return consumed
+}
+
+// nextWeekday returns the next date falling on day. Today counts only when includeToday is set.
+func (p *parser) nextWeekday(day time.Weekday, includeToday bool) time.Time {
+    today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+    offset := (int(day) - int(today.Weekday()) + 7) % 7
+    if offset == 0 && !includeToday {
+        offset = 7
+    }
+    return today.AddDate(0, 0, offset)
+}
+
+// nextDate returns the next occurrence of a month and day, today included
+func (p *parser) nextDate(month time.Month, day int) time.Time {
+    today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+    date := time.Date(today.Year(), month, day, 0, 0, 0, 0, p.now.Location())
+    if date.Before(today) {
+        date = date.AddDate(1, 0, 0)
+    }
+    return date
+}
+
+func dayOfMonth(token string) (int, bool) {
+    m := ordinalSuffix.FindStringSubmatch(token)
+    if m == nil {
+        return 0, false
+    }
+    day, _ := strconv.Atoi(m[1])
+    return day, day >= 1 && day <= 31
+}
+
+// clock returns a time of day the way the todo repositories store it
+func clock(hour, minute int) time.Time {
+    return time.Date(2000, 1, 1, hour, minute, 0, 0, time.UTC)
+}
@@ -0,0 +1,274 @@
+package agenda
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "sync"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+)
+
+const dateLayout = "2006-01-02"
+
+// Kinds of agenda items
+const (
+    KindTodo   = "todo"
+    KindShared = "shared"
+    KindTeam   = "team"
+)
+
+// AgendaService builds the "My Day" view from the user's todos, routines,
+// shared todos and team todos
+type AgendaService struct {
+    todoRepo       domain.TodoRepository
+    routineService *routines.RoutineService
+    sharedTodoRepo domain.SharedTodoRepository
+    teamRepo       domain.TeamRepository
+    teamTodoRepo   domain.TeamTodoRepository
+}
+
+// GetAgenda merges everything the user has on a day: their todos due that day,
+// the day's routines by slot, todos shared with them and team todos assigned to
+// them in any of their teams. Today's agenda also lists unfinished todos of
+// earlier days as overdue. The sources are fetched concurrently.
+func (s *AgendaService) GetAgenda(ctx context.Context, req *dto.AgendaRequest) (*dto.AgendaResponse, error) {
+    const functionName = "services.agenda.AgendaService.GetAgenda"
+
+    today := users.Today(req.Location)
+    date := today
+    if req.Date != "" {
+        parsed, err := time.Parse(dateLayout, req.Date)
+        if err != nil {
+            return nil, fmt.Errorf("%s: invalid date %q", functionName, req.Date)
+        }
+        date = parsed
+    }
+
+    var (
+        wg                  sync.WaitGroup
+        todos, shared, team []dto.AgendaItemResponse
+        slots               []dto.AgendaSlotResponse
+        errs                [4]error
+    )
+    fetch := func(i int, f func() error) {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            errs[i] = f()
+        }()
+    }
+    fetch(0, func() (err error) {
+        todos, err = s.todoItems(ctx, req.UserID, date, today)
+        return err
+    })
+    fetch(1, func() (err error) {
+        shared, err = s.sharedItems(ctx, req.UserID, date, today)
+        return err
+    })
+    fetch(2, func() (err error) {
+        team, err = s.teamItems(ctx, req.UserID, date, today)
+        return err
+    })
+    fetch(3, func() (err error) {
+        slots, err = s.routineSlots(ctx, req.UserID, date)
+        return err
+    })
+    wg.Wait()
+    for _, err := range errs {
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+    }
+
+    // A todo that repeats as one of the day's routines is only listed with its routine
+    routineTasks := make(map[string]bool)
+    for _, slot := range slots {
+        for _, routine := range slot.Routines {
+            routineTasks[routine.TaskID] = true
+        }
+    }
+
+    res := &dto.AgendaResponse{Date: date.Format(dateLayout), Items: []dto.AgendaItemResponse{}, Routines: slots}
+    for _, item := range todos {
+        if !routineTasks[item.ID] {
+            res.Items = append(res.Items, item)
+        }
+    }
+    res.Items = append(res.Items, shared...)
+    res.Items = append(res.Items, team...)
+    sortItems(res.Items)
+
+    return res, nil
+}
+
+func (s *AgendaService) todoItems(ctx context.Context, userID string, date, today time.Time) ([]dto.AgendaItemResponse, error) {
+    todos, err := s.todoRepo.GetTodosByUserID(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get todos: %w", err)
+    }
+    var items []dto.AgendaItemResponse
+    for _, todo := range todos {
+        due, overdue := onAgenda(todo.Date, todo.Done, date, today)
+        if !due && !overdue {
+            continue
+        }
+        item := newAgendaItem(KindTodo, todo.ID, todo.Task, todo.Description, todo.Done, todo.Important, todo.Date, todo.Time, overdue)
+        item.Version = todo.Version
+        items = append(items, item)
+    }
+    return items, nil
+}
+
+func (s *AgendaService) sharedItems(ctx context.Context, userID string, date, today time.Time) ([]dto.AgendaItemResponse, error) {
+    todos, err := s.sharedTodoRepo.GetSharedTodos(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get shared todos: %w", err)
+    }
+    var items []dto.AgendaItemResponse
+    // The same todo shared with the user more than once is listed once
+    seen := make(map[string]bool)
+    for _, todo := range todos {
+        if !todo.Accepted() || (todo.TodoID != "" && seen[todo.TodoID]) {
+            continue
+        }
+        due, overdue := onAgenda(todo.Date, todo.Done, date, today)
+        if !due && !overdue {
+            continue
+        }
+        if todo.TodoID != "" {
+            seen[todo.TodoID] = true
+        }
+        item := newAgendaItem(KindShared, todo.ID, todo.Task, todo.Description, todo.Done, todo.Important, todo.Date, todo.Time, overdue)
+        item.SharedBy = todo.SharedBy
+        items = append(items, item)
+    }
+    return items, nil
+}
+
+// teamItems collects the todos assigned to the user, fetching every team concurrently
+func (s *AgendaService) teamItems(ctx context.Context, userID string, date, today time.Time) ([]dto.AgendaItemResponse, error) {
+    teams, err := s.teamRepo.GetTeams(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get teams: %w", err)
+    }
+
+    var wg sync.WaitGroup
+    perTeam := make([][]domain.TeamTodo, len(teams))
+    errs := make([]error, len(teams))
+    for i, team := range teams {
+        wg.Add(1)
+        go func(i int, teamID string) {
+            defer wg.Done()
+            perTeam[i], errs[i] = s.teamTodoRepo.GetTeamTodos(ctx, teamID)
+        }(i, team.ID)
+    }
+    wg.Wait()
+
+    var items []dto.AgendaItemResponse
+    for i, team := range teams {
+        if errs[i] != nil {
+            return nil, fmt.Errorf("failed to get todos of team %s: %w", team.ID, errs[i])
+        }
+        for _, todo := range perTeam[i] {
+            if !todo.IsAssignedTo(userID) {
+                continue
+            }
+            due, overdue := onAgenda(todo.Date, todo.Done, date, today)
+            if !due && !overdue {
+                continue
+            }
+            item := newAgendaItem(KindTeam, todo.ID, todo.Task, todo.Description, todo.Done, todo.Important, todo.Date, todo.Time, overdue)
+            item.TeamID = team.ID
+            item.TeamName = team.Name
+            item.Version = todo.Version
+            items = append(items, item)
+        }
+    }
+    return items, nil
+}
+
+// routineSlots lists the routines of date by slot, converted to agenda entries
+func (s *AgendaService) routineSlots(ctx context.Context, userID string, date time.Time) ([]dto.AgendaSlotResponse, error) {
+    slots, err := s.routineService.GetSlotCheckins(ctx, userID, date)
+    if err != nil {
+        return nil, err
+    }
+
+    res := make([]dto.AgendaSlotResponse, len(slots))
+    for i, slot := range slots {
+        res[i] = dto.AgendaSlotResponse{Slot: slot.Slot, Routines: make([]dto.AgendaRoutineResponse, len(slot.Todos))}
+        for j, todo := range slot.Todos {
+            res[i].Routines[j] = dto.AgendaRoutineResponse{
+                RoutineID:   todo.RoutineID,
+                TaskID:      todo.ID,
+                Task:        todo.Task,
+                Description: todo.Description,
+                Important:   todo.Important,
+                CheckedIn:   todo.CheckedIn,
+            }
+        }
+    }
+    return res, nil
+}
+
+// onAgenda reports whether a todo dated due is due on date, or is an unfinished
+// todo of an earlier day shown on today's agenda
+func onAgenda(due time.Time, done bool, date, today time.Time) (bool, bool) {
+    if due.IsZero() {
+        return false, false
+    }
+    day := due.Format(dateLayout)
+    if day == date.Format(dateLayout) {
+        return true, false
+    }
+    overdue := !done && date.Equal(today) && day < today.Format(dateLayout)
+    return false, overdue
+}
+
+func newAgendaItem(kind, id, task, description string, done, important bool, date, timeValue time.Time, overdue bool) dto.AgendaItemResponse {
+    item := dto.AgendaItemResponse{
+        ID:          id,
+        Kind:        kind,
+        Task:        task,
+        Description: description,
+        Done:        done,
+        Important:   important,
+        Date:        date.Format(dateLayout),
+        Overdue:     overdue,
+    }
+    if !timeValue.IsZero() {
+        item.Time = timeValue.Format("15:04")
+    }
+    return item
+}
+
+// sortItems puts open todos before done ones, overdue todos first and then
+// timed todos by time ahead of untimed ones, with important todos first
+// among equals
+func sortItems(items []dto.AgendaItemResponse) {
+    sort.SliceStable(items, func(i, j int) bool {
+        a, b := items[i], items[j]
+        switch {
+        case a.Done != b.Done:
+            return !a.Done
+        case a.Overdue != b.Overdue:
+            return a.Overdue
+        case a.Overdue && a.Date != b.Date:
+            return a.Date < b.Date
+        case (a.Time == "") != (b.Time == ""):
+            return a.Time != ""
+        case a.Time != b.Time:
+            return a.Time < b.Time
+        case a.Important != b.Important:
+            return a.Important
+        case a.Task != b.Task:
+            return a.Task < b.Task
+        }
+        return a.ID < b.ID
+    })
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+package agenda
+
+import (
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+)
+
+func NewAgendaService(
+    todoRepo domain.TodoRepository,
+    routineService *routines.RoutineService,
+    sharedTodoRepo domain.SharedTodoRepository,
+    teamRepo domain.TeamRepository,
+    teamTodoRepo domain.TeamTodoRepository,
+) *AgendaService {
+    return &AgendaService{
+        todoRepo:       todoRepo,
+        routineService: routineService,
+        sharedTodoRepo: sharedTodoRepo,
+        teamRepo:       teamRepo,
+        teamTodoRepo:   teamTodoRepo,
+    }
+}
\ No newline at end of file
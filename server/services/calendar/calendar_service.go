@@ -0,0 +1,226 @@
+package calendar
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+)
+
+// Ways of rendering todos in a feed. Some calendar apps ignore VTODO entries,
+// so todos can also be published as events.
+const (
+    EntryModeTodo  = "todo"
+    EntryModeEvent = "event"
+)
+
+// uidDomain is appended to entry IDs to make globally unique iCalendar UIDs
+const uidDomain = "checkmate.todo-app"
+
+// todoEventDuration is the length of a timed todo published as an event
+const todoEventDuration = 30 * time.Minute
+
+// routineEventDuration is the length of a routine event
+const routineEventDuration = time.Hour
+
+var weekdayCodes = map[string]time.Weekday{
+    "sunday":    time.Sunday,
+    "monday":    time.Monday,
+    "tuesday":   time.Tuesday,
+    "wednesday": time.Wednesday,
+    "thursday":  time.Thursday,
+    "friday":    time.Friday,
+    "saturday":  time.Saturday,
+}
+
+var rruleDays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+type CalendarService struct {
+    feedRepo     domain.CalendarFeedRepository
+    todoRepo     domain.TodoRepository
+    routineRepo  domain.RoutineRepository
+    teamRepo     domain.TeamRepository
+    teamTodoRepo domain.TeamTodoRepository
+}
+
+// CreateFeedToken issues a new secret feed token for the user. Any previous
+// token stops working. The token is only returned here; just its hash is stored.
+func (s *CalendarService) CreateFeedToken(ctx context.Context, userID string) (*dto.CalendarFeedResponse, error) {
+    const functionName = "services.calendar.CalendarService.CreateFeedToken"
+
+    raw := make([]byte, 32)
+    if _, err := rand.Read(raw); err != nil {
+        return nil, fmt.Errorf("%s: failed to generate token: %w", functionName, err)
+    }
+    token := hex.EncodeToString(raw)
+
+    if err := s.feedRepo.SetCalendarFeedToken(ctx, userID, hashToken(token)); err != nil {
+        return nil, fmt.Errorf("%s: failed to store token: %w", functionName, err)
+    }
+    return &dto.CalendarFeedResponse{Token: token}, nil
+}
+
+// RevokeFeedToken disables the user's feed URL
+func (s *CalendarService) RevokeFeedToken(ctx context.Context, userID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.calendar.CalendarService.RevokeFeedToken"
+
+    revoked, err := s.feedRepo.DeleteCalendarFeedToken(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to revoke token: %w", functionName, err)
+    }
+    if !revoked {
+        return nil, fmt.Errorf("%s: calendar feed not found", functionName)
+    }
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// GetFeed renders the iCalendar feed behind a secret token
+func (s *CalendarService) GetFeed(ctx context.Context, token, entryMode string) ([]byte, error) {
+    const functionName = "services.calendar.CalendarService.GetFeed"
+
+    if entryMode == "" {
+        entryMode = EntryModeTodo
+    }
+    if entryMode != EntryModeTodo && entryMode != EntryModeEvent {
+        return nil, fmt.Errorf("%s: unsupported entry mode %q", functionName, entryMode)
+    }
+
+    userID, err := s.feedRepo.GetCalendarFeedUserID(ctx, hashToken(token))
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to find feed: %w", functionName, err)
+    }
+
+    now := time.Now()
+    entries, err := s.buildEntries(ctx, userID, entryMode, now)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to build feed: %w", functionName, err)
+    }
+    return renderCalendar("Todos", now, entries), nil
+}
+
+func (s *CalendarService) buildEntries(ctx context.Context, userID, entryMode string, now time.Time) ([]icsEntry, error) {
+    var entries []icsEntry
+
+    todos, err := s.todoRepo.GetTodosByUserID(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    slots, err := s.routineRepo.GetRoutineSlots(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    if len(slots) == 0 {
+        slots = domain.DefaultRoutineSlots()
+    }
+    slotStarts := make(map[string]string, len(slots))
+    for _, slot := range slots {
+        slotStarts[slot.Name] = slot.StartTime
+    }
+    for _, todo := range todos {
+        if !todo.Date.IsZero() {
+            entries = append(entries, todoEntry(entryMode, "todo-"+todo.ID, todo.Task, todo.Description,
+                todo.Date, todo.Time, todo.Done, todo.Important, nil))
+        }
+
+        routines, err := s.routineRepo.GetRoutinesByTaskID(ctx, todo.ID)
+        if err != nil {
+            return nil, err
+        }
+        for _, routine := range routines {
+            if routine.UserID != userID || !routine.IsActive {
+                continue
+            }
+            if entry, ok := routineEntry(routine, todo, slotStarts, now); ok {
+                entries = append(entries, entry)
+            }
+        }
+    }
+
+    teams, err := s.teamRepo.GetTeams(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    for _, team := range teams {
+        teamTodos, err := s.teamTodoRepo.GetTeamTodos(ctx, team.ID)
+        if err != nil {
+            return nil, err
+        }
+        for _, todo := range teamTodos {
+            if todo.Date.IsZero() {
+                continue
+            }
+            entries = append(entries, todoEntry(entryMode, "team-todo-"+todo.ID, todo.Task, todo.Description,
+                todo.Date, todo.Time, todo.Done, todo.Important, []string{team.Name}))
+        }
+    }
+
+    return entries, nil
+}
+
+// todoEntry publishes a dated todo. Todos without a time become all-day entries.
+func todoEntry(entryMode, uid, task, description string, date, timeValue time.Time, done, important bool, categories []string) icsEntry {
+    entry := icsEntry{
+        Component:   componentTodo,
+        UID:         uid + "@" + uidDomain,
+        Summary:     task,
+        Description: description,
+        AllDay:      timeValue.IsZero(),
+        Done:        done,
+        Important:   important,
+        Categories:  categories,
+    }
+    entry.Start = date
+    if !entry.AllDay {
+        hour, min, sec := timeValue.Clock()
+        entry.Start = time.Date(date.Year(), date.Month(), date.Day(), hour, min, sec, 0, time.UTC)
+    }
+
+    if entryMode == EntryModeEvent {
+        entry.Component = componentEvent
+        entry.Duration = todoEventDuration
+    }
+    return entry
+}
+
+// routineEntry expands a routine into a weekly recurring event starting at its
+// slot's start time. The series starts on the first matching weekday on or after
+// the routine was created.
+func routineEntry(routine domain.Routine, todo domain.Todo, slotStarts map[string]string, now time.Time) (icsEntry, bool) {
+    weekday, ok := weekdayCodes[routine.Day]
+    if !ok {
+        return icsEntry{}, false
+    }
+    start, err := time.Parse("15:04", slotStarts[routine.ScheduleType])
+    if err != nil {
+        return icsEntry{}, false
+    }
+
+    anchor := routine.CreatedAt
+    if anchor.IsZero() {
+        anchor = now
+    }
+    offset := (int(weekday) - int(anchor.Weekday()) + 7) % 7
+    first := anchor.AddDate(0, 0, offset)
+
+    return icsEntry{
+        Component:   componentEvent,
+        UID:         "routine-" + routine.ID + "@" + uidDomain,
+        Summary:     todo.Task,
+        Description: todo.Description,
+        Start:       time.Date(first.Year(), first.Month(), first.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC),
+        Duration:    routineEventDuration,
+        RRule:       "FREQ=WEEKLY;BYDAY=" + rruleDays[weekday],
+        Important:   todo.Important,
+        Categories:  []string{"Routine"},
+    }, true
+}
+
+func hashToken(token string) string {
+    sum := sha256.Sum256([]byte(token))
+    return hex.EncodeToString(sum[:])
+}
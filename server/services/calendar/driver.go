@@ -0,0 +1,21 @@
+package calendar
+
+import (
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+func NewCalendarService(
+    feedRepo domain.CalendarFeedRepository,
+    todoRepo domain.TodoRepository,
+    routineRepo domain.RoutineRepository,
+    teamRepo domain.TeamRepository,
+    teamTodoRepo domain.TeamTodoRepository,
+) *CalendarService {
+    return &CalendarService{
+        feedRepo:     feedRepo,
+        todoRepo:     todoRepo,
+        routineRepo:  routineRepo,
+        teamRepo:     teamRepo,
+        teamTodoRepo: teamTodoRepo,
+    }
+}
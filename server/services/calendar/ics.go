@@ -0,0 +1,144 @@
+package calendar
+
+import (
+    "bytes"
+    "fmt"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+const (
+    componentTodo  = "VTODO"
+    componentEvent = "VEVENT"
+)
+
+// icsEntry is a single VTODO or VEVENT. Times are floating local times, so
+// calendar apps show them in the subscriber's own timezone.
+type icsEntry struct {
+    Component   string
+    UID         string
+    Summary     string
+    Description string
+    Start       time.Time // DTSTART of an event or DUE of a todo
+    AllDay      bool
+    Duration    time.Duration
+    RRule       string
+    Done        bool
+    Important   bool
+    Categories  []string
+}
+
+// renderCalendar encodes the entries as an RFC 5545 calendar
+func renderCalendar(name string, stamp time.Time, entries []icsEntry) []byte {
+    var buf bytes.Buffer
+    line := func(name, value string) {
+        writeFolded(&buf, name+":"+value)
+    }
+
+    line("BEGIN", "VCALENDAR")
+    line("VERSION", "2.0")
+    line("PRODID", "-//Checkmate//Todo App//EN")
+    line("CALSCALE", "GREGORIAN")
+    line("METHOD", "PUBLISH")
+    if name != "" {
+        line("X-WR-CALNAME", escapeText(name))
+    }
+
+    for _, entry := range entries {
+        line("BEGIN", entry.Component)
+        line("UID", entry.UID)
+        line("DTSTAMP", stamp.UTC().Format("20060102T150405Z"))
+        line("SUMMARY", escapeText(entry.Summary))
+        if entry.Description != "" {
+            line("DESCRIPTION", escapeText(entry.Description))
+        }
+
+        startName := "DTSTART"
+        if entry.Component == componentTodo {
+            startName = "DUE"
+        }
+        switch {
+        case entry.Start.IsZero():
+            // Undated todos have no DUE
+        case entry.AllDay:
+            line(startName+";VALUE=DATE", entry.Start.Format("20060102"))
+        default:
+            line(startName, entry.Start.Format("20060102T150405"))
+        }
+        if entry.Component == componentEvent && !entry.AllDay && entry.Duration > 0 {
+            line("DURATION", formatDuration(entry.Duration))
+        }
+        if entry.RRule != "" {
+            line("RRULE", entry.RRule)
+        }
+
+        if entry.Component == componentTodo {
+            if entry.Done {
+                line("STATUS", "COMPLETED")
+            } else {
+                line("STATUS", "NEEDS-ACTION")
+            }
+        }
+        if entry.Important {
+            line("PRIORITY", "1")
+        }
+        if len(entry.Categories) > 0 {
+            categories := make([]string, len(entry.Categories))
+            for i, category := range entry.Categories {
+                categories[i] = escapeText(category)
+            }
+            line("CATEGORIES", strings.Join(categories, ","))
+        }
+        line("END", entry.Component)
+    }
+
+    line("END", "VCALENDAR")
+    return buf.Bytes()
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(value string) string {
+    replacer := strings.NewReplacer(
+        `\`, `\\`,
+        ";", `\;`,
+        ",", `\,`,
+        "\r\n", `\n`,
+        "\n", `\n`,
+        "\r", `\n`,
+    )
+    return replacer.Replace(value)
+}
+
+// writeFolded writes a content line, folding it at 75 octets without splitting UTF-8 characters
+func writeFolded(buf *bytes.Buffer, line string) {
+    const limit = 75
+    width := limit
+    for len(line) > width {
+        cut := width
+        for cut > 0 && !utf8.RuneStart(line[cut]) {
+            cut--
+        }
+        buf.WriteString(line[:cut])
+        buf.WriteString("\r\n ")
+        line = line[cut:]
+        // Continuation lines start with a space, which counts towards the limit
+        width = limit - 1
+    }
+    buf.WriteString(line)
+    buf.WriteString("\r\n")
+}
+
+// formatDuration renders a duration as an RFC 5545 DURATION value
+func formatDuration(d time.Duration) string {
+    hours := int(d.Hours())
+    minutes := int(d.Minutes()) % 60
+    switch {
+    case minutes == 0:
+        return fmt.Sprintf("PT%dH", hours)
+    case hours == 0:
+        return fmt.Sprintf("PT%dM", minutes)
+    default:
+        return fmt.Sprintf("PT%dH%dM", hours, minutes)
+    }
+}
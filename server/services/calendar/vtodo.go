@@ -0,0 +1,175 @@
+package calendar
+
+import (
+    "bufio"
+    "bytes"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// TodoResource is a todo as exchanged with CalDAV clients: one VTODO per calendar object
+type TodoResource struct {
+    UID         string
+    Summary     string
+    Description string
+    Done        bool
+    Important   bool
+    Due         time.Time // zero when the todo has no date
+    AllDay      bool      // Due has a date but no time
+}
+
+// EncodeTodoResource renders a calendar object holding a single VTODO
+func EncodeTodoResource(todo TodoResource, stamp time.Time) []byte {
+    entry := icsEntry{
+        Component:   componentTodo,
+        UID:         todo.UID,
+        Summary:     todo.Summary,
+        Description: todo.Description,
+        Start:       todo.Due,
+        AllDay:      todo.AllDay,
+        Done:        todo.Done,
+        Important:   todo.Important,
+    }
+    return renderCalendar("", stamp, []icsEntry{entry})
+}
+
+// ParseTodoResource reads the first VTODO of a calendar object. Properties the
+// app has no field for, and nested components such as VALARM, are ignored.
+// Due times are kept as wall-clock times whatever their timezone.
+func ParseTodoResource(data []byte) (*TodoResource, error) {
+    var todo *TodoResource
+    depth := 0 // nesting below the VTODO, so VALARM properties are skipped
+
+    for _, line := range unfoldLines(data) {
+        name, params, value, ok := splitContentLine(line)
+        if !ok {
+            continue
+        }
+
+        switch {
+        case name == "BEGIN" && strings.EqualFold(value, componentTodo) && todo == nil:
+            todo = &TodoResource{}
+            continue
+        case todo == nil:
+            continue
+        case name == "BEGIN":
+            depth++
+            continue
+        case name == "END" && depth > 0:
+            depth--
+            continue
+        case name == "END" && strings.EqualFold(value, componentTodo):
+            if strings.TrimSpace(todo.Summary) == "" {
+                return nil, fmt.Errorf("VTODO must have a SUMMARY")
+            }
+            return todo, nil
+        case depth > 0:
+            continue
+        }
+
+        switch name {
+        case "UID":
+            todo.UID = value
+        case "SUMMARY":
+            todo.Summary = unescapeText(value)
+        case "DESCRIPTION":
+            todo.Description = unescapeText(value)
+        case "STATUS":
+            todo.Done = strings.EqualFold(value, "COMPLETED")
+        case "COMPLETED":
+            todo.Done = true
+        case "PRIORITY":
+            // 1-4 is high priority in RFC 5545, 0 means undefined
+            priority, err := strconv.Atoi(value)
+            if err != nil {
+                return nil, fmt.Errorf("invalid PRIORITY %q", value)
+            }
+            todo.Important = priority >= 1 && priority <= 4
+        case "DUE":
+            due, allDay, err := parseDateTimeValue(params, value)
+            if err != nil {
+                return nil, err
+            }
+            todo.Due, todo.AllDay = due, allDay
+        }
+    }
+
+    if todo == nil {
+        return nil, fmt.Errorf("calendar data must contain a VTODO")
+    }
+    return nil, fmt.Errorf("VTODO is not terminated")
+}
+
+// unfoldLines splits calendar data into content lines, joining folded continuations
+func unfoldLines(data []byte) []string {
+    var lines []string
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+    for scanner.Scan() {
+        line := strings.TrimRight(scanner.Text(), "\r")
+        if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+            lines[len(lines)-1] += line[1:]
+            continue
+        }
+        if line != "" {
+            lines = append(lines, line)
+        }
+    }
+    return lines
+}
+
+// splitContentLine splits "NAME;PARAM=x:value" into its upper-cased name, parameters and value
+func splitContentLine(line string) (string, string, string, bool) {
+    inQuotes := false
+    for i, r := range line {
+        switch {
+        case r == '"':
+            inQuotes = !inQuotes
+        case r == ':' && !inQuotes:
+            head := line[:i]
+            name, params := head, ""
+            if semi := strings.Index(head, ";"); semi >= 0 {
+                name, params = head[:semi], head[semi+1:]
+            }
+            return strings.ToUpper(name), strings.ToUpper(params), line[i+1:], true
+        }
+    }
+    return "", "", "", false
+}
+
+// parseDateTimeValue parses a DATE or DATE-TIME value, dropping any timezone
+func parseDateTimeValue(params, value string) (time.Time, bool, error) {
+    if strings.Contains(params, "VALUE=DATE") && !strings.Contains(params, "VALUE=DATE-TIME") || len(value) == 8 {
+        date, err := time.Parse("20060102", value)
+        if err != nil {
+            return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+        }
+        return date, true, nil
+    }
+    parsed, err := time.Parse("20060102T150405", strings.TrimSuffix(value, "Z"))
+    if err != nil {
+        return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+    }
+    return parsed, false, nil
+}
+
+// unescapeText reverses escapeText
+func unescapeText(value string) string {
+    var out strings.Builder
+    for i := 0; i < len(value); i++ {
+        if value[i] != '\\' || i == len(value)-1 {
+            out.WriteByte(value[i])
+            continue
+        }
+        i++
+        switch value[i] {
+        case 'n', 'N':
+            out.WriteByte('\n')
+        default:
+            out.WriteByte(value[i])
+        }
+    }
+    return out.String()
+}
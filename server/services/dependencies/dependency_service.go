@@ -0,0 +1,422 @@
+package dependencies
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+)
+
+// DependencyService manages blocked-by links between a user's todos and between
+// todos of the same team. Links never form cycles, and a todo with open blockers
+// cannot be completed unless the caller forces it.
+type DependencyService struct {
+    repo         domain.DependencyRepository
+    todoRepo     domain.TodoRepository
+    teamTodoRepo domain.TeamTodoRepository
+}
+
+// node is the part of a todo or team todo the dependency rules look at
+type node struct {
+    Task string
+    Done bool
+}
+
+// graph holds the todos of one user or team and the links between them
+type graph struct {
+    nodes map[string]node
+    edges []domain.Dependency
+}
+
+func newGraph(nodes []domain.DependencyNode, edges []domain.Dependency) *graph {
+    g := &graph{nodes: make(map[string]node, len(nodes)), edges: edges}
+    for _, n := range nodes {
+        g.nodes[n.ID] = node{Task: n.Task, Done: n.Done}
+    }
+    return g
+}
+
+func (s *DependencyService) todoGraph(ctx context.Context, userID string) (*graph, error) {
+    todos, err := s.todoRepo.GetTodosByUserID(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    edges, err := s.repo.GetTodoDependencies(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    g := &graph{nodes: make(map[string]node, len(todos)), edges: edges}
+    for _, todo := range todos {
+        g.nodes[todo.ID] = node{Task: todo.Task, Done: todo.Done}
+    }
+    return g, nil
+}
+
+func (s *DependencyService) teamTodoGraph(ctx context.Context, teamID string) (*graph, error) {
+    todos, err := s.teamTodoRepo.GetTeamTodos(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    edges, err := s.repo.GetTeamTodoDependencies(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    g := &graph{nodes: make(map[string]node, len(todos)), edges: edges}
+    for _, todo := range todos {
+        g.nodes[todo.ID] = node{Task: todo.Task, Done: todo.Done}
+    }
+    return g, nil
+}
+
+// AddTodoDependency marks one of the user's todos as blocked by another. The
+// check runs inside the repository's transaction, so two requests that would
+// close a cycle together cannot both pass it.
+func (s *DependencyService) AddTodoDependency(ctx context.Context, req *dto.AddDependencyRequest) (*dto.DependenciesResponse, error) {
+    const functionName = "services.dependencies.DependencyService.AddTodoDependency"
+
+    var g *graph
+    var linkErr error
+    err := s.repo.AddTodoDependency(ctx, req.UserID, req.TodoID, req.BlockedByID, func(nodes []domain.DependencyNode, edges []domain.Dependency) error {
+        g = newGraph(nodes, edges)
+        linkErr = g.validateLink(req.TodoID, req.BlockedByID)
+        return linkErr
+    })
+    if linkErr != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, linkErr)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to add dependency: %w", functionName, err)
+    }
+    g.edges = append(g.edges, domain.Dependency{TodoID: req.TodoID, BlockedByID: req.BlockedByID})
+    return g.dependencies(req.TodoID), nil
+}
+
+// AddTeamTodoDependency marks a team todo as blocked by another todo of the same team
+func (s *DependencyService) AddTeamTodoDependency(ctx context.Context, req *dto.AddDependencyRequest) (*dto.DependenciesResponse, error) {
+    const functionName = "services.dependencies.DependencyService.AddTeamTodoDependency"
+
+    var g *graph
+    var linkErr error
+    err := s.repo.AddTeamTodoDependency(ctx, req.TeamID, req.TodoID, req.BlockedByID, func(nodes []domain.DependencyNode, edges []domain.Dependency) error {
+        g = newGraph(nodes, edges)
+        linkErr = g.validateLink(req.TodoID, req.BlockedByID)
+        return linkErr
+    })
+    if linkErr != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, linkErr)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to add dependency: %w", functionName, err)
+    }
+    g.edges = append(g.edges, domain.Dependency{TodoID: req.TodoID, BlockedByID: req.BlockedByID})
+    return g.dependencies(req.TodoID), nil
+}
+
+// RemoveTodoDependency deletes a blocked-by link between two of the user's todos
+func (s *DependencyService) RemoveTodoDependency(ctx context.Context, userID, todoID, blockedByID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.dependencies.DependencyService.RemoveTodoDependency"
+
+    todo, err := s.todoRepo.GetTodoByID(ctx, todoID)
+    if err != nil || todo.UserID != userID {
+        return nil, fmt.Errorf("%s: todo not found", functionName)
+    }
+
+    removed, err := s.repo.RemoveTodoDependency(ctx, todoID, blockedByID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to remove dependency: %w", functionName, err)
+    }
+    if !removed {
+        return nil, fmt.Errorf("%s: dependency not found", functionName)
+    }
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// RemoveTeamTodoDependency deletes a blocked-by link between two team todos
+func (s *DependencyService) RemoveTeamTodoDependency(ctx context.Context, teamID, todoID, blockedByID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.dependencies.DependencyService.RemoveTeamTodoDependency"
+
+    removed, err := s.repo.RemoveTeamTodoDependency(ctx, teamID, todoID, blockedByID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to remove dependency: %w", functionName, err)
+    }
+    if !removed {
+        return nil, fmt.Errorf("%s: dependency not found", functionName)
+    }
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// GetTodoDependencies lists what blocks a todo and what it blocks
+func (s *DependencyService) GetTodoDependencies(ctx context.Context, userID, todoID string) (*dto.DependenciesResponse, error) {
+    const functionName = "services.dependencies.DependencyService.GetTodoDependencies"
+
+    g, err := s.todoGraph(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to load todos: %w", functionName, err)
+    }
+    if _, ok := g.nodes[todoID]; !ok {
+        return nil, fmt.Errorf("%s: todo not found", functionName)
+    }
+    return g.dependencies(todoID), nil
+}
+
+// GetTeamTodoDependencies lists what blocks a team todo and what it blocks
+func (s *DependencyService) GetTeamTodoDependencies(ctx context.Context, teamID, todoID string) (*dto.DependenciesResponse, error) {
+    const functionName = "services.dependencies.DependencyService.GetTeamTodoDependencies"
+
+    g, err := s.teamTodoGraph(ctx, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to load team todos: %w", functionName, err)
+    }
+    if _, ok := g.nodes[todoID]; !ok {
+        return nil, fmt.Errorf("%s: team todo not found", functionName)
+    }
+    return g.dependencies(todoID), nil
+}
+
+// TodoBlockStates returns the blockers of each listed todo. Only todos with at
+// least one blocker appear in the map.
+func (s *DependencyService) TodoBlockStates(ctx context.Context, userID string, todos []dto.TodoResponse) (map[string]dto.BlockState, error) {
+    const functionName = "services.dependencies.DependencyService.TodoBlockStates"
+
+    edges, err := s.repo.GetTodoDependencies(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get dependencies: %w", functionName, err)
+    }
+    g := &graph{nodes: make(map[string]node, len(todos)), edges: edges}
+    for _, todo := range todos {
+        g.nodes[todo.ID] = node{Task: todo.Task, Done: todo.Done}
+    }
+    return g.blockStates(), nil
+}
+
+// TeamTodoBlockStates returns the blockers of each listed team todo
+func (s *DependencyService) TeamTodoBlockStates(ctx context.Context, teamID string, todos []dto.TeamTodoResponse) (map[string]dto.BlockState, error) {
+    const functionName = "services.dependencies.DependencyService.TeamTodoBlockStates"
+
+    edges, err := s.repo.GetTeamTodoDependencies(ctx, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get dependencies: %w", functionName, err)
+    }
+    g := &graph{nodes: make(map[string]node, len(todos)), edges: edges}
+    for _, todo := range todos {
+        g.nodes[todo.ID] = node{Task: todo.Task, Done: todo.Done}
+    }
+    return g.blockStates(), nil
+}
+
+// CheckTodosCanComplete fails when any of the todos has an open blocker. Todos
+// completed together count as done, so a blocker and the todo it blocks can be
+// completed in the same batch.
+func (s *DependencyService) CheckTodosCanComplete(ctx context.Context, userID string, todoIDs ...string) error {
+    const functionName = "services.dependencies.DependencyService.CheckTodosCanComplete"
+
+    g, err := s.todoGraph(ctx, userID)
+    if err != nil {
+        return fmt.Errorf("%s: failed to load todos: %w", functionName, err)
+    }
+    if err := g.checkCanComplete(todoIDs); err != nil {
+        return fmt.Errorf("%s: %w", functionName, err)
+    }
+    return nil
+}
+
+// CheckTeamTodosCanComplete fails when any of the team todos has an open blocker
+func (s *DependencyService) CheckTeamTodosCanComplete(ctx context.Context, teamID string, todoIDs ...string) error {
+    const functionName = "services.dependencies.DependencyService.CheckTeamTodosCanComplete"
+
+    g, err := s.teamTodoGraph(ctx, teamID)
+    if err != nil {
+        return fmt.Errorf("%s: failed to load team todos: %w", functionName, err)
+    }
+    if err := g.checkCanComplete(todoIDs); err != nil {
+        return fmt.Errorf("%s: %w", functionName, err)
+    }
+    return nil
+}
+
+// BlockedTodos returns why each of the todos cannot be completed, for those that
+// cannot. Todos completed together count as done, except a blocker that is itself
+// blocked, since it will not complete with them.
+func (s *DependencyService) BlockedTodos(ctx context.Context, userID string, todoIDs ...string) (map[string]error, error) {
+    const functionName = "services.dependencies.DependencyService.BlockedTodos"
+
+    g, err := s.todoGraph(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to load todos: %w", functionName, err)
+    }
+    return g.blockedTodos(todoIDs), nil
+}
+
+// BlockedTeamTodos returns why each of the team todos cannot be completed, for those that cannot
+func (s *DependencyService) BlockedTeamTodos(ctx context.Context, teamID string, todoIDs ...string) (map[string]error, error) {
+    const functionName = "services.dependencies.DependencyService.BlockedTeamTodos"
+
+    g, err := s.teamTodoGraph(ctx, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to load team todos: %w", functionName, err)
+    }
+    return g.blockedTodos(todoIDs), nil
+}
+
+// validateLink checks that todoID may be blocked by blockedByID
+func (g *graph) validateLink(todoID, blockedByID string) error {
+    if blockedByID == "" {
+        return fmt.Errorf("blocked_by is required")
+    }
+    if todoID == blockedByID {
+        return fmt.Errorf("a todo cannot block itself")
+    }
+    if _, ok := g.nodes[todoID]; !ok {
+        return fmt.Errorf("todo %s not found", todoID)
+    }
+    if _, ok := g.nodes[blockedByID]; !ok {
+        return fmt.Errorf("blocking todo %s not found", blockedByID)
+    }
+    for _, edge := range g.edges {
+        if edge.TodoID == todoID && edge.BlockedByID == blockedByID {
+            return fmt.Errorf("dependency already exists")
+        }
+    }
+
+    // The new link closes a cycle if todoID already blocks blockedByID, directly or not
+    if path := g.blockerPath(blockedByID, todoID); path != nil {
+        return fmt.Errorf("dependency would create a cycle: %s", strings.Join(append([]string{todoID}, path...), " -> "))
+    }
+    return nil
+}
+
+// blockerPath follows blocked-by links from one todo and returns the chain that
+// reaches target, or nil if there is none
+func (g *graph) blockerPath(from, target string) []string {
+    blockers := make(map[string][]string)
+    for _, edge := range g.edges {
+        blockers[edge.TodoID] = append(blockers[edge.TodoID], edge.BlockedByID)
+    }
+
+    visited := make(map[string]bool)
+    var visit func(id string) []string
+    visit = func(id string) []string {
+        if id == target {
+            return []string{id}
+        }
+        if visited[id] {
+            return nil
+        }
+        visited[id] = true
+        for _, next := range blockers[id] {
+            if path := visit(next); path != nil {
+                return append([]string{id}, path...)
+            }
+        }
+        return nil
+    }
+    return visit(from)
+}
+
+func (g *graph) dependencies(todoID string) *dto.DependenciesResponse {
+    res := &dto.DependenciesResponse{
+        TodoID:    todoID,
+        BlockedBy: []dto.DependencyResponse{},
+        Blocks:    []dto.DependencyResponse{},
+    }
+    for _, edge := range g.edges {
+        switch todoID {
+        case edge.TodoID:
+            blocker := g.nodes[edge.BlockedByID]
+            res.BlockedBy = append(res.BlockedBy, dto.DependencyResponse{ID: edge.BlockedByID, Task: blocker.Task, Done: blocker.Done})
+            if !blocker.Done {
+                res.Blocked = true
+            }
+        case edge.BlockedByID:
+            blocked := g.nodes[edge.TodoID]
+            res.Blocks = append(res.Blocks, dto.DependencyResponse{ID: edge.TodoID, Task: blocked.Task, Done: blocked.Done})
+        }
+    }
+    return res
+}
+
+func (g *graph) blockStates() map[string]dto.BlockState {
+    states := make(map[string]dto.BlockState)
+    for _, edge := range g.edges {
+        if _, ok := g.nodes[edge.TodoID]; !ok {
+            continue
+        }
+        state := states[edge.TodoID]
+        state.BlockedBy = append(state.BlockedBy, edge.BlockedByID)
+        if !g.nodes[edge.BlockedByID].Done {
+            state.Blocked = true
+        }
+        states[edge.TodoID] = state
+    }
+    for id, state := range states {
+        sort.Strings(state.BlockedBy)
+        states[id] = state
+    }
+    return states
+}
+
+func (g *graph) checkCanComplete(todoIDs []string) error {
+    var open []string
+    seen := make(map[string]bool)
+    for _, blockers := range g.openBlockers(todoIDs) {
+        for _, id := range blockers {
+            if !seen[id] {
+                open = append(open, id)
+                seen[id] = true
+            }
+        }
+    }
+    if len(open) > 0 {
+        sort.Strings(open)
+        return fmt.Errorf("blocked by open todos: %s", strings.Join(open, ", "))
+    }
+    return nil
+}
+
+// openBlockers returns the open blockers of each todo being completed that has any
+func (g *graph) openBlockers(todoIDs []string) map[string][]string {
+    completing := make(map[string]bool, len(todoIDs))
+    for _, id := range todoIDs {
+        completing[id] = true
+    }
+
+    // Todos that are already done are not being completed, so they are not checked
+    open := make(map[string][]string)
+    for _, edge := range g.edges {
+        if !completing[edge.TodoID] || g.nodes[edge.TodoID].Done || completing[edge.BlockedByID] {
+            continue
+        }
+        if blocker, ok := g.nodes[edge.BlockedByID]; ok && !blocker.Done {
+            open[edge.TodoID] = append(open[edge.TodoID], edge.BlockedByID)
+        }
+    }
+    for id := range open {
+        sort.Strings(open[id])
+    }
+    return open
+}
+
+// blockedTodos drops blocked todos from the completing set until the rest can all
+// be completed, and returns the blocker error of each dropped todo
+func (g *graph) blockedTodos(todoIDs []string) map[string]error {
+    blocked := make(map[string]error)
+    completing := todoIDs
+    for {
+        open := g.openBlockers(completing)
+        if len(open) == 0 {
+            return blocked
+        }
+        var rest []string
+        for _, id := range completing {
+            if blockers, ok := open[id]; ok {
+                blocked[id] = fmt.Errorf("blocked by open todos: %s", strings.Join(blockers, ", "))
+                continue
+            }
+            rest = append(rest, id)
+        }
+        completing = rest
+    }
+}
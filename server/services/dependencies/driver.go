@@ -0,0 +1,17 @@
+package dependencies
+
+import (
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+func NewDependencyService(
+    repo domain.DependencyRepository,
+    todoRepo domain.TodoRepository,
+    teamTodoRepo domain.TeamTodoRepository,
+) *DependencyService {
+    return &DependencyService{
+        repo:         repo,
+        todoRepo:     todoRepo,
+        teamTodoRepo: teamTodoRepo,
+    }
+}
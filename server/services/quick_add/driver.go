@@ -0,0 +1,25 @@
+package quick_add
+
+import (
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/shared_todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+)
+
+func NewQuickAddService(
+    todoRepo domain.TodoRepository,
+    todoService *todos.TodoService,
+    routineService *routines.RoutineService,
+    sharedTodoService *shared_todos.SharedTodoService,
+    userService *users.UserService,
+) *QuickAddService {
+    return &QuickAddService{
+        todoRepo:          todoRepo,
+        todoService:       todoService,
+        routineService:    routineService,
+        sharedTodoService: sharedTodoService,
+        userService:       userService,
+    }
+}
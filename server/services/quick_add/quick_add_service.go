@@ -0,0 +1,158 @@
+package quick_add
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/quickadd"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/routines"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/shared_todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+)
+
+// maxTagLength matches the todo_tags.tag column
+const maxTagLength = 50
+
+var allDays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// QuickAddService creates todos from free-form text
+type QuickAddService struct {
+    todoRepo          domain.TodoRepository
+    todoService       *todos.TodoService
+    routineService    *routines.RoutineService
+    sharedTodoService *shared_todos.SharedTodoService
+    userService       *users.UserService
+}
+
+// QuickAdd parses text such as "Pay rent tomorrow 9am !high #home @alice every month"
+// and creates the todo it describes. Tags are stored with the todo. Daily and
+// weekly recurrence becomes routines; other rules are stored with the todo.
+// @mentions share the todo, and failed shares are reported as warnings. With
+// DryRun only the parse result is returned.
+func (s *QuickAddService) QuickAdd(ctx context.Context, req *dto.QuickAddRequest) (*dto.QuickAddResponse, error) {
+    const functionName = "services.quick_add.QuickAddService.QuickAdd"
+
+    // Relative dates such as "tomorrow" are resolved in the user's timezone
+    now := users.LocalNow(req.Location)
+    parsed, err := quickadd.Parse(req.Text, now)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    for _, tag := range parsed.Tags {
+        if len(tag) > maxTagLength {
+            return nil, fmt.Errorf("%s: tag #%s is longer than %d characters", functionName, tag, maxTagLength)
+        }
+    }
+
+    res := &dto.QuickAddResponse{
+        Task:      parsed.Task,
+        Priority:  parsed.Priority,
+        Tags:      parsed.Tags,
+        ShareWith: parsed.Mentions,
+    }
+    if !parsed.Date.IsZero() {
+        res.Date = parsed.Date.Format("2006-01-02")
+    }
+    if !parsed.Time.IsZero() {
+        res.Time = parsed.Time.Format("15:04:05")
+    }
+
+    // Routines only repeat every day or every week on fixed weekdays
+    var routineDays []string
+    var recurrence *domain.TodoRecurrence
+    if rec := parsed.Recurrence; rec != nil {
+        res.Recurrence = &dto.QuickAddRecurrenceResponse{
+            Frequency: rec.Frequency,
+            Interval:  rec.Interval,
+            Weekdays:  rec.Weekdays,
+        }
+        switch {
+        case rec.Interval != 1 || rec.Frequency == quickadd.FrequencyMonthly || rec.Frequency == quickadd.FrequencyYearly:
+            recurrence = &domain.TodoRecurrence{Frequency: rec.Frequency, Interval: rec.Interval, Weekdays: rec.Weekdays}
+        case rec.Frequency == quickadd.FrequencyDaily:
+            routineDays = allDays
+        case len(rec.Weekdays) > 0:
+            routineDays = rec.Weekdays
+        default:
+            day := now
+            if !parsed.Date.IsZero() {
+                day = parsed.Date
+            }
+            routineDays = []string{strings.ToLower(day.Weekday().String())}
+        }
+    }
+
+    if req.DryRun {
+        return res, nil
+    }
+
+    created, err := s.todoService.CreateTodo(ctx, &dto.CreateTodoRequest{
+        Task:      parsed.Task,
+        Important: parsed.Priority == quickadd.PriorityHigh,
+        UserID:    req.UserID,
+        Date:      parsed.Date,
+        Time:      parsed.Time,
+        Location:  req.Location,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    res.ID = created.ID
+
+    if len(parsed.Tags) > 0 || recurrence != nil {
+        if recurrence != nil {
+            recurrence.TodoID = created.ID
+        }
+        if err := s.todoRepo.SetTodoDetails(ctx, created.ID, parsed.Tags, recurrence); err != nil {
+            // Without its tags and recurrence the todo is not what was asked for
+            if _, deleteErr := s.todoRepo.DeleteTodo(ctx, created.ID, req.UserID, 0); deleteErr != nil {
+                return nil, fmt.Errorf("%s: failed to save tags and recurrence: %v; failed to remove todo: %w", functionName, err, deleteErr)
+            }
+            return nil, fmt.Errorf("%s: failed to save tags and recurrence: %w", functionName, err)
+        }
+    }
+
+    if len(routineDays) > 0 {
+        scheduleType, err := s.routineService.SlotForTime(ctx, req.UserID, parsed.Time)
+        if err != nil {
+            res.Warnings = append(res.Warnings, "could not create routines: "+err.Error())
+            routineDays = nil
+        }
+        for _, day := range routineDays {
+            routine, err := s.routineService.CreateRoutine(ctx, &dto.CreateRoutineRequest{
+                Day:          day,
+                ScheduleType: scheduleType,
+                TaskID:       created.ID,
+                UserID:       req.UserID,
+                IsActive:     true,
+            })
+            if err != nil {
+                res.Warnings = append(res.Warnings, "could not create "+day+" routine: "+err.Error())
+                continue
+            }
+            res.RoutineIDs = append(res.RoutineIDs, routine.ID)
+        }
+    }
+
+    // Sharing failures do not undo the todo; they are reported as warnings
+    for _, username := range parsed.Mentions {
+        recipient, err := s.userService.GetUserByUsername(ctx, username)
+        if err != nil {
+            res.Warnings = append(res.Warnings, "user @"+username+" not found")
+            continue
+        }
+        if recipient.ID == req.UserID {
+            res.Warnings = append(res.Warnings, "cannot share todo with yourself")
+            continue
+        }
+        if err := s.sharedTodoService.ShareTodo(ctx, created.ID, recipient.ID, req.UserID, domain.SharePermissionView); err != nil {
+            res.Warnings = append(res.Warnings, "could not share with @"+username+": "+err.Error())
+        }
+    }
+
+    return res, nil
+}
@@ -4,6 +4,6 @@ import (
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
 )
 
-func NewRoutineService(repo domain.RoutineRepository) *RoutineService {
-    return &RoutineService{repo: repo}
+func NewRoutineService(repo domain.RoutineRepository, todoRepo domain.TodoRepository) *RoutineService {
+    return &RoutineService{repo: repo, todoRepo: todoRepo}
 }
\ No newline at end of file
@@ -6,18 +6,30 @@ import (
     "strings"
     "time"
     
+    "github.com/google/uuid"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
 )
 
+const dateLayout = "2006-01-02"
+
+// weekDays lists the routine days in the order of the routines.day ENUM
+var weekDays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
 type RoutineService struct {
-    repo domain.RoutineRepository
+    repo     domain.RoutineRepository
+    todoRepo domain.TodoRepository
 }
 
 // CreateRoutine creates a new routine
 func (s *RoutineService) CreateRoutine(ctx context.Context, req *dto.CreateRoutineRequest) (*dto.CreateResponse, error) {
     const functionName = "services.routines.RoutineService.CreateRoutine"
     
+    if err := s.checkSlots(ctx, req.UserID, []string{req.ScheduleType}); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
     id, err := s.repo.CreateRoutine(ctx, req.Day, req.ScheduleType, req.TaskID, req.UserID, req.IsActive)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to create routine: %w", functionName, err)
@@ -27,27 +39,45 @@ func (s *RoutineService) CreateRoutine(ctx context.Context, req *dto.CreateRouti
 }
 
 // UpdateRoutineStatus updates the active status of a routine
-func (s *RoutineService) UpdateRoutineStatus(ctx context.Context, id string, isActive bool) (*dto.SuccessResponse, error) {
+func (s *RoutineService) UpdateRoutineStatus(ctx context.Context, id string, isActive bool, expectedVersion int) (*dto.SuccessResponse, error) {
     const functionName = "services.routines.RoutineService.UpdateRoutineStatus"
     
-    err := s.repo.UpdateRoutineStatus(ctx, id, isActive)
+    err := s.repo.UpdateRoutineStatus(ctx, id, isActive, expectedVersion)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to update routine status: %w", functionName, err)
     }
     
-    return &dto.SuccessResponse{Success: true}, nil
+    res, err := s.written(ctx, id)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    return res, nil
 }
 
 // UpdateRoutineDay updates the day of a routine
-func (s *RoutineService) UpdateRoutineDay(ctx context.Context, id, day string) (*dto.SuccessResponse, error) {
+func (s *RoutineService) UpdateRoutineDay(ctx context.Context, id, day string, expectedVersion int) (*dto.SuccessResponse, error) {
     const functionName = "services.routines.RoutineService.UpdateRoutineDay"
     
-    err := s.repo.UpdateRoutineDay(ctx, id, day)
+    err := s.repo.UpdateRoutineDay(ctx, id, day, expectedVersion)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to update routine day: %w", functionName, err)
     }
     
-    return &dto.SuccessResponse{Success: true}, nil
+    res, err := s.written(ctx, id)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    return res, nil
+}
+
+// written reports a successful write together with the routine's new version,
+// which the handlers return as the ETag
+func (s *RoutineService) written(ctx context.Context, id string) (*dto.SuccessResponse, error) {
+    routine, err := s.repo.GetRoutineByID(ctx, id)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get routine: %w", err)
+    }
+    return &dto.SuccessResponse{Success: true, Version: routine.Version}, nil
 }
 
 // GetRoutinesByTaskID gets routines for a specific task
@@ -71,24 +101,59 @@ func (s *RoutineService) GetRoutinesByTaskID(ctx context.Context, taskID string)
             CreatedAt:    routine.CreatedAt,
             UpdatedAt:    routine.UpdatedAt,
             IsActive:     routine.IsActive,
+            Version:      routine.Version,
         })
     }
     
     return &dto.RoutinesResponse{Routines: routineResponses}, nil
 }
 
-// GetDailyRoutines gets todos for a specific day and schedule type
-func (s *RoutineService) GetDailyRoutines(ctx context.Context, day, scheduleType, userID string) (*dto.TodosResponse, error) {
+// GetDailyRoutines gets todos for a specific day and schedule type. Routines
+// paused on the next occurrence of day, counted in loc, are left out.
+func (s *RoutineService) GetDailyRoutines(ctx context.Context, day, scheduleType, userID string, loc *time.Location) (*dto.TodosResponse, error) {
     const functionName = "services.routines.RoutineService.GetDailyRoutines"
     
+    todos, err := s.dailyRoutines(ctx, day, scheduleType, userID, dateOn(users.Today(loc), day))
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    return todos, nil
+}
+
+// dailyRoutines gets the todos of a day and schedule type that are not paused on date
+func (s *RoutineService) dailyRoutines(ctx context.Context, day, scheduleType, userID string, date time.Time) (*dto.TodosResponse, error) {
     todos, err := s.repo.GetDailyRoutines(ctx, day, scheduleType, userID)
     if err != nil {
-        return nil, fmt.Errorf("%s: failed to get daily routines: %w", functionName, err)
+        return nil, fmt.Errorf("failed to get daily routines: %w", err)
+    }
+    
+    all, paused, err := s.pausedOn(ctx, userID, date)
+    if err != nil {
+        return nil, err
+    }
+    if all {
+        return &dto.TodosResponse{}, nil
+    }
+    pausedTasks := make(map[string]bool)
+    if len(paused) > 0 {
+        // Routines only reach the todo list through their task, so map the paused ones over
+        checkins, err := s.repo.GetDailyRoutineCheckins(ctx, day, scheduleType, userID, date)
+        if err != nil {
+            return nil, fmt.Errorf("failed to get routines: %w", err)
+        }
+        for _, checkin := range checkins {
+            if paused[checkin.RoutineID] {
+                pausedTasks[checkin.TaskID] = true
+            }
+        }
     }
     
     // Convert domain todos to DTO response
     var todoResponses []dto.TodoResponse
     for _, todo := range todos {
+        if pausedTasks[todo.ID] {
+            continue
+        }
         todoResponses = append(todoResponses, dto.TodoResponse{
             ID:          todo.ID,
             Task:        todo.Task,
@@ -98,22 +163,282 @@ func (s *RoutineService) GetDailyRoutines(ctx context.Context, day, scheduleType
             UserID:      todo.UserID,
             Date:        todo.Date,
             Time:        todo.Time,
+            Version:     todo.Version,
         })
     }
     
     return &dto.TodosResponse{Todos: todoResponses}, nil
 }
 
-// GetTodayRoutines gets todos for today's routines by schedule type
-func (s *RoutineService) GetTodayRoutines(ctx context.Context, scheduleType, userID string) (*dto.TodosResponse, error) {
+// pausedOn reports whether a pause covers all of the user's routines on date,
+// and otherwise which routines are paused
+func (s *RoutineService) pausedOn(ctx context.Context, userID string, date time.Time) (bool, map[string]bool, error) {
+    pauses, err := s.repo.GetRoutinePauses(ctx, userID)
+    if err != nil {
+        return false, nil, fmt.Errorf("failed to get pauses: %w", err)
+    }
+    all, paused := domain.PausedRoutines(pauses, date)
+    return all, paused, nil
+}
+
+// dateOn returns the first date on or after from that falls on day
+func dateOn(from time.Time, day string) time.Time {
+    for i := 0; i < 7; i++ {
+        date := from.AddDate(0, 0, i)
+        if strings.ToLower(date.Weekday().String()) == day {
+            return date
+        }
+    }
+    return from
+}
+
+// GetTodayRoutines gets todos for today's routines by schedule type, where today
+// is counted in loc. Done comes from today's check-in, so every routine starts
+// the day undone. Routines paused today are left out.
+func (s *RoutineService) GetTodayRoutines(ctx context.Context, scheduleType, userID string, loc *time.Location) (*dto.TodosResponse, error) {
     const functionName = "services.routines.RoutineService.GetTodayRoutines"
     
-    // Get today's day name (sunday, monday, etc.)
-    dayName := strings.ToLower(time.Now().Weekday().String())
+    checkins, err := s.GetTodayCheckins(ctx, scheduleType, userID, loc)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    todos := make([]dto.TodoResponse, len(checkins.Todos))
+    for i, todo := range checkins.Todos {
+        todos[i] = todo.TodoResponse
+    }
+    return &dto.TodosResponse{Todos: todos}, nil
+}
+
+// GetTodayCheckins lists the same todos as GetTodayRoutines together with the
+// routine each belongs to and whether it was checked in today
+func (s *RoutineService) GetTodayCheckins(ctx context.Context, scheduleType, userID string, loc *time.Location) (*dto.RoutineTodosResponse, error) {
+    const functionName = "services.routines.RoutineService.GetTodayCheckins"
+    
+    today := users.Today(loc)
+    todos, err := s.checkinsOn(ctx, scheduleType, userID, today)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    return &dto.RoutineTodosResponse{Date: today.Format(dateLayout), Todos: todos}, nil
+}
+
+// GetSlotCheckins lists the routines of date that are not paused, grouped by slot
+// in start time order, with whether each was checked in on date. Slots without
+// routines are left out.
+func (s *RoutineService) GetSlotCheckins(ctx context.Context, userID string, date time.Time) ([]dto.RoutineSlotCheckinsResponse, error) {
+    const functionName = "services.routines.RoutineService.GetSlotCheckins"
+    
+    slots, err := s.slots(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    res := []dto.RoutineSlotCheckinsResponse{}
+    for _, slot := range slots {
+        todos, err := s.checkinsOn(ctx, slot.Name, userID, date)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+        if len(todos) == 0 {
+            continue
+        }
+        res = append(res, dto.RoutineSlotCheckinsResponse{Slot: newRoutineSlotResponse(slot), Todos: todos})
+    }
+    
+    return res, nil
+}
+
+// checkinsOn gets the todos of a schedule type on date that are not paused, with
+// Done taken from the check-in of date
+func (s *RoutineService) checkinsOn(ctx context.Context, scheduleType, userID string, date time.Time) ([]dto.RoutineTodoResponse, error) {
+    // Get the date's day name (sunday, monday, etc.)
+    dayName := strings.ToLower(date.Weekday().String())
     
-    return s.GetDailyRoutines(ctx, dayName, scheduleType, userID)
+    todos, err := s.dailyRoutines(ctx, dayName, scheduleType, userID, date)
+    if err != nil {
+        return nil, err
+    }
+    checkins, err := s.repo.GetDailyRoutineCheckins(ctx, dayName, scheduleType, userID, date)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get check-ins: %w", err)
+    }
+    byTask := make(map[string]domain.RoutineCheckin, len(checkins))
+    for _, checkin := range checkins {
+        byTask[checkin.TaskID] = checkin
+    }
+    
+    res := []dto.RoutineTodoResponse{}
+    for _, todo := range todos.Todos {
+        checkin := byTask[todo.ID]
+        todo.Done = checkin.CheckedIn
+        res = append(res, dto.RoutineTodoResponse{
+            TodoResponse: todo,
+            RoutineID:    checkin.RoutineID,
+            CheckedIn:    checkin.CheckedIn,
+        })
+    }
+    return res, nil
 }
 
+// CheckIn records that the user did the routine on the requested day
+func (s *RoutineService) CheckIn(ctx context.Context, req *dto.RoutineCheckinRequest) (*dto.RoutineCheckinResponse, error) {
+    const functionName = "services.routines.RoutineService.CheckIn"
+    
+    routine, date, err := s.checkinTarget(ctx, req)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    if !routine.IsActive {
+        return nil, fmt.Errorf("%s: routine is not active", functionName)
+    }
+    
+    if err := s.repo.CheckInRoutine(ctx, routine.ID, req.UserID, date); err != nil {
+        return nil, fmt.Errorf("%s: failed to check in routine: %w", functionName, err)
+    }
+    
+    return &dto.RoutineCheckinResponse{RoutineID: routine.ID, Date: date.Format(dateLayout), CheckedIn: true}, nil
+}
+
+// Uncheck removes the check-in of the requested day. Unchecking a day that was
+// never checked in succeeds.
+func (s *RoutineService) Uncheck(ctx context.Context, req *dto.RoutineCheckinRequest) (*dto.RoutineCheckinResponse, error) {
+    const functionName = "services.routines.RoutineService.Uncheck"
+    
+    routine, date, err := s.checkinTarget(ctx, req)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    if _, err := s.repo.UncheckRoutine(ctx, routine.ID, date); err != nil {
+        return nil, fmt.Errorf("%s: failed to uncheck routine: %w", functionName, err)
+    }
+    
+    return &dto.RoutineCheckinResponse{RoutineID: routine.ID, Date: date.Format(dateLayout), CheckedIn: false}, nil
+}
+
+// checkinTarget loads the user's routine and validates the day of a check-in
+func (s *RoutineService) checkinTarget(ctx context.Context, req *dto.RoutineCheckinRequest) (*domain.Routine, time.Time, error) {
+    routine, err := s.repo.GetRoutineByID(ctx, req.RoutineID)
+    if err != nil {
+        return nil, time.Time{}, err
+    }
+    if routine.UserID != req.UserID {
+        return nil, time.Time{}, fmt.Errorf("routine not found")
+    }
+    
+    today := users.Today(req.Location)
+    date := today
+    if req.Date != "" {
+        parsed, err := time.Parse(dateLayout, req.Date)
+        if err != nil {
+            return nil, time.Time{}, fmt.Errorf("invalid date %q", req.Date)
+        }
+        date = parsed
+    }
+    if date.After(today) {
+        return nil, time.Time{}, fmt.Errorf("cannot check in a future date")
+    }
+    if weekday := strings.ToLower(date.Weekday().String()); weekday != routine.Day {
+        return nil, time.Time{}, fmt.Errorf("routine is not scheduled on %s", weekday)
+    }
+    return routine, date, nil
+}
+
+// GetStreak counts the check-ins of a routine together with the user's other
+// routines for the same task and schedule type, i.e. the same routine on its
+// other days. Today only extends the current streak once it is checked in, and
+// paused days are skipped like days off.
+func (s *RoutineService) GetStreak(ctx context.Context, routineID, userID string, loc *time.Location) (*dto.RoutineStreakResponse, error) {
+    const functionName = "services.routines.RoutineService.GetStreak"
+    
+    routine, err := s.repo.GetRoutineByID(ctx, routineID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    if routine.UserID != userID {
+        return nil, fmt.Errorf("%s: routine not found", functionName)
+    }
+    
+    days, err := s.repo.GetRoutineScheduleDays(ctx, routine.TaskID, routine.ScheduleType, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get schedule: %w", functionName, err)
+    }
+    dates, err := s.repo.GetRoutineCheckinDates(ctx, routine.TaskID, routine.ScheduleType, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get check-ins: %w", functionName, err)
+    }
+    pauses, err := s.repo.GetRoutinePauses(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get pauses: %w", functionName, err)
+    }
+    
+    res := &dto.RoutineStreakResponse{
+        RoutineID:     routine.ID,
+        TaskID:        routine.TaskID,
+        ScheduleType:  routine.ScheduleType,
+        ScheduledDays: days,
+        TotalCheckins: len(dates),
+        History:       []string{},
+    }
+    checked := make(map[string]bool, len(dates))
+    for _, date := range dates {
+        day := date.Format(dateLayout)
+        checked[day] = true
+        res.History = append(res.History, day)
+    }
+    if len(dates) == 0 {
+        return res, nil
+    }
+    res.LastCheckin = res.History[len(res.History)-1]
+    
+    scheduled := make(map[time.Weekday]bool, len(days))
+    for _, day := range days {
+        for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+            if strings.ToLower(weekday.String()) == day {
+                scheduled[weekday] = true
+            }
+        }
+    }
+    
+    today := users.Today(loc)
+    first, _ := time.Parse(dateLayout, res.History[0])
+    run := 0
+    for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+        key := day.Format(dateLayout)
+        switch {
+        case (!scheduled[day.Weekday()] || pausedDay(pauses, routine, day)) && !checked[key]:
+            // days off and paused days neither extend nor break the streak
+        case checked[key]:
+            run++
+            if run > res.LongestStreak {
+                res.LongestStreak = run
+            }
+        case !day.Equal(today):
+            run = 0
+        }
+    }
+    res.CurrentStreak = run
+    
+    return res, nil
+}
+
+// pausedDay reports whether a pause covers the routine's task and schedule type on date
+func pausedDay(pauses []domain.RoutinePause, routine *domain.Routine, date time.Time) bool {
+    weekday := strings.ToLower(date.Weekday().String())
+    for _, pause := range pauses {
+        if !pause.Covers(date) {
+            continue
+        }
+        if pause.RoutineID == "" || (pause.TaskID == routine.TaskID && pause.ScheduleType == routine.ScheduleType && pause.Day == weekday) {
+            return true
+        }
+    }
+    return false
+}
+
+
 // DeleteRoutinesByTaskID deletes all routines for a task
 func (s *RoutineService) DeleteRoutinesByTaskID(ctx context.Context, taskID string) (*dto.SuccessResponse, error) {
     const functionName = "services.routines.RoutineService.DeleteRoutinesByTaskID"
@@ -133,7 +458,11 @@ func (s *RoutineService) CreateOrUpdateRoutines(ctx context.Context, req *dto.Cr
     // Use current day if not provided
     day := req.Day
     if day == "" {
-        day = strings.ToLower(time.Now().Weekday().String())
+        day = strings.ToLower(users.Today(req.Location).Weekday().String())
+    }
+    
+    if err := s.checkSlots(ctx, req.UserID, req.Schedules); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
     }
     
     // Call the repository method
@@ -154,12 +483,417 @@ func (s *RoutineService) CreateOrUpdateRoutines(ctx context.Context, req *dto.Cr
             CreatedAt:    routine.CreatedAt,
             UpdatedAt:    routine.UpdatedAt,
             IsActive:     routine.IsActive,
+            Version:      routine.Version,
         })
     }
     
     return &dto.RoutinesResponse{Routines: routineResponses}, nil
 }
 
+// GetDayRoutines gets the todos of every slot on a day, ordered by slot start time.
+// Like GetDailyRoutines it leaves out routines paused on the next occurrence of day.
+func (s *RoutineService) GetDayRoutines(ctx context.Context, day, userID string, loc *time.Location) (*dto.RoutineDayResponse, error) {
+    const functionName = "services.routines.RoutineService.GetDayRoutines"
+    
+    slots, err := s.slots(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    date := dateOn(users.Today(loc), day)
+    res := &dto.RoutineDayResponse{Day: day, Slots: []dto.RoutineSlotTodosResponse{}}
+    for _, slot := range slots {
+        todos, err := s.dailyRoutines(ctx, day, slot.Name, userID, date)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+        if len(todos.Todos) == 0 {
+            continue
+        }
+        res.Slots = append(res.Slots, dto.RoutineSlotTodosResponse{Slot: newRoutineSlotResponse(slot), Todos: todos.Todos})
+    }
+    
+    return res, nil
+}
+
+// SetWeeklySchedule replaces the days and slots a task repeats in with the
+// requested matrix in one transaction
+func (s *RoutineService) SetWeeklySchedule(ctx context.Context, req *dto.SetWeeklyScheduleRequest) (*dto.WeeklyScheduleResponse, error) {
+    const functionName = "services.routines.RoutineService.SetWeeklySchedule"
+    
+    todo, err := s.todoRepo.GetTodoByID(ctx, req.TaskID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    if todo.UserID != req.UserID {
+        return nil, fmt.Errorf("%s: todo not found", functionName)
+    }
+    
+    for day := range req.Schedule {
+        if !contains(weekDays, day) {
+            return nil, fmt.Errorf("%s: invalid day %q", functionName, day)
+        }
+    }
+    
+    res := &dto.WeeklyScheduleResponse{TaskID: req.TaskID, Schedule: map[string][]string{}}
+    var schedule []domain.RoutineSchedule
+    var names []string
+    for _, day := range weekDays {
+        for _, name := range req.Schedule[day] {
+            if contains(res.Schedule[day], name) {
+                continue
+            }
+            res.Schedule[day] = append(res.Schedule[day], name)
+            schedule = append(schedule, domain.RoutineSchedule{Day: day, ScheduleType: name})
+            names = append(names, name)
+        }
+    }
+    if err := s.checkSlots(ctx, req.UserID, names); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    changes, err := s.repo.SetWeeklyRoutines(ctx, req.TaskID, req.UserID, schedule)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to set weekly routines: %w", functionName, err)
+    }
+    res.Created = changes.Created
+    res.Reactivated = changes.Reactivated
+    res.Deactivated = changes.Deactivated
+    
+    return res, nil
+}
+
+// GetWeek lists the user's active routines for every day and slot of the week
+func (s *RoutineService) GetWeek(ctx context.Context, userID string) (*dto.RoutineWeekResponse, error) {
+    const functionName = "services.routines.RoutineService.GetWeek"
+    
+    slots, err := s.slots(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    routines, err := s.repo.GetWeekRoutines(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get routines: %w", functionName, err)
+    }
+    
+    cells := make(map[domain.RoutineSchedule][]dto.RoutineWeekEntryResponse)
+    known := make(map[string]bool, len(slots))
+    for _, slot := range slots {
+        known[slot.Name] = true
+    }
+    for _, routine := range routines {
+        // Routines left in a slot that no longer exists still show up after the known slots
+        if !known[routine.ScheduleType] {
+            known[routine.ScheduleType] = true
+            slots = append(slots, domain.RoutineSlot{Name: routine.ScheduleType})
+        }
+        key := domain.RoutineSchedule{Day: routine.Day, ScheduleType: routine.ScheduleType}
+        cells[key] = append(cells[key], dto.RoutineWeekEntryResponse{
+            RoutineID:   routine.RoutineID,
+            TaskID:      routine.TaskID,
+            Task:        routine.Task,
+            Description: routine.Description,
+            Important:   routine.Important,
+        })
+    }
+    
+    res := &dto.RoutineWeekResponse{Days: make([]dto.RoutineWeekDayResponse, len(weekDays))}
+    for i, day := range weekDays {
+        res.Days[i] = dto.RoutineWeekDayResponse{Day: day, Slots: make([]dto.RoutineWeekSlotResponse, len(slots))}
+        for j, slot := range slots {
+            entries := cells[domain.RoutineSchedule{Day: day, ScheduleType: slot.Name}]
+            if entries == nil {
+                entries = []dto.RoutineWeekEntryResponse{}
+            }
+            res.Days[i].Slots[j] = dto.RoutineWeekSlotResponse{Slot: newRoutineSlotResponse(slot), Routines: entries}
+        }
+    }
+    
+    return res, nil
+}
+
+// CreatePause pauses one of the user's routines, or all of them, over a date range
+func (s *RoutineService) CreatePause(ctx context.Context, req *dto.CreateRoutinePauseRequest, loc *time.Location) (*dto.RoutinePauseResponse, error) {
+    const functionName = "services.routines.RoutineService.CreatePause"
+    
+    start, err := time.Parse(dateLayout, req.StartDate)
+    if err != nil {
+        return nil, fmt.Errorf("%s: invalid start_date %q", functionName, req.StartDate)
+    }
+    end, err := time.Parse(dateLayout, req.EndDate)
+    if err != nil {
+        return nil, fmt.Errorf("%s: invalid end_date %q", functionName, req.EndDate)
+    }
+    if end.Before(start) {
+        return nil, fmt.Errorf("%s: end_date must not be before start_date", functionName)
+    }
+    reason := strings.TrimSpace(req.Reason)
+    if len(reason) > 255 {
+        return nil, fmt.Errorf("%s: reason is too long", functionName)
+    }
+    
+    pause := domain.RoutinePause{
+        ID:        uuid.New().String(),
+        UserID:    req.UserID,
+        StartDate: start,
+        EndDate:   end,
+        Reason:    reason,
+    }
+    if req.RoutineID != "" {
+        routine, err := s.repo.GetRoutineByID(ctx, req.RoutineID)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+        if routine.UserID != req.UserID {
+            return nil, fmt.Errorf("%s: routine not found", functionName)
+        }
+        pause.RoutineID = routine.ID
+    }
+    
+    if err := s.repo.CreateRoutinePause(ctx, pause); err != nil {
+        return nil, fmt.Errorf("%s: failed to create pause: %w", functionName, err)
+    }
+    
+    res := newRoutinePauseResponse(pause, users.Today(loc))
+    return &res, nil
+}
+
+// GetPauses lists the user's pauses, including ended ones
+func (s *RoutineService) GetPauses(ctx context.Context, userID string, loc *time.Location) (*dto.RoutinePausesResponse, error) {
+    const functionName = "services.routines.RoutineService.GetPauses"
+    
+    pauses, err := s.repo.GetRoutinePauses(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get pauses: %w", functionName, err)
+    }
+    
+    today := users.Today(loc)
+    res := &dto.RoutinePausesResponse{Pauses: make([]dto.RoutinePauseResponse, len(pauses))}
+    for i, pause := range pauses {
+        res.Pauses[i] = newRoutinePauseResponse(pause, today)
+    }
+    return res, nil
+}
+
+// DeletePause removes a pause, resuming its routines straight away
+func (s *RoutineService) DeletePause(ctx context.Context, id, userID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.routines.RoutineService.DeletePause"
+    
+    deleted, err := s.repo.DeleteRoutinePause(ctx, id, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to delete pause: %w", functionName, err)
+    }
+    if !deleted {
+        return nil, fmt.Errorf("%s: pause not found", functionName)
+    }
+    
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+func newRoutinePauseResponse(pause domain.RoutinePause, today time.Time) dto.RoutinePauseResponse {
+    return dto.RoutinePauseResponse{
+        ID:        pause.ID,
+        RoutineID: pause.RoutineID,
+        StartDate: pause.StartDate.Format(dateLayout),
+        EndDate:   pause.EndDate.Format(dateLayout),
+        Reason:    pause.Reason,
+        Active:    pause.Covers(today),
+    }
+}
+
+// GetSlots lists the user's routine slots, earliest first
+func (s *RoutineService) GetSlots(ctx context.Context, userID string) (*dto.RoutineSlotsResponse, error) {
+    const functionName = "services.routines.RoutineService.GetSlots"
+    
+    slots, err := s.slots(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    res := &dto.RoutineSlotsResponse{Slots: make([]dto.RoutineSlotResponse, len(slots))}
+    for i, slot := range slots {
+        res.Slots[i] = newRoutineSlotResponse(slot)
+    }
+    return res, nil
+}
+
+// CreateSlot adds a named slot such as "gym" at "06:30"
+func (s *RoutineService) CreateSlot(ctx context.Context, req *dto.RoutineSlotRequest) (*dto.RoutineSlotResponse, error) {
+    const functionName = "services.routines.RoutineService.CreateSlot"
+    
+    slot, err := newRoutineSlot(req)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    // Make sure the defaults exist before the user's first own slot
+    if _, err := s.slots(ctx, req.UserID); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    slot.ID = uuid.New().String()
+    
+    if err := s.repo.CreateRoutineSlots(ctx, []domain.RoutineSlot{slot}); err != nil {
+        return nil, fmt.Errorf("%s: failed to create slot: %w", functionName, err)
+    }
+    
+    res := newRoutineSlotResponse(slot)
+    return &res, nil
+}
+
+// UpdateSlot renames or retimes a slot. Routines follow a renamed slot.
+func (s *RoutineService) UpdateSlot(ctx context.Context, req *dto.RoutineSlotRequest) (*dto.RoutineSlotResponse, error) {
+    const functionName = "services.routines.RoutineService.UpdateSlot"
+    
+    slot, err := newRoutineSlot(req)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    current, err := s.findSlot(ctx, req.ID, req.UserID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    slot.ID = current.ID
+    
+    if err := s.repo.UpdateRoutineSlot(ctx, slot, current.Name); err != nil {
+        return nil, fmt.Errorf("%s: failed to update slot: %w", functionName, err)
+    }
+    
+    res := newRoutineSlotResponse(slot)
+    return &res, nil
+}
+
+// DeleteSlot removes a slot that no routine is scheduled in
+func (s *RoutineService) DeleteSlot(ctx context.Context, id, userID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.routines.RoutineService.DeleteSlot"
+    
+    slots, err := s.slots(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    var slot *domain.RoutineSlot
+    for i := range slots {
+        if slots[i].ID == id {
+            slot = &slots[i]
+        }
+    }
+    if slot == nil {
+        return nil, fmt.Errorf("%s: slot not found", functionName)
+    }
+    if len(slots) == 1 {
+        return nil, fmt.Errorf("%s: cannot delete the last slot", functionName)
+    }
+    count, err := s.repo.CountSlotRoutines(ctx, userID, slot.Name)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to count routines: %w", functionName, err)
+    }
+    if count > 0 {
+        return nil, fmt.Errorf("%s: slot is in use by %d routines", functionName, count)
+    }
+    
+    deleted, err := s.repo.DeleteRoutineSlot(ctx, id, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to delete slot: %w", functionName, err)
+    }
+    if !deleted {
+        return nil, fmt.Errorf("%s: slot not found", functionName)
+    }
+    
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// SlotForTime picks the slot a time of day falls into: the latest slot starting
+// at or before it, or the first slot for times before every slot and for no time
+func (s *RoutineService) SlotForTime(ctx context.Context, userID string, t time.Time) (string, error) {
+    const functionName = "services.routines.RoutineService.SlotForTime"
+    
+    slots, err := s.slots(ctx, userID)
+    if err != nil {
+        return "", fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    name := slots[0].Name
+    if t.IsZero() {
+        return name, nil
+    }
+    clock := t.Format("15:04")
+    for _, slot := range slots {
+        if slot.StartTime <= clock {
+            name = slot.Name
+        }
+    }
+    return name, nil
+}
+
+// slots returns the user's slots ordered by start time, creating the default
+// slots for users who have none yet
+func (s *RoutineService) slots(ctx context.Context, userID string) ([]domain.RoutineSlot, error) {
+    slots, err := s.repo.GetRoutineSlots(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get slots: %w", err)
+    }
+    if len(slots) > 0 {
+        return slots, nil
+    }
+    
+    slots = domain.DefaultRoutineSlots()
+    for i := range slots {
+        slots[i].ID = uuid.New().String()
+        slots[i].UserID = userID
+    }
+    if err := s.repo.CreateRoutineSlots(ctx, slots); err != nil {
+        return nil, fmt.Errorf("failed to create default slots: %w", err)
+    }
+    return slots, nil
+}
+
+// findSlot returns the user's slot with the given ID
+func (s *RoutineService) findSlot(ctx context.Context, id, userID string) (*domain.RoutineSlot, error) {
+    slots, err := s.slots(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    for _, slot := range slots {
+        if slot.ID == id {
+            return &slot, nil
+        }
+    }
+    return nil, fmt.Errorf("slot not found")
+}
+
+// checkSlots verifies that every schedule type names one of the user's slots
+func (s *RoutineService) checkSlots(ctx context.Context, userID string, names []string) error {
+    slots, err := s.slots(ctx, userID)
+    if err != nil {
+        return err
+    }
+    known := make([]string, len(slots))
+    for i, slot := range slots {
+        known[i] = slot.Name
+    }
+    for _, name := range names {
+        if !contains(known, name) {
+            return fmt.Errorf("unknown slot %q", name)
+        }
+    }
+    return nil
+}
+
+// newRoutineSlot validates a slot request
+func newRoutineSlot(req *dto.RoutineSlotRequest) (domain.RoutineSlot, error) {
+    name := strings.TrimSpace(req.Name)
+    if name == "" || len(name) > 50 || strings.Contains(name, "/") {
+        return domain.RoutineSlot{}, fmt.Errorf("invalid slot name %q", req.Name)
+    }
+    start, err := time.Parse("15:04", req.StartTime)
+    if err != nil {
+        return domain.RoutineSlot{}, fmt.Errorf("invalid start time %q", req.StartTime)
+    }
+    return domain.RoutineSlot{UserID: req.UserID, Name: name, StartTime: start.Format("15:04")}, nil
+}
+
+func newRoutineSlotResponse(slot domain.RoutineSlot) dto.RoutineSlotResponse {
+    return dto.RoutineSlotResponse{ID: slot.ID, Name: slot.Name, StartTime: slot.StartTime}
+}
+
 // Helper function to check if a string is in a slice
 func contains(slice []string, item string) bool {
     for _, s := range slice {
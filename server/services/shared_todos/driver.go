@@ -4,10 +4,11 @@ import (
 	"github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
 )
 
-func NewSharedTodoService(repo domain.SharedTodoRepository, todoRepo domain.TodoRepository, userRepo domain.UserRepository) *SharedTodoService {
+func NewSharedTodoService(repo domain.SharedTodoRepository, todoRepo domain.TodoRepository, userRepo domain.UserRepository, teamMemberRepo domain.TeamMemberRepository) *SharedTodoService {
     return &SharedTodoService{
-        repo:     repo,
-        todoRepo: todoRepo,
-        userRepo: userRepo,
+        repo:           repo,
+        todoRepo:       todoRepo,
+        userRepo:       userRepo,
+        teamMemberRepo: teamMemberRepo,
     }
 }
\ No newline at end of file
@@ -13,13 +13,21 @@ type SharedTodoService struct {
     repo domain.SharedTodoRepository
     todoRepo domain.TodoRepository
     userRepo domain.UserRepository
+    teamMemberRepo domain.TeamMemberRepository
 }
 
 
-// ShareTodo shares a todo with another user
-func (s *SharedTodoService) ShareTodo(ctx context.Context, todoID string, recipientUserID string, sharedBy string) error {
+// ShareTodo shares a todo with another user. Without a permission the share is view only.
+func (s *SharedTodoService) ShareTodo(ctx context.Context, todoID string, recipientUserID string, sharedBy string, permission string) error {
     const functionName = "services.shared_todos.SharedTodoService.ShareTodo"
     
+    if permission == "" {
+        permission = domain.SharePermissionView
+    }
+    if !domain.ValidSharePermission(permission) {
+        return fmt.Errorf("%s: invalid permission %q", functionName, permission)
+    }
+    
     // Get the original todo to make sure it exists and belongs to the current user
     todo, err := s.todoRepo.GetTodoByID(ctx, todoID)
     if err != nil {
@@ -41,8 +49,12 @@ func (s *SharedTodoService) ShareTodo(ctx context.Context, todoID string, recipi
         return fmt.Errorf("%s: todo is already shared with this user", functionName)
     }
     
+    if err := s.checkNotBlocked(ctx, recipientUserID, sharedBy); err != nil {
+        return fmt.Errorf("%s: %w", functionName, err)
+    }
+    
     // Share the todo
-    err = s.repo.ShareTodo(ctx, todoID, recipientUserID, sharedBy)
+    err = s.repo.ShareTodo(ctx, todoID, recipientUserID, sharedBy, permission)
     if err != nil {
         return fmt.Errorf("%s: failed to share todo: %w", functionName, err)
     }
@@ -50,6 +62,89 @@ func (s *SharedTodoService) ShareTodo(ctx context.Context, todoID string, recipi
     return nil
 }
 
+// ShareTodoWithMany shares a todo with the given users and the other members of a
+// team in one go. Every recipient gets a result, so unknown usernames and users
+// who already have the todo do not fail the whole request.
+func (s *SharedTodoService) ShareTodoWithMany(ctx context.Context, req *dto.ShareTodoRequest) (*dto.ShareTodoResponse, error) {
+    const functionName = "services.shared_todos.SharedTodoService.ShareTodoWithMany"
+    
+    permission := req.Permission
+    if permission == "" {
+        permission = domain.SharePermissionView
+    }
+    if !domain.ValidSharePermission(permission) {
+        return nil, fmt.Errorf("%s: invalid permission %q", functionName, permission)
+    }
+    if len(req.Usernames) == 0 && req.TeamID == "" {
+        return nil, fmt.Errorf("%s: no recipients given", functionName)
+    }
+    
+    todo, err := s.todoRepo.GetTodoByID(ctx, req.TodoID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get todo: %w", functionName, err)
+    }
+    if todo.UserID != req.SharedBy {
+        return nil, fmt.Errorf("%s: unauthorized to share this todo", functionName)
+    }
+    
+    res := &dto.ShareTodoResponse{Results: []dto.ShareResultResponse{}}
+    var recipients []string
+    // index maps a recipient to its result, so a user named and in the team is shared with once
+    index := make(map[string]int)
+    seen := make(map[string]bool)
+    for _, username := range req.Usernames {
+        if seen[username] {
+            continue
+        }
+        seen[username] = true
+        
+        user, err := s.userRepo.GetUserByUsername(ctx, username)
+        _, added := index[user.ID]
+        switch {
+        case err != nil:
+            res.Results = append(res.Results, dto.ShareResultResponse{Username: username, Result: domain.ShareResultNotFound})
+        case user.ID == req.SharedBy:
+            res.Results = append(res.Results, dto.ShareResultResponse{Username: username, UserID: user.ID, Result: domain.ShareResultSelf})
+        case !added:
+            index[user.ID] = len(res.Results)
+            recipients = append(recipients, user.ID)
+            res.Results = append(res.Results, dto.ShareResultResponse{Username: username, UserID: user.ID})
+        }
+    }
+    
+    if req.TeamID != "" {
+        members, err := s.teamMemberRepo.GetTeamMembers(ctx, req.TeamID)
+        if err != nil {
+            return nil, fmt.Errorf("%s: failed to get team members: %w", functionName, err)
+        }
+        isMember := false
+        for _, member := range members {
+            isMember = isMember || member.UserID == req.SharedBy
+        }
+        if !isMember {
+            return nil, fmt.Errorf("%s: not a member of this team", functionName)
+        }
+        for _, member := range members {
+            if _, added := index[member.UserID]; added || member.UserID == req.SharedBy {
+                continue
+            }
+            index[member.UserID] = len(res.Results)
+            recipients = append(recipients, member.UserID)
+            res.Results = append(res.Results, dto.ShareResultResponse{UserID: member.UserID})
+        }
+    }
+    
+    outcomes, err := s.repo.ShareTodoWithUsers(ctx, req.TodoID, recipients, req.SharedBy, permission)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to share todo: %w", functionName, err)
+    }
+    for userID, i := range index {
+        res.Results[i].Result = outcomes[userID]
+    }
+    
+    return res, nil
+}
+
 // In server/services/shared_todos/shared_todo_service.go
 func (s *SharedTodoService) CreateSharedTodo(ctx context.Context, req *dto.CreateSharedTodoRequest) (*dto.CreateResponse, error) {
     const functionName = "services.shared_todos.SharedTodoService.CreateSharedTodo"
@@ -102,6 +197,10 @@ func (s *SharedTodoService) CreateSharedTodo(ctx context.Context, req *dto.Creat
         timeValue = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
     }
     
+    if err := s.checkNotBlocked(ctx, req.UserID, req.SharedBy); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
     id, err := s.repo.CreateSharedTodo(ctx, req.Task, req.Description, req.Done, req.Important, req.UserID, req.SharedBy)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to create shared todo: %w", functionName, err)
@@ -109,6 +208,8 @@ func (s *SharedTodoService) CreateSharedTodo(ctx context.Context, req *dto.Creat
     return &dto.CreateResponse{ID: id}, nil
 }
 
+// GetSharedTodos returns the shares the user accepted, and the pending ones
+// waiting in their inbox separately
 func (s *SharedTodoService) GetSharedTodos(ctx context.Context, userID string) (*dto.SharedTodosResponse, error) {
     const functionName = "services.shared_todos.SharedTodoService.GetSharedTodos"
     domainTodos, err := s.repo.GetSharedTodos(ctx, userID)
@@ -117,9 +218,9 @@ func (s *SharedTodoService) GetSharedTodos(ctx context.Context, userID string) (
     }
     
     // Convert domain.SharedTodo to dto.SharedTodoResponse
-    var receivedTodos []dto.SharedTodoResponse
+    var receivedTodos, pendingTodos []dto.SharedTodoResponse
     for _, todo := range domainTodos {
-        receivedTodos = append(receivedTodos, dto.SharedTodoResponse{
+        res := dto.SharedTodoResponse{
             ID:          todo.ID,
             Task:        todo.Task,
             Description: todo.Description,
@@ -129,10 +230,19 @@ func (s *SharedTodoService) GetSharedTodos(ctx context.Context, userID string) (
             Date:        todo.Date,
             Time:        todo.Time,
             SharedBy:    todo.SharedBy,
-        })
+            TodoID:      todo.TodoID,
+            Version:     todo.Version,
+            Permission:  todo.Permission,
+            Status:      todo.Status,
+        }
+        if todo.Accepted() {
+            receivedTodos = append(receivedTodos, res)
+        } else {
+            pendingTodos = append(pendingTodos, res)
+        }
     }
     
-    return &dto.SharedTodosResponse{Received: receivedTodos}, nil
+    return &dto.SharedTodosResponse{Received: receivedTodos, Pending: pendingTodos}, nil
 }
 
 func (s *SharedTodoService) GetSharedByMeTodos(ctx context.Context, sharedBy string) (*dto.SharedTodosResponse, error) {
@@ -155,9 +265,155 @@ func (s *SharedTodoService) GetSharedByMeTodos(ctx context.Context, sharedBy str
             Date:        todo.Date,
             Time:        todo.Time,
             SharedBy:    todo.SharedBy,
+            TodoID:      todo.TodoID,
+            Version:     todo.Version,
+            Permission:  todo.Permission,
+            Status:      todo.Status,
         })
     }
     
     return &dto.SharedTodosResponse{Shared: sharedTodos}, nil
 }
 
+// LinkedTodo returns a todo shared with userID with edit permission, together with
+// the original todo it links to. Edits by the recipient are made to that todo so
+// the sharer sees them.
+func (s *SharedTodoService) LinkedTodo(ctx context.Context, id, userID string) (*domain.SharedTodo, error) {
+    const functionName = "services.shared_todos.SharedTodoService.LinkedTodo"
+    
+    shared, err := s.repo.GetSharedTodoByID(ctx, id, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    if !shared.Accepted() {
+        return nil, fmt.Errorf("%s: share has not been accepted", functionName)
+    }
+    if !shared.CanEdit() {
+        return nil, fmt.Errorf("%s: edit permission required", functionName)
+    }
+    if shared.TodoID == "" {
+        return nil, fmt.Errorf("%s: shared todo is not linked to its original todo", functionName)
+    }
+    
+    return shared, nil
+}
+
+// UpdateSharePermission lets the owner of a share change what the recipient may do
+func (s *SharedTodoService) UpdateSharePermission(ctx context.Context, id, sharedBy, permission string) (*dto.SuccessResponse, error) {
+    const functionName = "services.shared_todos.SharedTodoService.UpdateSharePermission"
+    
+    if !domain.ValidSharePermission(permission) {
+        return nil, fmt.Errorf("%s: invalid permission %q", functionName, permission)
+    }
+    
+    found, err := s.repo.UpdateSharePermission(ctx, id, sharedBy, permission)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to update permission: %w", functionName, err)
+    }
+    if !found {
+        return nil, fmt.Errorf("%s: shared todo not found", functionName)
+    }
+    
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// RevokeShare removes a share so the recipient no longer sees the todo
+func (s *SharedTodoService) RevokeShare(ctx context.Context, id, sharedBy string) (*dto.SuccessResponse, error) {
+    const functionName = "services.shared_todos.SharedTodoService.RevokeShare"
+    
+    deleted, err := s.repo.DeleteSharedTodo(ctx, id, sharedBy)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to revoke share: %w", functionName, err)
+    }
+    if !deleted {
+        return nil, fmt.Errorf("%s: shared todo not found", functionName)
+    }
+    
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// AcceptShare moves a pending share from the user's inbox into their lists
+func (s *SharedTodoService) AcceptShare(ctx context.Context, id, userID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.shared_todos.SharedTodoService.AcceptShare"
+    
+    accepted, err := s.repo.AcceptSharedTodo(ctx, id, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to accept share: %w", functionName, err)
+    }
+    if !accepted {
+        return nil, fmt.Errorf("%s: pending share not found", functionName)
+    }
+    
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// DeclineShare removes a share the user received, whether pending or accepted
+func (s *SharedTodoService) DeclineShare(ctx context.Context, id, userID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.shared_todos.SharedTodoService.DeclineShare"
+    
+    declined, err := s.repo.DeclineSharedTodo(ctx, id, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to decline share: %w", functionName, err)
+    }
+    if !declined {
+        return nil, fmt.Errorf("%s: shared todo not found", functionName)
+    }
+    
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// BlockSender blocks whoever made the share id, removing all their shares to the user
+func (s *SharedTodoService) BlockSender(ctx context.Context, id, userID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.shared_todos.SharedTodoService.BlockSender"
+    
+    shared, err := s.repo.GetSharedTodoByID(ctx, id, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    if err := s.repo.BlockSender(ctx, userID, shared.SharedBy); err != nil {
+        return nil, fmt.Errorf("%s: failed to block sender: %w", functionName, err)
+    }
+    
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+func (s *SharedTodoService) UnblockSender(ctx context.Context, userID, senderID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.shared_todos.SharedTodoService.UnblockSender"
+    
+    unblocked, err := s.repo.UnblockSender(ctx, userID, senderID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to unblock sender: %w", functionName, err)
+    }
+    if !unblocked {
+        return nil, fmt.Errorf("%s: block not found", functionName)
+    }
+    
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+func (s *SharedTodoService) GetBlockedSenders(ctx context.Context, userID string) (*dto.ShareBlocksResponse, error) {
+    const functionName = "services.shared_todos.SharedTodoService.GetBlockedSenders"
+    
+    blocks, err := s.repo.GetBlockedSenders(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get blocked senders: %w", functionName, err)
+    }
+    
+    res := &dto.ShareBlocksResponse{Blocked: make([]dto.ShareBlockResponse, len(blocks))}
+    for i, block := range blocks {
+        res.Blocked[i] = dto.ShareBlockResponse{UserID: block.UserID, Username: block.Username}
+    }
+    return res, nil
+}
+
+// checkNotBlocked refuses shares to a recipient who blocked the sender
+func (s *SharedTodoService) checkNotBlocked(ctx context.Context, recipientUserID, sharedBy string) error {
+    blocked, err := s.repo.IsSenderBlocked(ctx, recipientUserID, sharedBy)
+    if err != nil {
+        return fmt.Errorf("failed to check blocks: %w", err)
+    }
+    if blocked {
+        return fmt.Errorf("recipient does not accept shares from you")
+    }
+    return nil
+}
\ No newline at end of file
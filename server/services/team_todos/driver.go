@@ -2,8 +2,9 @@ package team_todos
 
 import (
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
 )
 
-func NewTeamTodoService(repo domain.TeamTodoRepository) *TeamTodoService {
-    return &TeamTodoService{repo: repo}
+func NewTeamTodoService(repo domain.TeamTodoRepository, teamMemberRepo domain.TeamMemberRepository, dependencyService *dependencies.DependencyService) *TeamTodoService {
+    return &TeamTodoService{repo: repo, teamMemberRepo: teamMemberRepo, dependencyService: dependencyService}
 }
\ No newline at end of file
@@ -3,13 +3,19 @@ package team_todos
 import (
     "context"
     "fmt"
+    "sort"
     "time"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/todos"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
 )
 
 type TeamTodoService struct {
-    repo domain.TeamTodoRepository
+    repo              domain.TeamTodoRepository
+    teamMemberRepo    domain.TeamMemberRepository
+    dependencyService *dependencies.DependencyService
 }
 
 // In server/services/team_todos/team_todo_service.go
@@ -21,57 +27,47 @@ func (s *TeamTodoService) CreateTeamTodo(ctx context.Context, req *dto.CreateTea
         return nil, fmt.Errorf("%s: task cannot be empty", functionName)
     }
     
-    // Handle date value - if your CreateTeamTodoRequest has date fields
-    date := req.Date
-    if date.IsZero() {
-        // If DateString is provided, parse it
-        if req.DateString != "" {
-            parsedDate, err := time.Parse("2006-01-02", req.DateString)
-            if err == nil {
-                date = parsedDate
-            } else {
-                // If parsing fails, default to today
-                date = time.Now()
-            }
-        } else {
-            // If no date provided at all, default to today
-            date = time.Now()
-        }
+    date, timeValue, err := dueDateTime(req.Date, req.Time, req.DateString, req.TimeString, req.Location)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
     }
     
-    // Handle time value - if your CreateTeamTodoRequest has time fields
-    timeValue := req.Time
-    if timeValue.IsZero() {
-        if req.TimeString != "" {
-            parsedTime, err := time.Parse("15:04:05", req.TimeString)
-            if err == nil {
-                // Extract just the time parts and use a valid year
-                hour, min, sec := parsedTime.Clock()
-                timeValue = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
-            } else {
-                // If parsing fails, default to current time
-                now := time.Now()
-                timeValue = time.Date(2000, 1, 1, now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
-            }
-        } else {
-            // If no time provided, default to current time
-            now := time.Now()
-            timeValue = time.Date(2000, 1, 1, now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
-        }
-    } else if timeValue.Year() < 1 {
-        // Ensure the year is valid
-        hour, min, sec := timeValue.Clock()
-        timeValue = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
+    if err := s.checkAssignee(ctx, req.TeamID, req.AssignedTo, req.CreatedBy); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
     }
     
-    id, err := s.repo.CreateTeamTodo(ctx, req.Task, req.Description, req.Done, req.Important, req.TeamID, req.AssignedTo)
+    id, err := s.repo.CreateTeamTodo(ctx, req.Task, req.Description, req.Done, req.Important, req.TeamID, req.AssignedTo, req.CreatedBy, date, timeValue)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to create team todo: %w", functionName, err)
     }
     return &dto.CreateResponse{ID: id}, nil
 }
 
-func (s *TeamTodoService) GetTeamTodos(ctx context.Context, teamID string) (*dto.TeamTodosResponse, error) {
+// CreateTeamTodoWithID creates a team todo under an ID chosen by the client.
+// A missing date or time is left empty.
+func (s *TeamTodoService) CreateTeamTodoWithID(ctx context.Context, id string, req *dto.CreateTeamTodoRequest) (*dto.CreateResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.CreateTeamTodoWithID"
+    
+    if id == "" {
+        return nil, fmt.Errorf("%s: id cannot be empty", functionName)
+    }
+    if req.Task == "" {
+        return nil, fmt.Errorf("%s: task cannot be empty", functionName)
+    }
+    
+    if err := s.checkAssignee(ctx, req.TeamID, req.AssignedTo, req.CreatedBy); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    
+    err := s.repo.CreateTeamTodoWithID(ctx, id, req.Task, req.Description, req.Done, req.Important, req.TeamID, req.AssignedTo, req.CreatedBy, req.Date, req.Time)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to create team todo: %w", functionName, err)
+    }
+    return &dto.CreateResponse{ID: id}, nil
+}
+
+// GetTeamTodos returns the team's todos, flagging the overdue ones by the clock of loc
+func (s *TeamTodoService) GetTeamTodos(ctx context.Context, teamID string, loc *time.Location) (*dto.TeamTodosResponse, error) {
     const functionName = "services.team_todos.TeamTodoService.GetTeamTodos"
     domainTodos, err := s.repo.GetTeamTodos(ctx, teamID)
     if err != nil {
@@ -79,38 +75,503 @@ func (s *TeamTodoService) GetTeamTodos(ctx context.Context, teamID string) (*dto
     }
     
     // Convert domain.TeamTodo to dto.TeamTodoResponse
+    now := users.LocalNow(loc)
+    var todoResponses []dto.TeamTodoResponse
+    for _, todo := range domainTodos {
+        todoResponses = append(todoResponses, dto.NewTeamTodoResponseFromDomain(todo, now))
+    }
+    
+    return &dto.TeamTodosResponse{Todos: todoResponses}, nil
+}
+
+// GetTeamTodosByAssignee returns the team's todos assigned to the user, or the
+// unassigned ones when assignee is "none"
+func (s *TeamTodoService) GetTeamTodosByAssignee(ctx context.Context, teamID, assignee string, loc *time.Location) (*dto.TeamTodosResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.GetTeamTodosByAssignee"
+    domainTodos, err := s.repo.GetTeamTodos(ctx, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todos: %w", functionName, err)
+    }
+    
+    now := users.LocalNow(loc)
     var todoResponses []dto.TeamTodoResponse
     for _, todo := range domainTodos {
-        todoResponses = append(todoResponses, dto.TeamTodoResponse{
-            ID:          todo.ID,
-            Task:        todo.Task,
-            Description: todo.Description,
-            Done:        todo.Done,
-            Important:   todo.Important,
-            TeamID:      todo.TeamID,
-            AssignedTo:  todo.AssignedTo,
-            Date:        todo.Date,
-            Time:        todo.Time,
-        })
+        if assignee == "none" {
+            if todo.AssignedTo != "" || len(todo.Assignees) > 0 {
+                continue
+            }
+        } else if !todo.IsAssignedTo(assignee) {
+            continue
+        }
+        todoResponses = append(todoResponses, dto.NewTeamTodoResponseFromDomain(todo, now))
     }
     
     return &dto.TeamTodosResponse{Todos: todoResponses}, nil
 }
 
+// GetTeamTodo returns a single todo belonging to the team, flagged overdue by the clock of loc
+func (s *TeamTodoService) GetTeamTodo(ctx context.Context, id, teamID string, loc *time.Location) (*dto.TeamTodoResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.GetTeamTodo"
+    
+    todo, err := s.repo.GetTeamTodoByID(ctx, id, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todo: %w", functionName, err)
+    }
+    
+    res := dto.NewTeamTodoResponseFromDomain(*todo, users.LocalNow(loc))
+    return &res, nil
+}
+
 func (s *TeamTodoService) UpdateTeamTodo(ctx context.Context, req *dto.UpdateTeamTodoRequest) (*dto.SuccessResponse, error) {
     const functionName = "services.team_todos.TeamTodoService.UpdateTeamTodo"
-    success, err := s.repo.UpdateTeamTodo(ctx, req.ID, req.Task, req.Description, req.Done, req.Important, req.TeamID, req.AssignedTo)
+    due, err := updatedDue(req.DateString, req.TimeString)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    if err := s.checkAssignee(ctx, req.TeamID, req.AssignedTo, req.UpdatedBy); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    if req.Done && !req.Force {
+        if err := s.dependencyService.CheckTeamTodosCanComplete(ctx, req.TeamID, req.ID); err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+    }
+    success, err := s.repo.UpdateTeamTodo(ctx, req.ID, req.Task, req.Description, req.Done, req.Important, req.TeamID, req.AssignedTo, req.UpdatedBy, due, req.Version)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to update team todo: %w", functionName, err)
     }
-    return &dto.SuccessResponse{Success: success}, nil
+    if !success {
+        return &dto.SuccessResponse{Success: false}, nil
+    }
+    
+    // The new version becomes the ETag of the response
+    todo, err := s.repo.GetTeamTodoByID(ctx, req.ID, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todo: %w", functionName, err)
+    }
+    return &dto.SuccessResponse{Success: true, Version: todo.Version}, nil
 }
 
-func (s *TeamTodoService) DeleteTeamTodo(ctx context.Context, id, teamID string) (*dto.SuccessResponse, error) {
+// PatchTeamTodo applies a merge patch to a team todo and returns the updated todo
+func (s *TeamTodoService) PatchTeamTodo(ctx context.Context, req *dto.PatchTeamTodoRequest) (*dto.TeamTodoResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.PatchTeamTodo"
+    
+    patch, err := req.ConvertPatchTeamTodoRequestToDomainPatch()
+    if err != nil {
+        return nil, fmt.Errorf("%s: invalid patch: %w", functionName, err)
+    }
+    
+    if patch.AssignedTo != nil && !patch.ClearAssignedTo {
+        if err := s.checkAssignee(ctx, req.TeamID, *patch.AssignedTo, req.UserID); err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+    }
+    
+    if patch.Done != nil && *patch.Done && !req.Force {
+        if err := s.dependencyService.CheckTeamTodosCanComplete(ctx, req.TeamID, req.ID); err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+    }
+    
+    if _, err := s.repo.PatchTeamTodo(ctx, req.ID, req.TeamID, req.UserID, patch, req.Version); err != nil {
+        return nil, fmt.Errorf("%s: failed to patch team todo: %w", functionName, err)
+    }
+    
+    todo, err := s.repo.GetTeamTodoByID(ctx, req.ID, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todo: %w", functionName, err)
+    }
+    
+    res := dto.NewTeamTodoResponseFromDomain(*todo, users.LocalNow(req.Location))
+    return &res, nil
+}
+
+func (s *TeamTodoService) DeleteTeamTodo(ctx context.Context, id, teamID string, expectedVersion int) (*dto.SuccessResponse, error) {
     const functionName = "services.team_todos.TeamTodoService.DeleteTeamTodo"
-    success, err := s.repo.DeleteTeamTodo(ctx, id, teamID)
+    success, err := s.repo.DeleteTeamTodo(ctx, id, teamID, expectedVersion)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to delete team todo: %w", functionName, err)
     }
     return &dto.SuccessResponse{Success: success}, nil
-}
\ No newline at end of file
+}
+
+// ExecuteBatch validates and runs a list of team todo operations in a single transaction
+func (s *TeamTodoService) ExecuteBatch(ctx context.Context, req *dto.BatchRequest) (*dto.BatchResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.ExecuteBatch"
+
+    ops, atomic, err := todos.BuildBatchOperations(req)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+
+    members, err := s.memberIDs(ctx, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team members: %w", functionName, err)
+    }
+    for i, op := range ops {
+        if (op.Op == domain.BatchOpCreate || op.Op == domain.BatchOpUpdate) && op.AssignedTo != "" {
+            if !members[req.UserID] {
+                return nil, fmt.Errorf("%s: operation %d: only team members can assign todos", functionName, i)
+            }
+            if !members[op.AssignedTo] {
+                return nil, fmt.Errorf("%s: operation %d: assignee %q is not a member of this team", functionName, i, op.AssignedTo)
+            }
+        }
+    }
+    // An atomic batch fails as a whole on a blocked completion; a partial one only fails that step
+    var blocked map[string]error
+    if ids := todos.BatchCompletedIDs(ops); len(ids) > 0 && !req.Force {
+        if atomic {
+            if err := s.dependencyService.CheckTeamTodosCanComplete(ctx, req.TeamID, ids...); err != nil {
+                return nil, fmt.Errorf("%s: %w", functionName, err)
+            }
+        } else if blocked, err = s.dependencyService.BlockedTeamTodos(ctx, req.TeamID, ids...); err != nil {
+            return nil, fmt.Errorf("%s: failed to check blockers: %w", functionName, err)
+        }
+    }
+
+    results, committed, err := todos.RunUnblockedBatch(ops, blocked, func(ops []domain.BatchOperation) ([]domain.BatchResult, bool, error) {
+        return s.repo.ExecuteBatch(ctx, req.TeamID, req.UserID, ops, atomic)
+    })
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to execute batch: %w", functionName, err)
+    }
+
+    mode := domain.BatchModePartial
+    if atomic {
+        mode = domain.BatchModeAtomic
+    }
+    return dto.NewBatchResponse(mode, committed, results), nil
+}
+
+// memberIDs returns the set of user IDs belonging to the team
+func (s *TeamTodoService) memberIDs(ctx context.Context, teamID string) (map[string]bool, error) {
+    members, err := s.teamMemberRepo.GetTeamMembers(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    ids := make(map[string]bool, len(members))
+    for _, member := range members {
+        ids[member.UserID] = true
+    }
+    return ids, nil
+}
+
+// checkAssignee makes sure a todo is only assigned by and to members of its team.
+// An empty assignee leaves the todo unassigned.
+func (s *TeamTodoService) checkAssignee(ctx context.Context, teamID, assignedTo, assignedBy string) error {
+    if assignedTo == "" {
+        return nil
+    }
+    members, err := s.memberIDs(ctx, teamID)
+    if err != nil {
+        return fmt.Errorf("failed to get team members: %w", err)
+    }
+    if !members[assignedBy] {
+        return fmt.Errorf("only team members can assign todos")
+    }
+    if !members[assignedTo] {
+        return fmt.Errorf("assignee %q is not a member of this team", assignedTo)
+    }
+    return nil
+}
+
+// ReassignTeamTodo changes the assignee of a team todo, or unassigns it when
+// AssignedTo is empty, and returns the updated todo
+func (s *TeamTodoService) ReassignTeamTodo(ctx context.Context, req *dto.ReassignTeamTodoRequest) (*dto.TeamTodoResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.ReassignTeamTodo"
+    
+    members, err := s.memberIDs(ctx, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team members: %w", functionName, err)
+    }
+    if !members[req.AssignedBy] {
+        return nil, fmt.Errorf("%s: only team members can assign todos", functionName)
+    }
+    if req.AssignedTo != "" && !members[req.AssignedTo] {
+        return nil, fmt.Errorf("%s: assignee %q is not a member of this team", functionName, req.AssignedTo)
+    }
+    
+    if _, err := s.repo.ReassignTeamTodo(ctx, req.ID, req.TeamID, req.AssignedTo, req.AssignedBy, req.Version); err != nil {
+        return nil, fmt.Errorf("%s: failed to reassign team todo: %w", functionName, err)
+    }
+    
+    todo, err := s.repo.GetTeamTodoByID(ctx, req.ID, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todo: %w", functionName, err)
+    }
+    res := dto.NewTeamTodoResponseFromDomain(*todo, users.LocalNow(req.Location))
+    return &res, nil
+}
+
+// SetTeamTodoAssignees replaces the assignees of a team todo and optionally its
+// completion mode, and returns the updated todo
+func (s *TeamTodoService) SetTeamTodoAssignees(ctx context.Context, req *dto.SetTeamTodoAssigneesRequest) (*dto.TeamTodoResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.SetTeamTodoAssignees"
+    
+    if req.CompletionMode != "" && !domain.ValidCompletionMode(req.CompletionMode) {
+        return nil, fmt.Errorf("%s: invalid completion mode %q", functionName, req.CompletionMode)
+    }
+    
+    members, err := s.memberIDs(ctx, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team members: %w", functionName, err)
+    }
+    if !members[req.ChangedBy] {
+        return nil, fmt.Errorf("%s: only team members can assign todos", functionName)
+    }
+    
+    seen := make(map[string]bool, len(req.Assignees))
+    var userIDs []string
+    for _, userID := range req.Assignees {
+        if userID == "" || seen[userID] {
+            continue
+        }
+        if !members[userID] {
+            return nil, fmt.Errorf("%s: assignee %q is not a member of this team", functionName, userID)
+        }
+        seen[userID] = true
+        userIDs = append(userIDs, userID)
+    }
+    
+    if _, err := s.repo.SetTeamTodoAssignees(ctx, req.ID, req.TeamID, userIDs, req.CompletionMode, req.ChangedBy, req.Version); err != nil {
+        return nil, fmt.Errorf("%s: failed to set assignees: %w", functionName, err)
+    }
+    
+    todo, err := s.repo.GetTeamTodoByID(ctx, req.ID, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todo: %w", functionName, err)
+    }
+    res := dto.NewTeamTodoResponseFromDomain(*todo, users.LocalNow(req.Location))
+    return &res, nil
+}
+
+// SetAssigneeDone marks the caller's own part of a team todo as done or not done
+// and returns the updated todo
+func (s *TeamTodoService) SetAssigneeDone(ctx context.Context, req *dto.SetAssigneeDoneRequest) (*dto.TeamTodoResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.SetAssigneeDone"
+    
+    if req.CallerID != req.UserID {
+        return nil, fmt.Errorf("%s: only the assignee can update their part", functionName)
+    }
+    
+    if req.Done && !req.Force {
+        if err := s.checkAssigneeCompletes(ctx, req); err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+    }
+    
+    found, err := s.repo.SetAssigneeDone(ctx, req.ID, req.TeamID, req.UserID, req.Done)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to update assignee: %w", functionName, err)
+    }
+    if !found {
+        return nil, fmt.Errorf("%s: assignee not found", functionName)
+    }
+    
+    todo, err := s.repo.GetTeamTodoByID(ctx, req.ID, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todo: %w", functionName, err)
+    }
+    res := dto.NewTeamTodoResponseFromDomain(*todo, users.LocalNow(req.Location))
+    return &res, nil
+}
+
+// checkAssigneeCompletes fails when ticking off the assignee's part would complete
+// a todo that still has open blockers
+func (s *TeamTodoService) checkAssigneeCompletes(ctx context.Context, req *dto.SetAssigneeDoneRequest) error {
+    todo, err := s.repo.GetTeamTodoByID(ctx, req.ID, req.TeamID)
+    if err != nil {
+        return fmt.Errorf("failed to get team todo: %w", err)
+    }
+    if todo.Done {
+        return nil
+    }
+    after := make([]domain.TeamTodoAssignee, len(todo.Assignees))
+    copy(after, todo.Assignees)
+    for i := range after {
+        if after[i].UserID == req.UserID {
+            after[i].Done = true
+        }
+    }
+    if !domain.AssigneesDone(todo.CompletionMode, after) {
+        return nil
+    }
+    return s.dependencyService.CheckTeamTodosCanComplete(ctx, req.TeamID, req.ID)
+}
+
+// GetTeamTodoAssignments returns who a team todo was assigned to over time, oldest first
+func (s *TeamTodoService) GetTeamTodoAssignments(ctx context.Context, id, teamID string) (*dto.TeamTodoAssignmentsResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.GetTeamTodoAssignments"
+    
+    if _, err := s.repo.GetTeamTodoByID(ctx, id, teamID); err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todo: %w", functionName, err)
+    }
+    
+    assignments, err := s.repo.GetTeamTodoAssignments(ctx, id, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get assignments: %w", functionName, err)
+    }
+    
+    res := &dto.TeamTodoAssignmentsResponse{Assignments: make([]dto.TeamTodoAssignmentResponse, len(assignments))}
+    for i, a := range assignments {
+        res.Assignments[i] = dto.TeamTodoAssignmentResponse{
+            AssignedTo:               a.AssignedTo,
+            AssignedToUsername:       a.AssignedToName,
+            PreviousAssignee:         a.PreviousAssignee,
+            PreviousAssigneeUsername: a.PreviousAssigneeName,
+            AssignedBy:               a.AssignedBy,
+            AssignedByUsername:       a.AssignedByName,
+            AssignedAt:               a.AssignedAt,
+        }
+    }
+    return res, nil
+}
+
+// GetAssignedTeamTodos lists the team todos assigned to the user across all of
+// their teams, flagging the overdue ones by the clock of loc
+func (s *TeamTodoService) GetAssignedTeamTodos(ctx context.Context, userID string, loc *time.Location) (*dto.AssignedTeamTodosResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.GetAssignedTeamTodos"
+    
+    todos, err := s.repo.GetAssignedTeamTodos(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get assigned team todos: %w", functionName, err)
+    }
+    
+    now := users.LocalNow(loc)
+    res := &dto.AssignedTeamTodosResponse{Todos: make([]dto.AssignedTeamTodoResponse, len(todos))}
+    for i, todo := range todos {
+        res.Todos[i] = dto.AssignedTeamTodoResponse{
+            TeamTodoResponse: dto.NewTeamTodoResponseFromDomain(todo.TeamTodo, now),
+            TeamName:         todo.TeamName,
+        }
+    }
+    return res, nil
+}
+
+// maxDeadlineDays caps how far ahead GetTeamDeadlines looks
+const maxDeadlineDays = 90
+
+// GetTeamDeadlines returns the team's open todos that are overdue or fall due
+// within the next days, soonest first, by the clock of loc
+func (s *TeamTodoService) GetTeamDeadlines(ctx context.Context, teamID string, days int, loc *time.Location) (*dto.TeamDeadlinesResponse, error) {
+    const functionName = "services.team_todos.TeamTodoService.GetTeamDeadlines"
+    
+    if days < 1 || days > maxDeadlineDays {
+        return nil, fmt.Errorf("%s: days must be between 1 and %d", functionName, maxDeadlineDays)
+    }
+    
+    todos, err := s.repo.GetTeamTodos(ctx, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todos: %w", functionName, err)
+    }
+    
+    now := users.LocalNow(loc)
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+    until := today.AddDate(0, 0, days)
+    
+    var overdue, upcoming []domain.TeamTodo
+    for _, todo := range todos {
+        due, ok := todo.Due()
+        switch {
+        case !ok || todo.Done:
+        case due.Before(now):
+            overdue = append(overdue, todo)
+        case !todo.Date.After(until):
+            upcoming = append(upcoming, todo)
+        }
+    }
+    
+    res := &dto.TeamDeadlinesResponse{
+        From:     today,
+        Until:    until,
+        Overdue:  []dto.TeamTodoResponse{},
+        Upcoming: []dto.TeamTodoResponse{},
+    }
+    for _, todo := range sortByDue(overdue) {
+        res.Overdue = append(res.Overdue, dto.NewTeamTodoResponseFromDomain(todo, now))
+    }
+    for _, todo := range sortByDue(upcoming) {
+        res.Upcoming = append(res.Upcoming, dto.NewTeamTodoResponseFromDomain(todo, now))
+    }
+    return res, nil
+}
+
+// sortByDue orders dated todos by when they fall due, important ones first among equals
+func sortByDue(todos []domain.TeamTodo) []domain.TeamTodo {
+    sort.SliceStable(todos, func(i, j int) bool {
+        a, _ := todos[i].Due()
+        b, _ := todos[j].Due()
+        switch {
+        case !a.Equal(b):
+            return a.Before(b)
+        case todos[i].Important != todos[j].Important:
+            return todos[i].Important
+        }
+        return todos[i].Task < todos[j].Task
+    })
+    return todos
+}
+
+// dueDateTime resolves the due date and time of a new team todo. Values already
+// parsed take precedence over the strings, and a missing date or time defaults
+// to now in loc.
+func dueDateTime(date, timeValue time.Time, dateString, timeString string, loc *time.Location) (time.Time, time.Time, error) {
+    now := users.LocalNow(loc)
+    
+    if date.IsZero() {
+        date = now
+        if dateString != "" {
+            parsedDate, err := time.Parse("2006-01-02", dateString)
+            if err != nil {
+                return time.Time{}, time.Time{}, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+            }
+            date = parsedDate
+        }
+    }
+    
+    if timeValue.IsZero() {
+        timeValue = now
+        if timeString != "" {
+            parsedTime, err := time.Parse("15:04:05", timeString)
+            if err != nil {
+                return time.Time{}, time.Time{}, fmt.Errorf("invalid time format, use HH:MM:SS")
+            }
+            timeValue = parsedTime
+        }
+    }
+    // Keep only the clock, on a valid year
+    hour, min, sec := timeValue.Clock()
+    return date, time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC), nil
+}
+
+// updatedDue parses the optional date and time of a full update. A nil string
+// keeps the current value and an empty one clears it.
+func updatedDue(dateString, timeString *string) (domain.TeamTodoDue, error) {
+    var due domain.TeamTodoDue
+    if dateString != nil {
+        if *dateString == "" {
+            due.ClearDate = true
+        } else {
+            date, err := time.Parse("2006-01-02", *dateString)
+            if err != nil {
+                return due, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+            }
+            due.Date = &date
+        }
+    }
+    if timeString != nil {
+        if *timeString == "" {
+            due.ClearTime = true
+        } else {
+            parsedTime, err := time.Parse("15:04:05", *timeString)
+            if err != nil {
+                return due, fmt.Errorf("invalid time format, use HH:MM:SS")
+            }
+            hour, min, sec := parsedTime.Clock()
+            timeValue := time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
+            due.Time = &timeValue
+        }
+    }
+    return due, nil
+}
@@ -42,6 +42,26 @@ func (s *TeamService) GetTeamsByAdminID(ctx context.Context, adminID string) (*d
     return &dto.TeamsResponse{Teams: teamResponses}, nil
 }
 
+// GetTeams returns every team the user is a member of
+func (s *TeamService) GetTeams(ctx context.Context, userID string) (*dto.TeamsResponse, error) {
+    const functionName = "services.teams.TeamService.GetTeams"
+    domainTeams, err := s.repo.GetTeams(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get teams: %w", functionName, err)
+    }
+    
+    var teamResponses []dto.TeamResponse
+    for _, team := range domainTeams {
+        teamResponses = append(teamResponses, dto.TeamResponse{
+            ID:      team.ID,
+            Name:    team.Name,
+            AdminID: team.AdminID,
+        })
+    }
+    
+    return &dto.TeamsResponse{Teams: teamResponses}, nil
+}
+
 func (s *TeamService) GetTeamByID(ctx context.Context, id string) (*dto.TeamResponse, error) {
     const functionName = "services.teams.TeamService.GetTeamByID"
     team, err := s.repo.GetTeamByID(ctx, id)
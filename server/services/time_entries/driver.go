@@ -0,0 +1,13 @@
+package time_entries
+
+import (
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+func NewTimeEntryService(repo domain.TimeEntryRepository, todoRepo domain.TodoRepository, teamTodoRepo domain.TeamTodoRepository) *TimeEntryService {
+    return &TimeEntryService{
+        repo:         repo,
+        todoRepo:     todoRepo,
+        teamTodoRepo: teamTodoRepo,
+    }
+}
@@ -0,0 +1,368 @@
+package time_entries
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+)
+
+// maxNoteLength matches the size of the time_entries.note column
+const maxNoteLength = 255
+
+// Report groupings, applied in this order
+const (
+    GroupByDay  = "day"
+    GroupByUser = "user"
+    GroupByTodo = "todo"
+)
+
+var reportGroupings = []string{GroupByDay, GroupByUser, GroupByTodo}
+
+// TimeEntryService tracks time spent on todos and team todos, either with a
+// start/stop timer (one running timer per user) or with entries logged afterwards.
+// Running timers count up to the current time in totals and reports.
+type TimeEntryService struct {
+    repo         domain.TimeEntryRepository
+    todoRepo     domain.TodoRepository
+    teamTodoRepo domain.TeamTodoRepository
+}
+
+// checkTarget verifies the todo exists: a personal todo must belong to the user,
+// a team todo must belong to the team
+func (s *TimeEntryService) checkTarget(ctx context.Context, userID, teamID, todoID string) error {
+    if todoID == "" {
+        return fmt.Errorf("todo_id is required")
+    }
+    if teamID != "" {
+        if _, err := s.teamTodoRepo.GetTeamTodoByID(ctx, todoID, teamID); err != nil {
+            return fmt.Errorf("team todo not found")
+        }
+        return nil
+    }
+    todo, err := s.todoRepo.GetTodoByID(ctx, todoID)
+    if err != nil || todo.UserID != userID {
+        return fmt.Errorf("todo not found")
+    }
+    return nil
+}
+
+// StartTimer starts the user's timer on a todo or team todo
+func (s *TimeEntryService) StartTimer(ctx context.Context, req *dto.StartTimerRequest) (*dto.TimeEntryResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.StartTimer"
+
+    if len(req.Note) > maxNoteLength {
+        return nil, fmt.Errorf("%s: invalid time entry: note is longer than %d characters", functionName, maxNoteLength)
+    }
+    if err := s.checkTarget(ctx, req.UserID, req.TeamID, req.TodoID); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+
+    entry := domain.TimeEntry{
+        ID:        uuid.New().String(),
+        UserID:    req.UserID,
+        TodoID:    req.TodoID,
+        TeamID:    req.TeamID,
+        StartedAt: time.Now().UTC().Truncate(time.Second),
+        Note:      req.Note,
+    }
+    if err := s.repo.CreateTimeEntry(ctx, entry); err != nil {
+        return nil, fmt.Errorf("%s: failed to start timer: %w", functionName, err)
+    }
+
+    // Read it back for the task name and username
+    running, err := s.repo.GetRunningTimer(ctx, req.UserID)
+    if err != nil || running == nil {
+        res := newTimeEntryResponse(entry, entry.StartedAt)
+        return &res, nil
+    }
+    res := newTimeEntryResponse(*running, time.Now())
+    return &res, nil
+}
+
+// StopTimer stops the user's running timer
+func (s *TimeEntryService) StopTimer(ctx context.Context, userID string) (*dto.TimeEntryResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.StopTimer"
+
+    entry, err := s.repo.StopTimer(ctx, userID, time.Now())
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    res := newTimeEntryResponse(*entry, entry.EndedAt)
+    return &res, nil
+}
+
+// GetRunningTimer returns the user's running timer
+func (s *TimeEntryService) GetRunningTimer(ctx context.Context, userID string) (*dto.TimeEntryResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.GetRunningTimer"
+
+    entry, err := s.repo.GetRunningTimer(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get timer: %w", functionName, err)
+    }
+    if entry == nil {
+        return nil, fmt.Errorf("%s: no running timer", functionName)
+    }
+    res := newTimeEntryResponse(*entry, time.Now())
+    return &res, nil
+}
+
+// CreateTimeEntry logs a finished block of time against a todo or team todo
+func (s *TimeEntryService) CreateTimeEntry(ctx context.Context, req *dto.CreateTimeEntryRequest) (*dto.TimeEntryResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.CreateTimeEntry"
+
+    entry, err := newManualEntry(req)
+    if err != nil {
+        return nil, fmt.Errorf("%s: invalid time entry: %w", functionName, err)
+    }
+    if err := s.checkTarget(ctx, req.UserID, req.TeamID, req.TodoID); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+
+    if err := s.repo.CreateTimeEntry(ctx, entry); err != nil {
+        return nil, fmt.Errorf("%s: failed to create time entry: %w", functionName, err)
+    }
+    res := newTimeEntryResponse(entry, entry.EndedAt)
+    return &res, nil
+}
+
+// newManualEntry validates a logged entry and resolves its end time
+func newManualEntry(req *dto.CreateTimeEntryRequest) (domain.TimeEntry, error) {
+    if req.StartedAt.IsZero() {
+        return domain.TimeEntry{}, fmt.Errorf("started_at is required")
+    }
+    if len(req.Note) > maxNoteLength {
+        return domain.TimeEntry{}, fmt.Errorf("note is longer than %d characters", maxNoteLength)
+    }
+
+    started := req.StartedAt.UTC().Truncate(time.Second)
+    var ended time.Time
+    switch {
+    case req.EndedAt != nil && req.Minutes != 0:
+        return domain.TimeEntry{}, fmt.Errorf("give either ended_at or minutes, not both")
+    case req.EndedAt != nil:
+        ended = req.EndedAt.UTC().Truncate(time.Second)
+    case req.Minutes > 0:
+        ended = started.Add(time.Duration(req.Minutes) * time.Minute)
+    default:
+        return domain.TimeEntry{}, fmt.Errorf("ended_at or a positive minutes value is required")
+    }
+    if !ended.After(started) {
+        return domain.TimeEntry{}, fmt.Errorf("ended_at must be after started_at")
+    }
+
+    return domain.TimeEntry{
+        ID:        uuid.New().String(),
+        UserID:    req.UserID,
+        TodoID:    req.TodoID,
+        TeamID:    req.TeamID,
+        StartedAt: started,
+        EndedAt:   ended,
+        Note:      req.Note,
+    }, nil
+}
+
+// DeleteTimeEntry removes one of the user's entries, including a running timer
+func (s *TimeEntryService) DeleteTimeEntry(ctx context.Context, userID, id string) (*dto.SuccessResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.DeleteTimeEntry"
+
+    deleted, err := s.repo.DeleteTimeEntry(ctx, id, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to delete time entry: %w", functionName, err)
+    }
+    if !deleted {
+        return nil, fmt.Errorf("%s: time entry not found", functionName)
+    }
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// GetTodoTimeEntries lists the time logged on one of the user's todos
+func (s *TimeEntryService) GetTodoTimeEntries(ctx context.Context, userID, todoID string) (*dto.TimeEntriesResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.GetTodoTimeEntries"
+
+    if err := s.checkTarget(ctx, userID, "", todoID); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    entries, err := s.repo.GetTodoTimeEntries(ctx, todoID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get time entries: %w", functionName, err)
+    }
+    res := newTimeEntriesResponse(entries, time.Now())
+    res.TodoID = todoID
+    return res, nil
+}
+
+// GetTeamTodoTimeEntries lists the time every member logged on a team todo
+func (s *TimeEntryService) GetTeamTodoTimeEntries(ctx context.Context, teamID, todoID string) (*dto.TimeEntriesResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.GetTeamTodoTimeEntries"
+
+    if err := s.checkTarget(ctx, "", teamID, todoID); err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    entries, err := s.repo.GetTeamTodoTimeEntries(ctx, teamID, todoID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get time entries: %w", functionName, err)
+    }
+    res := newTimeEntriesResponse(entries, time.Now())
+    res.TodoID = todoID
+    res.TeamID = teamID
+    return res, nil
+}
+
+// GetTeamTimeEntries lists the time logged on all of a team's todos between from and to
+func (s *TimeEntryService) GetTeamTimeEntries(ctx context.Context, teamID string, from, to time.Time) (*dto.TimeEntriesResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.GetTeamTimeEntries"
+
+    from, to = resolveRange(from, to)
+    entries, err := s.repo.GetTeamTimeEntries(ctx, teamID, from, to)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get time entries: %w", functionName, err)
+    }
+    res := newTimeEntriesResponse(entries, time.Now())
+    res.TeamID = teamID
+    return res, nil
+}
+
+// GetReport totals the user's or a team's time, grouped by any of day, user and todo.
+// An entry counts towards the day it started on, in UTC.
+func (s *TimeEntryService) GetReport(ctx context.Context, req *dto.TimeReportRequest) (*dto.TimeReportResponse, error) {
+    const functionName = "services.time_entries.TimeEntryService.GetReport"
+
+    groupBy, err := normalizeGroupBy(req.GroupBy)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    from, to := resolveRange(req.From, req.To)
+    if !to.After(from) {
+        return nil, fmt.Errorf("%s: invalid range: from must be before to", functionName)
+    }
+
+    var entries []domain.TimeEntry
+    if req.TeamID != "" {
+        entries, err = s.repo.GetTeamTimeEntries(ctx, req.TeamID, from, to)
+    } else {
+        entries, err = s.repo.GetUserTimeEntries(ctx, req.UserID, from, to)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get time entries: %w", functionName, err)
+    }
+
+    now := time.Now()
+    res := &dto.TimeReportResponse{
+        From:    from.Format("2006-01-02"),
+        To:      to.Add(-time.Second).Format("2006-01-02"),
+        GroupBy: groupBy,
+        Rows:    []dto.TimeReportRow{},
+    }
+    index := make(map[dto.TimeReportRow]int)
+    for _, entry := range entries {
+        key := reportKey(entry, groupBy)
+        seconds := int64(entry.Duration(now) / time.Second)
+        res.TotalSeconds += seconds
+        if i, ok := index[key]; ok {
+            res.Rows[i].Seconds += seconds
+            continue
+        }
+        index[key] = len(res.Rows)
+        key.Seconds = seconds
+        res.Rows = append(res.Rows, key)
+    }
+    sort.SliceStable(res.Rows, func(i, j int) bool {
+        a, b := res.Rows[i], res.Rows[j]
+        if a.Day != b.Day {
+            return a.Day < b.Day
+        }
+        if a.Username != b.Username {
+            return a.Username < b.Username
+        }
+        return a.Task < b.Task
+    })
+    return res, nil
+}
+
+// normalizeGroupBy validates the groupings and puts them in canonical order.
+// No groupings means all of them.
+func normalizeGroupBy(groupBy []string) ([]string, error) {
+    if len(groupBy) == 0 {
+        return reportGroupings, nil
+    }
+    wanted := make(map[string]bool, len(groupBy))
+    for _, g := range groupBy {
+        g = strings.ToLower(strings.TrimSpace(g))
+        if g != GroupByDay && g != GroupByUser && g != GroupByTodo {
+            return nil, fmt.Errorf("invalid group_by %q: use day, user or todo", g)
+        }
+        wanted[g] = true
+    }
+    var normalized []string
+    for _, g := range reportGroupings {
+        if wanted[g] {
+            normalized = append(normalized, g)
+        }
+    }
+    return normalized, nil
+}
+
+// reportKey returns the row an entry is totalled in, with the ungrouped fields empty
+func reportKey(entry domain.TimeEntry, groupBy []string) dto.TimeReportRow {
+    var key dto.TimeReportRow
+    for _, g := range groupBy {
+        switch g {
+        case GroupByDay:
+            key.Day = entry.StartedAt.UTC().Format("2006-01-02")
+        case GroupByUser:
+            key.UserID = entry.UserID
+            key.Username = entry.Username
+        case GroupByTodo:
+            key.TodoID = entry.TodoID
+            key.TeamID = entry.TeamID
+            key.Task = entry.Task
+        }
+    }
+    return key
+}
+
+// resolveRange turns an open range into one covering all entries
+func resolveRange(from, to time.Time) (time.Time, time.Time) {
+    if from.IsZero() {
+        from = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+    }
+    if to.IsZero() {
+        to = time.Now().UTC().Add(24 * time.Hour)
+    }
+    return from, to
+}
+
+func newTimeEntryResponse(entry domain.TimeEntry, now time.Time) dto.TimeEntryResponse {
+    res := dto.TimeEntryResponse{
+        ID:        entry.ID,
+        UserID:    entry.UserID,
+        Username:  entry.Username,
+        TodoID:    entry.TodoID,
+        TeamID:    entry.TeamID,
+        Task:      entry.Task,
+        StartedAt: entry.StartedAt,
+        Seconds:   int64(entry.Duration(now) / time.Second),
+        Running:   entry.Running(),
+        Note:      entry.Note,
+    }
+    if !entry.Running() {
+        ended := entry.EndedAt
+        res.EndedAt = &ended
+    }
+    return res
+}
+
+func newTimeEntriesResponse(entries []domain.TimeEntry, now time.Time) *dto.TimeEntriesResponse {
+    res := &dto.TimeEntriesResponse{Entries: make([]dto.TimeEntryResponse, len(entries))}
+    for i, entry := range entries {
+        res.Entries[i] = newTimeEntryResponse(entry, now)
+        res.TotalSeconds += res.Entries[i].Seconds
+    }
+    return res
+}
@@ -2,8 +2,9 @@ package todos
 
 import (
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
 )
 
-func NewTodoService(repo domain.TodoRepository) *TodoService {
-    return &TodoService{repo: repo}
+func NewTodoService(repo domain.TodoRepository, dependencyService *dependencies.DependencyService) *TodoService {
+    return &TodoService{repo: repo, dependencyService: dependencyService}
 }
\ No newline at end of file
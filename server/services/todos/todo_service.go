@@ -6,10 +6,13 @@ import (
     "time"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
 )
 
 type TodoService struct {
-    repo domain.TodoRepository
+    repo              domain.TodoRepository
+    dependencyService *dependencies.DependencyService
 }
 
 
@@ -21,6 +24,8 @@ func (s *TodoService) CreateTodo(ctx context.Context, req *dto.CreateTodoRequest
         return nil, fmt.Errorf("%s: task cannot be empty", functionName)
     }
     
+    now := users.LocalNow(req.Location)
+    
     // Handle date value
     date := req.Date
     if date.IsZero() {
@@ -31,11 +36,11 @@ func (s *TodoService) CreateTodo(ctx context.Context, req *dto.CreateTodoRequest
                 date = parsedDate
             } else {
                 // If parsing fails, default to today
-                date = time.Now()
+                date = now
             }
         } else {
             // If no date provided at all, default to today
-            date = time.Now()
+            date = now
         }
     }
     
@@ -50,12 +55,10 @@ func (s *TodoService) CreateTodo(ctx context.Context, req *dto.CreateTodoRequest
                 timeValue = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
             } else {
                 // If parsing fails, default to current time with a valid year
-                now := time.Now()
                 timeValue = time.Date(2000, 1, 1, now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
             }
         } else {
             // If no time provided, default to current time with a valid year
-            now := time.Now()
             timeValue = time.Date(2000, 1, 1, now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
         }
     } else if timeValue.Year() < 1 {
@@ -71,6 +74,26 @@ func (s *TodoService) CreateTodo(ctx context.Context, req *dto.CreateTodoRequest
     return &dto.CreateResponse{ID: id}, nil
 }
 
+// CreateTodoWithID creates a todo under an ID chosen by the client, as CalDAV
+// clients do when they PUT a new resource. Unlike CreateTodo, a missing date or
+// time is left empty instead of defaulting to now.
+func (s *TodoService) CreateTodoWithID(ctx context.Context, id string, req *dto.CreateTodoRequest) (*dto.CreateResponse, error) {
+    const functionName = "services.todos.TodoService.CreateTodoWithID"
+    
+    if id == "" {
+        return nil, fmt.Errorf("%s: id cannot be empty", functionName)
+    }
+    if req.Task == "" {
+        return nil, fmt.Errorf("%s: task cannot be empty", functionName)
+    }
+    
+    err := s.repo.CreateTodoWithID(ctx, id, req.Task, req.Description, req.Done, req.Important, req.UserID, req.Date, req.Time)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to create todo: %w", functionName, err)
+    }
+    return &dto.CreateResponse{ID: id}, nil
+}
+
 func (s *TodoService) GetTodoByID(ctx context.Context, id string) (*domain.Todo, error) {
     const functionName = "services.todos.TodoService.GetTodoByID"
     
@@ -82,6 +105,31 @@ func (s *TodoService) GetTodoByID(ctx context.Context, id string) (*domain.Todo,
     return todo, nil
 }
 
+// GetTodo returns a single todo owned by the user
+func (s *TodoService) GetTodo(ctx context.Context, id, userID string) (*dto.TodoResponse, error) {
+    const functionName = "services.todos.TodoService.GetTodo"
+    
+    todo, err := s.repo.GetTodoByID(ctx, id)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get todo by ID: %w", functionName, err)
+    }
+    if todo.UserID != userID {
+        return nil, fmt.Errorf("%s: todo not found", functionName)
+    }
+    
+    return &dto.TodoResponse{
+        ID:          todo.ID,
+        Task:        todo.Task,
+        Description: todo.Description,
+        Done:        todo.Done,
+        Important:   todo.Important,
+        UserID:      todo.UserID,
+        Date:        todo.Date,
+        Time:        todo.Time,
+        Version:     todo.Version,
+    }, nil
+}
+
 func (s *TodoService) GetTodosByUserID(ctx context.Context, userID string) (*dto.TodosResponse, error) {
     const functionName = "services.todos.TodoService.GetTodosByUserID"
     domainTodos, err := s.repo.GetTodosByUserID(ctx, userID)
@@ -101,6 +149,7 @@ func (s *TodoService) GetTodosByUserID(ctx context.Context, userID string) (*dto
             UserID:      todo.UserID,
             Date:        todo.Date,
             Time:        todo.Time,
+            Version:     todo.Version,
         })
     }
     
@@ -109,27 +158,263 @@ func (s *TodoService) GetTodosByUserID(ctx context.Context, userID string) (*dto
 
 func (s *TodoService) UpdateTodo(ctx context.Context, req *dto.UpdateTodoRequest) (*dto.SuccessResponse, error) {
     const functionName = "services.todos.TodoService.UpdateTodo"
-    success, err := s.repo.UpdateTodo(ctx, req.ID, req.Task, req.Description, req.Done, req.Important, req.UserID)
+    if req.Done && !req.Force {
+        if err := s.dependencyService.CheckTodosCanComplete(ctx, req.UserID, req.ID); err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+    }
+    success, err := s.repo.UpdateTodo(ctx, req.ID, req.Task, req.Description, req.Done, req.Important, req.UserID, req.Version)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to update todo: %w", functionName, err)
     }
-    return &dto.SuccessResponse{Success: success}, nil
+    res, err := s.written(ctx, req.ID, success)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    return res, nil
 }
 
-func (s *TodoService) DeleteTodo(ctx context.Context, id, userID string) (*dto.SuccessResponse, error) {
+// PatchTodo applies a merge patch to a todo and returns the updated todo
+func (s *TodoService) PatchTodo(ctx context.Context, req *dto.PatchTodoRequest) (*dto.TodoResponse, error) {
+    const functionName = "services.todos.TodoService.PatchTodo"
+    
+    patch, err := req.ConvertPatchTodoRequestToDomainPatch()
+    if err != nil {
+        return nil, fmt.Errorf("%s: invalid patch: %w", functionName, err)
+    }
+    
+    if patch.Done != nil && *patch.Done && !req.Force {
+        if err := s.dependencyService.CheckTodosCanComplete(ctx, req.UserID, req.ID); err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+    }
+    
+    if _, err := s.repo.PatchTodo(ctx, req.ID, req.UserID, patch, req.Version); err != nil {
+        return nil, fmt.Errorf("%s: failed to patch todo: %w", functionName, err)
+    }
+    
+    todo, err := s.repo.GetTodoByID(ctx, req.ID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get todo by ID: %w", functionName, err)
+    }
+    
+    return &dto.TodoResponse{
+        ID:          todo.ID,
+        Task:        todo.Task,
+        Description: todo.Description,
+        Done:        todo.Done,
+        Important:   todo.Important,
+        UserID:      todo.UserID,
+        Date:        todo.Date,
+        Time:        todo.Time,
+        Version:     todo.Version,
+    }, nil
+}
+
+func (s *TodoService) DeleteTodo(ctx context.Context, id, userID string, expectedVersion int) (*dto.SuccessResponse, error) {
     const functionName = "services.todos.TodoService.DeleteTodo"
-    success, err := s.repo.DeleteTodo(ctx, id, userID)
+    success, err := s.repo.DeleteTodo(ctx, id, userID, expectedVersion)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to delete todo: %w", functionName, err)
     }
     return &dto.SuccessResponse{Success: success}, nil
 }
 
-func (s *TodoService) UndoTodo(ctx context.Context, id, userID string) (*dto.SuccessResponse, error) {
+func (s *TodoService) UndoTodo(ctx context.Context, id, userID string, expectedVersion int) (*dto.SuccessResponse, error) {
     const functionName = "services.todos.TodoService.UndoTodo"
-    success, err := s.repo.UndoTodo(ctx, id, userID)
+    success, err := s.repo.UndoTodo(ctx, id, userID, expectedVersion)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to undo todo: %w", functionName, err)
     }
-    return &dto.SuccessResponse{Success: success}, nil
-}
\ No newline at end of file
+    res, err := s.written(ctx, id, success)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    return res, nil
+}
+
+// written reports the outcome of a full write together with the todo's new
+// version, which the handlers return as the ETag
+func (s *TodoService) written(ctx context.Context, id string, success bool) (*dto.SuccessResponse, error) {
+    if !success {
+        return &dto.SuccessResponse{Success: false}, nil
+    }
+    todo, err := s.repo.GetTodoByID(ctx, id)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get todo: %w", err)
+    }
+    return &dto.SuccessResponse{Success: true, Version: todo.Version}, nil
+}
+
+// MaxBatchOperations caps the number of operations accepted in one batch request
+const MaxBatchOperations = 100
+
+// ExecuteBatch validates and runs a list of todo operations in a single transaction
+func (s *TodoService) ExecuteBatch(ctx context.Context, req *dto.BatchRequest) (*dto.BatchResponse, error) {
+    const functionName = "services.todos.TodoService.ExecuteBatch"
+
+    ops, atomic, err := BuildBatchOperations(req)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    // An atomic batch fails as a whole on a blocked completion; a partial one only fails that step
+    var blocked map[string]error
+    if ids := BatchCompletedIDs(ops); len(ids) > 0 && !req.Force {
+        if atomic {
+            if err := s.dependencyService.CheckTodosCanComplete(ctx, req.UserID, ids...); err != nil {
+                return nil, fmt.Errorf("%s: %w", functionName, err)
+            }
+        } else if blocked, err = s.dependencyService.BlockedTodos(ctx, req.UserID, ids...); err != nil {
+            return nil, fmt.Errorf("%s: failed to check blockers: %w", functionName, err)
+        }
+    }
+
+    results, committed, err := RunUnblockedBatch(ops, blocked, func(ops []domain.BatchOperation) ([]domain.BatchResult, bool, error) {
+        return s.repo.ExecuteBatch(ctx, req.UserID, ops, atomic)
+    })
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to execute batch: %w", functionName, err)
+    }
+
+    mode := domain.BatchModePartial
+    if atomic {
+        mode = domain.BatchModeAtomic
+    }
+    return dto.NewBatchResponse(mode, committed, results), nil
+}
+
+// BuildBatchOperations validates a batch request and converts it to domain operations.
+// Team todo batches share it so both endpoints accept the same operations.
+func BuildBatchOperations(req *dto.BatchRequest) ([]domain.BatchOperation, bool, error) {
+    var atomic bool
+    switch req.Mode {
+    case "", domain.BatchModeAtomic:
+        atomic = true
+    case domain.BatchModePartial:
+        atomic = false
+    default:
+        return nil, false, fmt.Errorf("invalid batch mode %q", req.Mode)
+    }
+
+    if len(req.Operations) == 0 {
+        return nil, false, fmt.Errorf("batch must contain at least one operation")
+    }
+    if len(req.Operations) > MaxBatchOperations {
+        return nil, false, fmt.Errorf("batch cannot contain more than %d operations", MaxBatchOperations)
+    }
+
+    ops := make([]domain.BatchOperation, len(req.Operations))
+    for i, item := range req.Operations {
+        op := domain.BatchOperation{
+            Op:          item.Op,
+            ID:          item.ID,
+            Task:        item.Task,
+            Description: item.Description,
+            Done:        item.Done,
+            Important:   item.Important,
+            AssignedTo:  item.AssignedTo,
+            Version:     item.Version,
+        }
+
+        switch item.Op {
+        case domain.BatchOpCreate:
+            if item.Task == "" {
+                return nil, false, fmt.Errorf("operation %d: task cannot be empty", i)
+            }
+            date, timeValue, err := parseBatchDateTime(item.DateString, item.TimeString, req.Location)
+            if err != nil {
+                return nil, false, fmt.Errorf("operation %d: %w", i, err)
+            }
+            op.Date = date
+            op.Time = timeValue
+        case domain.BatchOpUpdate:
+            if item.ID == "" {
+                return nil, false, fmt.Errorf("operation %d: id is required", i)
+            }
+            if item.Task == "" {
+                return nil, false, fmt.Errorf("operation %d: task cannot be empty", i)
+            }
+        case domain.BatchOpComplete, domain.BatchOpDelete:
+            if item.ID == "" {
+                return nil, false, fmt.Errorf("operation %d: id is required", i)
+            }
+        default:
+            return nil, false, fmt.Errorf("operation %d: invalid operation %q", i, item.Op)
+        }
+
+        ops[i] = op
+    }
+
+    return ops, atomic, nil
+}
+
+// BatchCompletedIDs returns the IDs a batch marks as done
+func BatchCompletedIDs(ops []domain.BatchOperation) []string {
+    var ids []string
+    for _, op := range ops {
+        if completesTodo(op) {
+            ids = append(ids, op.ID)
+        }
+    }
+    return ids
+}
+
+// RunUnblockedBatch fails each operation that completes a todo listed in blocked
+// with that todo's blocker error and passes the others to run. Results are
+// reported at the operations' indexes in the request.
+func RunUnblockedBatch(ops []domain.BatchOperation, blocked map[string]error, run func(ops []domain.BatchOperation) ([]domain.BatchResult, bool, error)) ([]domain.BatchResult, bool, error) {
+    results := make([]domain.BatchResult, len(ops))
+    var runnable []domain.BatchOperation
+    var indexes []int
+    for i, op := range ops {
+        if err, ok := blocked[op.ID]; ok && completesTodo(op) {
+            results[i] = domain.BatchResult{Index: i, Op: op.Op, ID: op.ID, Status: domain.BatchStatusFailed, Error: err.Error()}
+            continue
+        }
+        runnable = append(runnable, op)
+        indexes = append(indexes, i)
+    }
+    if len(runnable) == 0 {
+        return results, true, nil
+    }
+
+    ran, committed, err := run(runnable)
+    if err != nil {
+        return nil, false, err
+    }
+    for _, result := range ran {
+        result.Index = indexes[result.Index]
+        results[result.Index] = result
+    }
+    return results, committed, nil
+}
+
+func completesTodo(op domain.BatchOperation) bool {
+    return op.Op == domain.BatchOpComplete || (op.Op == domain.BatchOpUpdate && op.Done)
+}
+
+// parseBatchDateTime parses optional date/time strings, defaulting to now like CreateTodo
+func parseBatchDateTime(dateString, timeString string, loc *time.Location) (time.Time, time.Time, error) {
+    now := users.LocalNow(loc)
+
+    date := now
+    if dateString != "" {
+        parsedDate, err := time.Parse("2006-01-02", dateString)
+        if err != nil {
+            return time.Time{}, time.Time{}, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+        }
+        date = parsedDate
+    }
+
+    timeValue := time.Date(2000, 1, 1, now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+    if timeString != "" {
+        parsedTime, err := time.Parse("15:04:05", timeString)
+        if err != nil {
+            return time.Time{}, time.Time{}, fmt.Errorf("invalid time format, use HH:MM:SS")
+        }
+        hour, min, sec := parsedTime.Clock()
+        timeValue = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
+    }
+
+    return date, timeValue, nil
+}
@@ -0,0 +1,215 @@
+package transfer
+
+import (
+    "bytes"
+    "encoding/csv"
+    "fmt"
+    "io"
+    "strconv"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+)
+
+// Record types used in the record_type column of a CSV export
+const (
+    recordTodo       = "todo"
+    recordRoutine    = "routine"
+    recordSharedTodo = "shared_todo"
+    recordTeamTodo   = "team_todo"
+)
+
+// csvColumns is the header of a CSV export. Every record type uses the columns
+// that apply to it and leaves the others empty.
+var csvColumns = []string{
+    "record_type", "id", "task", "description", "done", "important", "date", "time",
+    "task_id", "day", "schedule_type", "is_active",
+    "team_id", "assigned_to", "shared_by", "shared_with",
+}
+
+// encodeCSV flattens an export document into a single CSV table
+func encodeCSV(doc *dto.ExportDocument) ([]byte, error) {
+    var buf bytes.Buffer
+    writer := csv.NewWriter(&buf)
+
+    rows := [][]string{csvColumns}
+    for _, todo := range doc.Todos {
+        rows = append(rows, csvRow(map[string]string{
+            "record_type": recordTodo,
+            "id":          todo.ID,
+            "task":        todo.Task,
+            "description": todo.Description,
+            "done":        strconv.FormatBool(todo.Done),
+            "important":   strconv.FormatBool(todo.Important),
+            "date":        todo.Date,
+            "time":        todo.Time,
+        }))
+    }
+    for _, routine := range doc.Routines {
+        rows = append(rows, csvRow(map[string]string{
+            "record_type":   recordRoutine,
+            "id":            routine.ID,
+            "task_id":       routine.TaskID,
+            "day":           routine.Day,
+            "schedule_type": routine.ScheduleType,
+            "is_active":     strconv.FormatBool(routine.IsActive),
+        }))
+    }
+    for _, todo := range doc.SharedTodos {
+        rows = append(rows, csvRow(map[string]string{
+            "record_type": recordSharedTodo,
+            "id":          todo.ID,
+            "task":        todo.Task,
+            "description": todo.Description,
+            "done":        strconv.FormatBool(todo.Done),
+            "important":   strconv.FormatBool(todo.Important),
+            "date":        todo.Date,
+            "time":        todo.Time,
+            "shared_by":   todo.SharedBy,
+            "shared_with": todo.SharedWith,
+        }))
+    }
+    for _, todo := range doc.TeamTodos {
+        rows = append(rows, csvRow(map[string]string{
+            "record_type": recordTeamTodo,
+            "id":          todo.ID,
+            "task":        todo.Task,
+            "description": todo.Description,
+            "done":        strconv.FormatBool(todo.Done),
+            "important":   strconv.FormatBool(todo.Important),
+            "date":        todo.Date,
+            "time":        todo.Time,
+            "team_id":     todo.TeamID,
+            "assigned_to": todo.AssignedTo,
+        }))
+    }
+
+    if err := writer.WriteAll(rows); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+func csvRow(values map[string]string) []string {
+    row := make([]string, len(csvColumns))
+    for i, column := range csvColumns {
+        row[i] = values[column]
+    }
+    return row
+}
+
+// decodeCSV reads a CSV export back into a document. Columns are matched by
+// header name, so files edited in a spreadsheet may reorder or drop unused columns.
+func decodeCSV(data []byte) (*dto.ExportDocument, error) {
+    reader := csv.NewReader(bytes.NewReader(data))
+    reader.FieldsPerRecord = -1
+
+    header, err := reader.Read()
+    if err == io.EOF {
+        return nil, fmt.Errorf("csv file is empty")
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    index := make(map[string]int, len(header))
+    for i, column := range header {
+        index[column] = i
+    }
+    if _, ok := index["record_type"]; !ok {
+        return nil, fmt.Errorf("csv header must include a record_type column")
+    }
+
+    doc := &dto.ExportDocument{}
+    for line := 2; ; line++ {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, err
+        }
+
+        field := func(column string) string {
+            if i, ok := index[column]; ok && i < len(record) {
+                return record[i]
+            }
+            return ""
+        }
+        flag := func(column string) (bool, error) {
+            value := field(column)
+            if value == "" {
+                return false, nil
+            }
+            parsed, err := strconv.ParseBool(value)
+            if err != nil {
+                return false, fmt.Errorf("line %d: %s must be true or false", line, column)
+            }
+            return parsed, nil
+        }
+
+        switch field("record_type") {
+        case recordTodo, recordSharedTodo, recordTeamTodo:
+            done, err := flag("done")
+            if err != nil {
+                return nil, err
+            }
+            important, err := flag("important")
+            if err != nil {
+                return nil, err
+            }
+
+            switch field("record_type") {
+            case recordTodo:
+                doc.Todos = append(doc.Todos, dto.ExportTodo{
+                    ID:          field("id"),
+                    Task:        field("task"),
+                    Description: field("description"),
+                    Done:        done,
+                    Important:   important,
+                    Date:        field("date"),
+                    Time:        field("time"),
+                })
+            case recordSharedTodo:
+                doc.SharedTodos = append(doc.SharedTodos, dto.ExportSharedTodo{
+                    ID:          field("id"),
+                    Task:        field("task"),
+                    Description: field("description"),
+                    Done:        done,
+                    Important:   important,
+                    Date:        field("date"),
+                    Time:        field("time"),
+                    SharedBy:    field("shared_by"),
+                    SharedWith:  field("shared_with"),
+                })
+            default:
+                doc.TeamTodos = append(doc.TeamTodos, dto.ExportTeamTodo{
+                    ID:          field("id"),
+                    TeamID:      field("team_id"),
+                    Task:        field("task"),
+                    Description: field("description"),
+                    Done:        done,
+                    Important:   important,
+                    AssignedTo:  field("assigned_to"),
+                    Date:        field("date"),
+                    Time:        field("time"),
+                })
+            }
+        case recordRoutine:
+            isActive, err := flag("is_active")
+            if err != nil {
+                return nil, err
+            }
+            doc.Routines = append(doc.Routines, dto.ExportRoutine{
+                ID:           field("id"),
+                TaskID:       field("task_id"),
+                Day:          field("day"),
+                ScheduleType: field("schedule_type"),
+                IsActive:     isActive,
+            })
+        default:
+            return nil, fmt.Errorf("line %d: unknown record_type %q", line, field("record_type"))
+        }
+    }
+
+    return doc, nil
+}
@@ -0,0 +1,23 @@
+package transfer
+
+import (
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+func NewTransferService(
+    todoRepo domain.TodoRepository,
+    routineRepo domain.RoutineRepository,
+    sharedTodoRepo domain.SharedTodoRepository,
+    teamRepo domain.TeamRepository,
+    teamTodoRepo domain.TeamTodoRepository,
+    teamMemberRepo domain.TeamMemberRepository,
+) *TransferService {
+    return &TransferService{
+        todoRepo:       todoRepo,
+        routineRepo:    routineRepo,
+        sharedTodoRepo: sharedTodoRepo,
+        teamRepo:       teamRepo,
+        teamTodoRepo:   teamTodoRepo,
+        teamMemberRepo: teamMemberRepo,
+    }
+}
@@ -0,0 +1,618 @@
+package transfer
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+)
+
+// Supported export formats and import ID modes
+const (
+    FormatJSON = "json"
+    FormatCSV  = "csv"
+
+    // IDModePreserve keeps the IDs from the file, updating records that already exist
+    IDModePreserve = "preserve"
+    // IDModeRemap gives imported records new IDs and skips ones the user already has
+    IDModeRemap = "remap"
+)
+
+// exportFormatVersion is bumped whenever the document layout changes incompatibly
+const exportFormatVersion = 1
+
+var validDays = map[string]bool{
+    "sunday": true, "monday": true, "tuesday": true, "wednesday": true,
+    "thursday": true, "friday": true, "saturday": true,
+}
+
+type TransferService struct {
+    todoRepo       domain.TodoRepository
+    routineRepo    domain.RoutineRepository
+    sharedTodoRepo domain.SharedTodoRepository
+    teamRepo       domain.TeamRepository
+    teamTodoRepo   domain.TeamTodoRepository
+    teamMemberRepo domain.TeamMemberRepository
+}
+
+// Export collects everything the user can see and encodes it as JSON or CSV
+func (s *TransferService) Export(ctx context.Context, userID, format string) ([]byte, error) {
+    const functionName = "services.transfer.TransferService.Export"
+
+    if format != FormatJSON && format != FormatCSV {
+        return nil, fmt.Errorf("%s: unsupported format %q", functionName, format)
+    }
+
+    doc, err := s.buildExport(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to build export: %w", functionName, err)
+    }
+
+    var data []byte
+    if format == FormatCSV {
+        data, err = encodeCSV(doc)
+    } else {
+        data, err = json.MarshalIndent(doc, "", "  ")
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to encode export: %w", functionName, err)
+    }
+    return data, nil
+}
+
+func (s *TransferService) buildExport(ctx context.Context, userID string) (*dto.ExportDocument, error) {
+    doc := &dto.ExportDocument{
+        FormatVersion: exportFormatVersion,
+        ExportedAt:    time.Now().UTC(),
+        UserID:        userID,
+        Todos:         []dto.ExportTodo{},
+        Routines:      []dto.ExportRoutine{},
+        SharedTodos:   []dto.ExportSharedTodo{},
+        TeamTodos:     []dto.ExportTeamTodo{},
+    }
+
+    todos, err := s.todoRepo.GetTodosByUserID(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    for _, todo := range todos {
+        doc.Todos = append(doc.Todos, dto.ExportTodo{
+            ID:          todo.ID,
+            Task:        todo.Task,
+            Description: todo.Description,
+            Done:        todo.Done,
+            Important:   todo.Important,
+            Date:        formatDate(todo.Date),
+            Time:        formatTime(todo.Time),
+        })
+
+        routines, err := s.routineRepo.GetRoutinesByTaskID(ctx, todo.ID)
+        if err != nil {
+            return nil, err
+        }
+        for _, routine := range routines {
+            if routine.UserID != userID {
+                continue
+            }
+            doc.Routines = append(doc.Routines, dto.ExportRoutine{
+                ID:           routine.ID,
+                TaskID:       routine.TaskID,
+                Day:          routine.Day,
+                ScheduleType: routine.ScheduleType,
+                IsActive:     routine.IsActive,
+            })
+        }
+    }
+
+    // Shared items in both directions
+    received, err := s.sharedTodoRepo.GetSharedTodos(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    sent, err := s.sharedTodoRepo.GetSharedByMeTodos(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    for _, todo := range append(received, sent...) {
+        doc.SharedTodos = append(doc.SharedTodos, dto.ExportSharedTodo{
+            ID:          todo.ID,
+            Task:        todo.Task,
+            Description: todo.Description,
+            Done:        todo.Done,
+            Important:   todo.Important,
+            Date:        formatDate(todo.Date),
+            Time:        formatTime(todo.Time),
+            SharedBy:    todo.SharedBy,
+            SharedWith:  todo.UserID,
+        })
+    }
+
+    // Todos of every team the user belongs to
+    teams, err := s.teamRepo.GetTeams(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    for _, team := range teams {
+        teamTodos, err := s.teamTodoRepo.GetTeamTodos(ctx, team.ID)
+        if err != nil {
+            return nil, err
+        }
+        for _, todo := range teamTodos {
+            doc.TeamTodos = append(doc.TeamTodos, dto.ExportTeamTodo{
+                ID:          todo.ID,
+                TeamID:      todo.TeamID,
+                Task:        todo.Task,
+                Description: todo.Description,
+                Done:        todo.Done,
+                Important:   todo.Important,
+                AssignedTo:  todo.AssignedTo,
+                Date:        formatDate(todo.Date),
+                Time:        formatTime(todo.Time),
+            })
+        }
+    }
+
+    return doc, nil
+}
+
+// Import validates a document produced by Export and stores it for the user.
+// Nothing is written unless every record is valid. Writes are not transactional
+// across repositories, but running the same import again converges on the same
+// state: existing records are updated or skipped instead of duplicated.
+// Shared items are never imported since they belong to the sharing relationship.
+func (s *TransferService) Import(ctx context.Context, req *dto.ImportRequest) (*dto.ImportResponse, error) {
+    const functionName = "services.transfer.TransferService.Import"
+
+    idMode := req.IDMode
+    if idMode == "" {
+        idMode = IDModePreserve
+    }
+    if idMode != IDModePreserve && idMode != IDModeRemap {
+        return nil, fmt.Errorf("%s: invalid import: unsupported id mode %q", functionName, idMode)
+    }
+
+    var doc *dto.ExportDocument
+    var err error
+    switch req.Format {
+    case FormatCSV:
+        doc, err = decodeCSV(req.Data)
+    case FormatJSON:
+        doc = &dto.ExportDocument{}
+        err = json.Unmarshal(req.Data, doc)
+    default:
+        return nil, fmt.Errorf("%s: invalid import: unsupported format %q", functionName, req.Format)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%s: invalid import: %w", functionName, err)
+    }
+    if doc.FormatVersion > exportFormatVersion {
+        return nil, fmt.Errorf("%s: invalid import: format version %d is newer than supported", functionName, doc.FormatVersion)
+    }
+
+    res := &dto.ImportResponse{IDMode: idMode}
+    state, errs, err := s.validateImport(ctx, req.UserID, idMode, doc)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to validate import: %w", functionName, err)
+    }
+    if len(errs) > 0 {
+        res.Errors = errs
+        return res, fmt.Errorf("%s: invalid import: %d record(s) failed validation", functionName, len(errs))
+    }
+
+    idMap := make(map[string]string)
+    if err := s.importTodos(ctx, req.UserID, idMode, doc.Todos, state, idMap, res); err != nil {
+        return res, fmt.Errorf("%s: failed to import todos: %w", functionName, err)
+    }
+    if err := s.importRoutines(ctx, req.UserID, doc.Routines, idMap, res); err != nil {
+        return res, fmt.Errorf("%s: failed to import routines: %w", functionName, err)
+    }
+    if err := s.importTeamTodos(ctx, req.UserID, idMode, doc.TeamTodos, idMap, res); err != nil {
+        return res, fmt.Errorf("%s: failed to import team todos: %w", functionName, err)
+    }
+    res.Skipped += len(doc.SharedTodos)
+
+    // Only report IDs that changed
+    for oldID, newID := range idMap {
+        if oldID != newID {
+            if res.IDMap == nil {
+                res.IDMap = make(map[string]string)
+            }
+            res.IDMap[oldID] = newID
+        }
+    }
+    return res, nil
+}
+
+// importState holds what validation learned about the user's existing data
+type importState struct {
+    userTodos map[string]domain.Todo // the user's todos by ID
+    teams     map[string]bool        // IDs of teams the user belongs to
+    slots     map[string]bool        // names of the user's routine slots
+}
+
+// validateImport checks every record before anything is written. The returned
+// error is for lookups that failed; invalid records are reported in the slice.
+func (s *TransferService) validateImport(ctx context.Context, userID, idMode string, doc *dto.ExportDocument) (*importState, []dto.ImportErrorResponse, error) {
+    var errs []dto.ImportErrorResponse
+    reject := func(recordType string, index int, id, message string) {
+        errs = append(errs, dto.ImportErrorResponse{Type: recordType, Index: index, ID: id, Error: message})
+    }
+
+    state := &importState{userTodos: make(map[string]domain.Todo), teams: make(map[string]bool), slots: make(map[string]bool)}
+    todos, err := s.todoRepo.GetTodosByUserID(ctx, userID)
+    if err != nil {
+        return nil, nil, err
+    }
+    for _, todo := range todos {
+        state.userTodos[todo.ID] = todo
+    }
+    teams, err := s.teamRepo.GetTeams(ctx, userID)
+    if err != nil {
+        return nil, nil, err
+    }
+    for _, team := range teams {
+        state.teams[team.ID] = true
+    }
+    if len(doc.Routines) > 0 {
+        slots, err := s.routineRepo.GetRoutineSlots(ctx, userID)
+        if err != nil {
+            return nil, nil, err
+        }
+        // Users who never listed their slots still have the defaults
+        if len(slots) == 0 {
+            slots = domain.DefaultRoutineSlots()
+        }
+        for _, slot := range slots {
+            state.slots[slot.Name] = true
+        }
+    }
+
+    importedTodos := make(map[string]bool)
+    for i, todo := range doc.Todos {
+        if strings.TrimSpace(todo.Task) == "" {
+            reject(recordTodo, i, todo.ID, "task cannot be empty")
+        }
+        if err := validateDateTime(todo.Date, todo.Time); err != nil {
+            reject(recordTodo, i, todo.ID, err.Error())
+        }
+        if todo.ID == "" {
+            if idMode == IDModePreserve {
+                reject(recordTodo, i, todo.ID, "id is required when preserving IDs")
+            }
+            continue
+        }
+        if importedTodos[todo.ID] {
+            reject(recordTodo, i, todo.ID, "duplicate id")
+        }
+        importedTodos[todo.ID] = true
+
+        // A preserved ID must not collide with another user's todo
+        if _, owned := state.userTodos[todo.ID]; idMode == IDModePreserve && !owned {
+            existing, err := s.todoRepo.GetTodoByID(ctx, todo.ID)
+            if err != nil && !strings.Contains(err.Error(), "not found") {
+                return nil, nil, err
+            }
+            if err == nil && existing.UserID != userID {
+                reject(recordTodo, i, todo.ID, "id is already used by another user")
+            }
+        }
+    }
+
+    for i, routine := range doc.Routines {
+        if !importedTodos[routine.TaskID] && state.userTodos[routine.TaskID].ID == "" {
+            reject(recordRoutine, i, routine.ID, "task_id does not match an imported or existing todo")
+        }
+        if !validDays[routine.Day] {
+            reject(recordRoutine, i, routine.ID, fmt.Sprintf("invalid day %q", routine.Day))
+        }
+        if !state.slots[routine.ScheduleType] {
+            reject(recordRoutine, i, routine.ID, fmt.Sprintf("unknown schedule_type %q", routine.ScheduleType))
+        }
+    }
+
+    members := make(map[string]map[string]bool)
+    for i, todo := range doc.TeamTodos {
+        if !state.teams[todo.TeamID] {
+            reject(recordTeamTodo, i, todo.ID, "team_id is not a team you belong to")
+        } else if todo.AssignedTo != "" {
+            if _, loaded := members[todo.TeamID]; !loaded {
+                teamMembers, err := s.teamMemberRepo.GetTeamMembers(ctx, todo.TeamID)
+                if err != nil {
+                    return nil, nil, err
+                }
+                members[todo.TeamID] = make(map[string]bool, len(teamMembers))
+                for _, member := range teamMembers {
+                    members[todo.TeamID][member.UserID] = true
+                }
+            }
+            if !members[todo.TeamID][todo.AssignedTo] {
+                reject(recordTeamTodo, i, todo.ID, "assigned_to is not a member of the team")
+            }
+        }
+        if strings.TrimSpace(todo.Task) == "" {
+            reject(recordTeamTodo, i, todo.ID, "task cannot be empty")
+        }
+        if err := validateDateTime(todo.Date, todo.Time); err != nil {
+            reject(recordTeamTodo, i, todo.ID, err.Error())
+        }
+        if todo.ID == "" && idMode == IDModePreserve {
+            reject(recordTeamTodo, i, todo.ID, "id is required when preserving IDs")
+        }
+
+        // A preserved ID must not collide with another team's todo
+        if todo.ID != "" && idMode == IDModePreserve && state.teams[todo.TeamID] {
+            teamID, err := s.teamTodoRepo.GetTeamTodoTeamID(ctx, todo.ID)
+            if err != nil && !strings.Contains(err.Error(), "not found") {
+                return nil, nil, err
+            }
+            if err == nil && teamID != todo.TeamID {
+                reject(recordTeamTodo, i, todo.ID, "id is already used by another team")
+            }
+        }
+    }
+
+    for i, todo := range doc.SharedTodos {
+        if err := validateDateTime(todo.Date, todo.Time); err != nil {
+            reject(recordSharedTodo, i, todo.ID, err.Error())
+        }
+    }
+
+    return state, errs, nil
+}
+
+func (s *TransferService) importTodos(ctx context.Context, userID, idMode string, todos []dto.ExportTodo, state *importState, idMap map[string]string, res *dto.ImportResponse) error {
+    for _, todo := range todos {
+        date, timeValue, _ := parseDateTime(todo.Date, todo.Time)
+
+        if idMode == IDModeRemap {
+            // A todo the user already has with the same content is not created twice
+            if existing, ok := findMatchingTodo(state.userTodos, todo); ok {
+                idMap[todo.ID] = existing.ID
+                res.Skipped++
+                continue
+            }
+            id, err := s.todoRepo.CreateTodo(ctx, todo.Task, todo.Description, todo.Done, todo.Important, userID, date, timeValue)
+            if err != nil {
+                return err
+            }
+            idMap[todo.ID] = id
+            state.userTodos[id] = domain.Todo{ID: id, Task: todo.Task, Description: todo.Description, Date: date, Time: timeValue}
+            res.Created++
+            continue
+        }
+
+        idMap[todo.ID] = todo.ID
+        existing, ok := state.userTodos[todo.ID]
+        if !ok {
+            if err := s.todoRepo.CreateTodoWithID(ctx, todo.ID, todo.Task, todo.Description, todo.Done, todo.Important, userID, date, timeValue); err != nil {
+                return err
+            }
+            res.Created++
+            continue
+        }
+        if sameTodo(existing, todo) {
+            res.Skipped++
+            continue
+        }
+
+        patch := domain.TodoPatch{
+            Task:        &todo.Task,
+            Description: &todo.Description,
+            Done:        &todo.Done,
+            Important:   &todo.Important,
+            Date:        &date,
+            ClearDate:   todo.Date == "",
+            Time:        &timeValue,
+            ClearTime:   todo.Time == "",
+        }
+        if _, err := s.todoRepo.PatchTodo(ctx, todo.ID, userID, patch, 0); err != nil {
+            return err
+        }
+        res.Updated++
+    }
+    return nil
+}
+
+// importRoutines applies the routine records one task and day at a time, since
+// CreateOrUpdateRoutines sets the full list of active schedules for that pair
+func (s *TransferService) importRoutines(ctx context.Context, userID string, routines []dto.ExportRoutine, idMap map[string]string, res *dto.ImportResponse) error {
+    type taskDay struct {
+        taskID string
+        day    string
+    }
+    var order []taskDay
+    groups := make(map[taskDay][]dto.ExportRoutine)
+    for _, routine := range routines {
+        taskID := routine.TaskID
+        if mapped, ok := idMap[taskID]; ok {
+            taskID = mapped
+        }
+        key := taskDay{taskID: taskID, day: routine.Day}
+        if _, seen := groups[key]; !seen {
+            order = append(order, key)
+        }
+        groups[key] = append(groups[key], routine)
+    }
+
+    for _, key := range order {
+        existing, err := s.routineRepo.GetRoutinesByTaskID(ctx, key.taskID)
+        if err != nil {
+            return err
+        }
+        current := make(map[string]bool) // schedule type -> active, for this user and day
+        for _, routine := range existing {
+            if routine.UserID == userID && routine.Day == key.day {
+                current[routine.ScheduleType] = routine.IsActive
+            }
+        }
+
+        var schedules []string
+        changed := false
+        for _, routine := range groups[key] {
+            active, exists := current[routine.ScheduleType]
+            switch {
+            case exists && active == routine.IsActive, !exists && !routine.IsActive:
+                res.Skipped++
+            case exists:
+                res.Updated++
+                changed = true
+            default:
+                res.Created++
+                changed = true
+            }
+            if routine.IsActive {
+                schedules = append(schedules, routine.ScheduleType)
+            }
+        }
+        if !changed {
+            continue
+        }
+        if _, err := s.routineRepo.CreateOrUpdateRoutines(ctx, key.taskID, schedules, key.day, userID); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *TransferService) importTeamTodos(ctx context.Context, userID, idMode string, todos []dto.ExportTeamTodo, idMap map[string]string, res *dto.ImportResponse) error {
+    teamTodos := make(map[string][]domain.TeamTodo)
+    for _, todo := range todos {
+        date, timeValue, _ := parseDateTime(todo.Date, todo.Time)
+
+        if idMode == IDModeRemap {
+            if _, loaded := teamTodos[todo.TeamID]; !loaded {
+                existing, err := s.teamTodoRepo.GetTeamTodos(ctx, todo.TeamID)
+                if err != nil {
+                    return err
+                }
+                teamTodos[todo.TeamID] = existing
+            }
+            if existing, ok := findMatchingTeamTodo(teamTodos[todo.TeamID], todo); ok {
+                idMap[todo.ID] = existing.ID
+                res.Skipped++
+                continue
+            }
+            id, err := s.teamTodoRepo.CreateTeamTodo(ctx, todo.Task, todo.Description, todo.Done, todo.Important, todo.TeamID, todo.AssignedTo, userID, date, timeValue)
+            if err != nil {
+                return err
+            }
+            idMap[todo.ID] = id
+            teamTodos[todo.TeamID] = append(teamTodos[todo.TeamID], domain.TeamTodo{ID: id, Task: todo.Task, Description: todo.Description, AssignedTo: todo.AssignedTo})
+            res.Created++
+            continue
+        }
+
+        idMap[todo.ID] = todo.ID
+        existing, err := s.teamTodoRepo.GetTeamTodoByID(ctx, todo.ID, todo.TeamID)
+        if err != nil && !strings.Contains(err.Error(), "not found") {
+            return err
+        }
+        if err != nil {
+            if err := s.teamTodoRepo.CreateTeamTodoWithID(ctx, todo.ID, todo.Task, todo.Description, todo.Done, todo.Important, todo.TeamID, todo.AssignedTo, userID, date, timeValue); err != nil {
+                return err
+            }
+            res.Created++
+            continue
+        }
+        if sameTeamTodo(*existing, todo) {
+            res.Skipped++
+            continue
+        }
+
+        patch := domain.TeamTodoPatch{
+            TodoPatch: domain.TodoPatch{
+                Task:        &todo.Task,
+                Description: &todo.Description,
+                Done:        &todo.Done,
+                Important:   &todo.Important,
+                Date:        &date,
+                ClearDate:   todo.Date == "",
+                Time:        &timeValue,
+                ClearTime:   todo.Time == "",
+            },
+            AssignedTo: &todo.AssignedTo,
+        }
+        if _, err := s.teamTodoRepo.PatchTeamTodo(ctx, todo.ID, todo.TeamID, userID, patch, 0); err != nil {
+            return err
+        }
+        res.Updated++
+    }
+    return nil
+}
+
+func findMatchingTodo(todos map[string]domain.Todo, todo dto.ExportTodo) (domain.Todo, bool) {
+    for _, existing := range todos {
+        if existing.Task == todo.Task && existing.Description == todo.Description &&
+            formatDate(existing.Date) == todo.Date && formatTime(existing.Time) == todo.Time {
+            return existing, true
+        }
+    }
+    return domain.Todo{}, false
+}
+
+func findMatchingTeamTodo(todos []domain.TeamTodo, todo dto.ExportTeamTodo) (domain.TeamTodo, bool) {
+    for _, existing := range todos {
+        if existing.Task == todo.Task && existing.Description == todo.Description && existing.AssignedTo == todo.AssignedTo {
+            return existing, true
+        }
+    }
+    return domain.TeamTodo{}, false
+}
+
+func sameTodo(existing domain.Todo, todo dto.ExportTodo) bool {
+    return existing.Task == todo.Task && existing.Description == todo.Description &&
+        existing.Done == todo.Done && existing.Important == todo.Important &&
+        formatDate(existing.Date) == todo.Date && formatTime(existing.Time) == todo.Time
+}
+
+func sameTeamTodo(existing domain.TeamTodo, todo dto.ExportTeamTodo) bool {
+    return existing.Task == todo.Task && existing.Description == todo.Description &&
+        existing.Done == todo.Done && existing.Important == todo.Important &&
+        existing.AssignedTo == todo.AssignedTo &&
+        formatDate(existing.Date) == todo.Date && formatTime(existing.Time) == todo.Time
+}
+
+func formatDate(date time.Time) string {
+    if date.IsZero() {
+        return ""
+    }
+    return date.Format("2006-01-02")
+}
+
+func formatTime(value time.Time) string {
+    if value.IsZero() {
+        return ""
+    }
+    return value.Format("15:04:05")
+}
+
+func validateDateTime(dateString, timeString string) error {
+    _, _, err := parseDateTime(dateString, timeString)
+    return err
+}
+
+// parseDateTime parses the export date and time columns. Empty values give zero times.
+func parseDateTime(dateString, timeString string) (time.Time, time.Time, error) {
+    var date, timeValue time.Time
+    if dateString != "" {
+        parsed, err := time.Parse("2006-01-02", dateString)
+        if err != nil {
+            return date, timeValue, fmt.Errorf("invalid date %q, use YYYY-MM-DD", dateString)
+        }
+        date = parsed
+    }
+    if timeString != "" {
+        parsed, err := time.Parse("15:04:05", timeString)
+        if err != nil {
+            return date, timeValue, fmt.Errorf("invalid time %q, use HH:MM:SS", timeString)
+        }
+        // Use a valid year for the time, as the repositories do
+        hour, min, sec := parsed.Clock()
+        timeValue = time.Date(2000, 1, 1, hour, min, sec, 0, time.UTC)
+    }
+    return date, timeValue, nil
+}
@@ -3,6 +3,8 @@ package users
 import (
     "context"
     "fmt"
+    "time"
+    
     "golang.org/x/crypto/bcrypt"
     
     "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
@@ -40,10 +42,90 @@ func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*
         ID:       user.ID,
         Username: user.Username,
         Password: user.Password,
+        Timezone: user.Timezone,
     }, nil
 }
 
 // VerifyPassword compares a hashed password with a plaintext password
 func (s *UserService) VerifyPassword(hashedPassword, password string) error {
     return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-}
\ No newline at end of file
+}
+
+// GetTimezone returns the timezone the user's days are counted in
+func (s *UserService) GetTimezone(ctx context.Context, userID string) (*dto.TimezoneResponse, error) {
+    const functionName = "services.users.UserService.GetTimezone"
+    
+    timezone, err := s.repo.GetUserTimezone(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get timezone: %w", functionName, err)
+    }
+    return &dto.TimezoneResponse{Timezone: timezone}, nil
+}
+
+// SetTimezone stores the user's IANA timezone such as "America/New_York"
+func (s *UserService) SetTimezone(ctx context.Context, req *dto.SetTimezoneRequest) (*dto.TimezoneResponse, error) {
+    const functionName = "services.users.UserService.SetTimezone"
+    
+    loc, err := ParseTimezone(req.Timezone)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    if err := s.repo.SetUserTimezone(ctx, req.UserID, loc.String()); err != nil {
+        return nil, fmt.Errorf("%s: failed to set timezone: %w", functionName, err)
+    }
+    return &dto.TimezoneResponse{Timezone: loc.String()}, nil
+}
+
+// Location resolves the timezone for a request: override, typically from the
+// X-Timezone header, wins over the stored timezone when it is set
+func (s *UserService) Location(ctx context.Context, userID, override string) (*time.Location, error) {
+    const functionName = "services.users.UserService.Location"
+    
+    if override != "" {
+        loc, err := ParseTimezone(override)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+        return loc, nil
+    }
+    
+    timezone, err := s.repo.GetUserTimezone(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get timezone: %w", functionName, err)
+    }
+    loc, err := ParseTimezone(timezone)
+    if err != nil {
+        // A zone removed from the tz database should not lock the user out
+        return time.UTC, nil
+    }
+    return loc, nil
+}
+
+// ParseTimezone loads an IANA timezone. "Local" is refused because it would
+// silently mean the server's zone.
+func ParseTimezone(name string) (*time.Location, error) {
+    if name == "" || name == "Local" {
+        return nil, fmt.Errorf("invalid timezone %q", name)
+    }
+    loc, err := time.LoadLocation(name)
+    if err != nil {
+        return nil, fmt.Errorf("invalid timezone %q", name)
+    }
+    return loc, nil
+}
+
+// LocalNow returns the current time with the wall clock of loc, labelled UTC so it
+// compares with dates and times parsed from strings and the database driver
+// stores it unchanged. A nil loc means the server's zone.
+func LocalNow(loc *time.Location) time.Time {
+    if loc == nil {
+        loc = time.Local
+    }
+    now := time.Now().In(loc)
+    return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+}
+
+// Today returns the current date in loc, labelled UTC like LocalNow
+func Today(loc *time.Location) time.Time {
+    return LocalNow(loc).Truncate(24 * time.Hour)
+}
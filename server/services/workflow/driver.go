@@ -0,0 +1,14 @@
+package workflow
+
+import (
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+)
+
+func NewWorkflowService(repo domain.WorkflowRepository, teamTodoRepo domain.TeamTodoRepository, dependencyService *dependencies.DependencyService) *WorkflowService {
+    return &WorkflowService{
+        repo:              repo,
+        teamTodoRepo:      teamTodoRepo,
+        dependencyService: dependencyService,
+    }
+}
@@ -0,0 +1,425 @@
+package workflow
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/dependencies"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+)
+
+// maxStatusNameLength matches the size of the team_statuses.name column
+const maxStatusNameLength = 64
+
+// defaultStatuses is the workflow a team starts with until it configures its own
+var defaultStatuses = []dto.TeamStatusRequest{
+    {Name: "Backlog"},
+    {Name: "In Progress"},
+    {Name: "Review"},
+    {Name: "Done", Terminal: true},
+}
+
+// WorkflowService manages per-team statuses and the Kanban board. The done flag
+// of a team todo follows the terminal flag of its status, so clients that only
+// know about done keep working. When such a client flips done directly, the todo
+// is shown in the first status matching the new flag until it is moved again.
+type WorkflowService struct {
+    repo              domain.WorkflowRepository
+    teamTodoRepo      domain.TeamTodoRepository
+    dependencyService *dependencies.DependencyService
+}
+
+// workflow is a team's statuses and transition rules
+type workflow struct {
+    statuses    []domain.TeamStatus
+    transitions []domain.StatusTransition
+}
+
+// load returns the team's workflow. A team that has not configured one gets the
+// default workflow, which reads keep in memory and writes store first by passing
+// persist.
+func (s *WorkflowService) load(ctx context.Context, teamID string, persist bool) (*workflow, error) {
+    statuses, err := s.repo.GetTeamStatuses(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    if len(statuses) == 0 {
+        if !persist {
+            return &workflow{statuses: defaultWorkflow(teamID)}, nil
+        }
+        statuses, err = s.repo.EnsureTeamWorkflow(ctx, teamID, defaultWorkflow(teamID))
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    transitions, err := s.repo.GetStatusTransitions(ctx, teamID)
+    if err != nil {
+        return nil, err
+    }
+    sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].Position < statuses[j].Position })
+    return &workflow{statuses: statuses, transitions: transitions}, nil
+}
+
+// defaultWorkflow builds the default statuses of a team. Their IDs are derived
+// from the team and the name, so reads see the IDs the statuses get once stored.
+func defaultWorkflow(teamID string) []domain.TeamStatus {
+    statuses := newStatuses(teamID, defaultStatuses, nil)
+    for i := range statuses {
+        statuses[i].ID = uuid.NewSHA1(uuid.NameSpaceOID, []byte(teamID+"/"+statuses[i].Name)).String()
+    }
+    return statuses
+}
+
+// GetWorkflow returns the team's statuses in board order and its transition rules
+func (s *WorkflowService) GetWorkflow(ctx context.Context, teamID string) (*dto.WorkflowResponse, error) {
+    const functionName = "services.workflow.WorkflowService.GetWorkflow"
+
+    wf, err := s.load(ctx, teamID, false)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to load workflow: %w", functionName, err)
+    }
+    return wf.response(), nil
+}
+
+// UpdateWorkflow replaces the team's statuses and transitions. Statuses are matched
+// to existing ones by name so todos keep their place; todos in a removed status
+// fall back to the first status matching their done flag.
+func (s *WorkflowService) UpdateWorkflow(ctx context.Context, req *dto.UpdateWorkflowRequest) (*dto.WorkflowResponse, error) {
+    const functionName = "services.workflow.WorkflowService.UpdateWorkflow"
+
+    if err := validateWorkflow(req); err != nil {
+        return nil, fmt.Errorf("%s: invalid workflow: %w", functionName, err)
+    }
+
+    existing, err := s.repo.GetTeamStatuses(ctx, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get statuses: %w", functionName, err)
+    }
+    // Keep the IDs clients saw for the default statuses
+    if len(existing) == 0 {
+        existing = defaultWorkflow(req.TeamID)
+    }
+    statuses := newStatuses(req.TeamID, req.Statuses, existing)
+
+    byName := make(map[string]string, len(statuses))
+    for _, status := range statuses {
+        byName[strings.ToLower(status.Name)] = status.ID
+    }
+    var transitions []domain.StatusTransition
+    for _, t := range req.Transitions {
+        transitions = append(transitions, domain.StatusTransition{
+            FromStatusID: byName[strings.ToLower(strings.TrimSpace(t.From))],
+            ToStatusID:   byName[strings.ToLower(strings.TrimSpace(t.To))],
+        })
+    }
+
+    if err := s.repo.ReplaceTeamWorkflow(ctx, req.TeamID, statuses, transitions); err != nil {
+        return nil, fmt.Errorf("%s: failed to save workflow: %w", functionName, err)
+    }
+    wf := &workflow{statuses: statuses, transitions: transitions}
+    return wf.response(), nil
+}
+
+// GetBoard groups the team's todos by status, each column in board order, and
+// flags the overdue ones by the clock of loc
+func (s *WorkflowService) GetBoard(ctx context.Context, teamID string, loc *time.Location) (*dto.BoardResponse, error) {
+    const functionName = "services.workflow.WorkflowService.GetBoard"
+
+    wf, err := s.load(ctx, teamID, false)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to load workflow: %w", functionName, err)
+    }
+    columns, todos, err := s.columns(ctx, teamID, wf)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+
+    now := users.LocalNow(loc)
+    res := &dto.BoardResponse{TeamID: teamID}
+    for _, status := range wf.statuses {
+        column := dto.BoardColumnResponse{Status: statusResponse(status), Todos: []dto.TeamTodoResponse{}}
+        for _, id := range columns[status.ID] {
+            column.Todos = append(column.Todos, dto.NewTeamTodoResponseFromDomain(todos[id], now))
+        }
+        res.Columns = append(res.Columns, column)
+    }
+    return res, nil
+}
+
+// MoveTeamTodo puts a team todo into a status at the requested position and
+// updates its done flag to match the status
+func (s *WorkflowService) MoveTeamTodo(ctx context.Context, req *dto.MoveTeamTodoRequest) (*dto.MoveTeamTodoResponse, error) {
+    const functionName = "services.workflow.WorkflowService.MoveTeamTodo"
+
+    wf, err := s.load(ctx, req.TeamID, true)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to load workflow: %w", functionName, err)
+    }
+    target, ok := wf.find(req.Status)
+    if !ok {
+        return nil, fmt.Errorf("%s: status %q not found", functionName, req.Status)
+    }
+
+    columns, todos, err := s.columns(ctx, req.TeamID, wf)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", functionName, err)
+    }
+    todo, ok := todos[req.ID]
+    if !ok {
+        return nil, fmt.Errorf("%s: team todo not found", functionName)
+    }
+
+    current := currentStatus(columns, req.ID)
+    if current != target.ID && !wf.allows(current, target.ID) {
+        return nil, fmt.Errorf("%s: transition from %q to %q is not allowed", functionName, wf.name(current), target.Name)
+    }
+    // Moving into a terminal status completes the todo
+    if target.Terminal && !todo.Done && !req.Force {
+        if err := s.dependencyService.CheckTeamTodosCanComplete(ctx, req.TeamID, req.ID); err != nil {
+            return nil, fmt.Errorf("%s: %w", functionName, err)
+        }
+    }
+
+    // Renumber the target column with the todo at its new position
+    var order []string
+    for _, id := range columns[target.ID] {
+        if id != req.ID {
+            order = append(order, id)
+        }
+    }
+    position := len(order)
+    if req.Position != nil {
+        if *req.Position < 0 {
+            return nil, fmt.Errorf("%s: position cannot be negative", functionName)
+        }
+        if *req.Position < position {
+            position = *req.Position
+        }
+    }
+    order = append(order[:position], append([]string{req.ID}, order[position:]...)...)
+
+    if _, err := s.teamTodoRepo.MoveTeamTodo(ctx, req.ID, req.TeamID, target.ID, target.Terminal, order, req.Version); err != nil {
+        return nil, fmt.Errorf("%s: failed to move team todo: %w", functionName, err)
+    }
+
+    moved, err := s.teamTodoRepo.GetTeamTodoByID(ctx, req.ID, req.TeamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todo: %w", functionName, err)
+    }
+    return &dto.MoveTeamTodoResponse{
+        Todo:     dto.NewTeamTodoResponseFromDomain(*moved, users.LocalNow(req.Location)),
+        Status:   statusResponse(target),
+        Position: position,
+    }, nil
+}
+
+// columns places every team todo in a status and returns the todo IDs of each
+// status in board order, along with the todos by ID
+func (s *WorkflowService) columns(ctx context.Context, teamID string, wf *workflow) (map[string][]string, map[string]domain.TeamTodo, error) {
+    list, err := s.teamTodoRepo.GetTeamTodos(ctx, teamID)
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to get team todos: %w", err)
+    }
+    placements, err := s.repo.GetTeamTodoPlacements(ctx, teamID)
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to get placements: %w", err)
+    }
+    placed := make(map[string]domain.TeamTodoPlacement, len(placements))
+    for _, p := range placements {
+        placed[p.TodoID] = p
+    }
+
+    type entry struct {
+        id       string
+        placed   bool
+        position int
+    }
+    entries := make(map[string][]entry)
+    todos := make(map[string]domain.TeamTodo, len(list))
+    for _, todo := range list {
+        todos[todo.ID] = todo
+        p, ok := placed[todo.ID]
+        status, known := wf.byID(p.StatusID)
+
+        // A placement only counts while its status agrees with the done flag
+        if !ok || !known || status.Terminal != todo.Done {
+            status = wf.fallback(todo.Done)
+            entries[status.ID] = append(entries[status.ID], entry{id: todo.ID})
+            continue
+        }
+        entries[status.ID] = append(entries[status.ID], entry{id: todo.ID, placed: true, position: p.Position})
+    }
+
+    // Placed todos keep their order; todos without a place go to the end of the column
+    columns := make(map[string][]string, len(entries))
+    for statusID, list := range entries {
+        sort.SliceStable(list, func(i, j int) bool {
+            if list[i].placed != list[j].placed {
+                return list[i].placed
+            }
+            return list[i].position < list[j].position
+        })
+        for _, e := range list {
+            columns[statusID] = append(columns[statusID], e.id)
+        }
+    }
+    return columns, todos, nil
+}
+
+func currentStatus(columns map[string][]string, todoID string) string {
+    for statusID, ids := range columns {
+        for _, id := range ids {
+            if id == todoID {
+                return statusID
+            }
+        }
+    }
+    return ""
+}
+
+func (wf *workflow) byID(id string) (domain.TeamStatus, bool) {
+    for _, status := range wf.statuses {
+        if status.ID == id {
+            return status, true
+        }
+    }
+    return domain.TeamStatus{}, false
+}
+
+// find looks a status up by ID or, case-insensitively, by name
+func (wf *workflow) find(ref string) (domain.TeamStatus, bool) {
+    if status, ok := wf.byID(ref); ok {
+        return status, true
+    }
+    for _, status := range wf.statuses {
+        if strings.EqualFold(status.Name, strings.TrimSpace(ref)) {
+            return status, true
+        }
+    }
+    return domain.TeamStatus{}, false
+}
+
+func (wf *workflow) name(id string) string {
+    status, _ := wf.byID(id)
+    return status.Name
+}
+
+// fallback is the first status whose terminal flag matches done
+func (wf *workflow) fallback(done bool) domain.TeamStatus {
+    for _, status := range wf.statuses {
+        if status.Terminal == done {
+            return status
+        }
+    }
+    return wf.statuses[0]
+}
+
+// allows reports whether the transition rules permit a move. Without rules every move is allowed.
+func (wf *workflow) allows(from, to string) bool {
+    if len(wf.transitions) == 0 {
+        return true
+    }
+    for _, t := range wf.transitions {
+        if t.FromStatusID == from && t.ToStatusID == to {
+            return true
+        }
+    }
+    return false
+}
+
+func (wf *workflow) response() *dto.WorkflowResponse {
+    res := &dto.WorkflowResponse{
+        Statuses:    []dto.TeamStatusResponse{},
+        Transitions: []dto.StatusTransitionResponse{},
+    }
+    for _, status := range wf.statuses {
+        res.Statuses = append(res.Statuses, statusResponse(status))
+    }
+    for _, t := range wf.transitions {
+        res.Transitions = append(res.Transitions, dto.StatusTransitionResponse{From: wf.name(t.FromStatusID), To: wf.name(t.ToStatusID)})
+    }
+    return res
+}
+
+// newStatuses builds statuses in the requested order, reusing the IDs of existing
+// statuses with the same name
+func newStatuses(teamID string, requested []dto.TeamStatusRequest, existing []domain.TeamStatus) []domain.TeamStatus {
+    ids := make(map[string]string, len(existing))
+    for _, status := range existing {
+        ids[strings.ToLower(status.Name)] = status.ID
+    }
+
+    statuses := make([]domain.TeamStatus, len(requested))
+    for i, r := range requested {
+        name := strings.TrimSpace(r.Name)
+        id, ok := ids[strings.ToLower(name)]
+        if !ok {
+            id = uuid.New().String()
+        }
+        statuses[i] = domain.TeamStatus{ID: id, TeamID: teamID, Name: name, Position: i, Terminal: r.Terminal}
+    }
+    return statuses
+}
+
+func validateWorkflow(req *dto.UpdateWorkflowRequest) error {
+    if len(req.Statuses) == 0 {
+        return fmt.Errorf("at least one status is required")
+    }
+
+    names := make(map[string]bool, len(req.Statuses))
+    var terminal, open bool
+    for i, status := range req.Statuses {
+        name := strings.TrimSpace(status.Name)
+        if name == "" {
+            return fmt.Errorf("status %d: name cannot be empty", i)
+        }
+        if len(name) > maxStatusNameLength {
+            return fmt.Errorf("status %d: name cannot be longer than %d characters", i, maxStatusNameLength)
+        }
+        if names[strings.ToLower(name)] {
+            return fmt.Errorf("status %d: duplicate name %q", i, name)
+        }
+        names[strings.ToLower(name)] = true
+        if status.Terminal {
+            terminal = true
+        } else {
+            open = true
+        }
+    }
+    // done is derived from the status, so both values must be reachable
+    if !terminal || !open {
+        return fmt.Errorf("a workflow needs at least one terminal and one non-terminal status")
+    }
+
+    seen := make(map[string]bool, len(req.Transitions))
+    for i, t := range req.Transitions {
+        from, to := strings.ToLower(strings.TrimSpace(t.From)), strings.ToLower(strings.TrimSpace(t.To))
+        if !names[from] || !names[to] {
+            return fmt.Errorf("transition %d: unknown status", i)
+        }
+        if from == to {
+            return fmt.Errorf("transition %d: from and to must differ", i)
+        }
+        if seen[from+"\x00"+to] {
+            return fmt.Errorf("transition %d: duplicate transition", i)
+        }
+        seen[from+"\x00"+to] = true
+    }
+    return nil
+}
+
+func statusResponse(status domain.TeamStatus) dto.TeamStatusResponse {
+    return dto.TeamStatusResponse{
+        ID:       status.ID,
+        Name:     status.Name,
+        Position: status.Position,
+        Terminal: status.Terminal,
+    }
+}
@@ -0,0 +1,12 @@
+package workload
+
+import (
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+)
+
+func NewWorkloadService(repo domain.WorkloadRepository, teamTodoRepo domain.TeamTodoRepository) *WorkloadService {
+    return &WorkloadService{
+        repo:         repo,
+        teamTodoRepo: teamTodoRepo,
+    }
+}
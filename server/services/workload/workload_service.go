@@ -0,0 +1,176 @@
+package workload
+
+import (
+    "context"
+    "fmt"
+    "math"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/domain"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/persistent/dto"
+    "github.com/its-AbhaySahani/Todo-app-Using-Go-React/services/users"
+)
+
+// maxEstimate is the largest value the team_todo_estimates.estimate column holds
+const maxEstimate = 999999.99
+
+// WorkloadService manages team todo estimates and reports how the open work of a
+// team is spread across its members
+type WorkloadService struct {
+    repo         domain.WorkloadRepository
+    teamTodoRepo domain.TeamTodoRepository
+}
+
+// SetEstimate sets or replaces the estimate of a team todo
+func (s *WorkloadService) SetEstimate(ctx context.Context, req *dto.SetEstimateRequest) (*dto.EstimateResponse, error) {
+    const functionName = "services.workload.WorkloadService.SetEstimate"
+
+    unit := strings.ToLower(strings.TrimSpace(req.Unit))
+    if unit == "" {
+        unit = domain.EstimateUnitHours
+    }
+    if unit != domain.EstimateUnitHours && unit != domain.EstimateUnitPoints {
+        return nil, fmt.Errorf("%s: invalid estimate: unit must be hours or points", functionName)
+    }
+    if req.Value <= 0 || req.Value > maxEstimate || math.IsNaN(req.Value) {
+        return nil, fmt.Errorf("%s: invalid estimate: value must be between 0 and %v", functionName, maxEstimate)
+    }
+    if _, err := s.teamTodoRepo.GetTeamTodoByID(ctx, req.TodoID, req.TeamID); err != nil {
+        return nil, fmt.Errorf("%s: team todo not found", functionName)
+    }
+
+    estimate := domain.Estimate{TodoID: req.TodoID, Value: math.Round(req.Value*100) / 100, Unit: unit}
+    if err := s.repo.SetTeamTodoEstimate(ctx, req.TeamID, estimate); err != nil {
+        return nil, fmt.Errorf("%s: failed to set estimate: %w", functionName, err)
+    }
+    return &dto.EstimateResponse{TodoID: estimate.TodoID, Value: estimate.Value, Unit: estimate.Unit}, nil
+}
+
+// ClearEstimate removes the estimate of a team todo
+func (s *WorkloadService) ClearEstimate(ctx context.Context, teamID, todoID string) (*dto.SuccessResponse, error) {
+    const functionName = "services.workload.WorkloadService.ClearEstimate"
+
+    deleted, err := s.repo.DeleteTeamTodoEstimate(ctx, teamID, todoID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to clear estimate: %w", functionName, err)
+    }
+    if !deleted {
+        return nil, fmt.Errorf("%s: estimate not found", functionName)
+    }
+    return &dto.SuccessResponse{Success: true}, nil
+}
+
+// TeamTodoEstimates returns the estimates of a team's todos by todo ID, for list responses
+func (s *WorkloadService) TeamTodoEstimates(ctx context.Context, teamID string) (map[string]dto.EstimateResponse, error) {
+    const functionName = "services.workload.WorkloadService.TeamTodoEstimates"
+
+    estimates, err := s.repo.GetTeamTodoEstimates(ctx, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get estimates: %w", functionName, err)
+    }
+    byTodo := make(map[string]dto.EstimateResponse, len(estimates))
+    for _, estimate := range estimates {
+        byTodo[estimate.TodoID] = dto.EstimateResponse{TodoID: estimate.TodoID, Value: estimate.Value, Unit: estimate.Unit}
+    }
+    return byTodo, nil
+}
+
+// GetWorkload counts open, overdue and completed todos and totals their estimates
+// for every member, busiest first. A todo with several assignees counts, with its
+// estimate, for each of them, open until their own part is done. Whether a todo
+// is overdue is decided on the wall clock of loc, as in the team todo responses.
+func (s *WorkloadService) GetWorkload(ctx context.Context, teamID string, loc *time.Location) (*dto.WorkloadResponse, error) {
+    const functionName = "services.workload.WorkloadService.GetWorkload"
+
+    members, err := s.repo.GetTeamMemberDetails(ctx, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get members: %w", functionName, err)
+    }
+    todos, err := s.repo.GetTeamWorkloadTodos(ctx, teamID)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to get team todos: %w", functionName, err)
+    }
+
+    res := &dto.WorkloadResponse{TeamID: teamID, Members: []dto.MemberWorkloadResponse{}}
+    rows := make(map[string]*dto.MemberWorkloadResponse, len(members))
+    for _, member := range members {
+        rows[member.UserID] = &dto.MemberWorkloadResponse{
+            UserID:   member.UserID,
+            Username: member.Username,
+            IsMember: true,
+            IsAdmin:  member.IsAdmin,
+        }
+    }
+
+    now := users.LocalNow(loc)
+    for _, todo := range todos {
+        row := &res.Unassigned
+        if todo.AssignedTo != "" {
+            row = rows[todo.AssignedTo]
+            if row == nil {
+                row = &dto.MemberWorkloadResponse{UserID: todo.AssignedTo, Username: todo.AssigneeName}
+                rows[todo.AssignedTo] = row
+            }
+        }
+        addTodo(row, todo, now)
+    }
+
+    for _, row := range rows {
+        res.Members = append(res.Members, *row)
+    }
+    for i := range res.Members {
+        roundTotals(&res.Members[i])
+    }
+    roundTotals(&res.Unassigned)
+    sort.Slice(res.Members, func(i, j int) bool {
+        a, b := res.Members[i], res.Members[j]
+        if a.OpenEstimate.Hours != b.OpenEstimate.Hours {
+            return a.OpenEstimate.Hours > b.OpenEstimate.Hours
+        }
+        if a.OpenEstimate.Points != b.OpenEstimate.Points {
+            return a.OpenEstimate.Points > b.OpenEstimate.Points
+        }
+        if a.Open != b.Open {
+            return a.Open > b.Open
+        }
+        return a.Username < b.Username
+    })
+    return res, nil
+}
+
+// addTodo counts a todo towards a workload row
+func addTodo(row *dto.MemberWorkloadResponse, todo domain.WorkloadTodo, now time.Time) {
+    totals := &row.OpenEstimate
+    if todo.Done {
+        row.Completed++
+        totals = &row.CompletedEstimate
+    } else {
+        row.Open++
+        if (domain.TeamTodo{Date: todo.Date, Time: todo.Time}).Overdue(now) {
+            row.Overdue++
+        }
+        if todo.Estimate == nil {
+            row.Unestimated++
+        }
+    }
+
+    if todo.Estimate == nil {
+        return
+    }
+    switch todo.Estimate.Unit {
+    case domain.EstimateUnitPoints:
+        totals.Points += todo.Estimate.Value
+    default:
+        totals.Hours += todo.Estimate.Value
+    }
+}
+
+// roundTotals trims the float noise of summing two-decimal estimates
+func roundTotals(row *dto.MemberWorkloadResponse) {
+    for _, totals := range []*dto.EstimateTotals{&row.OpenEstimate, &row.CompletedEstimate} {
+        totals.Hours = math.Round(totals.Hours*100) / 100
+        totals.Points = math.Round(totals.Points*100) / 100
+    }
+}